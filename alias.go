@@ -0,0 +1,117 @@
+package gboost
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// HashFeatureAliases derives an opaque alias for each name from a keyed
+// hash: "f_" followed by 16 hex digits of the HMAC-SHA256 of the name under
+// key. The same names and key always give the same aliases, so the mapping
+// need not be stored; without the key, the aliases cannot be matched to
+// guessed column names. Keep the key private, like the mapping it stands
+// for.
+func HashFeatureAliases(names []string, key []byte) map[string]string {
+	aliases := make(map[string]string, len(names))
+	for _, name := range names {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(name))
+		aliases[name] = "f_" + hex.EncodeToString(mac.Sum(nil)[:8])
+	}
+	return aliases
+}
+
+// AliasFeatureNames replaces each of the model's feature names with
+// aliases[name], so that a model shared outside the team does not expose
+// sensitive column names. aliases maps private names to public ones, from
+// [HashFeatureAliases] or written by hand. The aliases are saved in place of
+// the names, with a flag reported by [GBM.FeatureNamesAliased]; the mapping
+// is not. The aliased model predicts as before, and the names of its
+// exports, explanations, and [GBM.PredictNamed] are the aliases. Holders of
+// the mapping restore the names with [GBM.UnaliasFeatureNames].
+//
+// Returns an error wrapping [ErrInvalidAliases] if the model has no feature
+// names, a name has no alias, or two names share one.
+func (g *GBM) AliasFeatureNames(aliases map[string]string) error {
+	names, err := aliasNames(g.featureNames, aliases)
+	if err != nil {
+		return err
+	}
+	g.featureNames = names
+	g.aliasedNames = true
+	return nil
+}
+
+// UnaliasFeatureNames restores the private feature names of a model aliased
+// by [GBM.AliasFeatureNames], given the same mapping from private names to
+// aliases, and clears the flag of [GBM.FeatureNamesAliased].
+//
+// Returns an error wrapping [ErrInvalidAliases] if the model's names are not
+// aliased, or an alias is not in the mapping or belongs to several names.
+func (g *GBM) UnaliasFeatureNames(aliases map[string]string) error {
+	if !g.aliasedNames {
+		return fmt.Errorf("%w: feature names are not aliased", ErrInvalidAliases)
+	}
+	inverse := make(map[string]string, len(aliases))
+	for name, alias := range aliases {
+		inverse[alias] = name
+	}
+	if len(inverse) != len(aliases) {
+		return fmt.Errorf("%w: names share an alias", ErrInvalidAliases)
+	}
+	names, err := aliasNames(g.featureNames, inverse)
+	if err != nil {
+		return err
+	}
+	g.featureNames = names
+	g.aliasedNames = false
+	return nil
+}
+
+// FeatureNamesAliased reports whether the model's feature names are aliases
+// set by [GBM.AliasFeatureNames].
+func (g *GBM) FeatureNamesAliased() bool {
+	return g.aliasedNames
+}
+
+// AliasFeatureNames replaces each of the dataset's feature names with
+// aliases[name], in FeatureNames and Header, so that a model trained on it
+// only ever sees the aliases. The target's header name is kept unless
+// aliases lists it. Returns the errors of [GBM.AliasFeatureNames].
+func (ds *Dataset) AliasFeatureNames(aliases map[string]string) error {
+	names, err := aliasNames(ds.FeatureNames, aliases)
+	if err != nil {
+		return err
+	}
+	for i, name := range ds.Header {
+		if alias, ok := aliases[name]; ok {
+			ds.Header[i] = alias
+		}
+	}
+	ds.FeatureNames = names
+	return nil
+}
+
+// aliasNames returns aliases[name] for each of names, which must all have
+// distinct, non-empty aliases.
+func aliasNames(names []string, aliases map[string]string) ([]string, error) {
+	if names == nil {
+		return nil, fmt.Errorf("%w: no feature names to alias", ErrInvalidAliases)
+	}
+	out := make([]string, len(names))
+	seen := make(map[string]string, len(names))
+	for j, name := range names {
+		alias := aliases[name]
+		if alias == "" {
+			return nil, fmt.Errorf("%w: no alias for %q", ErrInvalidAliases, name)
+		}
+		if other, ok := seen[alias]; ok {
+			return nil, fmt.Errorf("%w: %q and %q share alias %q", ErrInvalidAliases, other, name, alias)
+		}
+		seen[alias] = name
+		out[j] = alias
+	}
+	return out, nil
+}
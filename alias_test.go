@@ -0,0 +1,113 @@
+package gboost
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAliasFeatureNames(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	gbm := New(DefaultConfig())
+	if err := gbm.SetFeatureNames([]string{"salary", "credit_score"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	want := gbm.Predict(X)
+	private := gbm.FeatureNames()
+
+	aliases := HashFeatureAliases(private, []byte("secret"))
+	if again := HashFeatureAliases(private, []byte("secret")); aliases["salary"] != again["salary"] {
+		t.Error("hashing is not deterministic")
+	}
+	if other := HashFeatureAliases(private, []byte("other")); aliases["salary"] == other["salary"] {
+		t.Error("alias does not depend on the key")
+	}
+	if err := gbm.AliasFeatureNames(aliases); err != nil {
+		t.Fatal(err)
+	}
+	if !gbm.FeatureNamesAliased() || gbm.FeatureNames()[0] != aliases["salary"] || !strings.HasPrefix(gbm.FeatureNames()[0], "f_") {
+		t.Fatalf("aliased names = %v", gbm.FeatureNames())
+	}
+
+	// The shared artifact carries neither the names nor the mapping.
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := gbm.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "salary") || strings.Contains(string(data), "credit_score") {
+		t.Error("saved model exposes a private name")
+	}
+	shared, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !shared.FeatureNamesAliased() || !slices.Equal(shared.Predict(X), want) {
+		t.Error("the shared model lost its flag or its predictions")
+	}
+	if _, err := shared.PredictNamed(map[string]float64{aliases["salary"]: 0.5, aliases["credit_score"]: 0.5}, -1); err != nil {
+		t.Errorf("PredictNamed with aliases: %v", err)
+	}
+
+	if err := shared.UnaliasFeatureNames(aliases); err != nil {
+		t.Fatal(err)
+	}
+	if shared.FeatureNamesAliased() || !slices.Equal(shared.FeatureNames(), private) {
+		t.Errorf("restored names = %v", shared.FeatureNames())
+	}
+}
+
+func TestAliasFeatureNamesErrors(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	unnamed := New(DefaultConfig())
+	if err := unnamed.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if err := unnamed.AliasFeatureNames(map[string]string{}); !errors.Is(err, ErrInvalidAliases) {
+		t.Errorf("unnamed model: err = %v", err)
+	}
+	if err := unnamed.UnaliasFeatureNames(map[string]string{}); !errors.Is(err, ErrInvalidAliases) {
+		t.Errorf("unaliased model: err = %v", err)
+	}
+
+	named := New(DefaultConfig())
+	named.SetFeatureNames([]string{"a", "b"})
+	for _, aliases := range []map[string]string{
+		{"a": "x"},
+		{"a": "x", "b": ""},
+		{"a": "x", "b": "x"},
+	} {
+		if err := named.AliasFeatureNames(aliases); !errors.Is(err, ErrInvalidAliases) {
+			t.Errorf("%v: err = %v", aliases, err)
+		}
+	}
+	if named.FeatureNamesAliased() || !slices.Equal(named.FeatureNames(), []string{"a", "b"}) {
+		t.Error("a failed alias changed the names")
+	}
+}
+
+func TestDatasetAliasFeatureNames(t *testing.T) {
+	path := writeTestCSV(t, "alias.csv", `income,age,label
+1,20,0
+2,30,1
+`)
+	ds, err := LoadCSV(path, -1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.AliasFeatureNames(map[string]string{"income": "c1", "age": "c2"}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ds.FeatureNames, []string{"c1", "c2"}) || !slices.Equal(ds.Header, []string{"c1", "c2", "label"}) {
+		t.Errorf("FeatureNames = %v, Header = %v", ds.FeatureNames, ds.Header)
+	}
+}
@@ -0,0 +1,87 @@
+package gboost
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// LeafIndices returns the leaf each row of X reaches in every tree:
+// LeafIndices(X)[i][t] is the preorder index, within tree t, of the leaf row
+// i lands in, counting every node from the root at 0 and visiting left
+// subtrees before right ones, as [GBM.Tree] exposes them. Together with the
+// model's [GBM.Fingerprint] it records the exact path behind a prediction.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or
+// [ErrFeatureCountMismatch] if any row of X does not have numFeatures columns.
+func (g *GBM) LeafIndices(X [][]float64) ([][]int, error) {
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+	ids := g.nodeIDs()
+	res := make([][]int, len(X))
+	for i, x := range X {
+		if len(x) != g.numFeatures {
+			return nil, ErrFeatureCountMismatch
+		}
+		res[i] = g.leafIndices(ids, x)
+	}
+	return res, nil
+}
+
+// nodeIDs numbers the nodes of every tree in preorder, one map per tree.
+// The maps are only read afterwards, so they can be shared by goroutines.
+func (g *GBM) nodeIDs() []map[*Node]int {
+	ids := make([]map[*Node]int, len(g.trees))
+	for t, tree := range g.trees {
+		ids[t] = make(map[*Node]int)
+		numberNodes(tree, ids[t])
+	}
+	return ids
+}
+
+func numberNodes(n *Node, ids map[*Node]int) {
+	ids[n] = len(ids)
+	if n.isLeaf() {
+		return
+	}
+	numberNodes(n.Left, ids)
+	numberNodes(n.Right, ids)
+}
+
+// leafIndices returns the index of the leaf x reaches in each tree.
+func (g *GBM) leafIndices(ids []map[*Node]int, x []float64) []int {
+	leaves := make([]int, len(g.trees))
+	for t, n := range g.trees {
+		for !n.isLeaf() {
+			n = n.step(x)
+		}
+		leaves[t] = ids[t][n]
+	}
+	return leaves
+}
+
+// writeAudit writes the audit trail of [BatchOptions.Audit].
+func writeAudit(w io.Writer, fingerprint string, preds []float64, leaves [][]int, numTrees int) error {
+	cw := csv.NewWriter(w)
+	header := []string{"row", "prediction", "fingerprint"}
+	for t := range numTrees {
+		header = append(header, "leaf_"+strconv.Itoa(t))
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, 0, len(header))
+	for i, p := range preds {
+		record = append(record[:0], strconv.Itoa(i), formatCSVFloat(p), fingerprint)
+		for _, leaf := range leaves[i] {
+			record = append(record, strconv.Itoa(leaf))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,79 @@
+package gboost
+
+import "slices"
+
+// AutoFitOptions controls [AutoFit]. Zero values select the defaults.
+type AutoFitOptions struct {
+	// Config is the starting configuration. Nil means [DefaultConfig] with
+	// "logloss" if y holds only 0s and 1s, both present, and "mse"
+	// otherwise. Its NEstimators, ValidationFraction, Patience, and
+	// LRPatience are replaced by AutoFit.
+	Config *Config
+
+	// MaxEstimators caps the number of trees tried. Zero means 1000.
+	MaxEstimators int
+
+	// ValidationFraction is the fraction of rows held out to choose the
+	// number of trees. Zero means 0.2.
+	ValidationFraction float64
+
+	// Patience is the number of rounds without improvement on the held-out
+	// rows after which the search stops. Zero means 20.
+	Patience int
+}
+
+// AutoFit trains a model without tuning the number of trees by hand. It
+// holds out opts.ValidationFraction of the rows, trains up to
+// opts.MaxEstimators trees with early stopping on them, and then refits on
+// all of X with as many trees as the search kept, so that no row goes
+// unused. The returned model's Config.NEstimators is that number, and its
+// [GBM.History] is the refit's. [FitCVEarlyStop] chooses the number of trees
+// more robustly, from k folds, at k times the cost.
+//
+// Returns the errors of [GBM.Fit], such as [ErrInvalidNEstimators] or
+// [ErrInvalidPatience] for negative options, and [ErrInvalidValidationFraction]
+// for a fraction outside [0, 1).
+func AutoFit(X [][]float64, y []float64, opts AutoFitOptions) (*GBM, error) {
+	var cfg Config
+	if opts.Config != nil {
+		cfg = *opts.Config
+	} else {
+		cfg = DefaultConfig()
+		if isBinaryTarget(y) {
+			cfg.Loss = "logloss"
+		}
+	}
+	cfg.NEstimators = opts.MaxEstimators
+	if cfg.NEstimators == 0 {
+		cfg.NEstimators = 1000
+	}
+	cfg.ValidationFraction = opts.ValidationFraction
+	if cfg.ValidationFraction == 0 {
+		cfg.ValidationFraction = 0.2
+	}
+	cfg.Patience = opts.Patience
+	if cfg.Patience == 0 {
+		cfg.Patience = 20
+	}
+	// A learning rate reduced mid-search could not be replayed by the refit.
+	cfg.LRPatience = 0
+
+	search := New(cfg)
+	if err := search.Fit(X, y); err != nil {
+		return nil, err
+	}
+
+	cfg.NEstimators = search.NumTrees()
+	cfg.ValidationFraction, cfg.Patience = 0, 0
+	model := New(cfg)
+	if err := model.Fit(X, y); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// isBinaryTarget reports whether y holds only 0s and 1s, both present.
+func isBinaryTarget(y []float64) bool {
+	return slices.Contains(y, 0) && slices.Contains(y, 1) &&
+		!slices.ContainsFunc(y, func(v float64) bool { return v != 0 && v != 1 })
+}
@@ -0,0 +1,78 @@
+package gboost
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAutoFit(t *testing.T) {
+	X, y := generateNoisyData()
+	model, err := AutoFit(X, y, AutoFitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Config.Loss != "mse" {
+		t.Errorf("Loss = %q for a continuous target, want mse", model.Config.Loss)
+	}
+	n := model.NumTrees()
+	if n < 1 || n >= 1000 {
+		t.Fatalf("kept %d trees; early stopping should stop well before the cap on noisy data", n)
+	}
+	if model.Config.NEstimators != n || model.Config.ValidationFraction != 0 || len(model.History()) != n {
+		t.Errorf("refit: NEstimators %d, ValidationFraction %v, %d rounds of history for %d trees",
+			model.Config.NEstimators, model.Config.ValidationFraction, len(model.History()), n)
+	}
+
+	// The search is reproducible, and the refit equals a plain Fit with the
+	// chosen number of trees on all rows.
+	cfg := DefaultConfig()
+	cfg.NEstimators = n
+	plain := New(cfg)
+	if err := plain.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	for i, x := range X {
+		if got, want := model.PredictSingle(x), plain.PredictSingle(x); got != want {
+			t.Fatalf("row %d: AutoFit predicts %v, Fit with %d trees %v", i, got, n, want)
+		}
+	}
+}
+
+func TestAutoFitInfersLogloss(t *testing.T) {
+	X, y := generateNoisyData()
+	for i, v := range y {
+		y[i] = 0
+		if v > 0.5 {
+			y[i] = 1
+		}
+	}
+	model, err := AutoFit(X, y, AutoFitOptions{MaxEstimators: 50, Patience: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Config.Loss != "logloss" || model.NumTrees() > 50 {
+		t.Errorf("Loss = %q with %d trees, want logloss with at most 50", model.Config.Loss, model.NumTrees())
+	}
+
+	cfg := DefaultConfig()
+	model, err = AutoFit(X, y, AutoFitOptions{Config: &cfg, MaxEstimators: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Config.Loss != "mse" {
+		t.Errorf("Loss = %q, want the given config's mse", model.Config.Loss)
+	}
+}
+
+func TestAutoFitErrors(t *testing.T) {
+	X, y := generateNoisyData()
+	if _, err := AutoFit(X, y, AutoFitOptions{MaxEstimators: -1}); !errors.Is(err, ErrInvalidNEstimators) {
+		t.Errorf("MaxEstimators -1: err = %v, want ErrInvalidNEstimators", err)
+	}
+	if _, err := AutoFit(X, y, AutoFitOptions{ValidationFraction: 1}); !errors.Is(err, ErrInvalidValidationFraction) {
+		t.Errorf("ValidationFraction 1: err = %v, want ErrInvalidValidationFraction", err)
+	}
+	if _, err := AutoFit(nil, nil, AutoFitOptions{}); !errors.Is(err, ErrEmptyDataset) {
+		t.Errorf("no rows: err = %v, want ErrEmptyDataset", err)
+	}
+}
@@ -0,0 +1,100 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+)
+
+// RollingOriginFolds returns the folds of a rolling-origin backtest over n
+// rows in time order. Fold k trains on the window rows starting at row
+// k*step and tests on the horizon rows right after them, so every model is
+// scored only on rows later than any it was trained on. Folds are generated
+// while the test rows fit in n.
+//
+// Returns [ErrInvalidBacktest] if window, horizon, or step is less than 1,
+// or window + horizon exceeds n.
+func RollingOriginFolds(n, window, horizon, step int) ([]Fold, error) {
+	if window < 1 || horizon < 1 || step < 1 || window+horizon > n {
+		return nil, ErrInvalidBacktest
+	}
+	var folds []Fold
+	for start := 0; start+window+horizon <= n; start += step {
+		fold := Fold{Train: make([]int, window), Test: make([]int, horizon)}
+		for i := range fold.Train {
+			fold.Train[i] = start + i
+		}
+		for i := range fold.Test {
+			fold.Test[i] = start + window + i
+		}
+		folds = append(folds, fold)
+	}
+	return folds, nil
+}
+
+// BacktestFold holds the scores of one fold of a [Backtest].
+type BacktestFold struct {
+	Fold   Fold
+	Scores map[string]float64
+}
+
+// BacktestResult holds the per-fold scores of a [Backtest] and their
+// aggregates over folds, by metric name.
+type BacktestResult struct {
+	Folds []BacktestFold // in time order
+	Mean  map[string]float64
+	Std   map[string]float64
+}
+
+// Backtest validates cfg for forecasting by rolling-origin evaluation on
+// the rows of ds, which must be in time order. It trains a model on each
+// window of rows from [RollingOriginFolds] and scores it on the next
+// horizon rows, so that, unlike [CrossValidate], no model is scored on rows
+// earlier than those it learned from. Metrics score each fold by name; like
+// [CrossValidate] metrics they receive probabilities for logloss models,
+// expected values for gamma models, and raw predictions otherwise. Empty
+// metrics means the training loss, reported as "loss".
+//
+// Returns [ErrInvalidBacktest] for an invalid window, horizon, or step,
+// [ErrLengthMismatch] if ds.X and ds.Y differ in length, or the first error
+// returned while fitting a fold, naming the fold.
+func Backtest(cfg Config, ds *Dataset, window, horizon, step int, metrics map[string]Metric) (*BacktestResult, error) {
+	if len(ds.X) != len(ds.Y) {
+		return nil, ErrLengthMismatch
+	}
+	folds, err := RollingOriginFolds(len(ds.X), window, horizon, step)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &BacktestResult{
+		Folds: make([]BacktestFold, len(folds)),
+		Mean:  make(map[string]float64),
+		Std:   make(map[string]float64),
+	}
+	for k, fold := range folds {
+		XTrain, XTest, yTrain, yTest, _ := fold.Split(ds.X, ds.Y)
+		model := New(cfg)
+		if err := model.Fit(XTrain, yTrain); err != nil {
+			return nil, fmt.Errorf("backtest fold %d: %w", k, err)
+		}
+		scores := make(map[string]float64, max(len(metrics), 1))
+		if len(metrics) == 0 {
+			scores["loss"] = evalLoss(cfg.Loss, yTest, model.Predict(XTest), nil)
+		}
+		output := model.predictOutput(XTest)
+		for name, metric := range metrics {
+			scores[name] = metric(yTest, output)
+		}
+		res.Folds[k] = BacktestFold{Fold: fold, Scores: scores}
+	}
+
+	values := make([]float64, len(folds))
+	for name := range res.Folds[0].Scores {
+		for k, f := range res.Folds {
+			values[k] = f.Scores[name]
+		}
+		res.Mean[name] = mean(values)
+		res.Std[name] = math.Sqrt(variance(values))
+	}
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestRollingOriginFolds(t *testing.T) {
+	folds, err := RollingOriginFolds(10, 4, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Fold{
+		{Train: []int{0, 1, 2, 3}, Test: []int{4, 5}},
+		{Train: []int{3, 4, 5, 6}, Test: []int{7, 8}},
+	}
+	if len(folds) != len(want) {
+		t.Fatalf("got %d folds, want %d: %v", len(folds), len(want), folds)
+	}
+	for k := range want {
+		if !slices.Equal(folds[k].Train, want[k].Train) || !slices.Equal(folds[k].Test, want[k].Test) {
+			t.Errorf("fold %d = %v, want %v", k, folds[k], want[k])
+		}
+	}
+
+	for _, args := range [][4]int{{10, 0, 2, 1}, {10, 4, 0, 1}, {10, 4, 2, 0}, {5, 4, 2, 1}} {
+		if _, err := RollingOriginFolds(args[0], args[1], args[2], args[3]); !errors.Is(err, ErrInvalidBacktest) {
+			t.Errorf("RollingOriginFolds%v: err = %v, want ErrInvalidBacktest", args, err)
+		}
+	}
+}
+
+func TestBacktest(t *testing.T) {
+	// A trend the models can only follow within their window: each fold's
+	// test rows lie beyond the targets it trained on.
+	ds := &Dataset{}
+	for i := range 60 {
+		ds.X = append(ds.X, []float64{float64(i % 7)})
+		ds.Y = append(ds.Y, float64(i)+float64(i%7))
+	}
+	cfg := DefaultConfig()
+	cfg.NEstimators = 20
+
+	res, err := Backtest(cfg, ds, 20, 5, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Folds) != 4 {
+		t.Fatalf("got %d folds, want 4", len(res.Folds))
+	}
+	for k, f := range res.Folds {
+		if f.Fold.Test[0] != 10*k+20 || !(f.Scores["loss"] > 0) {
+			t.Errorf("fold %d: %+v", k, f)
+		}
+	}
+	if res.Mean["loss"] <= 0 || math.IsNaN(res.Std["loss"]) {
+		t.Errorf("aggregates: mean %v, std %v", res.Mean, res.Std)
+	}
+
+	mae := func(y, pred []float64) float64 {
+		var s float64
+		for i := range y {
+			s += math.Abs(y[i] - pred[i])
+		}
+		return s / float64(len(y))
+	}
+	res, err = Backtest(cfg, ds, 20, 5, 10, map[string]Metric{"mae": mae})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.Mean["loss"]; ok || !(res.Mean["mae"] > 0) {
+		t.Errorf("metrics: %v", res.Mean)
+	}
+
+	if _, err := Backtest(cfg, ds, 50, 20, 1, nil); !errors.Is(err, ErrInvalidBacktest) {
+		t.Errorf("window too long: err = %v", err)
+	}
+	if _, err := Backtest(Config{NEstimators: -1}, ds, 20, 5, 10, nil); !errors.Is(err, ErrInvalidNEstimators) {
+		t.Errorf("bad config: err = %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package gboost
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// BatchOptions controls [GBM.PredictBatch].
+type BatchOptions struct {
+	// ChunkSize is the number of rows scored between cancellation checks and
+	// progress reports. Zero means 4096.
+	ChunkSize int
+
+	// Workers bounds the number of chunks scored concurrently.
+	// Zero means runtime.GOMAXPROCS(0).
+	Workers int
+
+	// Proba returns P(y=1) instead of raw log-odds. Only meaningful for
+	// models trained with logloss.
+	Proba bool
+
+	// Progress, if set, is called after each chunk with the number of rows
+	// scored so far and the total. Calls are serialized and done increases
+	// monotonically, so the callback need not be safe for concurrent use.
+	Progress func(done, total int)
+
+	// Audit, if set, receives an audit trail of the batch once every row is
+	// scored: a CSV header "row,prediction,fingerprint,leaf_0,...", then one
+	// line per row with its returned score, the model's [GBM.Fingerprint],
+	// and the leaf it reached in each tree as [GBM.LeafIndices] numbers them.
+	// The record ties each score to the exact model and path it came from.
+	Audit io.Writer
+}
+
+func (o BatchOptions) validate() error {
+	switch {
+	case o.ChunkSize < 0:
+		return ErrInvalidChunkSize
+	case o.Workers < 0:
+		return ErrInvalidWorkers
+	}
+	return nil
+}
+
+func (o BatchOptions) chunkSize() int {
+	if o.ChunkSize == 0 {
+		return 4096
+	}
+	return o.ChunkSize
+}
+
+func (o BatchOptions) workers() int {
+	if o.Workers == 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.Workers
+}
+
+// PredictBatch scores X in chunks on up to opts.Workers goroutines, checking
+// ctx between chunks. It suits long scoring jobs that must be interruptible
+// and report progress; results are identical to [GBM.Predict] (or
+// [GBM.PredictProbaAll] with opts.Proba).
+//
+// Returns [ErrModelNotFitted] if the model has not been trained,
+// [ErrFeatureCountMismatch] if a row does not have numFeatures columns,
+// [ErrInvalidChunkSize] or [ErrInvalidWorkers] for invalid options, or
+// ctx.Err() if the context is cancelled before every chunk is scored. An
+// error writing opts.Audit is returned as is, without the scores, so that no
+// unaudited score is used.
+func (g *GBM) PredictBatch(ctx context.Context, X [][]float64, opts BatchOptions) ([]float64, error) {
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	size := opts.chunkSize()
+	numChunks := (len(X) + size - 1) / size
+	res := make([]float64, len(X))
+	var (
+		ids    []map[*Node]int
+		leaves [][]int
+	)
+	if opts.Audit != nil {
+		ids = g.nodeIDs()
+		leaves = make([][]int, len(X))
+	}
+	chunks := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for range min(opts.workers(), numChunks) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range chunks {
+				start, end := c*size, min((c+1)*size, len(X))
+				for i := start; i < end; i++ {
+					if len(X[i]) != g.numFeatures {
+						fail(ErrFeatureCountMismatch)
+						cancel()
+						break
+					}
+					res[i] = g.PredictSingle(X[i])
+					if opts.Proba {
+						res[i] = sigmoid(res[i])
+					}
+					if leaves != nil {
+						leaves[i] = g.leafIndices(ids, X[i])
+					}
+				}
+				if opts.Progress != nil {
+					mu.Lock()
+					done += end - start
+					if firstErr == nil {
+						opts.Progress(done, len(X))
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	fed := 0
+feed:
+	for fed < numChunks {
+		select {
+		case <-ctx.Done():
+			break feed
+		case chunks <- fed:
+			fed++
+		}
+	}
+	close(chunks)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if fed < numChunks {
+		return nil, ctx.Err()
+	}
+	if opts.Audit != nil {
+		fingerprint, err := g.Fingerprint()
+		if err != nil {
+			return nil, err
+		}
+		if err := writeAudit(opts.Audit, fingerprint, res, leaves, len(g.trees)); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,183 @@
+package gboost
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestPredictBatchMatchesPredict(t *testing.T) {
+	X, y := generateBinaryData(5)
+	gbm := fitBinaryModel(t, X, y)
+
+	var calls []int
+	got, err := gbm.PredictBatch(context.Background(), X, BatchOptions{
+		ChunkSize: 7,
+		Workers:   3,
+		Progress:  func(done, total int) { calls = append(calls, done) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range gbm.Predict(X) {
+		if got[i] != want {
+			t.Fatalf("PredictBatch[%d] = %v, want %v", i, got[i], want)
+		}
+	}
+
+	wantCalls := (len(X) + 6) / 7
+	if len(calls) != wantCalls {
+		t.Fatalf("Progress called %d times, want %d", len(calls), wantCalls)
+	}
+	for i := 1; i < len(calls); i++ {
+		if calls[i] <= calls[i-1] {
+			t.Fatalf("progress went from %d to %d", calls[i-1], calls[i])
+		}
+	}
+	if last := calls[len(calls)-1]; last != len(X) {
+		t.Errorf("final progress = %d, want %d", last, len(X))
+	}
+
+	proba, err := gbm.PredictBatch(context.Background(), X, BatchOptions{Proba: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range gbm.PredictProbaAll(X) {
+		if proba[i] != want {
+			t.Fatalf("PredictBatch proba[%d] = %v, want %v", i, proba[i], want)
+		}
+	}
+}
+
+func TestPredictBatchCancel(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	gbm := New(DefaultConfig())
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := 0
+	_, err := gbm.PredictBatch(ctx, X, BatchOptions{
+		ChunkSize: 1,
+		Workers:   1,
+		Progress: func(n, total int) {
+			done = n
+			if n == 3 {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if done >= len(X) {
+		t.Errorf("scored all %d rows despite cancellation", done)
+	}
+
+	if _, err := gbm.PredictBatch(ctx, X, BatchOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for an already cancelled context, got %v", err)
+	}
+}
+
+func TestPredictBatchErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := New(DefaultConfig()).PredictBatch(ctx, [][]float64{{1}}, BatchOptions{}); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+
+	gbm := fitStumpModel(t)
+	if _, err := gbm.PredictBatch(ctx, [][]float64{{1}}, BatchOptions{ChunkSize: -1}); !errors.Is(err, ErrInvalidChunkSize) {
+		t.Errorf("expected ErrInvalidChunkSize, got %v", err)
+	}
+	if _, err := gbm.PredictBatch(ctx, [][]float64{{1}}, BatchOptions{Workers: -1}); !errors.Is(err, ErrInvalidWorkers) {
+		t.Errorf("expected ErrInvalidWorkers, got %v", err)
+	}
+	if _, err := gbm.PredictBatch(ctx, [][]float64{{1}, {1, 2}}, BatchOptions{}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+	if res, err := gbm.PredictBatch(ctx, nil, BatchOptions{}); err != nil || len(res) != 0 {
+		t.Errorf("empty input returned %v, %v", res, err)
+	}
+}
+
+func TestPredictBatchAudit(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 5
+	cfg.MaxDepth = 3
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	preds, err := gbm.PredictBatch(context.Background(), X, BatchOptions{ChunkSize: 7, Workers: 3, Audit: &buf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(X)+1 || len(records[0]) != 3+gbm.NumTrees() {
+		t.Fatalf("audit is %d lines of %d fields", len(records), len(records[0]))
+	}
+	if records[0][2] != "fingerprint" || records[0][3] != "leaf_0" {
+		t.Errorf("header = %v", records[0])
+	}
+
+	fingerprint, _ := gbm.Fingerprint()
+	leaves, err := gbm.LeafIndices(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, rec := range records[1:] {
+		if p, _ := strconv.ParseFloat(rec[1], 64); p != preds[i] {
+			t.Errorf("row %d: audited prediction %s, want %v", i, rec[1], preds[i])
+		}
+		if rec[2] != fingerprint {
+			t.Errorf("row %d: fingerprint %s, want %s", i, rec[2], fingerprint)
+		}
+		// The audited leaves alone reproduce the prediction.
+		want := gbm.initialPrediction
+		for tr, field := range rec[3:] {
+			leaf, _ := strconv.Atoi(field)
+			if leaf != leaves[i][tr] {
+				t.Fatalf("row %d tree %d: audited leaf %d, LeafIndices %d", i, tr, leaf, leaves[i][tr])
+			}
+			tree, _ := gbm.Tree(tr)
+			n := preorder(tree)[leaf]
+			if !n.isLeaf() {
+				t.Fatalf("row %d tree %d: node %d is not a leaf", i, tr, leaf)
+			}
+			want += float64(cfg.LearningRate * n.Value)
+		}
+		if want != preds[i] {
+			t.Errorf("row %d: leaves give %v, prediction is %v", i, want, preds[i])
+		}
+	}
+
+	if _, err := gbm.PredictBatch(context.Background(), X, BatchOptions{Audit: failingWriter{}}); !errors.Is(err, errWriteFailed) {
+		t.Errorf("failing audit writer: err = %v", err)
+	}
+	if _, err := gbm.LeafIndices([][]float64{{1}}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("LeafIndices on a short row: err = %v", err)
+	}
+}
+
+func preorder(n *Node) []*Node {
+	if n.isLeaf() {
+		return []*Node{n}
+	}
+	return append(append([]*Node{n}, preorder(n.Left)...), preorder(n.Right)...)
+}
+
+var errWriteFailed = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWriteFailed }
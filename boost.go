@@ -0,0 +1,332 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// BoostOneRound grows one tree on caller-supplied first and second
+// derivatives and appends it to the ensemble. It exposes the tree engine to
+// training loops written outside the package: custom objectives, custom row
+// sampling (pass only the rows to train on), or federated schemes that
+// aggregate gradients from elsewhere.
+//
+// grads[i] and hessians[i] are the derivatives of the caller's loss for row
+// X[i] with respect to the model's current raw prediction, as returned by
+// [GBM.PredictSingle]. The tree fits the Newton step -grads/hessians, so for
+// squared error (y - p)²/2 pass grads p - y and hessians 1. The returned
+// slice holds the tree's contribution, already scaled by
+// Config.LearningRate, to each row's raw prediction; add it to the
+// predictions before computing the next round's derivatives.
+//
+// On an untrained model the first call sets the feature count and starts
+// from a raw prediction of 0, discarding any trees a failed [GBM.Fit] left;
+// on a model trained by [GBM.Fit] it continues boosting from the existing
+// trees. The tree honors MaxDepth, MinSamplesLeaf, ColsampleByTree,
+// HonestFraction, HierarchicalShrinkage, and DropRedundantFeatures, drawing
+// randomness from Config.Seed and the round number as Fit does. Row
+// sampling, CostMatrix weighting, and early stopping are the caller's;
+// each call appends a [RoundStats] entry whose losses are NaN, since the
+// objective is unknown to the package.
+//
+// Returns an error for an invalid Config, [ErrEmptyDataset] or
+// [ErrEmptyFeatures] for empty input, [ErrLengthMismatch] if grads or
+// hessians do not have one entry per row, [ErrFeatureCountMismatch] if rows
+// differ in length or from the model's feature count, or
+// [ErrInvalidGradients] for derivatives that could not yield finite leaf
+// values; see checkDerivatives. Set Config.MinHessian to floor the Hessians
+// of a loss with zero or negative curvature.
+func (g *GBM) BoostOneRound(X [][]float64, grads, hessians []float64) ([]float64, error) {
+	if err := g.Config.validate(); err != nil {
+		return nil, err
+	}
+	switch {
+	case len(X) < 1:
+		return nil, ErrEmptyDataset
+	case len(X[0]) < 1:
+		return nil, ErrEmptyFeatures
+	case len(grads) != len(X) || len(hessians) != len(X):
+		return nil, ErrLengthMismatch
+	case !hasSimilarLength(X):
+		return nil, ErrFeatureCountMismatch
+	case g.isFitted && len(X[0]) != g.numFeatures:
+		return nil, ErrFeatureCountMismatch
+	case g.featureNames != nil && len(g.featureNames) != len(X[0]):
+		return nil, ErrFeatureCountMismatch
+	}
+	if g.Config.MinHessian > 0 {
+		hessians = slices.Clone(hessians)
+		floorHessians(hessians, g.Config.MinHessian)
+	}
+	if err := checkDerivatives(grads, hessians); err != nil {
+		return nil, err
+	}
+
+	if !g.isFitted {
+		// Start from scratch, dropping whatever a failed Fit left behind.
+		g.numFeatures = len(X[0])
+		g.loss = createLossFunction(g.Config)
+		g.initialPrediction = 0
+		g.trees = nil
+		g.maxDepth = 0
+		g.featureImportance = nil
+		g.snapshotWeights = nil
+		g.history = nil
+		g.diagnostics = nil
+		g.leafTransform = ""
+		g.warnings = nil
+		g.featureMin, g.featureMax = featureRanges(X)
+	} else {
+		g.widenFeatureRanges(X)
+	}
+
+	meter := startMeter(g.Config.TrackResources)
+	// The tree engine fits negative gradients.
+	residuals := make([]float64, len(grads))
+	for i, gr := range grads {
+		residuals[i] = -gr
+	}
+	tree, features, err := g.growTree(X, residuals, hessians)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas := make([]float64, len(X))
+	for i, x := range X {
+		deltas[i] = float64(g.Config.LearningRate * tree.predict(x))
+	}
+
+	g.history = append(g.history, RoundStats{
+		Round:               len(g.trees),
+		TrainLoss:           math.NaN(),
+		SampleSize:          len(X),
+		EffectiveSampleSize: float64(len(X)),
+		Features:            features,
+		TreeStats:           tree.stats(),
+		ValidationLoss:      math.NaN(),
+		LearningRate:        g.Config.LearningRate,
+		Resources:           meter.finish(),
+	})
+	g.calculateFeatureImportance()
+	g.isFitted = true
+	return deltas, nil
+}
+
+// widenFeatureRanges extends the recorded training feature ranges to cover
+// X. Models loaded from files without ranges keep none.
+func (g *GBM) widenFeatureRanges(X [][]float64) {
+	if g.featureMin == nil {
+		return
+	}
+	lo, hi := featureRanges(X)
+	for j := range lo {
+		if math.IsNaN(g.featureMin[j]) || lo[j] < g.featureMin[j] {
+			g.featureMin[j] = lo[j]
+		}
+		if math.IsNaN(g.featureMax[j]) || hi[j] > g.featureMax[j] {
+			g.featureMax[j] = hi[j]
+		}
+	}
+}
+
+// checkDerivatives returns an [ErrInvalidGradients] naming the first row
+// whose derivatives could make a leaf value sum(g)/sum(h) infinite or NaN:
+// a gradient or Hessian that is not finite, a Hessian that is not positive,
+// either one larger in magnitude than math.MaxFloat64/len(grads), past
+// which a node's sum can overflow, or a Newton step g/h that overflows. A
+// leaf value is a weighted mean of its rows' Newton steps, so it is finite
+// when they all are.
+func checkDerivatives(grads, hessians []float64) error {
+	limit := math.MaxFloat64 / float64(len(grads))
+	for i, gr := range grads {
+		h := hessians[i]
+		switch {
+		case math.IsNaN(gr) || math.IsInf(gr, 0):
+			return fmt.Errorf("%w: row %d: gradient is %v", ErrInvalidGradients, i, gr)
+		case math.IsNaN(h) || math.IsInf(h, 0):
+			return fmt.Errorf("%w: row %d: Hessian is %v", ErrInvalidGradients, i, h)
+		case h <= 0:
+			return fmt.Errorf("%w: row %d: Hessian is %v; set Config.MinHessian to floor it", ErrInvalidGradients, i, h)
+		case math.Abs(gr) > limit || h > limit:
+			return fmt.Errorf("%w: row %d: gradient %v or Hessian %v exceeds %v, so sums over %d rows can overflow", ErrInvalidGradients, i, gr, h, limit, len(grads))
+		case math.IsInf(gr/h, 0):
+			return fmt.Errorf("%w: row %d: Newton step %v/%v overflows; raise Config.MinHessian", ErrInvalidGradients, i, gr, h)
+		}
+	}
+	return nil
+}
+
+// floorHessians raises every Hessian below floor to floor.
+func floorHessians(hessians []float64, floor float64) {
+	if floor <= 0 {
+		return
+	}
+	for i, h := range hessians {
+		hessians[i] = max(h, floor)
+	}
+}
+
+// growTree grows the next round's tree on every row of X from negative
+// gradients and Hessians and appends it to the ensemble. It returns the tree
+// and the number of features it could split on, or the error of
+// Config.LeafTransform, in which case nothing is appended.
+func (g *GBM) growTree(X [][]float64, residuals, hessians []float64) (*Node, int, error) {
+	indices := make([]int, len(X))
+	for i := range indices {
+		indices[i] = i
+	}
+	features := allFeatures(g.numFeatures)
+	if g.Config.DropRedundantFeatures {
+		features = nonRedundantFeatures(X)
+	}
+	roundSeed := deriveSeed(g.Config.Seed, len(g.trees))
+	if r := g.Config.ColsampleByTree; r > 0 && r < 1.0 {
+		features = g.sampleColumns(newRNG(roundSeed, 0), features)
+	}
+	structureIndices, leafIndices := indices, indices
+	if g.Config.HonestFraction > 0 && len(indices) >= 2 {
+		structureIndices, leafIndices = honestSplit(newRNG(roundSeed, 1), indices, g.Config.HonestFraction)
+	}
+	tree := buildTree(X, residuals, hessians, structureIndices, features, 0, g.Config)
+	if g.Config.HierarchicalShrinkage > 0 || g.Config.HonestFraction > 0 {
+		shrinkTree(tree, X, residuals, hessians, leafIndices, g.Config.HierarchicalShrinkage)
+	}
+	if err := g.transformLeaves(tree); err != nil {
+		return nil, 0, err
+	}
+	g.appendTree(tree)
+	return tree, len(features), nil
+}
+
+// Batch is a mini-batch of training rows for [GBM.FitStream].
+type Batch struct {
+	X [][]float64
+	Y []float64
+}
+
+// FitStream trains the model from scratch on mini-batches received from
+// batches, growing one tree per batch: each round computes the gradients of
+// the current ensemble on the next batch only and fits a tree to them, in
+// the manner of streaming gradient boosting. Training ends when batches is
+// closed or Config.NEstimators trees have been grown, after which FitStream
+// stops receiving; senders must not block forever on a full channel. A bad
+// batch stops it receiving as well.
+//
+// FitStream is experimental. It suits data that arrives continuously, where
+// full passes are impossible, at a cost in accuracy: each tree sees only its
+// batch, so small batches give noisy trees, and no tree revisits earlier
+// data. Larger batches and a smaller learning rate narrow the gap to
+// [GBM.Fit]. The initial prediction is fixed by the first batch.
+//
+// Each round's [RoundStats] reports as ValidationLoss the loss on the batch
+// before its tree was added, an honest out-of-sample estimate since the
+// model has not yet seen the batch, and as TrainLoss the loss after. The
+// tree honors the same settings as [GBM.BoostOneRound] and
+// Config.CostMatrix; row sampling, validation and early stopping, learning
+// rate reduction, EvalSlices, and KeepDiagnostics do not apply. OnRoundEnd
+// is called after every round.
+//
+// Returns an error for an invalid Config, an error wrapping
+// [ErrInvalidNEstimators] if Config.NEstimators is 0, since the stream
+// would never end, [ErrEmptyDataset] if no batch arrives, and for the
+// first bad batch, an error naming it that wraps [ErrEmptyDataset],
+// [ErrEmptyFeatures], [ErrLengthMismatch], or [ErrFeatureCountMismatch].
+// On error the model is left untrained.
+func (g *GBM) FitStream(batches <-chan Batch) error {
+	return g.tracked(func() error { return g.fitStream(batches) })
+}
+
+// fitStream trains the model for FitStream.
+func (g *GBM) fitStream(batches <-chan Batch) error {
+	if err := g.Config.validate(); err != nil {
+		return err
+	}
+	if g.Config.NEstimators == 0 {
+		return fmt.Errorf("%w: FitStream needs at least one tree", ErrInvalidNEstimators)
+	}
+	g.isFitted = false
+	g.trees = nil
+	g.maxDepth = 0
+	g.history = nil
+	g.diagnostics = nil
+	g.leafTransform = ""
+	g.warnings = nil
+	g.loss = createLossFunction(g.Config)
+
+	for batch := range batches {
+		round := len(g.trees)
+		X, y := batch.X, batch.Y
+		var err error
+		switch {
+		case len(X) < 1:
+			err = ErrEmptyDataset
+		case len(X[0]) < 1:
+			err = ErrEmptyFeatures
+		case len(X) != len(y):
+			err = ErrLengthMismatch
+		case !hasSimilarLength(X):
+			err = ErrFeatureCountMismatch
+		case round > 0 && len(X[0]) != g.numFeatures:
+			err = ErrFeatureCountMismatch
+		case g.featureNames != nil && len(g.featureNames) != len(X[0]):
+			err = ErrFeatureCountMismatch
+		case g.Config.Loss == "logloss" && !validLabels(y, g.Config.CostMatrix == nil):
+			err = ErrInvalidLabels
+		case g.Config.Loss == "gamma" && !positiveTargets(y):
+			err = ErrInvalidGammaTargets
+		}
+		if err != nil {
+			return fmt.Errorf("batch %d: %w", round, err)
+		}
+
+		if round == 0 {
+			g.numFeatures = len(X[0])
+			g.featureMin, g.featureMax = featureRanges(X)
+			g.initialPrediction = startingPrediction(g.loss, g.Config.Loss, y, nil)
+		} else {
+			g.widenFeatureRanges(X)
+		}
+
+		meter := startMeter(g.Config.TrackResources)
+		predictions := make([]float64, len(X))
+		for i, x := range X {
+			predictions[i] = g.PredictSingle(x)
+		}
+		before := evalLoss(g.Config.Loss, y, predictions, nil)
+		residuals, hessians := g.gradients(g.loss, y, predictions)
+		tree, features, err := g.growTree(X, residuals, hessians)
+		if err != nil {
+			return err
+		}
+		for i, x := range X {
+			predictions[i] += float64(g.Config.LearningRate * tree.predict(x))
+		}
+
+		g.history = append(g.history, RoundStats{
+			Round:               round + 1,
+			SampleSize:          len(X),
+			EffectiveSampleSize: float64(len(X)),
+			Features:            features,
+			TreeStats:           tree.stats(),
+			TrainLoss:           evalLoss(g.Config.Loss, y, predictions, nil),
+			ValidationLoss:      before,
+			LearningRate:        g.Config.LearningRate,
+			Resources:           meter.finish(),
+		})
+		if err := g.fireRoundEndCallback(round + 1); err != nil {
+			return err
+		}
+		if round+1 == g.Config.NEstimators {
+			break
+		}
+	}
+	if len(g.trees) == 0 {
+		return ErrEmptyDataset
+	}
+
+	g.blendSnapshots()
+	g.calculateFeatureImportance()
+	g.isFitted = true
+	return nil
+}
@@ -0,0 +1,340 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// squaredErrorDerivatives returns the gradients and Hessians of (y - p)²/2.
+func squaredErrorDerivatives(y, pred []float64) (grads, hessians []float64) {
+	grads = make([]float64, len(y))
+	hessians = make([]float64, len(y))
+	for i := range y {
+		grads[i] = pred[i] - y[i]
+		hessians[i] = 1
+	}
+	return grads, hessians
+}
+
+func TestBoostOneRoundMatchesFit(t *testing.T) {
+	X, y := generateNoisyData()
+	cfg := DefaultConfig()
+	cfg.NEstimators = 5
+	cfg.MaxDepth = 3
+	cfg.ColsampleByTree = 0.5
+	cfg.Seed = 3
+
+	fitted := New(cfg)
+	if err := fitted.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	// Start from the same initial prediction with zero rounds, then boost
+	// by hand.
+	zero := cfg
+	zero.NEstimators = 0
+	manual := New(zero)
+	if err := manual.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	pred := manual.Predict(X)
+	for range cfg.NEstimators {
+		grads, hessians := squaredErrorDerivatives(y, pred)
+		deltas, err := manual.BoostOneRound(X, grads, hessians)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range pred {
+			pred[i] += deltas[i]
+		}
+	}
+
+	if manual.NumTrees() != fitted.NumTrees() {
+		t.Fatalf("NumTrees = %d, want %d", manual.NumTrees(), fitted.NumTrees())
+	}
+	want := fitted.Predict(X)
+	got := manual.Predict(X)
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 || math.Abs(pred[i]-want[i]) > 1e-9 {
+			t.Fatalf("row %d: BoostOneRound predicts %v (tracked %v), Fit predicts %v", i, got[i], pred[i], want[i])
+		}
+	}
+	if len(manual.History()) != cfg.NEstimators || !math.IsNaN(manual.History()[0].TrainLoss) {
+		t.Errorf("History = %+v, want %d rounds with NaN losses", manual.History(), cfg.NEstimators)
+	}
+}
+
+func TestBoostOneRoundFromScratch(t *testing.T) {
+	X, y := generateNoisyData()
+	cfg := DefaultConfig()
+	cfg.MaxDepth = 3
+	cfg.LearningRate = 0.3
+
+	gbm := New(cfg)
+	pred := make([]float64, len(y))
+	for range 30 {
+		grads, hessians := squaredErrorDerivatives(y, pred)
+		deltas, err := gbm.BoostOneRound(X, grads, hessians)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range pred {
+			pred[i] += deltas[i]
+		}
+	}
+
+	var before, after float64
+	for i := range y {
+		before += y[i] * y[i]
+		after += (y[i] - pred[i]) * (y[i] - pred[i])
+	}
+	if after > 0.5*before {
+		t.Errorf("squared error %v, want well below the %v of a zero model", after, before)
+	}
+	if gbm.NumFeatures() != len(X[0]) || len(gbm.FeatureImportance()) != len(X[0]) {
+		t.Errorf("model not initialized: %d features, importance %v", gbm.NumFeatures(), gbm.FeatureImportance())
+	}
+	if got := gbm.PredictSingle(X[0]); math.Abs(got-pred[0]) > 1e-9 {
+		t.Errorf("PredictSingle = %v, want the tracked %v", got, pred[0])
+	}
+}
+
+func TestBoostOneRoundAfterFailedFit(t *testing.T) {
+	X, y := generateNoisyData()
+	cfg := DefaultConfig()
+	cfg.OnRoundEnd = func(round, total int) error {
+		if round == 5 {
+			return errors.New("stop")
+		}
+		return nil
+	}
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err == nil {
+		t.Fatal("Fit succeeded despite the callback error")
+	}
+
+	// The failed Fit left trees and an initial prediction behind; the first
+	// round must not build on them.
+	gbm.Config.OnRoundEnd = nil
+	grads, hessians := squaredErrorDerivatives(y, make([]float64, len(y)))
+	deltas, err := gbm.BoostOneRound(X, grads, hessians)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gbm.NumTrees() != 1 || len(gbm.History()) != 1 {
+		t.Errorf("%d trees and %d rounds after one round, want 1 and 1", gbm.NumTrees(), len(gbm.History()))
+	}
+	if got := gbm.PredictSingle(X[0]); got != deltas[0] {
+		t.Errorf("PredictSingle = %v, want the first round's %v", got, deltas[0])
+	}
+}
+
+func TestBoostOneRoundErrors(t *testing.T) {
+	X := [][]float64{{1}, {2}, {3}}
+	ones := []float64{1, 1, 1}
+
+	tests := []struct {
+		name            string
+		X               [][]float64
+		grads, hessians []float64
+		want            error
+	}{
+		{"empty", nil, nil, nil, ErrEmptyDataset},
+		{"short gradients", X, ones[:2], ones, ErrLengthMismatch},
+		{"ragged", [][]float64{{1}, {2, 3}, {4}}, ones, ones, ErrFeatureCountMismatch},
+		{"NaN gradient", X, []float64{1, math.NaN(), 1}, ones, ErrInvalidGradients},
+		{"zero Hessian", X, ones, []float64{1, 0, 1}, ErrInvalidGradients},
+		{"infinite Hessian", X, ones, []float64{1, math.Inf(1), 1}, ErrInvalidGradients},
+		{"overflowing gradient sum", X, []float64{1, math.MaxFloat64 / 2, 1}, ones, ErrInvalidGradients},
+		{"overflowing Newton step", X, []float64{1, 1e300, 1}, []float64{1, 1e-300, 1}, ErrInvalidGradients},
+	}
+	for _, tt := range tests {
+		if _, err := New(DefaultConfig()).BoostOneRound(tt.X, tt.grads, tt.hessians); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	gbm := New(DefaultConfig())
+	if _, err := gbm.BoostOneRound(X, ones, ones); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gbm.BoostOneRound([][]float64{{1, 2}}, ones[:1], ones[:1]); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("feature count changed: err = %v, want ErrFeatureCountMismatch", err)
+	}
+}
+
+func TestBoostOneRoundMinHessian(t *testing.T) {
+	X := [][]float64{{1}, {2}, {3}, {4}}
+	grads := []float64{1, 1, -1, -1}
+	concave := []float64{1, -0.5, 0, 1}
+
+	_, err := New(DefaultConfig()).BoostOneRound(X, grads, concave)
+	if !errors.Is(err, ErrInvalidGradients) || !strings.Contains(err.Error(), "row 1") {
+		t.Fatalf("err = %v, want ErrInvalidGradients naming row 1", err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.MinHessian = 0.5
+	cfg.LearningRate = 1
+	cfg.MaxDepth = 1
+	gbm := New(cfg)
+	delta, err := gbm.BoostOneRound(X, grads, concave)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Floored Hessians are {1, 0.5, 0.5, 1}: the left leaf steps -2/1.5 and
+	// the right one 2/1.5.
+	for i, want := range []float64{-2 / 1.5, -2 / 1.5, 2 / 1.5, 2 / 1.5} {
+		if math.Abs(delta[i]-want) > 1e-12 {
+			t.Errorf("delta[%d] = %v, want %v", i, delta[i], want)
+		}
+	}
+	if concave[1] != -0.5 {
+		t.Error("BoostOneRound modified the caller's Hessians")
+	}
+}
+
+func TestFitMinHessian(t *testing.T) {
+	X, y := generateBinaryData(5)
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.MinHessian = 0.2
+	gbm := New(cfg)
+	gbm.numFeatures = len(X[0])
+	_, hessians := gbm.gradients(&LogLoss{}, y, make([]float64, len(y)))
+	for i, h := range hessians {
+		if h != 0.25 {
+			t.Fatalf("hessian[%d] = %v at p = 0.5, want 0.25", i, h)
+		}
+	}
+	predictions := make([]float64, len(y))
+	for i := range predictions {
+		predictions[i] = 40 // sigmoid(40) rounds to 1, so p(1-p) is 0
+	}
+	_, hessians = gbm.gradients(&LogLoss{}, y, predictions)
+	for i, h := range hessians {
+		if h != 0.2 {
+			t.Fatalf("hessian[%d] = %v on a saturated prediction, want the floor 0.2", i, h)
+		}
+	}
+}
+
+func TestFitStreamRepeatedBatchMatchesFit(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+
+	want := New(cfg)
+	if err := want.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	// More batches than rounds: FitStream stops after NEstimators.
+	batches := make(chan Batch, 15)
+	for range 15 {
+		batches <- Batch{X: X, Y: y}
+	}
+	close(batches)
+	got := New(cfg)
+	if err := got.FitStream(batches); err != nil {
+		t.Fatal(err)
+	}
+	if got.NumTrees() != 10 || len(batches) != 5 {
+		t.Fatalf("grew %d trees leaving %d batches, want 10 trees and 5 batches", got.NumTrees(), len(batches))
+	}
+	for i, x := range X {
+		if g, w := got.PredictSingle(x), want.PredictSingle(x); g != w {
+			t.Fatalf("row %d: FitStream predicts %v, Fit %v", i, g, w)
+		}
+	}
+}
+
+func TestFitStreamMiniBatches(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.NEstimators = 100
+	cfg.MaxDepth = 3
+
+	// 40 batches of fresh rows from y = x1 + 2·x2.
+	const size, count = 50, 40
+	batches := make(chan Batch)
+	go func() {
+		defer close(batches)
+		rnd := rand.New(rand.NewSource(0))
+		for range count {
+			b := Batch{X: make([][]float64, size), Y: make([]float64, size)}
+			for i := range size {
+				x1, x2 := rnd.Float64(), rnd.Float64()
+				b.X[i], b.Y[i] = []float64{x1, x2}, x1+2*x2
+			}
+			batches <- b
+		}
+	}()
+	gbm := New(cfg)
+	if err := gbm.FitStream(batches); err != nil {
+		t.Fatal(err)
+	}
+
+	history := gbm.History()
+	if len(history) != count || gbm.NumTrees() != count {
+		t.Fatalf("%d rounds and %d trees from %d batches", len(history), gbm.NumTrees(), count)
+	}
+	var early, late float64
+	for k := range 5 {
+		early += history[k].ValidationLoss
+		late += history[count-1-k].ValidationLoss
+	}
+	if late >= early/2 {
+		t.Errorf("loss on unseen batches fell only from %v to %v", early/5, late/5)
+	}
+	for _, h := range history {
+		if h.SampleSize != size || h.TrainLoss > h.ValidationLoss {
+			t.Errorf("round %d: %d samples, loss %v before and %v after its tree", h.Round, h.SampleSize, h.ValidationLoss, h.TrainLoss)
+		}
+	}
+}
+
+func TestFitStreamErrors(t *testing.T) {
+	stream := func(batches ...Batch) <-chan Batch {
+		ch := make(chan Batch, len(batches))
+		for _, b := range batches {
+			ch <- b
+		}
+		close(ch)
+		return ch
+	}
+	good := Batch{X: [][]float64{{1}, {2}, {3}}, Y: []float64{1, 2, 3}}
+
+	tests := []struct {
+		name    string
+		batches <-chan Batch
+		want    error
+	}{
+		{"no batches", stream(), ErrEmptyDataset},
+		{"empty batch", stream(good, Batch{}), ErrEmptyDataset},
+		{"length mismatch", stream(Batch{X: good.X, Y: good.Y[:2]}), ErrLengthMismatch},
+		{"feature count changed", stream(good, Batch{X: [][]float64{{1, 2}}, Y: []float64{1}}), ErrFeatureCountMismatch},
+	}
+	for _, tt := range tests {
+		gbm := New(DefaultConfig())
+		if err := gbm.FitStream(tt.batches); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+		if _, err := gbm.ShapValuesSingle([]float64{1}); !errors.Is(err, ErrModelNotFitted) {
+			t.Errorf("%s: model usable after a failed FitStream", tt.name)
+		}
+	}
+
+	// Without a tree limit, FitStream would drain the stream forever.
+	cfg := DefaultConfig()
+	cfg.NEstimators = 0
+	batches := stream(good)
+	if err := New(cfg).FitStream(batches); !errors.Is(err, ErrInvalidNEstimators) {
+		t.Errorf("NEstimators 0: err = %v, want ErrInvalidNEstimators", err)
+	}
+	if len(batches) != 1 {
+		t.Error("NEstimators 0: FitStream received from the stream")
+	}
+}
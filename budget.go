@@ -0,0 +1,107 @@
+package gboost
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// BudgetPredictor scores samples under a latency budget by evaluating the
+// trees of a trained [GBM] in descending order of weight, their expected
+// absolute contribution to a prediction, and stopping at a deadline. Under
+// load a service then degrades to a coarser score instead of missing its
+// deadline. It is safe for concurrent use as long as the underlying model is
+// not refitted.
+type BudgetPredictor struct {
+	model *GBM
+	order []int // tree indices, heaviest first
+}
+
+// BudgetResult holds the predictions of [BudgetPredictor.Predict].
+type BudgetResult struct {
+	// Predictions holds a raw prediction for each sample: the initial
+	// prediction plus the contributions of the trees evaluated.
+	Predictions []float64
+
+	// Trees is the number of trees evaluated, the same for every sample.
+	Trees int
+
+	// Complete reports whether every tree was evaluated.
+	Complete bool
+}
+
+// NewBudgetPredictor returns a [BudgetPredictor] for g. A tree's weight is
+// the mean absolute value of its leaves, weighted by their training sample
+// counts when the model has them; ties keep the training order.
+//
+// Returns [ErrModelNotFitted] if g has not been trained.
+func (g *GBM) NewBudgetPredictor() (*BudgetPredictor, error) {
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+	weights := make([]float64, len(g.trees))
+	order := make([]int, len(g.trees))
+	for i, tree := range g.trees {
+		sum, n := tree.leafWeight(tree.NSamples > 0)
+		weights[i] = sum / n
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(weights[b], weights[a])
+	})
+	return &BudgetPredictor{model: g, order: order}, nil
+}
+
+// leafWeight returns the sum of the absolute leaf values below n and their
+// count, both weighted by NSamples if bySamples is set.
+func (n *Node) leafWeight(bySamples bool) (sum, count float64) {
+	if n.isLeaf() {
+		w := 1.0
+		if bySamples {
+			w = float64(n.NSamples)
+		}
+		return float64(w * math.Abs(n.Value)), w
+	}
+	ls, lc := n.Left.leafWeight(bySamples)
+	rs, rc := n.Right.leafWeight(bySamples)
+	return ls + rs, lc + rc
+}
+
+// Predict returns raw predictions for X from as many trees as can be
+// evaluated before deadline, heaviest first. The trees are evaluated one at
+// a time over all of X, and the deadline is checked after each, so the
+// heaviest tree is always evaluated and a large X can overrun the deadline
+// by the time one tree takes on it. A zero deadline evaluates every tree.
+//
+// Complete predictions equal those of [GBM.Predict] up to floating-point
+// rounding, since the trees are summed in a different order.
+func (p *BudgetPredictor) Predict(X [][]float64, deadline time.Time) BudgetResult {
+	g := p.model
+	res := BudgetResult{Predictions: make([]float64, len(X))}
+	for i := range res.Predictions {
+		res.Predictions[i] = g.initialPrediction
+	}
+	for _, t := range p.order {
+		tree := g.trees[t]
+		for i, x := range X {
+			res.Predictions[i] += float64(g.Config.LearningRate * tree.predict(x))
+		}
+		res.Trees++
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			break
+		}
+	}
+	res.Complete = res.Trees == len(g.trees)
+	return res
+}
+
+// PredictProba is like Predict but returns P(y=1) for each sample. Only
+// meaningful for models trained with logloss.
+func (p *BudgetPredictor) PredictProba(X [][]float64, deadline time.Time) BudgetResult {
+	res := p.Predict(X, deadline)
+	for i, v := range res.Predictions {
+		res.Predictions[i] = sigmoid(v)
+	}
+	return res
+}
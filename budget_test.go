@@ -0,0 +1,59 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBudgetPredictor(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 30
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	p, err := gbm.NewBudgetPredictor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i < len(p.order); i++ {
+		a, an := gbm.trees[p.order[i-1]].leafWeight(true)
+		b, bn := gbm.trees[p.order[i]].leafWeight(true)
+		if a/an < b/bn {
+			t.Fatalf("tree %d (weight %v) ordered before heavier tree %d (weight %v)", p.order[i-1], a/an, p.order[i], b/bn)
+		}
+	}
+
+	full := p.Predict(X, time.Time{})
+	if !full.Complete || full.Trees != gbm.NumTrees() {
+		t.Fatalf("without a deadline: %d trees, complete %v", full.Trees, full.Complete)
+	}
+	for i, want := range gbm.Predict(X) {
+		if math.Abs(full.Predictions[i]-want) > 1e-9 {
+			t.Fatalf("row %d: %v, want %v", i, full.Predictions[i], want)
+		}
+	}
+
+	// A deadline already passed still evaluates the heaviest tree.
+	partial := p.Predict(X[:5], time.Now().Add(-time.Second))
+	if partial.Complete || partial.Trees != 1 {
+		t.Fatalf("past deadline: %d trees, complete %v", partial.Trees, partial.Complete)
+	}
+	heaviest := gbm.trees[p.order[0]]
+	for i, x := range X[:5] {
+		want := gbm.initialPrediction + float64(cfg.LearningRate*heaviest.predict(x))
+		if partial.Predictions[i] != want {
+			t.Errorf("row %d: %v, want %v", i, partial.Predictions[i], want)
+		}
+	}
+}
+
+func TestBudgetPredictorNotFitted(t *testing.T) {
+	if _, err := New(DefaultConfig()).NewBudgetPredictor(); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("err = %v, want ErrModelNotFitted", err)
+	}
+}
@@ -0,0 +1,387 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// WOEEncoder is a supervised [Transformer] that replaces each category of a
+// categorical feature, such as a label-encoded column from [LoadCSV], with
+// its smoothed weight of evidence against a binary target:
+//
+//	WOE = ln(share of non-events in the category / share of events in the category)
+//
+// where Smoothing is added to both counts of every category.
+//
+// Encoding the training rows with statistics that include their own target
+// leaks the target into the feature. [WOEEncoder.FitTransform], which
+// [Pipeline.Fit] uses, therefore encodes each training row with statistics
+// from the other Folds-1 folds only; [WOEEncoder.Transform] uses statistics
+// from all of the training data. Categories not seen during Fit, and NaN,
+// encode to 0.
+type WOEEncoder struct {
+	Columns   []int   `json:"columns"`   // Categorical columns to encode.
+	Smoothing float64 `json:"smoothing"` // Pseudo-count added to each category's event and non-event counts.
+	Folds     int     `json:"folds"`     // Folds for out-of-fold training encodings; must be >= 2.
+	Seed      int64   `json:"seed"`      // Seed for the fold assignment.
+
+	// Encodings holds the fitted encoding of each column; nil until fitted.
+	Encodings []WOEEncoding `json:"encodings,omitempty"`
+
+	NumFeatures int `json:"num_features,omitempty"`
+}
+
+// WOEEncoding is the fitted encoding of one categorical column. Categories
+// is sorted, and WOE[i], Counts[i], and Events[i] describe Categories[i].
+type WOEEncoding struct {
+	Column     int       `json:"column"`
+	Categories []float64 `json:"categories"`
+	WOE        []float64 `json:"woe"`
+	Counts     []int     `json:"counts"`
+	Events     []int     `json:"events"`
+	IV         float64   `json:"iv"` // Information value of the feature.
+}
+
+// NewWOEEncoder creates an unfitted [WOEEncoder] for columns with a
+// smoothing of 0.5 and 5 folds.
+func NewWOEEncoder(columns ...int) *WOEEncoder {
+	return &WOEEncoder{Columns: columns, Smoothing: 0.5, Folds: 5}
+}
+
+// CategoricalColumns returns the sorted indices of the feature columns that
+// [LoadCSV] label-encoded.
+func (ds *Dataset) CategoricalColumns() []int {
+	var cols []int
+	for j := range ds.Encodings {
+		cols = append(cols, j)
+	}
+	slices.Sort(cols)
+	return cols
+}
+
+// Lookup returns the WOE of category v, or 0 if v was not seen during Fit.
+func (e *WOEEncoding) Lookup(v float64) float64 {
+	if i, found := slices.BinarySearch(e.Categories, v); found {
+		return e.WOE[i]
+	}
+	return 0
+}
+
+// Fit learns the WOE of every category of the selected columns from all of X.
+//
+// Returns [ErrEmptyDataset] if X is empty, [ErrLengthMismatch] if X and y
+// differ in length, [ErrFeatureCountMismatch] if rows differ in length or a
+// column index is out of range, [ErrNonBinaryTarget] unless y holds both 0s
+// and 1s and nothing else, or an error unless Smoothing is positive: without
+// it a category with no events or no non-events would encode to ±Inf.
+func (w *WOEEncoder) Fit(X [][]float64, y []float64) error {
+	switch {
+	case len(X) == 0:
+		return ErrEmptyDataset
+	case len(X) != len(y):
+		return ErrLengthMismatch
+	case !hasSimilarLength(X):
+		return ErrFeatureCountMismatch
+	case !(w.Smoothing > 0):
+		return fmt.Errorf("Smoothing must be > 0, got %v", w.Smoothing)
+	}
+	for _, j := range w.Columns {
+		if j < 0 || j >= len(X[0]) {
+			return fmt.Errorf("column %d: %w", j, ErrFeatureCountMismatch)
+		}
+	}
+
+	encodings, err := w.encode(X, y, allFeatures(len(X)))
+	if err != nil {
+		return err
+	}
+	w.Encodings = encodings
+	w.NumFeatures = len(X[0])
+	return nil
+}
+
+// encode computes the encodings of the selected columns from the rows in
+// indices.
+func (w *WOEEncoder) encode(X [][]float64, y []float64, indices []int) ([]WOEEncoding, error) {
+	rows := extractRows(y, indices)
+	totalEvents, err := binaryTargetEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	totalNonEvents := len(rows) - totalEvents
+
+	encodings := make([]WOEEncoding, len(w.Columns))
+	for c, j := range w.Columns {
+		enc := WOEEncoding{Column: j}
+		for _, i := range indices {
+			v := X[i][j]
+			if math.IsNaN(v) {
+				continue
+			}
+			k, found := slices.BinarySearch(enc.Categories, v)
+			if !found {
+				enc.Categories = slices.Insert(enc.Categories, k, v)
+				enc.Counts = slices.Insert(enc.Counts, k, 0)
+				enc.Events = slices.Insert(enc.Events, k, 0)
+			}
+			enc.Counts[k]++
+			enc.Events[k] += int(y[i])
+		}
+		enc.WOE = make([]float64, len(enc.Categories))
+		for k := range enc.Categories {
+			nonEvents := enc.Counts[k] - enc.Events[k]
+			enc.WOE[k] = weightOfEvidence(enc.Events[k], nonEvents, totalEvents, totalNonEvents, w.Smoothing)
+			share := float64(nonEvents)/float64(totalNonEvents) - float64(enc.Events[k])/float64(totalEvents)
+			enc.IV += share * enc.WOE[k]
+		}
+		encodings[c] = enc
+	}
+	return encodings, nil
+}
+
+// FitTransform fits the encoder on all of X, like [WOEEncoder.Fit], and
+// returns X with each training row encoded out of fold: by statistics from
+// the folds that do not contain it.
+//
+// In addition to the errors of Fit, returns [ErrInvalidFolds] if Folds is
+// below 2 or above the number of rows, or [ErrNonBinaryTarget] if the rows
+// outside some fold hold a single class.
+func (w *WOEEncoder) FitTransform(X [][]float64, y []float64) ([][]float64, error) {
+	if err := w.Fit(X, y); err != nil {
+		return nil, err
+	}
+	folds, err := KFold(len(X), w.Folds, w.Seed)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		res[i] = slices.Clone(row)
+	}
+	for f, fold := range folds {
+		encodings, err := w.encode(X, y, fold.Train)
+		if err != nil {
+			return nil, fmt.Errorf("fold %d: %w", f, err)
+		}
+		for _, i := range fold.Test {
+			for _, enc := range encodings {
+				res[i][enc.Column] = enc.Lookup(X[i][enc.Column])
+			}
+		}
+	}
+	return res, nil
+}
+
+// Transform returns a copy of X with each encoded column replaced by the WOE
+// of its category. Other columns are copied unchanged.
+//
+// Returns [ErrModelNotFitted] if the encoder has not been fitted, or
+// [ErrFeatureCountMismatch] if a row's length differs from the data it was
+// fitted on.
+func (w *WOEEncoder) Transform(X [][]float64) ([][]float64, error) {
+	if w.Encodings == nil {
+		return nil, ErrModelNotFitted
+	}
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		if len(row) != w.NumFeatures {
+			return nil, ErrFeatureCountMismatch
+		}
+		res[i] = slices.Clone(row)
+		for _, enc := range w.Encodings {
+			res[i][enc.Column] = enc.Lookup(row[enc.Column])
+		}
+	}
+	return res, nil
+}
+
+func (w *WOEEncoder) transformerKind() string { return "woe_encoder" }
+
+// OrderedTargetEncoder is a supervised [Transformer] that replaces each
+// category of a categorical feature with a smoothed mean of the target, the
+// ordered target statistics of CatBoost:
+//
+//	TS = (sum of y in the category + PriorWeight*Prior) / (rows in the category + PriorWeight)
+//
+// where Prior is the mean of y over all training rows. It suits regression
+// as well as binary targets.
+//
+// [OrderedTargetEncoder.FitTransform], which [Pipeline.Fit] uses, visits the
+// training rows in Permutations random orders and encodes each row with the
+// statistics of the rows before it only, then averages the encodings over
+// the orders. No row sees its own target, as with the out-of-fold encoding
+// of [WOEEncoder], but without setting aside folds: rows early in an order
+// get noisy, prior-heavy encodings, which averaging over several orders
+// smooths. [OrderedTargetEncoder.Transform] uses statistics from all of the
+// training data. Categories not seen during Fit, and NaN, encode to Prior.
+type OrderedTargetEncoder struct {
+	Columns      []int   `json:"columns"`      // Categorical columns to encode.
+	PriorWeight  float64 `json:"prior_weight"` // Pseudo-count of the prior in every statistic; must be > 0.
+	Permutations int     `json:"permutations"` // Random orders averaged over by FitTransform; must be >= 1.
+	Seed         int64   `json:"seed"`         // Seed for the orders.
+
+	// Prior is the mean training target; set by Fit.
+	Prior float64 `json:"prior"`
+
+	// Encodings holds the fitted encoding of each column; nil until fitted.
+	Encodings []TargetEncoding `json:"encodings,omitempty"`
+
+	NumFeatures int `json:"num_features,omitempty"`
+}
+
+// TargetEncoding is the fitted encoding of one categorical column. Categories
+// is sorted, and Means[i], Counts[i], and Sums[i] describe Categories[i].
+type TargetEncoding struct {
+	Column     int       `json:"column"`
+	Categories []float64 `json:"categories"`
+	Means      []float64 `json:"means"` // Smoothed target means.
+	Counts     []int     `json:"counts"`
+	Sums       []float64 `json:"sums"` // Sums of the target.
+}
+
+// NewOrderedTargetEncoder creates an unfitted [OrderedTargetEncoder] for
+// columns with a prior weight of 1 and 4 permutations.
+func NewOrderedTargetEncoder(columns ...int) *OrderedTargetEncoder {
+	return &OrderedTargetEncoder{Columns: columns, PriorWeight: 1, Permutations: 4}
+}
+
+// lookup returns the smoothed target mean of category v, or the prior if v
+// was not seen during Fit.
+func (e *TargetEncoding) lookup(v, prior float64) float64 {
+	if i, found := slices.BinarySearch(e.Categories, v); found {
+		return e.Means[i]
+	}
+	return prior
+}
+
+// Fit learns the prior and the smoothed target mean of every category of the
+// selected columns from all of X.
+//
+// Returns [ErrEmptyDataset] if X is empty, [ErrLengthMismatch] if X and y
+// differ in length, [ErrFeatureCountMismatch] if rows differ in length or a
+// column index is out of range, or an error for a PriorWeight that is not
+// positive or fewer than 1 Permutations.
+func (o *OrderedTargetEncoder) Fit(X [][]float64, y []float64) error {
+	switch {
+	case len(X) == 0:
+		return ErrEmptyDataset
+	case len(X) != len(y):
+		return ErrLengthMismatch
+	case !hasSimilarLength(X):
+		return ErrFeatureCountMismatch
+	case !(o.PriorWeight > 0):
+		return fmt.Errorf("PriorWeight must be > 0, got %v", o.PriorWeight)
+	case o.Permutations < 1:
+		return fmt.Errorf("Permutations must be >= 1, got %d", o.Permutations)
+	}
+	for _, j := range o.Columns {
+		if j < 0 || j >= len(X[0]) {
+			return fmt.Errorf("column %d: %w", j, ErrFeatureCountMismatch)
+		}
+	}
+
+	o.Prior = mean(y)
+	o.Encodings = make([]TargetEncoding, len(o.Columns))
+	for c, j := range o.Columns {
+		enc := TargetEncoding{Column: j}
+		for i, row := range X {
+			v := row[j]
+			if math.IsNaN(v) {
+				continue
+			}
+			k, found := slices.BinarySearch(enc.Categories, v)
+			if !found {
+				enc.Categories = slices.Insert(enc.Categories, k, v)
+				enc.Counts = slices.Insert(enc.Counts, k, 0)
+				enc.Sums = slices.Insert(enc.Sums, k, 0)
+			}
+			enc.Counts[k]++
+			enc.Sums[k] += y[i]
+		}
+		enc.Means = make([]float64, len(enc.Categories))
+		for k := range enc.Categories {
+			enc.Means[k] = o.statistic(enc.Sums[k], enc.Counts[k])
+		}
+		o.Encodings[c] = enc
+	}
+	o.NumFeatures = len(X[0])
+	return nil
+}
+
+// statistic returns the smoothed mean of count targets summing to sum.
+func (o *OrderedTargetEncoder) statistic(sum float64, count int) float64 {
+	return (sum + float64(o.PriorWeight*o.Prior)) / (float64(count) + o.PriorWeight)
+}
+
+// FitTransform fits the encoder on all of X, like [OrderedTargetEncoder.Fit],
+// and returns X with each training row encoded by ordered target
+// statistics: the mean, over Permutations random orders of the rows, of the
+// statistic computed from the rows preceding it. The result depends only on
+// X, y, and Seed.
+//
+// Returns the errors of Fit.
+func (o *OrderedTargetEncoder) FitTransform(X [][]float64, y []float64) ([][]float64, error) {
+	if err := o.Fit(X, y); err != nil {
+		return nil, err
+	}
+
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		res[i] = slices.Clone(row)
+		for _, j := range o.Columns {
+			res[i][j] = 0
+		}
+	}
+	sums := make(map[float64]float64)
+	counts := make(map[float64]int)
+	for p := range o.Permutations {
+		perm := newRNG(o.Seed, p).Perm(len(X))
+		for _, j := range o.Columns {
+			clear(sums)
+			clear(counts)
+			for _, i := range perm {
+				v := X[i][j]
+				if math.IsNaN(v) {
+					res[i][j] += o.Prior
+					continue
+				}
+				res[i][j] += o.statistic(sums[v], counts[v])
+				sums[v] += y[i]
+				counts[v]++
+			}
+		}
+	}
+	for i := range res {
+		for _, j := range o.Columns {
+			res[i][j] /= float64(o.Permutations)
+		}
+	}
+	return res, nil
+}
+
+// Transform returns a copy of X with each encoded column replaced by the
+// smoothed target mean of its category. Other columns are copied unchanged.
+//
+// Returns [ErrModelNotFitted] if the encoder has not been fitted, or
+// [ErrFeatureCountMismatch] if a row's length differs from the data it was
+// fitted on.
+func (o *OrderedTargetEncoder) Transform(X [][]float64) ([][]float64, error) {
+	if o.Encodings == nil {
+		return nil, ErrModelNotFitted
+	}
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		if len(row) != o.NumFeatures {
+			return nil, ErrFeatureCountMismatch
+		}
+		res[i] = slices.Clone(row)
+		for _, enc := range o.Encodings {
+			res[i][enc.Column] = enc.lookup(row[enc.Column], o.Prior)
+		}
+	}
+	return res, nil
+}
+
+func (o *OrderedTargetEncoder) transformerKind() string { return "ordered_target_encoder" }
@@ -0,0 +1,318 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// generateCategoricalData returns rows of (category, noise) where category
+// c in 0..4 has an event rate of 0.1 + 0.15*c.
+func generateCategoricalData() ([][]float64, []float64) {
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 1000)
+	y := make([]float64, 1000)
+	for i := range X {
+		c := float64(rnd.Intn(5))
+		X[i] = []float64{c, rnd.Float64()}
+		if rnd.Float64() < 0.1+0.15*c {
+			y[i] = 1
+		}
+	}
+	return X, y
+}
+
+func TestWOEEncoderFit(t *testing.T) {
+	X, y := generateCategoricalData()
+	w := NewWOEEncoder(0)
+	if err := w.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	enc := w.Encodings[0]
+	if !slices.Equal(enc.Categories, []float64{0, 1, 2, 3, 4}) {
+		t.Fatalf("Categories = %v, want 0..4", enc.Categories)
+	}
+	// Higher event rates mean lower WOE.
+	for k := 1; k < len(enc.WOE); k++ {
+		if enc.WOE[k] >= enc.WOE[k-1] {
+			t.Errorf("WOE %v is not decreasing with the event rate", enc.WOE)
+			break
+		}
+	}
+	if enc.IV < 0.3 {
+		t.Errorf("IV = %v, want a strong predictor", enc.IV)
+	}
+
+	got, err := w.Transform([][]float64{{2, 0.5}, {7, 0.5}, {math.NaN(), 0.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0][0] != enc.WOE[2] || got[1][0] != 0 || got[2][0] != 0 {
+		t.Errorf("Transform = %v, want [%v 0 0] in column 0", got, enc.WOE[2])
+	}
+	if got[0][1] != 0.5 {
+		t.Errorf("unencoded column changed to %v", got[0][1])
+	}
+}
+
+func TestWOEEncoderFitTransformOutOfFold(t *testing.T) {
+	X, y := generateCategoricalData()
+	w := NewWOEEncoder(0)
+	w.Folds = 4
+	oof, err := w.FitTransform(X, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, _ := w.Transform(X)
+
+	// Every training row is encoded without its own target, so it differs
+	// from the full-data encoding but stays close to it.
+	for i := range X {
+		if oof[i][0] == full[i][0] {
+			t.Fatalf("row %d: out-of-fold encoding equals the full-data encoding %v", i, full[i][0])
+		}
+		if math.Abs(oof[i][0]-full[i][0]) > 0.3 {
+			t.Fatalf("row %d: out-of-fold %v far from full-data %v", i, oof[i][0], full[i][0])
+		}
+	}
+
+	// A high-cardinality ID column is pure noise, but in-sample WOE makes it
+	// look predictive (WOE falls as the event rate rises, hence the negative
+	// correlation); out of fold it does not.
+	ids := make([][]float64, len(X))
+	for i := range X {
+		ids[i] = []float64{float64(i / 2)}
+	}
+	idEncoder := NewWOEEncoder(0)
+	oof, err = idEncoder.FitTransform(ids, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inSample, _ := idEncoder.Transform(ids)
+	if c, o := correlation(inSample, y), correlation(oof, y); c > -0.5 || math.Abs(o) > 0.2 {
+		t.Errorf("ID column correlation: in-sample %v, out-of-fold %v; want leakage only in-sample", c, o)
+	}
+}
+
+// correlation returns the Pearson correlation of column 0 of X with y.
+func correlation(X [][]float64, y []float64) float64 {
+	x := make([]float64, len(X))
+	for i := range X {
+		x[i] = X[i][0]
+	}
+	mx, my := mean(x), mean(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		sxy += (x[i] - mx) * (y[i] - my)
+		sxx += (x[i] - mx) * (x[i] - mx)
+		syy += (y[i] - my) * (y[i] - my)
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+func TestWOEEncoderInPipeline(t *testing.T) {
+	X, y := generateCategoricalData()
+	config := DefaultConfig()
+	config.Loss = "logloss"
+	config.NEstimators = 20
+	p := NewPipeline(New(config), NewWOEEncoder(0))
+	if err := p.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "pipeline.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPipeline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := p.PredictProbaAll(X[:50])
+	got, err := loaded.PredictProbaAll(X[:50])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("loaded PredictProbaAll[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWOEEncoderErrors(t *testing.T) {
+	X := [][]float64{{0}, {1}, {0}, {1}}
+	y := []float64{0, 1, 1, 0}
+	if err := NewWOEEncoder(0).Fit(X, []float64{0, 2, 1, 0}); !errors.Is(err, ErrNonBinaryTarget) {
+		t.Errorf("expected ErrNonBinaryTarget, got %v", err)
+	}
+	if err := NewWOEEncoder(1).Fit(X, y); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+	if _, err := NewWOEEncoder(0).FitTransform(X, y); !errors.Is(err, ErrInvalidFolds) {
+		t.Errorf("expected ErrInvalidFolds for 5 folds over 4 rows, got %v", err)
+	}
+	if _, err := NewWOEEncoder(0).Transform(X); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+	// Category 0 has no non-events here, so unsmoothed counts give an
+	// infinite WOE.
+	for _, smoothing := range []float64{0, -1, math.NaN()} {
+		w := NewWOEEncoder(0)
+		w.Smoothing = smoothing
+		if err := w.Fit(X, []float64{1, 0, 1, 1}); err == nil {
+			t.Errorf("Smoothing %v accepted", smoothing)
+		}
+	}
+}
+
+func TestOrderedTargetEncoderFit(t *testing.T) {
+	X := [][]float64{{0, 9}, {1, 9}, {0, 9}, {1, 9}, {0, 9}, {math.NaN(), 9}}
+	y := []float64{1, 0, 1, 4, 7, 2}
+	o := NewOrderedTargetEncoder(0)
+	if err := o.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if o.Prior != 2.5 {
+		t.Fatalf("Prior = %v, want 2.5", o.Prior)
+	}
+	enc := o.Encodings[0]
+	// Category 0: (1+1+7 + 2.5) / (3 + 1); category 1: (0+4 + 2.5) / (2 + 1).
+	want := []float64{11.5 / 4, 6.5 / 3}
+	if !slices.Equal(enc.Categories, []float64{0, 1}) || !slices.Equal(enc.Means, want) || !slices.Equal(enc.Counts, []int{3, 2}) {
+		t.Fatalf("encoding = %+v, want means %v", enc, want)
+	}
+
+	o.PriorWeight = 2
+	if err := o.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	got, err := o.Transform([][]float64{{0, 1}, {7, 1}, {math.NaN(), 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 14.0 / 5; got[0][0] != want || got[1][0] != 2.5 || got[2][0] != 2.5 || got[0][1] != 1 {
+		t.Errorf("Transform = %v, want [%v 2.5 2.5] in column 0 and column 1 unchanged", got, want)
+	}
+}
+
+func TestOrderedTargetEncoderFitTransform(t *testing.T) {
+	X := [][]float64{{0}, {0}, {0}, {math.NaN()}}
+	y := []float64{3, 1, 2, 6}
+	o := NewOrderedTargetEncoder(0)
+	o.Permutations = 1
+	got, err := o.FitTransform(X, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With one order, the rows of the category, in that order, see the
+	// prior alone, then the prior and one target, then two.
+	perm := rand.New(rand.NewSource(deriveSeed(o.Seed, 0))).Perm(len(X))
+	var sum float64
+	var n int
+	for _, i := range perm {
+		if i == 3 {
+			if got[i][0] != o.Prior {
+				t.Errorf("NaN row encoded as %v, want the prior %v", got[i][0], o.Prior)
+			}
+			continue
+		}
+		if want := (sum + o.Prior) / float64(n+1); got[i][0] != want {
+			t.Errorf("row %d: %v, want %v", i, got[i][0], want)
+		}
+		sum += y[i]
+		n++
+	}
+
+	// A high-cardinality ID column is pure noise, but in-sample target
+	// means make it look predictive; ordered statistics do not.
+	_, y = generateCategoricalData()
+	ids := make([][]float64, len(y))
+	for i := range ids {
+		ids[i] = []float64{float64(i / 2)}
+	}
+	idEncoder := NewOrderedTargetEncoder(0)
+	ordered, err := idEncoder.FitTransform(ids, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inSample, _ := idEncoder.Transform(ids)
+	if c, o := correlation(inSample, y), correlation(ordered, y); c < 0.5 || math.Abs(o) > 0.2 {
+		t.Errorf("ID column correlation: in-sample %v, ordered %v; want leakage only in-sample", c, o)
+	}
+	again, _ := NewOrderedTargetEncoder(0).FitTransform(ids, y)
+	for i := range again {
+		if again[i][0] != ordered[i][0] {
+			t.Fatalf("row %d: FitTransform is not deterministic for a fixed seed", i)
+		}
+	}
+}
+
+func TestOrderedTargetEncoderInPipeline(t *testing.T) {
+	X, y := generateCategoricalData()
+	config := DefaultConfig()
+	config.Loss = "logloss"
+	config.NEstimators = 20
+	p := NewPipeline(New(config), NewOrderedTargetEncoder(0))
+	if err := p.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "pipeline.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPipeline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := p.PredictProbaAll(X[:50])
+	got, err := loaded.PredictProbaAll(X[:50])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("loaded PredictProbaAll[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestOrderedTargetEncoderErrors(t *testing.T) {
+	X := [][]float64{{0}, {1}}
+	y := []float64{0, 1}
+	if err := NewOrderedTargetEncoder(1).Fit(X, y); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+	if err := NewOrderedTargetEncoder(0).Fit(X, y[:1]); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+	o := NewOrderedTargetEncoder(0)
+	o.PriorWeight = 0
+	if err := o.Fit(X, y); err == nil {
+		t.Error("expected an error for a zero PriorWeight")
+	}
+	o = NewOrderedTargetEncoder(0)
+	o.Permutations = 0
+	if _, err := o.FitTransform(X, y); err == nil {
+		t.Error("expected an error for zero Permutations")
+	}
+	if _, err := NewOrderedTargetEncoder(0).Transform(X); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+}
+
+func TestDatasetCategoricalColumns(t *testing.T) {
+	path := writeTestCSV(t, "cat.csv", "red,1,small,0\nblue,2,large,1\nred,3,small,1\n")
+	ds, err := LoadCSV(path, -1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ds.CategoricalColumns(); !slices.Equal(got, []int{0, 2}) {
+		t.Errorf("CategoricalColumns() = %v, want [0 2]", got)
+	}
+}
@@ -0,0 +1,203 @@
+package gboost
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CExportOptions configures [GBM.WriteC].
+type CExportOptions struct {
+	// Prefix names the generated arrays, functions, and macros, so that
+	// several models can be linked into one program. It must be a valid C
+	// identifier. Empty means "gboost_model".
+	Prefix string
+
+	// Float stores thresholds and leaf values as float instead of double and
+	// scores float inputs, halving the tables for microcontrollers without a
+	// double-precision FPU. Predictions then match the model only up to
+	// float32 rounding, and inputs within that rounding of a threshold can
+	// take the other branch.
+	Float bool
+}
+
+// cIdentifier matches the prefixes WriteC accepts.
+var cIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WriteC writes the model to w as a self-contained C99 header for firmware
+// and other targets where Go does not run. The trees are flattened into
+// const arrays, placed in flash by most embedded toolchains, and scored by a
+// loop without recursion or allocation:
+//
+//	#include "model.h"
+//	double raw = gboost_model_predict(x); // x holds GBOOST_MODEL_NUM_FEATURES values
+//
+// Logloss models also get gboost_model_predict_proba, and gamma models
+// gboost_model_predict_mean for the expected value; both need exp from
+// libm. Missing values are NaN, sent right as in Go. With the default
+// double precision, and a compiler that does not reassociate floating-point
+// additions (no -ffast-math), predictions are bit for bit those of
+// [GBM.PredictSingle]. Include the header in one translation unit; its
+// definitions are static.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or an error
+// for a prefix that is not a C identifier or a non-finite threshold or leaf
+// value, which C has no literal for.
+func (g *GBM) WriteC(w io.Writer, opts CExportOptions) error {
+	if !g.isFitted {
+		return ErrModelNotFitted
+	}
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = "gboost_model"
+	}
+	if !cIdentifier.MatchString(prefix) {
+		return fmt.Errorf("C export prefix %q is not a C identifier", prefix)
+	}
+	macro := strings.ToUpper(prefix)
+	ctype, one := "double", "1.0"
+	if opts.Float {
+		ctype, one = "float", "1.0f"
+	}
+
+	// Trees are flattened in pre-order, so children always follow their
+	// parent and a left index of 0 marks a leaf. Leaves store their value,
+	// already multiplied by the learning rate as in PredictSingle, in place
+	// of a threshold.
+	var feature, left, right []int
+	var split []float64
+	var roots []int
+	var flatten func(n *Node) int
+	flatten = func(n *Node) int {
+		i := len(split)
+		feature, left, right = append(feature, 0), append(left, 0), append(right, 0)
+		if n.isLeaf() {
+			split = append(split, float64(g.Config.LearningRate*n.Value))
+			return i
+		}
+		split = append(split, n.Threshold)
+		feature[i] = n.FeatureIndex
+		l := flatten(n.Left)
+		r := flatten(n.Right)
+		left[i], right[i] = l, r
+		return i
+	}
+	for _, tree := range g.trees {
+		roots = append(roots, flatten(tree))
+	}
+	for _, v := range slices.Concat(split, []float64{g.initialPrediction}) {
+		if opts.Float {
+			v = float64(float32(v))
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("C export: non-finite threshold or value %v", v)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "/* Generated by gboost: a %d-tree %s model over %d features. Do not edit. */\n", len(g.trees), g.Config.Loss, g.numFeatures)
+	fmt.Fprintf(bw, "#ifndef %s_H\n#define %s_H\n\n#include <stdint.h>\n", macro, macro)
+	if g.Config.Loss == "logloss" || g.Config.Loss == "gamma" {
+		fmt.Fprintln(bw, "#include <math.h>")
+	}
+	fmt.Fprintf(bw, "\n#define %s_NUM_FEATURES %d\n#define %s_NUM_TREES %d\n#define %s_NUM_NODES %d\n\n",
+		macro, g.numFeatures, macro, len(g.trees), macro, len(split))
+
+	writeCInts(bw, prefix+"_feature", feature)
+	writeCInts(bw, prefix+"_left", left)
+	writeCInts(bw, prefix+"_right", right)
+	writeCInts(bw, prefix+"_roots", roots)
+	fmt.Fprintf(bw, "static const %s %s_split[%d] = {", ctype, prefix, max(len(split), 1))
+	for i, v := range split {
+		if i%4 == 0 {
+			fmt.Fprint(bw, "\n   ")
+		}
+		fmt.Fprintf(bw, " %s,", formatCReal(v, opts.Float))
+	}
+	if len(split) == 0 {
+		// ISO C has no empty initializers; a model without trees never
+		// reads the placeholder.
+		fmt.Fprintf(bw, "\n    %s,", formatCReal(0, opts.Float))
+	}
+	fmt.Fprint(bw, "\n};\n\n")
+
+	fmt.Fprintf(bw, "/* Returns the raw prediction (a regression value, log-odds, or log mean) for x. */\n")
+	fmt.Fprintf(bw, "static %s %s_predict(const %s *x) {\n", ctype, prefix, ctype)
+	fmt.Fprintf(bw, "    %s pred = %s;\n", ctype, formatCReal(g.initialPrediction, opts.Float))
+	fmt.Fprintf(bw, "    for (uint32_t t = 0; t < %s_NUM_TREES; t++) {\n", macro)
+	fmt.Fprintf(bw, "        uint32_t i = %s_roots[t];\n", prefix)
+	fmt.Fprintf(bw, "        while (%s_left[i] != 0) {\n", prefix)
+	fmt.Fprintf(bw, "            i = x[%s_feature[i]] < %s_split[i] ? %s_left[i] : %s_right[i];\n", prefix, prefix, prefix, prefix)
+	fmt.Fprintln(bw, "        }")
+	fmt.Fprintf(bw, "        pred += %s_split[i];\n", prefix)
+	fmt.Fprintln(bw, "    }")
+	fmt.Fprintln(bw, "    return pred;")
+	fmt.Fprintln(bw, "}")
+	exp := "exp"
+	if opts.Float {
+		exp = "expf"
+	}
+	switch g.Config.Loss {
+	case "logloss":
+		fmt.Fprintf(bw, "\n/* Returns P(y=1) for x. */\n")
+		fmt.Fprintf(bw, "static %s %s_predict_proba(const %s *x) {\n", ctype, prefix, ctype)
+		fmt.Fprintf(bw, "    return %s / (%s + %s(-%s_predict(x)));\n", one, one, exp, prefix)
+		fmt.Fprintln(bw, "}")
+	case "gamma":
+		fmt.Fprintf(bw, "\n/* Returns the expected value for x. */\n")
+		fmt.Fprintf(bw, "static %s %s_predict_mean(const %s *x) {\n", ctype, prefix, ctype)
+		fmt.Fprintf(bw, "    return %s(%s_predict(x));\n", exp, prefix)
+		fmt.Fprintln(bw, "}")
+	}
+	fmt.Fprintf(bw, "\n#endif /* %s_H */\n", macro)
+	return bw.Flush()
+}
+
+// writeCInts writes a const array of the smallest unsigned type holding
+// values, or a single 0 if values is empty.
+func writeCInts(w io.Writer, name string, values []int) {
+	typ, m := "uint8_t", 0
+	if len(values) > 0 {
+		m = slices.Max(values)
+	}
+	if m > math.MaxUint16 {
+		typ = "uint32_t"
+	} else if m > math.MaxUint8 {
+		typ = "uint16_t"
+	}
+	fmt.Fprintf(w, "static const %s %s[%d] = {", typ, name, max(len(values), 1))
+	for i, v := range values {
+		if i%16 == 0 {
+			fmt.Fprint(w, "\n   ")
+		}
+		fmt.Fprintf(w, " %d,", v)
+	}
+	if len(values) == 0 {
+		fmt.Fprint(w, "\n    0,")
+	}
+	fmt.Fprint(w, "\n};\n\n")
+}
+
+// formatCReal formats v as a C literal that reads back exactly: the
+// shortest decimal for float64, or for float the shortest one of v rounded
+// to float32.
+func formatCReal(v float64, float bool) string {
+	var s string
+	if float {
+		s = strconv.FormatFloat(float64(float32(v)), 'g', -1, 32)
+	} else {
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	if float {
+		s += "f"
+	}
+	return s
+}
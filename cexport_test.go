@@ -0,0 +1,188 @@
+package gboost
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// compileC compiles and runs a C program printing the header's predictions
+// for rows, one per line, and returns them. It skips the test without a C
+// compiler.
+func compileC(t *testing.T, header []byte, fn, ctype string, rows [][]float64) []float64 {
+	t.Helper()
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("no C compiler in PATH")
+	}
+	var src strings.Builder
+	src.WriteString("#include <stdio.h>\n#include <math.h>\n#include \"model.h\"\n\n")
+	fmt.Fprintf(&src, "static const %s rows[%d][%d] = {\n", ctype, len(rows), len(rows[0]))
+	for _, row := range rows {
+		src.WriteString("    {")
+		for _, v := range row {
+			if math.IsNaN(v) {
+				src.WriteString("NAN, ")
+			} else {
+				src.WriteString(strconv.FormatFloat(v, 'g', -1, 64) + ", ")
+			}
+		}
+		src.WriteString("},\n")
+	}
+	fmt.Fprintf(&src, "};\n\nint main(void) {\n    for (int i = 0; i < %d; i++) printf(\"%%.17g\\n\", (double)%s(rows[i]));\n    return 0;\n}\n", len(rows), fn)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "model.h"), header, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.c"), []byte(src.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(dir, "main")
+	if out, err := exec.Command(cc, "-std=c99", "-pedantic", "-O2", "-Wall", "-Werror", "-o", bin, filepath.Join(dir, "main.c"), "-lm").CombinedOutput(); err != nil {
+		t.Fatalf("cc: %v\n%s", err, out)
+	}
+	out, err := exec.Command(bin).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var preds []float64
+	for _, line := range strings.Fields(string(out)) {
+		v, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		preds = append(preds, v)
+	}
+	return preds
+}
+
+func TestWriteC(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 20
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	rows := append(X[:10:10], []float64{math.NaN(), 0.5}, []float64{0.5, math.NaN()})
+
+	var buf bytes.Buffer
+	if err := gbm.WriteC(&buf, CExportOptions{Prefix: "house"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "#define HOUSE_NUM_FEATURES 2") {
+		t.Errorf("header lacks the feature count:\n%s", buf.String())
+	}
+	for i, got := range compileC(t, buf.Bytes(), "house_predict", "double", rows) {
+		if want := gbm.PredictSingle(rows[i]); got != want {
+			t.Errorf("row %d: C predicts %v, Go %v", i, got, want)
+		}
+	}
+
+	buf.Reset()
+	if err := gbm.WriteC(&buf, CExportOptions{Float: true}); err != nil {
+		t.Fatal(err)
+	}
+	for i, got := range compileC(t, buf.Bytes(), "gboost_model_predict", "float", rows[:10]) {
+		if want := gbm.PredictSingle(rows[i]); math.Abs(got-want) > 1e-4*math.Max(1, math.Abs(want)) {
+			t.Errorf("row %d: float C predicts %v, Go %v", i, got, want)
+		}
+	}
+}
+
+func TestWriteCLogloss(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	labels := make([]float64, len(y))
+	for i, v := range y {
+		if v > 0 {
+			labels[i] = 1
+		}
+	}
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	cfg.Loss = "logloss"
+	gbm := New(cfg)
+	if err := gbm.Fit(X, labels); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := gbm.WriteC(&buf, CExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for i, got := range compileC(t, buf.Bytes(), "gboost_model_predict_proba", "double", X[:10]) {
+		if want := gbm.PredictProba(X[i]); math.Abs(got-want) > 1e-12 {
+			t.Errorf("row %d: C probability %v, Go %v", i, got, want)
+		}
+	}
+}
+
+func TestWriteCGamma(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	for i, v := range y {
+		y[i] = math.Abs(v) + 1
+	}
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	cfg.Loss = "gamma"
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := gbm.WriteC(&buf, CExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for i, got := range compileC(t, buf.Bytes(), "gboost_model_predict_mean", "double", X[:10]) {
+		if want := inverseLink("gamma", gbm.PredictSingle(X[i])); math.Abs(got-want) > 1e-12*want {
+			t.Errorf("row %d: C expected value %v, Go %v", i, got, want)
+		}
+	}
+}
+
+func TestWriteCNoTrees(t *testing.T) {
+	// A constant target trains no trees.
+	X := [][]float64{{1, 2}, {3, 4}, {5, 6}}
+	gbm := New(DefaultConfig())
+	if err := gbm.Fit(X, []float64{7, 7, 7}); err != nil {
+		t.Fatal(err)
+	}
+	if gbm.NumTrees() != 0 {
+		t.Fatalf("setup: model has %d trees", gbm.NumTrees())
+	}
+	for _, float := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := gbm.WriteC(&buf, CExportOptions{Float: float}); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), "{\n};") {
+			t.Fatalf("header has an empty initializer:\n%s", buf.String())
+		}
+		ctype := "double"
+		if float {
+			ctype = "float"
+		}
+		for i, got := range compileC(t, buf.Bytes(), "gboost_model_predict", ctype, X) {
+			if got != 7 {
+				t.Errorf("row %d: C predicts %v, want 7", i, got)
+			}
+		}
+	}
+}
+
+func TestWriteCErrors(t *testing.T) {
+	if err := New(DefaultConfig()).WriteC(&bytes.Buffer{}, CExportOptions{}); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("untrained: err = %v, want ErrModelNotFitted", err)
+	}
+	gbm := fitStumpModel(t)
+	if err := gbm.WriteC(&bytes.Buffer{}, CExportOptions{Prefix: "my-model"}); err == nil {
+		t.Error("expected an error for a prefix that is not a C identifier")
+	}
+}
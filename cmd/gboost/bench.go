@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/datasets"
+)
+
+// countFlag is an integer flag that also accepts scientific notation,
+// so "--rows 1e6" works.
+type countFlag int
+
+func (c *countFlag) String() string { return strconv.Itoa(int(*c)) }
+
+func (c *countFlag) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 1 || v != math.Trunc(v) || v > math.MaxInt32 {
+		return fmt.Errorf("want a positive whole number, got %q", s)
+	}
+	*c = countFlag(v)
+	return nil
+}
+
+func runBench(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	rows := countFlag(1000)
+	features := countFlag(10)
+	fs.Var(&rows, "rows", "number of synthetic training rows (accepts 1e6)")
+	fs.Var(&features, "features", "number of synthetic features")
+	cfg := bindConfigFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gboost bench [--rows N] [--features N] [flags]")
+		fmt.Fprintln(fs.Output(), "\nTrains on synthetic Friedman #1 data (thresholded at its median for --loss logloss)")
+		fmt.Fprintln(fs.Output(), "and reports training and prediction throughput.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if features < 5 {
+		return fmt.Errorf("--features must be >= 5 (Friedman #1 uses the first five)")
+	}
+
+	genStart := time.Now()
+	X, y := friedman1(int(rows), int(features), cfg.Seed, cfg.Loss == "logloss")
+	genTime := time.Since(genStart)
+
+	model := gboost.New(*cfg)
+	trainStart := time.Now()
+	if err := model.Fit(X, y); err != nil {
+		return err
+	}
+	trainTime := time.Since(trainStart)
+
+	predictStart := time.Now()
+	model.Predict(X)
+	predictTime := time.Since(predictStart)
+
+	n := float64(rows)
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "--- Environment ---")
+	fmt.Fprintf(tw, "Go:\t%s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(tw, "CPUs:\t%d (GOMAXPROCS %d)\n", runtime.NumCPU(), runtime.GOMAXPROCS(0))
+
+	fmt.Fprintln(tw, "\n--- Workload ---")
+	fmt.Fprintf(tw, "Rows:\t%d\n", rows)
+	fmt.Fprintf(tw, "Features:\t%d\n", features)
+	fmt.Fprintf(tw, "Loss:\t%s\n", cfg.Loss)
+	fmt.Fprintf(tw, "Trees:\t%d (max depth %d, lr %g, subsample %g)\n", cfg.NEstimators, cfg.MaxDepth, cfg.LearningRate, cfg.SubsampleRatio)
+	fmt.Fprintf(tw, "Data generation:\t%s\n", genTime.Round(time.Millisecond))
+
+	fmt.Fprintln(tw, "\n--- Training ---")
+	fmt.Fprintf(tw, "Wall time:\t%s\n", trainTime.Round(time.Millisecond))
+	if cfg.NEstimators > 0 {
+		fmt.Fprintf(tw, "Per tree:\t%s\n", (trainTime / time.Duration(cfg.NEstimators)).Round(time.Microsecond))
+		fmt.Fprintf(tw, "Throughput:\t%.0f row-trees/s\n", n*float64(cfg.NEstimators)/trainTime.Seconds())
+	}
+
+	fmt.Fprintln(tw, "\n--- Prediction ---")
+	fmt.Fprintf(tw, "Wall time:\t%s\n", predictTime.Round(time.Microsecond))
+	fmt.Fprintf(tw, "Per row:\t%s\n", (predictTime / time.Duration(rows)).Round(time.Nanosecond))
+	fmt.Fprintf(tw, "Throughput:\t%.0f rows/s\n", n/predictTime.Seconds())
+	return tw.Flush()
+}
+
+// friedman1 generates nRows of [datasets.Friedman1] with unit noise. Its
+// ten features are cut to the first nFeatures, or padded with further
+// irrelevant uniform [0, 1) features. With binary set, y is thresholded at
+// 14.4, roughly its median.
+func friedman1(nRows, nFeatures int, seed int64, binary bool) ([][]float64, []float64) {
+	ds := datasets.Friedman1(nRows, 1, seed)
+	rnd := rand.New(rand.NewSource(seed))
+	for i, x := range ds.X {
+		if nFeatures <= len(x) {
+			ds.X[i] = x[:nFeatures]
+			continue
+		}
+		for len(x) < nFeatures {
+			x = append(x, rnd.Float64())
+		}
+		ds.X[i] = x
+	}
+	if binary {
+		for i, v := range ds.Y {
+			if v > 14.4 {
+				ds.Y[i] = 1
+			} else {
+				ds.Y[i] = 0
+			}
+		}
+	}
+	return ds.X, ds.Y
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+func runCV(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("cv", flag.ContinueOnError)
+	data := bindDataFlags(fs)
+	cfg := bindConfigFlags(fs)
+	folds := fs.Int("folds", 5, "number of cross-validation folds")
+	metricName := fs.String("metric", "", "metric: mse, rmse, mae, accuracy, logloss, auc (default auc for logloss, rmse for mse)")
+	repeats := fs.Int("repeats", 1, "repeat k-fold over this many shuffles and report a 95% confidence interval")
+	workers := fs.Int("workers", 0, "concurrent fold fits (0 = all cores)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gboost cv --data train.csv [flags]")
+		fs.PrintDefaults()
+	}
+
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	name, metric, err := lookupMetric(*metricName, cfg.Loss)
+	if err != nil {
+		return err
+	}
+	ds, err := data.load()
+	if err != nil {
+		return err
+	}
+
+	opts := gboost.CVOptions{
+		Folds:           *folds,
+		Metric:          metric.fn,
+		GreaterIsBetter: metric.greaterIsBetter,
+		Seed:            cfg.Seed,
+		Workers:         *workers,
+	}
+	if *repeats != 1 {
+		return printRepeatedCV(stdout, *cfg, ds, data.path, name, *repeats, opts)
+	}
+	res, err := gboost.CrossValidate(*cfg, ds.X, ds.Y, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%d-fold cross-validation on %s (%d samples, %d features)\n\n", *folds, data.path, len(ds.X), len(ds.X[0]))
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Fold\t%s\n", name)
+	for i, score := range res.Scores {
+		fmt.Fprintf(tw, "%d\t%.6f\n", i+1, score)
+	}
+	fmt.Fprintf(tw, "\nMean\t%.6f\n", res.Mean)
+	fmt.Fprintf(tw, "Std\t%.6f\n", res.Std)
+	return tw.Flush()
+}
+
+// printRepeatedCV runs repeated k-fold cross-validation and prints the mean
+// score of each repeat and a 95% confidence interval for the overall mean.
+func printRepeatedCV(stdout io.Writer, cfg gboost.Config, ds *gboost.Dataset, path, metric string, repeats int, opts gboost.CVOptions) error {
+	res, err := gboost.RepeatedCV(cfg, ds.X, ds.Y, repeats, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%d x %d-fold cross-validation on %s (%d samples, %d features)\n\n", repeats, opts.Folds, path, len(ds.X), len(ds.X[0]))
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Repeat\tMean %s\n", metric)
+	for i, m := range res.RepeatMeans {
+		fmt.Fprintf(tw, "%d\t%.6f\n", i+1, m)
+	}
+	low, high := res.ConfidenceInterval(0.95)
+	fmt.Fprintf(tw, "\nMean\t%.6f\n", res.Mean)
+	fmt.Fprintf(tw, "Std\t%.6f\n", res.Std)
+	fmt.Fprintf(tw, "95%% CI\t[%.6f, %.6f]\n", low, high)
+	return tw.Flush()
+}
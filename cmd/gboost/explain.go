@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+func runExplain(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	modelPath := fs.String("model", "", "path to the model `file` (required)")
+	data := bindDataFlags(fs)
+	format := fs.String("format", "csv", "output format: csv or json")
+	outPath := fs.String("out", "", "write to this `file` instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gboost explain --model model.json --data data.csv [flags]")
+		fs.PrintDefaults()
+	}
+
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *modelPath == "" {
+		return fmt.Errorf("--model is required")
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown format %q (want csv or json)", *format)
+	}
+
+	model, err := gboost.Load(*modelPath)
+	if err != nil {
+		return fmt.Errorf("load model: %w", err)
+	}
+	ds, err := data.load()
+	if err != nil {
+		return err
+	}
+	if model.FeatureNames() == nil && len(ds.FeatureNames) == model.NumFeatures() {
+		if err := model.SetFeatureNames(ds.FeatureNames); err != nil {
+			return err
+		}
+	}
+	explanation, err := model.Explain(ds.X)
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "json" {
+		return explanation.WriteJSON(w)
+	}
+	return explanation.WriteCSV(w)
+}
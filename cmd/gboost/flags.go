@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/metrics"
+)
+
+// bindConfigFlags registers the training hyperparameter flags on fs and
+// returns the Config they populate, starting from gboost.DefaultConfig.
+func bindConfigFlags(fs *flag.FlagSet) *gboost.Config {
+	cfg := gboost.DefaultConfig()
+	fs.StringVar(&cfg.Loss, "loss", cfg.Loss, `loss function: "mse", "logloss", or "gamma"`)
+	fs.IntVar(&cfg.NEstimators, "n-estimators", cfg.NEstimators, "number of boosting rounds")
+	fs.Float64Var(&cfg.LearningRate, "learning-rate", cfg.LearningRate, "shrinkage applied to each tree")
+	fs.IntVar(&cfg.MaxDepth, "max-depth", cfg.MaxDepth, "maximum tree depth")
+	fs.IntVar(&cfg.MinSamplesLeaf, "min-samples-leaf", cfg.MinSamplesLeaf, "minimum samples per leaf")
+	fs.Float64Var(&cfg.SubsampleRatio, "subsample", cfg.SubsampleRatio, "fraction of rows sampled per tree")
+	fs.Int64Var(&cfg.Seed, "seed", cfg.Seed, "random seed")
+	fs.Float64Var(&cfg.ValidationFraction, "validation-fraction", cfg.ValidationFraction, "fraction of rows held out for early stopping (0 disables)")
+	fs.IntVar(&cfg.Patience, "patience", cfg.Patience, "rounds without validation improvement before stopping")
+	fs.Float64Var(&cfg.MinDelta, "min-delta", cfg.MinDelta, "minimum validation loss decrease that counts as improvement")
+	fs.Float64Var(&cfg.MinGainFraction, "min-gain-fraction", cfg.MinGainFraction, "stop when a tree's gain falls below this fraction of the first tree's (0 disables)")
+	fs.IntVar(&cfg.MaxSingleLeafTrees, "max-single-leaf-trees", cfg.MaxSingleLeafTrees, "stop after this many consecutive splitless trees (0 disables)")
+	fs.BoolVar(&cfg.SkipSingleLeafTrees, "skip-single-leaf-trees", cfg.SkipSingleLeafTrees, "leave splitless trees out of the model")
+	fs.IntVar(&cfg.LRPatience, "lr-patience", cfg.LRPatience, "rounds without validation improvement before reducing the learning rate (0 disables)")
+	fs.Float64Var(&cfg.LRFactor, "lr-factor", cfg.LRFactor, "learning rate multiplier at each plateau (0 means 0.5)")
+	fs.Float64Var(&cfg.MinLearningRate, "min-learning-rate", cfg.MinLearningRate, "floor for plateau learning rate reductions")
+	fs.IntVar(&cfg.SnapshotBlend, "snapshot-blend", cfg.SnapshotBlend, "average the predictions of the last N checkpoints (0 disables)")
+	return &cfg
+}
+
+// dataFlags locates a CSV dataset on disk.
+type dataFlags struct {
+	path     string
+	target   int
+	noHeader bool
+}
+
+func bindDataFlags(fs *flag.FlagSet) *dataFlags {
+	d := &dataFlags{}
+	fs.StringVar(&d.path, "data", "", "path to the CSV `file` (required)")
+	fs.IntVar(&d.target, "target", -1, "target column index; negative values count from the end")
+	fs.BoolVar(&d.noHeader, "no-header", false, "the CSV has no header row")
+	return d
+}
+
+func (d *dataFlags) load() (*gboost.Dataset, error) {
+	if d.path == "" {
+		return nil, fmt.Errorf("--data is required")
+	}
+	ds, err := gboost.LoadCSV(d.path, d.target, !d.noHeader)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", d.path, err)
+	}
+	return ds, nil
+}
+
+type namedMetric struct {
+	fn              gboost.Metric
+	greaterIsBetter bool
+}
+
+var metricsByName = map[string]namedMetric{
+	"mse":      {metrics.MSE, false},
+	"rmse":     {metrics.RMSE, false},
+	"mae":      {metrics.MAE, false},
+	"accuracy": {metrics.Accuracy, true},
+	"logloss":  {metrics.LogLoss, false},
+	"auc":      {metrics.AUC, true},
+}
+
+// lookupMetric resolves a metric by name. An empty name picks a default for
+// the loss: AUC for classification, RMSE for regression.
+func lookupMetric(name, loss string) (string, namedMetric, error) {
+	if name == "" {
+		name = "rmse"
+		if loss == "logloss" {
+			name = "auc"
+		}
+	}
+	m, ok := metricsByName[strings.ToLower(name)]
+	if !ok {
+		known := make([]string, 0, len(metricsByName))
+		for k := range metricsByName {
+			known = append(known, k)
+		}
+		sort.Strings(known)
+		return "", namedMetric{}, fmt.Errorf("unknown metric %q (known: %s)", name, strings.Join(known, ", "))
+	}
+	return strings.ToLower(name), m, nil
+}
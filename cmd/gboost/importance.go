@@ -0,0 +1,142 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+func runImportance(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("importance", flag.ContinueOnError)
+	modelPath := fs.String("model", "", "path to the model `file` (required)")
+	data := bindDataFlags(fs)
+	method := fs.String("method", "gain", "importance method: gain, split, cover, permutation, or shap (permutation and shap need --data)")
+	metricName := fs.String("metric", "", "metric for permutation importance (default auc for logloss, rmse for mse)")
+	repeats := fs.Int("repeats", 5, "shuffles per feature for permutation importance")
+	seed := fs.Int64("seed", 0, "random seed for permutation importance")
+	top := fs.Int("top", 0, "only list the top `N` features (0 = all)")
+	svgPath := fs.String("svg", "", "also write a horizontal bar chart to this SVG `file`")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gboost importance --model model.json [--data test.csv] [flags]")
+		fs.PrintDefaults()
+	}
+
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *modelPath == "" {
+		return fmt.Errorf("--model is required")
+	}
+
+	model, err := gboost.Load(*modelPath)
+	if err != nil {
+		return fmt.Errorf("load model: %w", err)
+	}
+	names := featureNames(model)
+
+	var importance []float64
+	var title string
+	switch *method {
+	case "gain", "split", "cover":
+		title = fmt.Sprintf("Feature Importance (%s)", *method)
+		importance, err = model.FeatureImportanceByType(gboost.ImportanceType(*method))
+		if err != nil {
+			return err
+		}
+	case "permutation", "shap":
+		ds, err := data.load()
+		if err != nil {
+			return err
+		}
+		if model.FeatureNames() == nil && len(ds.FeatureNames) == len(names) {
+			names = ds.FeatureNames
+		}
+
+		if *method == "shap" {
+			title = "Feature Importance (mean |SHAP|)"
+			importance, err = model.ShapImportance(ds.X)
+		} else {
+			var mname string
+			var metric namedMetric
+			mname, metric, err = lookupMetric(*metricName, model.Config.Loss)
+			if err != nil {
+				return err
+			}
+			title = fmt.Sprintf("Feature Importance (permutation, %s drop)", mname)
+			importance, err = model.PermutationImportance(ds.X, ds.Y, gboost.PermutationOptions{
+				Metric:          metric.fn,
+				GreaterIsBetter: metric.greaterIsBetter,
+				Repeats:         *repeats,
+				Seed:            *seed,
+			})
+		}
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown method %q (want gain, split, cover, permutation, or shap)", *method)
+	}
+
+	printImportance(stdout, title, names, importance, *top)
+
+	if *svgPath != "" {
+		if err := writeImportanceSVG(*svgPath, title, names, importance, *top); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "\nWrote %s\n", *svgPath)
+	}
+	return nil
+}
+
+// writeImportanceSVG renders a horizontal bar chart of the top features,
+// largest first. Negative values (possible for permutation importance) are
+// drawn as empty bars.
+func writeImportanceSVG(path, title string, names []string, importance []float64, top int) error {
+	order := make([]int, len(importance))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return importance[order[a]] > importance[order[b]] })
+	if top > 0 && top < len(order) {
+		order = order[:top]
+	}
+
+	const (
+		labelWidth = 180
+		barWidth   = 400
+		rowHeight  = 22
+		headHeight = 36
+	)
+	maxVal := 0.0
+	for _, j := range order {
+		maxVal = max(maxVal, importance[j])
+	}
+	width := labelWidth + barWidth + 90
+	height := headHeight + rowHeight*len(order) + 10
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", width, height)
+	fmt.Fprintf(f, `  <text x="10" y="22" font-size="14" font-weight="bold">%s</text>`+"\n", html.EscapeString(title))
+	for row, j := range order {
+		y := headHeight + row*rowHeight
+		w := 0.0
+		if maxVal > 0 && importance[j] > 0 {
+			w = importance[j] / maxVal * barWidth
+		}
+		fmt.Fprintf(f, `  <text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", labelWidth-8, y+15, html.EscapeString(names[j]))
+		fmt.Fprintf(f, `  <rect x="%d" y="%d" width="%.1f" height="%d" fill="#4c78a8"/>`+"\n", labelWidth, y+3, w, rowHeight-6)
+		fmt.Fprintf(f, `  <text x="%.1f" y="%d">%.4f</text>`+"\n", float64(labelWidth)+w+6, y+15, importance[j])
+	}
+	fmt.Fprintln(f, "</svg>")
+	return nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+func runInspect(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	dumpTrees := fs.Bool("dump-trees", false, "print every tree as indented text")
+	dot := fs.Int("dot", -1, "print tree `N` in Graphviz DOT format instead of the summary")
+	cHeader := fs.Bool("c", false, "print the model as a C header for embedded inference instead of the summary")
+	cPrefix := fs.String("c-prefix", "", "name `prefix` for the C header's arrays and functions (default gboost_model)")
+	cFloat := fs.Bool("c-float", false, "use float instead of double in the C header")
+	usage := fs.Bool("usage", false, "also print where in the ensemble each top feature is split on")
+	top := fs.Int("top", 10, "number of features to list by importance")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gboost inspect [flags] model.json")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one model path, got %d", len(positional))
+	}
+	path := positional[0]
+
+	model, err := gboost.Load(path)
+	if err != nil {
+		return fmt.Errorf("load model: %w", err)
+	}
+
+	if *cHeader {
+		return model.WriteC(stdout, gboost.CExportOptions{Prefix: *cPrefix, Float: *cFloat})
+	}
+	if *dot >= 0 {
+		return model.WriteDot(stdout, *dot)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	printSummary(stdout, path, info.Size(), model, *top)
+	if *usage {
+		if err := printUsage(stdout, model, *top); err != nil {
+			return err
+		}
+	}
+
+	if *dumpTrees {
+		fmt.Fprintln(stdout)
+		return model.DumpTrees(stdout)
+	}
+	return nil
+}
+
+func printSummary(w io.Writer, path string, size int64, model *gboost.GBM, top int) {
+	cfg := model.Config
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "--- Model ---")
+	fmt.Fprintf(tw, "File:\t%s (%d bytes)\n", path, size)
+	fmt.Fprintf(tw, "Loss:\t%s\n", cfg.Loss)
+	fmt.Fprintf(tw, "Features:\t%d\n", model.NumFeatures())
+	fmt.Fprintf(tw, "Trees:\t%d\n", model.NumTrees())
+	fmt.Fprintf(tw, "Base value:\t%.6f\n", model.BaseValue())
+	if fp, err := model.Fingerprint(); err == nil {
+		fmt.Fprintf(tw, "Fingerprint:\t%s\n", fp)
+	}
+
+	fmt.Fprintln(tw, "\n--- Config ---")
+	fmt.Fprintf(tw, "NEstimators:\t%d\n", cfg.NEstimators)
+	fmt.Fprintf(tw, "LearningRate:\t%g\n", cfg.LearningRate)
+	fmt.Fprintf(tw, "MaxDepth:\t%d\n", cfg.MaxDepth)
+	fmt.Fprintf(tw, "MinSamplesLeaf:\t%d\n", cfg.MinSamplesLeaf)
+	fmt.Fprintf(tw, "SubsampleRatio:\t%g\n", cfg.SubsampleRatio)
+	fmt.Fprintf(tw, "Seed:\t%d\n", cfg.Seed)
+
+	if stats := model.TreeStats(); len(stats) > 0 {
+		fmt.Fprintln(tw, "\n--- Tree Stats ---")
+		fmt.Fprintln(tw, "\tmin\tmean\tmax")
+		depth := summarize(stats, func(s gboost.TreeStats) float64 { return float64(s.Depth) })
+		leaves := summarize(stats, func(s gboost.TreeStats) float64 { return float64(s.Leaves) })
+		gain := summarize(stats, func(s gboost.TreeStats) float64 { return s.Gain })
+		fmt.Fprintf(tw, "Depth\t%.0f\t%.2f\t%.0f\n", depth[0], depth[1], depth[2])
+		fmt.Fprintf(tw, "Leaves\t%.0f\t%.2f\t%.0f\n", leaves[0], leaves[1], leaves[2])
+		fmt.Fprintf(tw, "Gain\t%.4g\t%.4g\t%.4g\n", gain[0], gain[1], gain[2])
+	}
+	tw.Flush()
+
+	printImportance(w, "Feature Importance (gain)", featureNames(model), model.FeatureImportance(), top)
+}
+
+// printImportance prints the top features by descending importance.
+func printImportance(w io.Writer, title string, names []string, importance []float64, top int) {
+	order := make([]int, len(importance))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return importance[order[a]] > importance[order[b]]
+	})
+	if top > 0 && top < len(order) {
+		order = order[:top]
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "\n--- %s ---\n", title)
+	for rank, j := range order {
+		fmt.Fprintf(tw, "%d\t%s\t%.4f\n", rank+1, names[j], importance[j])
+	}
+	tw.Flush()
+}
+
+// printUsage prints, for the top features by gain, how often the model
+// splits on them, how many of those splits are roots, the first tree and
+// the mean depth at which they appear, and their share of the total gain.
+func printUsage(w io.Writer, model *gboost.GBM, top int) error {
+	usage, err := model.FeatureUsage()
+	if err != nil {
+		return err
+	}
+	importance := model.FeatureImportance()
+	order := make([]int, len(usage))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return importance[order[a]] > importance[order[b]]
+	})
+	if top > 0 && top < len(order) {
+		order = order[:top]
+	}
+
+	names := featureNames(model)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\n--- Feature Usage ---")
+	fmt.Fprintln(tw, "feature\tsplits\troots\tfirst tree\tmean depth\tgain share")
+	for _, j := range order {
+		uses := usage[j]
+		if len(uses) == 0 {
+			fmt.Fprintf(tw, "%s\t0\t0\t-\t-\t%.4f\n", names[j], importance[j])
+			continue
+		}
+		roots, depth := 0, 0
+		for _, u := range uses {
+			if u.Depth == 0 {
+				roots++
+			}
+			depth += u.Depth
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.2f\t%.4f\n", names[j], len(uses), roots, uses[0].Tree, float64(depth)/float64(len(uses)), importance[j])
+	}
+	return tw.Flush()
+}
+
+// featureNames returns the model's feature names, falling back to f0, f1, ...
+func featureNames(model *gboost.GBM) []string {
+	if names := model.FeatureNames(); names != nil {
+		return names
+	}
+	names := make([]string, model.NumFeatures())
+	for j := range names {
+		names[j] = fmt.Sprintf("f%d", j)
+	}
+	return names
+}
+
+// summarize returns the min, mean, and max of f over stats.
+func summarize(stats []gboost.TreeStats, f func(gboost.TreeStats) float64) [3]float64 {
+	lo, hi, total := f(stats[0]), f(stats[0]), 0.0
+	for _, s := range stats {
+		v := f(s)
+		lo = min(lo, v)
+		hi = max(hi, v)
+		total += v
+	}
+	return [3]float64{lo, total / float64(len(stats)), hi}
+}
@@ -0,0 +1,91 @@
+// Command gboost trains, evaluates, and inspects gboost models from the shell.
+//
+// Usage:
+//
+//	gboost <command> [flags] [args]
+//
+// Run "gboost help" for the list of commands, or "gboost <command> -h" for
+// the flags of a single command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"inspect", "show model metadata, config, tree stats, and feature importance", runInspect},
+	{"cv", "run k-fold cross-validation and report per-fold scores", runCV},
+	{"tune", "search a hyperparameter grid with cross-validation", runTune},
+	{"importance", "report feature importance by gain, permutation, or SHAP", runImportance},
+	{"explain", "write per-row SHAP explanations of a dataset as CSV or JSON", runExplain},
+	{"registry", "compare feature importance across the models saved in a directory", runRegistry},
+	{"bench", "time training and prediction on synthetic data", runBench},
+	{"serve", "serve named models over HTTP from a manifest", runServe},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage(os.Stdout)
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		if err := cmd.run(os.Args[2:], os.Stdout); err != nil {
+			if err == flag.ErrHelp {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "gboost %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "gboost: unknown command %q\n\n", name)
+	usage(os.Stderr)
+	os.Exit(2)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gboost <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// parseArgs parses flags that may appear before or after positional
+// arguments (e.g. "inspect model.json --dump-trees") and returns the
+// positional arguments in order.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/serve"
+)
+
+// writeFixtures writes a small regression CSV and a model trained on it to
+// a temporary directory and returns their paths.
+func writeFixtures(t *testing.T) (dataPath, modelPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	var csv strings.Builder
+	csv.WriteString("a,b,y\n")
+	X := make([][]float64, 40)
+	y := make([]float64, 40)
+	for i := range X {
+		X[i] = []float64{float64(i), float64(i % 5)}
+		y[i] = 2*X[i][0] + X[i][1]
+		fmt.Fprintf(&csv, "%g,%g,%g\n", X[i][0], X[i][1], y[i])
+	}
+	dataPath = filepath.Join(dir, "train.csv")
+	if err := os.WriteFile(dataPath, []byte(csv.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := gboost.DefaultConfig()
+	cfg.NEstimators = 5
+	model := gboost.New(cfg)
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	modelPath = filepath.Join(dir, "model.json")
+	if err := model.Save(modelPath); err != nil {
+		t.Fatal(err)
+	}
+	return dataPath, modelPath
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		top        int
+		wantErr    error
+	}{
+		{"flags first", []string{"--top", "3", "model.json"}, []string{"model.json"}, 3, nil},
+		{"flags last", []string{"model.json", "--top=4"}, []string{"model.json"}, 4, nil},
+		{"interleaved", []string{"a", "--top", "2", "b"}, []string{"a", "b"}, 2, nil},
+		{"no args", nil, nil, 10, nil},
+		{"help", []string{"-h"}, nil, 10, flag.ErrHelp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			top := fs.Int("top", 10, "")
+			positional, err := parseArgs(fs, tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(positional, tt.positional) || *top != tt.top {
+				t.Errorf("positional %v, top %d, want %v, %d", positional, *top, tt.positional, tt.top)
+			}
+		})
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if _, err := parseArgs(fs, []string{"model.json", "--bogus"}); err == nil {
+		t.Error("unknown flag accepted")
+	}
+}
+
+func TestLookupMetric(t *testing.T) {
+	tests := []struct {
+		name, loss, want string
+		wantErr          bool
+	}{
+		{"", "mse", "rmse", false},
+		{"", "logloss", "auc", false},
+		{"MAE", "mse", "mae", false},
+		{"accuracy", "logloss", "accuracy", false},
+		{"r2", "mse", "", true},
+	}
+	for _, tt := range tests {
+		got, _, err := lookupMetric(tt.name, tt.loss)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("lookupMetric(%q, %q) = %q, %v, want %q (error %v)", tt.name, tt.loss, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCountFlag(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"1000", 1000, false},
+		{"1e6", 1000000, false},
+		{"0", 0, true},
+		{"-5", 0, true},
+		{"2.5", 0, true},
+		{"lots", 0, true},
+	}
+	for _, tt := range tests {
+		var c countFlag
+		err := c.Set(tt.in)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && int(c) != tt.want) {
+			t.Errorf("Set(%q) = %d, %v, want %d (error %v)", tt.in, c, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	dataPath, modelPath := writeFixtures(t)
+	dir := t.TempDir()
+	badGrid := filepath.Join(dir, "grid.json")
+	if err := os.WriteFile(badGrid, []byte(`{"depth": [2, 3]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.json")
+
+	tests := []struct {
+		cmd     string
+		args    []string
+		wantErr string
+	}{
+		{"inspect", nil, "expected exactly one model path"},
+		{"inspect", []string{"a.json", "b.json"}, "expected exactly one model path"},
+		{"inspect", []string{missing}, "load model"},
+		{"inspect", []string{"--bogus", modelPath}, "flag provided but not defined"},
+		{"cv", nil, "--data is required"},
+		{"cv", []string{"--data", dataPath, "--metric", "r2"}, "unknown metric"},
+		{"cv", []string{"--data", missing}, "load " + missing},
+		{"tune", []string{"--data", dataPath}, "--grid is required"},
+		{"tune", []string{"--data", dataPath, "--grid", badGrid}, "unknown field"},
+		{"tune", []string{"--data", dataPath, "--grid", missing}, "no such file"},
+		{"importance", nil, "--model is required"},
+		{"importance", []string{"--model", missing}, "load model"},
+		{"importance", []string{"--model", modelPath, "--method", "lime"}, "unknown method"},
+		{"importance", []string{"--model", modelPath, "--method", "shap"}, "--data is required"},
+		{"importance", []string{"--model", modelPath, "--method", "permutation", "--data", dataPath, "--metric", "r2"}, "unknown metric"},
+		{"explain", []string{"--data", dataPath}, "--model is required"},
+		{"explain", []string{"--model", modelPath, "--data", dataPath, "--format", "xml"}, "unknown format"},
+		{"explain", []string{"--model", modelPath}, "--data is required"},
+		{"registry", nil, "expected exactly one model directory"},
+		{"registry", []string{dir, "--format", "xml"}, "unknown format"},
+		{"registry", []string{dir}, "no saved models"},
+		{"registry", []string{filepath.Dir(modelPath), "--method", "bogus"}, "bogus"},
+		{"bench", []string{"--features", "3"}, "--features must be >= 5"},
+		{"bench", []string{"--rows", "1.5"}, "positive whole number"},
+		{"serve", nil, "--manifest is required"},
+		{"serve", []string{"--manifest", missing}, "no such file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd+" "+strings.Join(tt.args, " "), func(t *testing.T) {
+			run := lookupCommand(t, tt.cmd)
+			err := run(tt.args, io.Discard)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommandsRun(t *testing.T) {
+	dataPath, modelPath := writeFixtures(t)
+
+	tests := []struct {
+		cmd  string
+		args []string
+		want string
+	}{
+		{"inspect", []string{modelPath, "--top", "1"}, "--- Feature Importance (gain) ---"},
+		{"cv", []string{"--data", dataPath, "--folds", "2", "--n-estimators", "5"}, "2-fold cross-validation"},
+		{"importance", []string{"--model", modelPath, "--method", "split"}, "Feature Importance (split)"},
+		{"explain", []string{"--model", modelPath, "--data", dataPath, "--format", "json"}, "["},
+		{"registry", []string{filepath.Dir(modelPath)}, "model.json"},
+		{"bench", []string{"--rows", "50", "--features", "5", "--n-estimators", "2"}, "--- Prediction ---"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := lookupCommand(t, tt.cmd)(tt.args, &out); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestLogDriftAlert(t *testing.T) {
+	tests := []struct {
+		features []serve.FeatureDrift
+		want     string
+	}{
+		{nil, "worst no features"},
+		{[]serve.FeatureDrift{{Index: 0, Name: "age", PSI: 0.1}, {Index: 1, PSI: 0.4}}, "worst feature 1"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		logDriftAlert(&out, serve.DriftReport{Model: "m", Score: 0.4, Features: tt.features})
+		if !strings.Contains(out.String(), tt.want) {
+			t.Errorf("alert %q does not mention %q", out.String(), tt.want)
+		}
+	}
+}
+
+func lookupCommand(t *testing.T, name string) func([]string, io.Writer) error {
+	t.Helper()
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run
+		}
+	}
+	t.Fatalf("no command %q", name)
+	return nil
+}
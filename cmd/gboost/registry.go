@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+func runRegistry(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("registry", flag.ContinueOnError)
+	method := fs.String("method", "gain", "importance method: gain, split, or cover")
+	format := fs.String("format", "csv", "output format: csv or json")
+	outPath := fs.String("out", "", "write to this `file` instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gboost registry [flags] <dir>")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one model directory, got %d", len(positional))
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown format %q (want csv or json)", *format)
+	}
+
+	report, err := gboost.LoadRegistryReport(positional[0], gboost.ImportanceType(*method))
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "json" {
+		return report.WriteJSON(w)
+	}
+	return report.WriteCSV(w)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ahmedaabouzied/gboost/serve"
+)
+
+func runServe(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	manifestPath := fs.String("manifest", "", "YAML or JSON `file` listing the models to serve (required)")
+	addr := fs.String("addr", ":8080", "address to listen on")
+	shadowLog := fs.String("shadow-log", "", "append challenger predictions to `file` as JSON lines (default stderr)")
+	auditLog := fs.String("audit-log", "", "append every request and response to `file` as JSON lines")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gboost serve --manifest models.yaml [flags]")
+		fmt.Fprintln(fs.Output(), "\nEndpoints:")
+		fmt.Fprintln(fs.Output(), "  GET  /models            list the served models")
+		fmt.Fprintln(fs.Output(), `  POST /predict/{model}   predict {"rows": [[...], ...]} with the named model`)
+		fmt.Fprintln(fs.Output(), "  GET  /drift/{model}     input drift report for a model with a drift profile")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *manifestPath == "" {
+		fs.Usage()
+		return fmt.Errorf("--manifest is required")
+	}
+
+	srv, err := serve.Load(*manifestPath)
+	if err != nil {
+		return err
+	}
+	shadowed := false
+	for _, m := range srv.Models() {
+		fmt.Fprintf(stdout, "loaded %s (version %s): %d features, %d trees\n", m.Name, m.Version, m.Pipeline.Model.NumFeatures(), m.Pipeline.Model.NumTrees())
+		if m.Challenger != "" {
+			fmt.Fprintf(stdout, "  shadowed by %s\n", m.Challenger)
+			shadowed = true
+		}
+	}
+
+	srv.DriftAlert = func(r serve.DriftReport) { logDriftAlert(os.Stderr, r) }
+
+	if *auditLog != "" {
+		f, err := openLog(*auditLog)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		srv.Audit = serve.NewJSONAuditLog(f)
+	}
+	if shadowed {
+		var w io.Writer = os.Stderr
+		if *shadowLog != "" {
+			f, err := openLog(*shadowLog)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		srv.StartShadow(w)
+		defer func() {
+			if dropped := srv.StopShadow(); dropped > 0 {
+				fmt.Fprintf(stdout, "dropped %d shadow requests while the challenger queue was full\n", dropped)
+			}
+		}()
+	}
+
+	defer srv.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- httpServer.ListenAndServe() }()
+	fmt.Fprintf(stdout, "serving %d models on %s\n", len(srv.Models()), *addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	}
+}
+
+// openLog opens path for appending, creating it if needed.
+func openLog(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+}
+
+// logDriftAlert writes one line to w describing the drift report r and the
+// feature that drifted most, if the report has any features.
+func logDriftAlert(w io.Writer, r serve.DriftReport) {
+	worst := "no features"
+	if len(r.Features) > 0 {
+		f := r.Features[0]
+		for _, g := range r.Features {
+			if g.PSI > f.PSI {
+				f = g
+			}
+		}
+		worst = f.Name
+		if worst == "" {
+			worst = fmt.Sprintf("feature %d", f.Index)
+		}
+	}
+	fmt.Fprintf(w, "drift alert: model %s: PSI %.3f over the last %d rows (threshold %.2f), worst %s\n", r.Model, r.Score, r.Rows, r.Threshold, worst)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+func runTune(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("tune", flag.ContinueOnError)
+	data := bindDataFlags(fs)
+	cfg := bindConfigFlags(fs)
+	gridPath := fs.String("grid", "", "YAML or JSON `file` mapping hyperparameters to candidate values (required)")
+	folds := fs.Int("cv", 5, "number of cross-validation folds")
+	random := fs.Int("random", 0, "evaluate `N` randomly sampled grid points instead of the full grid")
+	metricName := fs.String("metric", "", "metric: mse, rmse, mae, accuracy, logloss, auc (default auc for logloss, rmse for mse)")
+	workers := fs.Int("workers", 0, "concurrent fits (0 = all cores)")
+	resultsPath := fs.String("results", "tune_results.csv", "where to write per-candidate scores")
+	modelPath := fs.String("model", "best_model.json", "where to write the best model, refit on all data")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gboost tune --data train.csv --grid params.yaml [flags]")
+		fmt.Fprintln(fs.Output(), "\nGrid keys: n_estimators, learning_rate, max_depth, min_samples_leaf, subsample_ratio.")
+		fmt.Fprintln(fs.Output(), "Flags not covered by the grid set the fixed base configuration.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *gridPath == "" {
+		return fmt.Errorf("--grid is required")
+	}
+
+	grid, err := loadGrid(*gridPath)
+	if err != nil {
+		return err
+	}
+	name, metric, err := lookupMetric(*metricName, cfg.Loss)
+	if err != nil {
+		return err
+	}
+	ds, err := data.load()
+	if err != nil {
+		return err
+	}
+
+	candidates := grid.Expand(*cfg)
+	if *random > 0 {
+		candidates = grid.Sample(*cfg, *random, cfg.Seed)
+	}
+	fmt.Fprintf(stdout, "Evaluating %d candidates with %d-fold CV (%s)...\n", len(candidates), *folds, name)
+
+	res, err := gboost.GridSearch(candidates, ds.X, ds.Y, gboost.CVOptions{
+		Folds:           *folds,
+		Metric:          metric.fn,
+		GreaterIsBetter: metric.greaterIsBetter,
+		Seed:            cfg.Seed,
+		Workers:         *workers,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writeResults(*resultsPath, name, metric.greaterIsBetter, res); err != nil {
+		return err
+	}
+
+	best := res.BestConfig()
+	model := gboost.New(best)
+	if err := model.SetFeatureNames(ds.FeatureNames); err != nil {
+		return err
+	}
+	if err := model.Fit(ds.X, ds.Y); err != nil {
+		return fmt.Errorf("refit best config: %w", err)
+	}
+	if err := model.Save(*modelPath); err != nil {
+		return fmt.Errorf("save model: %w", err)
+	}
+
+	r := res.Results[res.Best]
+	fmt.Fprintf(stdout, "\nBest %s: %.6f ± %.6f\n", name, r.Mean, r.Std)
+	fmt.Fprintf(stdout, "  n_estimators=%d learning_rate=%g max_depth=%d min_samples_leaf=%d subsample_ratio=%g\n",
+		best.NEstimators, best.LearningRate, best.MaxDepth, best.MinSamplesLeaf, best.SubsampleRatio)
+	fmt.Fprintf(stdout, "\nWrote %s and %s\n", *resultsPath, *modelPath)
+	return nil
+}
+
+// loadGrid reads a ParamGrid from a JSON or YAML file, rejecting unknown keys.
+func loadGrid(path string) (gboost.ParamGrid, error) {
+	var grid gboost.ParamGrid
+	err := gboost.DecodeFile(path, &grid)
+	return grid, err
+}
+
+// writeResults writes one CSV row per candidate, best first.
+func writeResults(path, metricName string, greaterIsBetter bool, res *gboost.SearchResult) error {
+	order := make([]int, len(res.Results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ma, mb := res.Results[order[a]].Mean, res.Results[order[b]].Mean
+		if greaterIsBetter {
+			return ma > mb
+		}
+		return ma < mb
+	})
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	header := []string{"rank", "n_estimators", "learning_rate", "max_depth", "min_samples_leaf", "subsample_ratio", metricName + "_mean", metricName + "_std"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for rank, i := range order {
+		r := res.Results[i]
+		c := r.Config
+		row := []string{
+			strconv.Itoa(rank + 1),
+			strconv.Itoa(c.NEstimators),
+			strconv.FormatFloat(c.LearningRate, 'g', -1, 64),
+			strconv.Itoa(c.MaxDepth),
+			strconv.Itoa(c.MinSamplesLeaf),
+			strconv.FormatFloat(c.SubsampleRatio, 'g', -1, 64),
+			strconv.FormatFloat(r.Mean, 'g', -1, 64),
+			strconv.FormatFloat(r.Std, 'g', -1, 64),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
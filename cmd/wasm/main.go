@@ -0,0 +1,85 @@
+//go:build js && wasm
+
+// Command wasm scores gboost models in the browser. It registers two
+// JavaScript functions on the global object:
+//
+//	gboostLoad(json)     parses a model saved by GBM.Save and returns its
+//	                     feature count.
+//	gboostPredict(rows)  scores an array of feature arrays and returns an
+//	                     array of predictions: P(y=1) for classifiers,
+//	                     expected values for the gamma loss, and raw values
+//	                     for other regression.
+//
+// On invalid input both return an Error object instead of throwing.
+//
+// Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o gboost.wasm ./cmd/wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .
+//
+// and load it from a page:
+//
+//	const go = new Go();
+//	const { instance } = await WebAssembly.instantiateStreaming(fetch("gboost.wasm"), go.importObject);
+//	go.run(instance);
+//	gboostLoad(await (await fetch("model.json")).text());
+//	console.log(gboostPredict([[5.1, 3.5, 1.4, 0.2]]));
+//
+// The same code builds with TinyGo (tinygo build -target wasm).
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/ahmedaabouzied/gboost/infer"
+)
+
+var model *infer.Model
+
+func main() {
+	js.Global().Set("gboostLoad", js.FuncOf(load))
+	js.Global().Set("gboostPredict", js.FuncOf(predict))
+	select {} // Keep the functions alive for the page's lifetime.
+}
+
+func load(_ js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return jsError("gboostLoad expects one argument: the model JSON")
+	}
+	m, err := infer.Parse([]byte(args[0].String()))
+	if err != nil {
+		return jsError(err.Error())
+	}
+	model = m
+	return m.NumFeatures()
+}
+
+func predict(_ js.Value, args []js.Value) any {
+	if model == nil {
+		return jsError("no model loaded; call gboostLoad first")
+	}
+	if len(args) != 1 {
+		return jsError("gboostPredict expects one argument: an array of rows")
+	}
+	rows := args[0]
+	out := make([]any, rows.Length())
+	x := make([]float64, model.NumFeatures())
+	for i := range out {
+		row := rows.Index(i)
+		if row.Length() != len(x) {
+			return jsError("row has the wrong number of features")
+		}
+		for j := range x {
+			x[j] = row.Index(j).Float()
+		}
+		out[i] = model.PredictValue(x)
+	}
+	return out
+}
+
+// jsError returns a JavaScript Error for the caller to check with
+// instanceof. Panicking inside a js.FuncOf callback would end the Go program
+// rather than throw.
+func jsError(msg string) any {
+	return js.Global().Get("Error").New(msg)
+}
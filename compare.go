@@ -0,0 +1,181 @@
+package gboost
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// ExplanationComparison summarizes how two models, typically a deployed
+// model and its retrain, attribute their predictions on the same rows. See
+// [CompareExplanations].
+type ExplanationComparison struct {
+	// Rows is the number of rows compared.
+	Rows int
+
+	// BaseValueA and BaseValueB are the models' [GBM.BaseValue]s.
+	BaseValueA float64
+	BaseValueB float64
+
+	// PredictionShift is the mean absolute difference between the models'
+	// raw predictions (log-odds for logloss).
+	PredictionShift float64
+
+	// Features holds one entry per feature, ordered by decreasing Shift, so
+	// the features whose role changed most come first.
+	Features []ContributionShift
+}
+
+// ContributionShift compares one feature's SHAP contributions under two
+// models. Contributions are in the models' raw output units.
+type ContributionShift struct {
+	// Feature is the column index, and Name the first model's name for it
+	// (f0, f1, ... for models without names).
+	Feature int
+	Name    string
+
+	// MeanAbsA and MeanAbsB are the feature's SHAP importance under each
+	// model: its mean absolute contribution, as by [GBM.ShapImportance].
+	MeanAbsA float64
+	MeanAbsB float64
+
+	// MeanA and MeanB are the mean signed contributions, showing whether the
+	// feature pushes predictions up or down on average.
+	MeanA float64
+	MeanB float64
+
+	// RankA and RankB are the feature's 1-based ranks by mean absolute
+	// contribution under each model, ties in column order.
+	RankA int
+	RankB int
+
+	// Shift is the mean absolute difference between the feature's
+	// contributions to the same row under the two models. It catches
+	// changes that leave the averages alone, such as a feature whose effect
+	// moved from one group of rows to another.
+	Shift float64
+
+	// Correlation is the Pearson correlation between the feature's
+	// contributions under the two models across rows. Near 1, the models
+	// use the feature the same way, whatever the scale; it is NaN when the
+	// contributions are constant under either model, such as a feature one
+	// of them never splits on.
+	Correlation float64
+
+	// SignFlips is the fraction of rows on which the feature's contribution
+	// is positive under one model and negative under the other.
+	SignFlips float64
+}
+
+// CompareExplanations explains X with both models and summarizes, per
+// feature, how the distribution of SHAP contributions shifted from a to b,
+// so that a reviewer can see whether a retrain still relies on the same
+// features in the same way before approving it. Both models must use the
+// same loss and features; feature names are taken from a.
+//
+// Returns [ErrModelNotFitted] if either model has not been trained,
+// [ErrIncompatibleModels] if the models differ in loss,
+// [ErrFeatureCountMismatch] if they differ in feature count or any row of X
+// does not match it, or [ErrEmptyDataset] if X is empty.
+func CompareExplanations(a, b *GBM, X [][]float64) (*ExplanationComparison, error) {
+	switch {
+	case !a.isFitted || !b.isFitted:
+		return nil, ErrModelNotFitted
+	case a.Config.Loss != b.Config.Loss:
+		return nil, ErrIncompatibleModels
+	case a.numFeatures != b.numFeatures:
+		return nil, ErrFeatureCountMismatch
+	case len(X) == 0:
+		return nil, ErrEmptyDataset
+	}
+	phiA, err := a.ShapValues(X)
+	if err != nil {
+		return nil, err
+	}
+	phiB, err := b.ShapValues(X)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ExplanationComparison{
+		Rows:       len(X),
+		BaseValueA: a.BaseValue(),
+		BaseValueB: b.BaseValue(),
+		Features:   make([]ContributionShift, a.numFeatures),
+	}
+	predA, predB := a.Predict(X), b.Predict(X)
+	diffs := make([]float64, len(X))
+	for i := range X {
+		diffs[i] = math.Abs(predA[i] - predB[i])
+	}
+	c.PredictionShift = mean(diffs)
+
+	names := a.names()
+	colA := make([]float64, len(X))
+	colB := make([]float64, len(X))
+	absA := make([]float64, a.numFeatures)
+	absB := make([]float64, a.numFeatures)
+	for j := range c.Features {
+		flips := 0
+		for i := range X {
+			colA[i], colB[i] = phiA[i][j], phiB[i][j]
+			diffs[i] = math.Abs(colA[i] - colB[i])
+			if colA[i]*colB[i] < 0 {
+				flips++
+			}
+		}
+		s := ContributionShift{
+			Feature:     j,
+			Name:        names[j],
+			MeanA:       mean(colA),
+			MeanB:       mean(colB),
+			Shift:       mean(diffs),
+			Correlation: pearson(colA, colB),
+			SignFlips:   float64(flips) / float64(len(X)),
+		}
+		for i := range X {
+			colA[i], colB[i] = math.Abs(colA[i]), math.Abs(colB[i])
+		}
+		s.MeanAbsA, s.MeanAbsB = mean(colA), mean(colB)
+		absA[j], absB[j] = s.MeanAbsA, s.MeanAbsB
+		c.Features[j] = s
+	}
+	rankA, rankB := ranks(absA), ranks(absB)
+	for j := range c.Features {
+		c.Features[j].RankA, c.Features[j].RankB = rankA[j], rankB[j]
+	}
+	slices.SortStableFunc(c.Features, func(x, y ContributionShift) int { return cmp.Compare(y.Shift, x.Shift) })
+	return c, nil
+}
+
+// ranks returns the 1-based rank of each value, largest first, with ties in
+// index order.
+func ranks(values []float64) []int {
+	order := make([]int, len(values))
+	for j := range order {
+		order[j] = j
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(values[b], values[a]) })
+	r := make([]int, len(values))
+	for k, j := range order {
+		r[j] = k + 1
+	}
+	return r
+}
+
+// pearson returns the Pearson correlation of x and y, or NaN if either is
+// constant.
+func pearson(x, y []float64) float64 {
+	mx, my := mean(x), mean(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
@@ -0,0 +1,93 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCompareExplanations(t *testing.T) {
+	fit := func(f func(x1, x2 float64) float64) *GBM {
+		t.Helper()
+		X, y := generateDataWithFunc(f)
+		cfg := DefaultConfig()
+		cfg.NEstimators = 20
+		gbm := New(cfg)
+		if err := gbm.Fit(X, y); err != nil {
+			t.Fatal(err)
+		}
+		return gbm
+	}
+	X, _ := generateDataWithFunc(linearFunc)
+	onX1 := fit(func(x1, x2 float64) float64 { return 3*x1 + 0.1*x2 })
+	onX2 := fit(func(x1, x2 float64) float64 { return 0.1*x1 - 3*x2 })
+
+	same, err := CompareExplanations(onX1, onX1, X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same.PredictionShift != 0 || same.Rows != len(X) {
+		t.Errorf("self-comparison: %+v", same)
+	}
+	for _, s := range same.Features {
+		if s.Shift != 0 || s.SignFlips != 0 || s.RankA != s.RankB || s.MeanAbsA != s.MeanAbsB {
+			t.Errorf("self-comparison of %s: %+v", s.Name, s)
+		}
+		if !math.IsNaN(s.Correlation) && math.Abs(s.Correlation-1) > 1e-9 {
+			t.Errorf("self-comparison of %s: correlation %v", s.Name, s.Correlation)
+		}
+	}
+
+	c, err := CompareExplanations(onX1, onX2, X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]ContributionShift{}
+	for k, s := range c.Features {
+		byName[s.Name] = s
+		if k > 0 && s.Shift > c.Features[k-1].Shift {
+			t.Errorf("features not sorted by shift: %+v", c.Features)
+		}
+	}
+	f0, f1 := byName["f0"], byName["f1"]
+	if f0.RankA != 1 || f0.RankB != 2 || f1.RankA != 2 || f1.RankB != 1 {
+		t.Errorf("ranks f0 %d→%d, f1 %d→%d, want the features to swap", f0.RankA, f0.RankB, f1.RankA, f1.RankB)
+	}
+	if f1.MeanAbsB < 10*f1.MeanAbsA || f1.Shift < f1.MeanAbsB/2 {
+		t.Errorf("f1 = %+v, want a large shift", f1)
+	}
+	if c.PredictionShift <= 0 || c.BaseValueA != onX1.BaseValue() || c.BaseValueB != onX2.BaseValue() {
+		t.Errorf("comparison = %+v", c)
+	}
+}
+
+func TestCompareExplanationsErrors(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	model := New(DefaultConfig())
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	classifier := fitBinaryModel(t, [][]float64{{0, 0}, {1, 1}}, []float64{0, 1})
+	wide := New(DefaultConfig())
+	if err := wide.Fit([][]float64{{1, 2, 3}, {4, 5, 6}}, []float64{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		a, b *GBM
+		X    [][]float64
+		want error
+	}{
+		{"unfitted", model, New(DefaultConfig()), X, ErrModelNotFitted},
+		{"loss", model, classifier, X, ErrIncompatibleModels},
+		{"feature count", model, wide, X, ErrFeatureCountMismatch},
+		{"row width", model, model, [][]float64{{1}}, ErrFeatureCountMismatch},
+		{"empty", model, model, nil, ErrEmptyDataset},
+	}
+	for _, tt := range tests {
+		if _, err := CompareExplanations(tt.a, tt.b, tt.X); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package gboost
 
+import "math"
+
 // Config controls the hyperparameters for training a [GBM] model.
 type Config struct {
 	// Seed for the random number generator used in subsampling.
-	// A fixed seed produces deterministic, reproducible models.
+	// A fixed seed produces deterministic, reproducible models. Each boosting
+	// round draws from its own stream derived from Seed, so round i samples
+	// the same rows regardless of what other rounds did. [Config.WithRNG]
+	// draws it from a caller's generator.
 	Seed int64
 
 	// NEstimators is the number of boosting rounds (trees) to build.
@@ -22,18 +27,214 @@ type Config struct {
 	// Higher values prevent the model from learning overly specific patterns.
 	MinSamplesLeaf int
 
+	// HierarchicalShrinkage is the strength lambda of per-leaf shrinkage toward
+	// ancestor values. After a tree is built, each node's value is replaced by
+	// its parent's value plus the raw change along the edge divided by
+	// 1 + lambda/N, where N is the parent's sample count. Leaves backed by few
+	// samples move toward their parents, which reduces the variance of deep
+	// trees. Zero disables shrinkage; must be >= 0.
+	HierarchicalShrinkage float64
+
+	// HonestFraction enables honest trees: each round's sampled rows are split
+	// at random, the tree structure is chosen on 1-HonestFraction of them, and
+	// leaf values are estimated from the held-out HonestFraction only. Leaves
+	// that receive no held-out rows take their nearest ancestor's estimate.
+	// This removes the optimism of leaf values fitted on the rows that chose
+	// the splits, at the cost of noisier trees, and is mainly useful when the
+	// leaf values themselves are interpreted, as in uplift models. Zero
+	// disables it; must be in [0, 1).
+	HonestFraction float64
+
 	// SubsampleRatio is the fraction of training samples used to build each tree.
 	// Values less than 1.0 enable stochastic gradient boosting, which can reduce overfitting.
 	// Must be in the range (0, 1].
 	SubsampleRatio float64
 
-	// Loss is the loss function name: "mse" for regression or "logloss" for binary classification.
+	// SamplingMethod selects how SubsampleRatio draws each round's rows:
+	//   - "shuffle" (or empty, the default): exactly SubsampleRatio·n rows
+	//     without replacement.
+	//   - "bernoulli": each row independently with probability SubsampleRatio,
+	//     so the sample size varies from round to round.
+	//   - "bootstrap": SubsampleRatio·n draws with replacement; a row drawn k
+	//     times enters the tree once with weight k. Unlike the other methods,
+	//     it resamples even when SubsampleRatio is 1.
+	// The rows a round does not draw are its out-of-bag rows: about
+	// 1-SubsampleRatio of them for "shuffle" and "bernoulli", and about
+	// exp(-SubsampleRatio), or 36.8% at 1, for "bootstrap". They are
+	// independent of that round's tree, which is what OOB estimates rely on;
+	// with SubsampleRatio at 1, "shuffle" and "bernoulli" leave none.
+	SamplingMethod string
+
+	// GOSSTopRate and GOSSOtherRate enable gradient-based one-side sampling:
+	// each round keeps the GOSSTopRate fraction of rows with the largest
+	// absolute gradients plus a random GOSSOtherRate fraction of the others,
+	// whose gradients and Hessians are scaled by (1-GOSSTopRate)/GOSSOtherRate.
+	// Both zero disables GOSS. When enabled, both must be in (0, 1) with a sum
+	// of at most 1, SubsampleRatio must be 1, SamplingMethod must not be
+	// "bootstrap", and NegativeSampleRatio must be unset.
+	GOSSTopRate   float64
+	GOSSOtherRate float64
+
+	// ColsampleByTree is the fraction of features each tree may split on,
+	// drawn independently per tree (at least one feature). It composes with
+	// any row sampling. Zero or 1.0 uses every feature.
+	ColsampleByTree float64
+
+	// NegativeSampleRatio is the fraction of negative (y == 0) samples kept in
+	// each boosting round when training with logloss on imbalanced data. All
+	// positives are kept, and the gradients and Hessians of the kept negatives
+	// are scaled by 1/NegativeSampleRatio so leaf values stay unbiased. It is
+	// applied after SubsampleRatio. Zero or 1.0 disables negative downsampling.
+	NegativeSampleRatio float64
+
+	// CostMatrix trains a logloss model toward misclassification cost rather
+	// than raw log loss. CostMatrix[i][j] is the cost of predicting class j
+	// for a sample of class i, so the diagonal holds the (usually zero) cost
+	// of correct predictions. Each sample's gradient and Hessian are weighted
+	// by the cost of misclassifying its class, CostMatrix[0][1]-CostMatrix[0][0]
+	// for negatives and CostMatrix[1][0]-CostMatrix[1][1] for positives,
+	// normalized to average 1. The initial prediction is corrected afterwards,
+	// so probabilities keep their meaning and [GBM.DecisionThreshold] returns
+	// the cost-minimizing threshold. Nil disables cost weighting. Only 2x2
+	// matrices are supported; entries must be finite and >= 0, with each
+	// misclassification costing more than the correct prediction. Labels
+	// must be hard 0s and 1s.
+	CostMatrix [][]float64
+
+	// Loss is the loss function name: "mse" for regression, "logloss" for
+	// binary classification, or "gamma" for positive, right-skewed targets
+	// such as claim amounts, fitted on a log scale (see [GammaLoss]).
 	Loss string
 
+	// MinHessian floors every Hessian before a tree is grown: the loss's in
+	// [GBM.Fit] and [GBM.FitStream], and the caller's in [GBM.BoostOneRound],
+	// where it also admits zero and negative Hessians instead of rejecting
+	// them. Leaf values sum(g)/sum(h) then stay bounded where the loss is
+	// flat, such as logloss on rows predicted with near certainty, or
+	// concave. Zero disables flooring; must be finite and >= 0.
+	MinHessian float64
+
+	// ValidationFraction is the fraction of the training rows held out, in a
+	// seeded random split, to monitor the loss for early stopping. Zero
+	// disables early stopping. Must be in [0, 1).
+	ValidationFraction float64
+
+	// Patience is the number of consecutive rounds the validation loss may
+	// fail to improve by more than MinDelta before training stops. Must be
+	// >= 1 when ValidationFraction > 0. The trees built after the last
+	// improving round are discarded.
+	Patience int
+
+	// MinDelta is the amount by which the validation loss must drop below
+	// its best value so far to count as an improvement. A small positive
+	// value keeps noisy validation losses from resetting Patience on
+	// meaningless gains. Must be >= 0.
+	MinDelta float64
+
+	// LRPatience enables reducing the learning rate on a plateau: after
+	// LRPatience consecutive rounds in which the validation loss fails to
+	// improve by more than MinDelta, later trees are added with their
+	// learning rate multiplied by LRFactor. It squeezes more out of a fixed
+	// NEstimators budget, typically with an LRPatience well below Patience
+	// so that training can recover before it stops. Zero disables it; when
+	// positive, ValidationFraction must be > 0.
+	LRPatience int
+
+	// LRFactor multiplies the learning rate at each plateau. Zero means 0.5;
+	// otherwise it must be in (0, 1).
+	LRFactor float64
+
+	// MinLearningRate is the floor below which plateaus no longer reduce
+	// the learning rate. Must be in [0, LearningRate].
+	MinLearningRate float64
+
+	// MinGainFraction stops training once a tree's total split gain falls
+	// below this fraction of the first tree's, a sign that the trees have run
+	// out of signal to fit. Unlike Patience it needs no validation set, and
+	// it applies with or without one. Zero disables it; must be in [0, 1).
+	MinGainFraction float64
+
+	// MaxSingleLeafTrees stops training after this many consecutive trees
+	// that found no split at all. Zero disables it; must be >= 0.
+	MaxSingleLeafTrees int
+
+	// SkipSingleLeafTrees leaves out of the model the trees of rounds that
+	// found no split at all. Such a tree only shifts every prediction by
+	// the same small amount, so it costs memory and prediction time for
+	// almost no change in fit. The rounds still count toward NEstimators
+	// and appear in [GBM.History] with Skipped set. Applies to [GBM.Fit].
+	SkipSingleLeafTrees bool
+
+	// SnapshotBlend averages the predictions of the last SnapshotBlend
+	// checkpoints of training, the models with T-SnapshotBlend+1 through T
+	// of the final T trees, which smooths out the last rounds' noise at no
+	// extra training or prediction cost. The average is folded into the
+	// trees when Fit or FitStream returns: a tree in k of the averaged
+	// checkpoints keeps k/SnapshotBlend of its value, so the model predicts,
+	// explains, and exports as usual. The weights are saved with the model
+	// and reported by [GBM.SnapshotWeights]. Values above T average all T
+	// checkpoints. Zero or 1 disables it; must be >= 0.
+	SnapshotBlend int
+
+	// DropRedundantFeatures excludes constant and exactly duplicated feature columns
+	// from split search. Excluded columns keep their position in the input, so
+	// prediction is unaffected, and they receive zero feature importance.
+	DropRedundantFeatures bool
+
+	// SplitThresholds restricts the thresholds of the features it lists to
+	// the given values, such as policy cutoffs like ages 18, 21, and 65, so
+	// that every split on them reads "feature < value". Features not listed
+	// split on their data as usual; an empty list keeps a feature from being
+	// split on at all. Keys must be valid feature indices and values finite.
+	SplitThresholds map[int][]float64
+
+	// SplitCriterion, if set, scores candidate splits in place of the
+	// variance reduction of the gradients; see [SplitCriterion] and the
+	// built-in [VarianceCriterion], [NewtonCriterion], and [GiniCriterion].
+	// It only affects training and is not saved with the model.
+	SplitCriterion SplitCriterion `json:"-"`
+
+	// LeafTransform, if set, post-processes the leaf values of every tree
+	// as it is built; see [LeafTransform] and the built-in [ClipLeaves] and
+	// [RoundLeaves]. Its String is saved with the model and reported by
+	// [GBM.LeafTransform]; the transform itself is not.
+	LeafTransform LeafTransform `json:"-"`
+
+	// SplitWorkers is the number of goroutines that search for each node's
+	// best split, each owning a disjoint block of the tree's features and
+	// proposing its best local split. The best proposal wins, so the trees
+	// are identical to a serial search. It pays off on wide data, with
+	// thousands of features; on narrow data the coordination costs more
+	// than it saves. Zero or 1 searches serially; must be >= 0.
+	SplitWorkers int
+
+	// KeepDiagnostics retains the final per-sample residuals and losses of
+	// the training rows, available from [GBM.TrainingDiagnostics] until the
+	// next Fit. It costs one prediction pass over the training data and two
+	// floats per row; the diagnostics are not saved with the model.
+	KeepDiagnostics bool
+
+	// TrackResources records each round's wall time, heap allocations, and
+	// peak extra heap in [RoundStats].Resources, to compare what settings
+	// such as SplitWorkers, DropRedundantFeatures, or GOSS cost on a given
+	// data shape. It reads runtime metrics twice per round and samples the
+	// heap every millisecond from a background goroutine.
+	TrackResources bool
+
+	// EvalSlices are data segments scored during training, each on its own
+	// schedule and with its own metrics; see [EvalSlice]. Names must be
+	// unique and non-empty. They do not affect the model.
+	EvalSlices []EvalSlice `json:"-"`
+
 	// OnRoundEnd is a callback to report how much progress we
 	// have made during training. It can be used by the library
 	// callers to track and report training progress.
 	OnRoundEnd func(round, total int) error `json:"-"`
+
+	// Tracker, if set, records each training run, its settings, per-round
+	// metrics, and the trained model in an experiment tracking system; see
+	// [Tracker].
+	Tracker Tracker `json:"-"`
 }
 
 func (c Config) validate() error {
@@ -46,14 +247,128 @@ func (c Config) validate() error {
 		return ErrInvalidMaxDepth
 	case c.MinSamplesLeaf < 1:
 		return ErrInvalidMinSamplesLeaf
+	case c.HierarchicalShrinkage < 0:
+		return ErrInvalidHierarchicalShrinkage
+	case c.HonestFraction < 0 || c.HonestFraction >= 1.0:
+		return ErrInvalidHonestFraction
 	case c.SubsampleRatio <= 0 || c.SubsampleRatio > 1.0:
 		return ErrInvalidSubsampleRatio
-	case c.Loss != "mse" && c.Loss != "logloss":
+	case c.Loss != "mse" && c.Loss != "logloss" && c.Loss != "gamma":
 		return ErrInvalidLoss
+	case !(c.MinHessian >= 0) || math.IsInf(c.MinHessian, 0):
+		return ErrInvalidMinHessian
+	case c.ValidationFraction < 0 || c.ValidationFraction >= 1.0:
+		return ErrInvalidValidationFraction
+	case c.Patience < 0 || (c.ValidationFraction > 0 && c.Patience < 1):
+		return ErrInvalidPatience
+	case c.MinDelta < 0:
+		return ErrInvalidMinDelta
+	case c.LRPatience < 0 || (c.LRPatience > 0 && c.ValidationFraction == 0):
+		return ErrInvalidLRSchedule
+	case c.MinGainFraction < 0 || c.MinGainFraction >= 1.0 || c.MaxSingleLeafTrees < 0:
+		return ErrInvalidGainStop
+	case c.LRFactor < 0 || c.LRFactor >= 1.0 || c.MinLearningRate < 0 || c.MinLearningRate > c.LearningRate:
+		return ErrInvalidLRSchedule
+	case c.SnapshotBlend < 0:
+		return ErrInvalidSnapshotBlend
+	case c.ColsampleByTree < 0 || c.ColsampleByTree > 1.0:
+		return ErrInvalidColsampleByTree
+	case c.NegativeSampleRatio < 0 || c.NegativeSampleRatio > 1.0:
+		return ErrInvalidNegativeSampleRatio
+	case c.NegativeSampleRatio > 0 && c.NegativeSampleRatio < 1.0 && c.Loss != "logloss":
+		return ErrInvalidNegativeSampleRatio
+	case (c.GOSSTopRate != 0 || c.GOSSOtherRate != 0) && !c.validGOSSRates():
+		return ErrInvalidGOSSRates
+	case c.SplitWorkers < 0:
+		return ErrInvalidSplitWorkers
+	case !validLeafTransform(c.LeafTransform):
+		return ErrInvalidLeafTransform
+	case !c.validSplitThresholds(math.MaxInt):
+		return ErrInvalidSplitThresholds
+	case c.SamplingMethod != "" && c.SamplingMethod != "shuffle" && c.SamplingMethod != "bernoulli" && c.SamplingMethod != "bootstrap":
+		return ErrInvalidSamplingMethod
+	case c.CostMatrix != nil && (c.Loss != "logloss" || !c.validCostMatrix()):
+		return ErrInvalidCostMatrix
+	case !c.validEvalSlices():
+		return ErrInvalidEvalSlices
+	case c.GOSSTopRate > 0 && (c.SubsampleRatio < 1.0 || c.SamplingMethod == "bootstrap" || (c.NegativeSampleRatio > 0 && c.NegativeSampleRatio < 1.0)):
+		return ErrGOSSWithSubsampling
 	}
 	return nil
 }
 
+func (c Config) validGOSSRates() bool {
+	a, b := c.GOSSTopRate, c.GOSSOtherRate
+	return a > 0 && a < 1.0 && b > 0 && b < 1.0 && a+b <= 1.0
+}
+
+func (c Config) validCostMatrix() bool {
+	m := c.CostMatrix
+	if len(m) != 2 || len(m[0]) != 2 || len(m[1]) != 2 {
+		return false
+	}
+	for _, row := range m {
+		for _, v := range row {
+			if v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+				return false
+			}
+		}
+	}
+	return m[0][1] > m[0][0] && m[1][0] > m[1][1]
+}
+
+// validSplitThresholds reports whether SplitThresholds lists only features
+// in [0, numFeatures) with finite thresholds.
+func (c Config) validSplitThresholds(numFeatures int) bool {
+	for j, thresholds := range c.SplitThresholds {
+		if j < 0 || j >= numFeatures {
+			return false
+		}
+		for _, t := range thresholds {
+			if math.IsInf(t, 0) || math.IsNaN(t) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// reducedLRScale returns the scale of the learning rate after a plateau,
+// given its current scale.
+func (c Config) reducedLRScale(scale float64) float64 {
+	factor := c.LRFactor
+	if factor == 0 {
+		factor = 0.5
+	}
+	return max(scale*factor, c.MinLearningRate/c.LearningRate)
+}
+
+// gainExhausted reports whether training should stop after a tree with the
+// given total gain, given the first tree's gain and the current run of
+// single-leaf trees.
+func (c Config) gainExhausted(gain, firstGain float64, singleLeaves int) bool {
+	switch {
+	case c.MaxSingleLeafTrees > 0 && singleLeaves >= c.MaxSingleLeafTrees:
+		return true
+	case c.MinGainFraction > 0 && gain < c.MinGainFraction*firstGain:
+		return true
+	}
+	return false
+}
+
+// classWeights returns the gradient weights of negatives and positives
+// implied by CostMatrix, normalized so that they average 1. Without a cost
+// matrix both are 1.
+func (c Config) classWeights() (w0, w1 float64) {
+	if c.CostMatrix == nil {
+		return 1, 1
+	}
+	w0 = c.CostMatrix[0][1] - c.CostMatrix[0][0]
+	w1 = c.CostMatrix[1][0] - c.CostMatrix[1][1]
+	total := w0 + w1
+	return 2 * w0 / total, 2 * w1 / total
+}
+
 // DefaultConfig returns a Config with sensible defaults for regression:
 // 100 trees, learning rate 0.1, max depth 6, no subsampling, MSE loss.
 func DefaultConfig() Config {
@@ -0,0 +1,257 @@
+package gboost
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// FeatureChange is one edit proposed by [GBM.Counterfactual].
+type FeatureChange struct {
+	FeatureIndex int
+	Feature      string // Name from [GBM.FeatureNames], or "f<j>".
+	From         float64
+	To           float64
+}
+
+// CounterfactualResult is a modified sample that the model assigns to the
+// requested class.
+type CounterfactualResult struct {
+	X           []float64       // The modified sample.
+	Changes     []FeatureChange // Edits applied to the original, by feature index.
+	Probability float64         // P(y=1) for X.
+}
+
+// Counterfactual searches for a small change to x, touching only the
+// features listed in mutableFeatures, that makes the model predict
+// targetClass: 1 when [GBM.PredictProba] is at or above
+// [GBM.DecisionThreshold], 0 below it.
+//
+// Candidate values come from the split thresholds in the trees: for each
+// threshold t on a mutable feature, the search tries t itself and the largest
+// value below t, the smallest edits that send x down the other branch.
+// Solutions changing a single feature are preferred; among them, the one
+// with the smallest change relative to the spread of that feature's
+// thresholds wins. Otherwise features are changed greedily, each time taking
+// the edit that moves the probability furthest toward targetClass, and edits
+// that turn out to be unnecessary are reverted at the end. Where no single
+// edit moves the probability at all, as when the model only responds to two
+// features changing together, the step changes the best pair of features.
+// The search is a heuristic: the result is a valid counterfactual but not
+// necessarily the globally smallest one.
+//
+// If x is already predicted as targetClass, the result has no changes.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained,
+// [ErrNotClassifier] if it was not trained with logloss,
+// [ErrFeatureCountMismatch] if len(x) does not match the number of features,
+// or [ErrNoCounterfactual] if no combination of the mutable features reaches
+// targetClass.
+func (g *GBM) Counterfactual(x []float64, targetClass int, mutableFeatures []int) (*CounterfactualResult, error) {
+	switch {
+	case !g.isFitted:
+		return nil, ErrModelNotFitted
+	case g.Config.Loss != "logloss":
+		return nil, ErrNotClassifier
+	case len(x) != g.numFeatures:
+		return nil, ErrFeatureCountMismatch
+	case targetClass != 0 && targetClass != 1:
+		return nil, fmt.Errorf("targetClass must be 0 or 1, got %d", targetClass)
+	}
+	for _, j := range mutableFeatures {
+		if j < 0 || j >= g.numFeatures {
+			return nil, fmt.Errorf("feature index %d out of range for %d features", j, g.numFeatures)
+		}
+	}
+
+	// score is the probability of targetClass; the target is reached where
+	// the model's decision threshold labels x as targetClass.
+	threshold := g.DecisionThreshold()
+	score := func(x []float64) float64 {
+		p := g.PredictProba(x)
+		if targetClass == 0 {
+			return 1 - p
+		}
+		return p
+	}
+	reached := func(x []float64) bool {
+		return (g.PredictProba(x) >= threshold) == (targetClass == 1)
+	}
+
+	cur := slices.Clone(x)
+	if reached(cur) {
+		return g.counterfactualResult(x, cur), nil
+	}
+
+	candidates, scales := g.counterfactualCandidates(x, mutableFeatures)
+	cost := func(j int, v float64) float64 {
+		return math.Abs(v-x[j]) / scales[j]
+	}
+	// Visit features in index order so ties resolve the same way every run.
+	features := slices.Sorted(maps.Keys(candidates))
+
+	// Single-feature edits.
+	bestJ, bestV, bestCost := -1, 0.0, math.Inf(1)
+	for _, j := range features {
+		for _, v := range candidates[j] {
+			c := cost(j, v)
+			if c >= bestCost {
+				continue
+			}
+			cur[j] = v
+			if reached(cur) {
+				bestJ, bestV, bestCost = j, v, c
+			}
+		}
+		cur[j] = x[j]
+	}
+	if bestJ >= 0 {
+		cur[bestJ] = bestV
+		return g.counterfactualResult(x, cur), nil
+	}
+
+	// Greedy multi-feature edits: change one more feature per step.
+	changed := make(map[int]bool)
+	for !reached(cur) {
+		curScore := score(cur)
+		stepJ, stepV, stepScore, stepCost := -1, 0.0, curScore, math.Inf(1)
+		for _, j := range features {
+			if changed[j] {
+				continue
+			}
+			for _, v := range candidates[j] {
+				cur[j] = v
+				s, c := score(cur), cost(j, v)
+				if s > stepScore || (s == stepScore && s > curScore && c < stepCost) {
+					stepJ, stepV, stepScore, stepCost = j, v, s, c
+				}
+			}
+			cur[j] = x[j]
+		}
+		if stepJ < 0 {
+			j, v, k, w, ok := counterfactualPair(cur, x, features, changed, candidates, score, cost)
+			if !ok {
+				return nil, ErrNoCounterfactual
+			}
+			cur[j], cur[k] = v, w
+			changed[j], changed[k] = true, true
+			continue
+		}
+		cur[stepJ] = stepV
+		changed[stepJ] = true
+	}
+
+	// Revert edits the solution does not need, in feature order for
+	// reproducibility.
+	for j := range g.numFeatures {
+		if !changed[j] {
+			continue
+		}
+		v := cur[j]
+		cur[j] = x[j]
+		if !reached(cur) {
+			cur[j] = v
+		}
+	}
+	return g.counterfactualResult(x, cur), nil
+}
+
+// counterfactualPair returns the pair of edits to unchanged features, j to
+// v and k to w, that moves score furthest above its value for cur, breaking
+// ties by the smaller combined cost. ok is false if no pair improves the
+// score. cur holds x's values for every unchanged feature and is restored
+// before returning.
+func counterfactualPair(cur, x []float64, features []int, changed map[int]bool, candidates map[int][]float64,
+	score func([]float64) float64, cost func(int, float64) float64) (j int, v float64, k int, w float64, ok bool) {
+	curScore := score(cur)
+	bestScore, bestCost := curScore, math.Inf(1)
+	for a, fj := range features {
+		if changed[fj] {
+			continue
+		}
+		for _, fk := range features[a+1:] {
+			if changed[fk] {
+				continue
+			}
+			for _, vj := range candidates[fj] {
+				cur[fj] = vj
+				for _, vk := range candidates[fk] {
+					cur[fk] = vk
+					s, c := score(cur), cost(fj, vj)+cost(fk, vk)
+					if s > bestScore || (s == bestScore && s > curScore && c < bestCost) {
+						j, v, k, w, ok = fj, vj, fk, vk, true
+						bestScore, bestCost = s, c
+					}
+				}
+				cur[fk] = x[fk]
+			}
+			cur[fj] = x[fj]
+		}
+	}
+	return j, v, k, w, ok
+}
+
+// counterfactualCandidates returns, for each distinct mutable feature, the
+// threshold-crossing values to try, and a per-feature scale (the spread of
+// its thresholds, or 1) used to compare edits across features.
+func (g *GBM) counterfactualCandidates(x []float64, mutableFeatures []int) (map[int][]float64, map[int]float64) {
+	thresholds := make(map[int][]float64)
+	for _, j := range mutableFeatures {
+		thresholds[j] = nil
+	}
+	for _, tree := range g.trees {
+		collectThresholds(tree, thresholds)
+	}
+
+	candidates := make(map[int][]float64, len(thresholds))
+	scales := make(map[int]float64, len(thresholds))
+	for j, ts := range thresholds {
+		slices.Sort(ts)
+		ts = slices.Compact(ts)
+
+		var values []float64
+		for _, t := range ts {
+			for _, v := range []float64{t, math.Nextafter(t, math.Inf(-1))} {
+				if v != x[j] {
+					values = append(values, v)
+				}
+			}
+		}
+		candidates[j] = values
+
+		scales[j] = 1
+		if len(ts) > 1 {
+			scales[j] = ts[len(ts)-1] - ts[0]
+		}
+	}
+	return candidates, scales
+}
+
+// collectThresholds appends the split thresholds of n to thresholds for every
+// feature that already has an entry in the map.
+func collectThresholds(n *Node, thresholds map[int][]float64) {
+	if n.isLeaf() {
+		return
+	}
+	if ts, ok := thresholds[n.FeatureIndex]; ok {
+		thresholds[n.FeatureIndex] = append(ts, n.Threshold)
+	}
+	collectThresholds(n.Left, thresholds)
+	collectThresholds(n.Right, thresholds)
+}
+
+func (g *GBM) counterfactualResult(original, modified []float64) *CounterfactualResult {
+	res := &CounterfactualResult{X: modified, Probability: g.PredictProba(modified)}
+	for j := range original {
+		if modified[j] != original[j] {
+			res.Changes = append(res.Changes, FeatureChange{
+				FeatureIndex: j,
+				Feature:      g.featureName(j),
+				From:         original[j],
+				To:           modified[j],
+			})
+		}
+	}
+	return res
+}
@@ -0,0 +1,165 @@
+package gboost
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func fitBinaryModel(t *testing.T, X [][]float64, y []float64) *GBM {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 30
+	cfg.MaxDepth = 3
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	return gbm
+}
+
+func TestCounterfactualSingleFeature(t *testing.T) {
+	X, y := generateBinaryData(5)
+	gbm := fitBinaryModel(t, X, y)
+
+	x := []float64{2, 5}
+	if gbm.PredictProba(x) >= 0.5 {
+		t.Fatalf("setup: expected class 0 for %v", x)
+	}
+
+	res, err := gbm.Counterfactual(x, 1, []int{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Probability < 0.5 {
+		t.Errorf("counterfactual probability %v, want >= 0.5", res.Probability)
+	}
+	if len(res.Changes) != 1 || res.Changes[0].FeatureIndex != 0 {
+		t.Fatalf("expected a single change to f0, got %+v", res.Changes)
+	}
+	c := res.Changes[0]
+	if c.From != 2 || c.To < 4 || c.To > 6 {
+		t.Errorf("expected f0 moved from 2 to about 5, got %+v", c)
+	}
+	if x[0] != 2 {
+		t.Errorf("input modified: %v", x)
+	}
+}
+
+func TestCounterfactualMultipleFeatures(t *testing.T) {
+	// Class 1 requires both features to be high.
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 300)
+	y := make([]float64, 300)
+	for i := range X {
+		X[i] = []float64{rnd.Float64() * 10, rnd.Float64() * 10}
+		if X[i][0] > 5 && X[i][1] > 5 {
+			y[i] = 1
+		}
+	}
+	gbm := fitBinaryModel(t, X, y)
+
+	res, err := gbm.Counterfactual([]float64{2, 2}, 1, []int{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Changes) != 2 {
+		t.Errorf("expected both features to change, got %+v", res.Changes)
+	}
+	if res.Probability < 0.5 {
+		t.Errorf("counterfactual probability %v, want >= 0.5", res.Probability)
+	}
+
+	// Only one feature may change, which cannot reach class 1.
+	if _, err := gbm.Counterfactual([]float64{2, 2}, 1, []int{1}); !errors.Is(err, ErrNoCounterfactual) {
+		t.Errorf("expected ErrNoCounterfactual, got %v", err)
+	}
+}
+
+func TestCounterfactualAlreadyTarget(t *testing.T) {
+	X, y := generateBinaryData(5)
+	gbm := fitBinaryModel(t, X, y)
+
+	res, err := gbm.Counterfactual([]float64{2, 5}, 0, []int{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Changes) != 0 {
+		t.Errorf("expected no changes, got %+v", res.Changes)
+	}
+}
+
+func TestCounterfactualDecisionThreshold(t *testing.T) {
+	// P(y=1) rises smoothly with f0, so some samples fall between a
+	// cost-sensitive threshold and 0.5.
+	rnd := rand.New(rand.NewSource(1))
+	X := make([][]float64, 400)
+	y := make([]float64, 400)
+	for i := range X {
+		X[i] = []float64{rnd.Float64() * 10, rnd.Float64() * 10}
+		if rnd.Float64() < X[i][0]/10 {
+			y[i] = 1
+		}
+	}
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 30
+	cfg.MaxDepth = 2
+	cfg.CostMatrix = [][]float64{{0, 1}, {4, 0}}
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	threshold := gbm.DecisionThreshold()
+	if threshold >= 0.5 {
+		t.Fatalf("setup: threshold %v, want below 0.5", threshold)
+	}
+
+	var x []float64
+	for v := 0.0; v < 10 && x == nil; v += 0.05 {
+		if p := gbm.PredictProba([]float64{v, 5}); p >= threshold && p < 0.5 {
+			x = []float64{v, 5}
+		}
+	}
+	if x == nil {
+		t.Fatal("setup: no sample between the threshold and 0.5")
+	}
+
+	// Labeled 1 at the model's threshold, though below 0.5.
+	res, err := gbm.Counterfactual(x, 1, []int{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Changes) != 0 {
+		t.Errorf("expected no changes for a sample already labeled 1, got %+v", res.Changes)
+	}
+	res, err = gbm.Counterfactual(x, 0, []int{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Changes) == 0 || res.Probability >= threshold {
+		t.Errorf("counterfactual probability %v with changes %+v, want below %v", res.Probability, res.Changes, threshold)
+	}
+}
+
+func TestCounterfactualErrors(t *testing.T) {
+	X, y := generateBinaryData(5)
+	gbm := fitBinaryModel(t, X, y)
+
+	if _, err := New(DefaultConfig()).Counterfactual([]float64{1, 1}, 1, []int{0}); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+	if _, err := fitStumpModel(t).Counterfactual([]float64{1}, 1, []int{0}); !errors.Is(err, ErrNotClassifier) {
+		t.Errorf("expected ErrNotClassifier, got %v", err)
+	}
+	if _, err := gbm.Counterfactual([]float64{1}, 1, []int{0}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+	if _, err := gbm.Counterfactual([]float64{1, 1}, 2, []int{0}); err == nil {
+		t.Error("expected error for invalid target class")
+	}
+	if _, err := gbm.Counterfactual([]float64{1, 1}, 1, []int{2}); err == nil {
+		t.Error("expected error for out-of-range feature")
+	}
+}
@@ -0,0 +1,124 @@
+package gboost
+
+import (
+	"maps"
+	"slices"
+)
+
+// SplitCriterion scores the candidate splits of a node during tree
+// building. The split with the highest gain is made, and only if its gain is
+// positive, so a criterion can veto a split by returning 0 or less.
+//
+// Set Config.SplitCriterion to experiment with a criterion without changing
+// the tree engine: which rows reach each candidate side, the stopping rules,
+// and the Newton leaf values stay the same. The gain is stored as
+// [Node.Gain], and gain importance weights it by [Node.NSamples], so
+// criteria should return a gain per row, as the built-in ones do.
+//
+// Gain is called from several goroutines at once with SplitWorkers above 1.
+type SplitCriterion interface {
+	Gain(parent, left, right NodeRows) float64
+}
+
+// NodeRows holds the training rows of a node, or of one side of a candidate
+// split, in the same order in all three slices.
+type NodeRows struct {
+	// Indices are the rows' indices in the X being trained on: the X passed
+	// to [GBM.Fit], [GBM.BoostOneRound], or the current [Batch].
+	Indices []int
+
+	// Gradients and Hessians are the rows' weighted negative gradients and
+	// Hessians under the Config's loss, the targets the tree is fitted to.
+	Gradients []float64
+	Hessians  []float64
+}
+
+func nodeRows(gradients, hessians []float64, indices []int) NodeRows {
+	return NodeRows{
+		Indices:   indices,
+		Gradients: extractRows(gradients, indices),
+		Hessians:  extractRows(hessians, indices),
+	}
+}
+
+// VarianceCriterion is the default criterion: the reduction in the variance
+// of the negative gradients, weighting each side by its share of the rows.
+// A nil Config.SplitCriterion grows the same trees.
+type VarianceCriterion struct{}
+
+// Gain returns the variance reduction of the split.
+func (VarianceCriterion) Gain(parent, left, right NodeRows) float64 {
+	return varianceReduction(variance(parent.Gradients), left.Gradients, right.Gradients)
+}
+
+// varianceReduction returns parentVariance minus the row-weighted variances
+// of left and right.
+func varianceReduction(parentVariance float64, left, right []float64) float64 {
+	n := float64(len(left) + len(right))
+	weightedChildVariance := float64((float64(len(left))/n)*variance(left)) +
+		float64((float64(len(right))/n)*variance(right))
+	return parentVariance - weightedChildVariance
+}
+
+// NewtonCriterion is the second-order gain used by XGBoost and LightGBM:
+//
+//	(G_L²/(H_L+λ) + G_R²/(H_R+λ) - G²/(H+λ)) / 2
+//
+// where G and H are sums of gradients and Hessians, divided by the number of
+// rows to give a gain per row. It scores a split by the loss reduction of
+// the Newton leaf values it would fit, so, unlike variance, it accounts for
+// the Hessians of logloss. A side whose H+λ is not positive scores 0.
+type NewtonCriterion struct {
+	// Lambda is the L2 penalty λ on leaf values. It damps splits that would
+	// isolate rows with small Hessians. It should be >= 0.
+	Lambda float64
+}
+
+// Gain returns the Newton loss reduction of the split, per row.
+func (c NewtonCriterion) Gain(parent, left, right NodeRows) float64 {
+	gain := c.score(left) + c.score(right) - c.score(parent)
+	return gain / float64(2*len(parent.Indices))
+}
+
+func (c NewtonCriterion) score(r NodeRows) float64 {
+	h := sum(r.Hessians) + c.Lambda
+	if h <= 0 {
+		return 0
+	}
+	g := sum(r.Gradients)
+	return float64(g*g) / h
+}
+
+// GiniCriterion is the CART classification criterion: the decrease in the
+// Gini impurity 1 - Σ p_k² of the class labels, weighting each side by its
+// share of the rows. The gradients cannot recover the labels, so they are
+// looked up by row index. Sample weights do not enter the impurity, and
+// leaf values are still fitted by Newton steps.
+type GiniCriterion struct {
+	// Labels holds the class of every row of the X being trained on, such as
+	// the y passed to [GBM.Fit] for binary classification.
+	Labels []float64
+}
+
+// Gain returns the Gini impurity decrease of the split.
+func (c GiniCriterion) Gain(parent, left, right NodeRows) float64 {
+	n := float64(len(parent.Indices))
+	return c.impurity(parent.Indices) -
+		float64(float64(len(left.Indices))/n*c.impurity(left.Indices)) -
+		float64(float64(len(right.Indices))/n*c.impurity(right.Indices))
+}
+
+func (c GiniCriterion) impurity(indices []int) float64 {
+	counts := make(map[float64]int)
+	for _, i := range indices {
+		counts[c.Labels[i]]++
+	}
+	// Classes are summed in order so the impurity does not depend on map
+	// iteration order.
+	impurity := 1.0
+	for _, class := range slices.Sorted(maps.Keys(counts)) {
+		p := float64(counts[class]) / float64(len(indices))
+		impurity -= float64(p * p)
+	}
+	return impurity
+}
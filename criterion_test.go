@@ -0,0 +1,132 @@
+package gboost
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVarianceCriterionMatchesDefault(t *testing.T) {
+	X, y := generateNoisyData()
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	base := New(cfg)
+	if err := base.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.SplitCriterion = VarianceCriterion{}
+	withCriterion := New(cfg)
+	if err := withCriterion.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := base.Fingerprint()
+	if got, _ := withCriterion.Fingerprint(); got != want {
+		t.Errorf("VarianceCriterion grew a different model than the default")
+	}
+}
+
+func TestNewtonCriterionGain(t *testing.T) {
+	g := []float64{1, 2, -1, -2}
+	h := []float64{1, 1, 2, 2}
+	rows := func(idx ...int) NodeRows { return nodeRows(g, h, idx) }
+
+	// G_L = 3, H_L = 2, G_R = -3, H_R = 4, G = 0, H = 6.
+	got := NewtonCriterion{Lambda: 1}.Gain(rows(0, 1, 2, 3), rows(0, 1), rows(2, 3))
+	want := (9.0/3 + 9.0/5 - 0) / 8
+	if math.Abs(got-want) > 1e-12 {
+		t.Errorf("Gain = %v, want %v", got, want)
+	}
+
+	// A side without Hessian mass scores 0 instead of dividing by zero.
+	got = NewtonCriterion{}.Gain(nodeRows(g, []float64{0, 0, 1, 1}, []int{0, 1, 2, 3}), nodeRows(g, []float64{0, 0}, []int{0, 1}), rows(2, 3))
+	if math.IsInf(got, 0) || math.IsNaN(got) {
+		t.Errorf("Gain with zero Hessians = %v", got)
+	}
+}
+
+func TestGiniCriterionGain(t *testing.T) {
+	c := GiniCriterion{Labels: []float64{0, 0, 1, 1}}
+	rows := func(idx ...int) NodeRows { return NodeRows{Indices: idx} }
+
+	if got := c.Gain(rows(0, 1, 2, 3), rows(0, 1), rows(2, 3)); got != 0.5 {
+		t.Errorf("pure split: Gain = %v, want 0.5", got)
+	}
+	if got := c.Gain(rows(0, 1, 2, 3), rows(0, 2), rows(1, 3)); got != 0 {
+		t.Errorf("uninformative split: Gain = %v, want 0", got)
+	}
+}
+
+func TestSplitCriterionTraining(t *testing.T) {
+	X, y := generateBinaryData(5)
+	for name, criterion := range map[string]SplitCriterion{
+		"newton": NewtonCriterion{Lambda: 1},
+		"gini":   GiniCriterion{Labels: y},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Loss = "logloss"
+			cfg.NEstimators = 20
+			cfg.MaxDepth = 3
+			cfg.SplitCriterion = criterion
+			serial := New(cfg)
+			if err := serial.Fit(X, y); err != nil {
+				t.Fatal(err)
+			}
+
+			correct := 0
+			for i, p := range serial.PredictProbaAll(X) {
+				if (p >= 0.5) == (y[i] == 1) {
+					correct++
+				}
+			}
+			if acc := float64(correct) / float64(len(y)); acc < 0.95 {
+				t.Errorf("training accuracy = %v", acc)
+			}
+
+			cfg.SplitWorkers = 2
+			parallel := New(cfg)
+			if err := parallel.Fit(X, y); err != nil {
+				t.Fatal(err)
+			}
+			want, _ := serial.Fingerprint()
+			if got, _ := parallel.Fingerprint(); got != want {
+				t.Errorf("SplitWorkers changed the model")
+			}
+		})
+	}
+}
+
+// vetoCriterion is a user-defined criterion that scores variance reduction
+// but refuses splits leaving fewer than min rows on a side.
+type vetoCriterion struct{ min int }
+
+func (c vetoCriterion) Gain(parent, left, right NodeRows) float64 {
+	if len(left.Indices) < c.min || len(right.Indices) < c.min {
+		return 0
+	}
+	return VarianceCriterion{}.Gain(parent, left, right)
+}
+
+func TestCustomSplitCriterion(t *testing.T) {
+	X, y := generateNoisyData()
+	cfg := DefaultConfig()
+	cfg.NEstimators = 5
+	cfg.SplitCriterion = vetoCriterion{min: 15}
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range gbm.NumTrees() {
+		tree, _ := gbm.Tree(i)
+		walkSplits(tree, func(n *Node) {
+			if n.Left.NSamples < 15 || n.Right.NSamples < 15 {
+				t.Errorf("tree %d splits %d rows into %d and %d", i, n.NSamples, n.Left.NSamples, n.Right.NSamples)
+			}
+		})
+	}
+	if gbm.FeatureImportance()[0] == 0 {
+		t.Error("no splits were made")
+	}
+}
@@ -0,0 +1,603 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// Metric scores model output against true targets. For regression models it
+// receives raw predictions; for classification (Loss="logloss") it receives
+// P(y=1) probabilities, and for Loss="gamma" expected values. The functions
+// in the metrics subpackage satisfy it.
+type Metric func(y, pred []float64) float64
+
+// CVOptions controls [CrossValidate] and [GridSearch].
+type CVOptions struct {
+	// Folds is the number of cross-validation folds. Must be >= 2 and no larger
+	// than the number of samples.
+	Folds int
+
+	// Metric scores each held-out fold.
+	Metric Metric
+
+	// GreaterIsBetter reports whether higher Metric values are better
+	// (e.g. AUC, accuracy) or worse (e.g. RMSE, log loss). Used by GridSearch
+	// to pick the best candidate.
+	GreaterIsBetter bool
+
+	// Seed is the master seed. It determines the fold assignment, and each
+	// fold's model is trained with a seed derived deterministically from it,
+	// so results do not depend on the order in which folds finish.
+	Seed int64
+
+	// Workers bounds the number of models trained concurrently.
+	// Zero means runtime.GOMAXPROCS(0). Config.OnRoundEnd, if set, must be
+	// safe for concurrent use when Workers != 1.
+	Workers int
+}
+
+func (o CVOptions) validate(n int) error {
+	switch {
+	case o.Folds < 2 || o.Folds > n:
+		return ErrInvalidFolds
+	case o.Metric == nil:
+		return ErrNilMetric
+	case o.Workers < 0:
+		return ErrInvalidWorkers
+	}
+	return nil
+}
+
+func (o CVOptions) workers() int {
+	if o.Workers == 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.Workers
+}
+
+// Fold holds the row indices of one cross-validation split.
+type Fold struct {
+	Train []int `json:"train"`
+	Test  []int `json:"test"`
+}
+
+// Split returns the rows of X and y that f assigns to training and testing,
+// in the order of f.Train and f.Test. Rows are shared with X, not copied.
+//
+// Returns [ErrLengthMismatch] if X and y differ in length, or
+// [ErrInvalidFold] if f is not a valid split of len(X) rows.
+func (f Fold) Split(X [][]float64, y []float64) (XTrain, XTest [][]float64, yTrain, yTest []float64, err error) {
+	if len(X) != len(y) {
+		return nil, nil, nil, nil, ErrLengthMismatch
+	}
+	if err := f.validate(len(X)); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return extractRows(X, f.Train), extractRows(X, f.Test), extractRows(y, f.Train), extractRows(y, f.Test), nil
+}
+
+// validate checks that f has non-empty train and test sets of distinct
+// indices in [0, n). A negative n skips the upper bound.
+func (f Fold) validate(n int) error {
+	if len(f.Train) == 0 || len(f.Test) == 0 {
+		return ErrInvalidFold
+	}
+	seen := make(map[int]bool, len(f.Train)+len(f.Test))
+	for _, i := range slices.Concat(f.Train, f.Test) {
+		if i < 0 || (n >= 0 && i >= n) || seen[i] {
+			return ErrInvalidFold
+		}
+		seen[i] = true
+	}
+	return nil
+}
+
+// KFold shuffles the indices 0..n-1 with the given seed and partitions them
+// into k folds of nearly equal size. Fold i uses the i-th partition as its
+// test set and all remaining indices as its training set.
+func KFold(n, k int, seed int64) ([]Fold, error) {
+	if k < 2 || k > n {
+		return nil, ErrInvalidFolds
+	}
+
+	perm := newRNG(seed).Perm(n)
+
+	folds := make([]Fold, k)
+	start := 0
+	for i := range folds {
+		size := n / k
+		if i < n%k {
+			size++
+		}
+		end := start + size
+
+		test := make([]int, size)
+		copy(test, perm[start:end])
+		train := make([]int, 0, n-size)
+		train = append(train, perm[:start]...)
+		train = append(train, perm[end:]...)
+
+		folds[i] = Fold{Train: train, Test: test}
+		start = end
+	}
+	return folds, nil
+}
+
+// foldsFile is the JSON layout written by [SaveFolds]:
+//
+//	{"folds": [{"train": [...], "test": [...]}, ...]}
+//
+// Indices are 0-based row numbers, so a Python pipeline can apply them
+// directly with numpy or pandas' iloc.
+type foldsFile struct {
+	Folds []Fold `json:"folds"`
+}
+
+// SaveFolds writes folds, from [KFold] or [TrainTestSplitIndices], to a JSON
+// file at path, so the same split can be reproduced later with [LoadFolds]
+// or shared with other tools. Returns [ErrInvalidFold] if a fold is invalid.
+func SaveFolds(path string, folds ...Fold) error {
+	for _, f := range folds {
+		if err := f.validate(-1); err != nil {
+			return err
+		}
+	}
+	return writeJSON(path, foldsFile{Folds: folds})
+}
+
+// LoadFolds reads folds previously written by [SaveFolds]. Returns
+// [ErrInvalidFold] if a fold has an empty side, a negative index, or an
+// index listed twice; bounds are checked when a fold is applied with
+// [Fold.Split].
+func LoadFolds(path string) ([]Fold, error) {
+	var file foldsFile
+	if err := readJSON(path, &file); err != nil {
+		return nil, err
+	}
+	for _, f := range file.Folds {
+		if err := f.validate(-1); err != nil {
+			return nil, err
+		}
+	}
+	return file.Folds, nil
+}
+
+// CVResult holds the cross-validated scores of a single configuration.
+type CVResult struct {
+	Config Config
+	Scores []float64 // one score per fold, in fold order
+	Mean   float64
+	Std    float64
+}
+
+// SearchResult holds the outcome of a [GridSearch].
+type SearchResult struct {
+	Results []CVResult // one result per candidate, in input order
+	Best    int        // index into Results of the best-scoring candidate
+}
+
+// BestConfig returns the configuration with the best mean score.
+func (r *SearchResult) BestConfig() Config {
+	return r.Results[r.Best].Config
+}
+
+// CrossValidate estimates the generalization score of cfg with k-fold
+// cross-validation. Folds are trained concurrently on up to opts.Workers
+// goroutines; fold assignment and per-fold model seeds derive from opts.Seed,
+// so the result is identical regardless of scheduling.
+//
+// Returns a validation error for invalid options or data, or the first error
+// returned while fitting a fold.
+func CrossValidate(cfg Config, X [][]float64, y []float64, opts CVOptions) (*CVResult, error) {
+	res, err := GridSearch([]Config{cfg}, X, y, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &res.Results[0], nil
+}
+
+// RepeatedCVResult holds the scores of [RepeatedCV].
+type RepeatedCVResult struct {
+	// CVResult holds every fold's score, repeat by repeat, with their mean
+	// and standard deviation.
+	CVResult
+
+	// RepeatMeans holds the mean score of each repeat. Their spread shows
+	// how much a single k-fold estimate depends on the shuffle.
+	RepeatMeans []float64
+
+	// Folds is the number of folds per repeat.
+	Folds int
+}
+
+// RepeatedCV runs k-fold cross-validation of cfg opts.Folds at a time,
+// repeats times over different shuffles, so that the mean score does not
+// hinge on one fold assignment and comes with an uncertainty estimate; see
+// [RepeatedCVResult.ConfidenceInterval]. Repeat r is the k-fold
+// [CrossValidate] would run with opts.Seed replaced by a seed derived from
+// opts.Seed and r. All repeats × folds models share one pool of
+// opts.Workers goroutines, and the result does not depend on scheduling.
+//
+// Returns [ErrInvalidRepeats] if repeats < 1, a validation error for
+// invalid options or data, or the first error returned while fitting a
+// fold.
+func RepeatedCV(cfg Config, X [][]float64, y []float64, repeats int, opts CVOptions) (*RepeatedCVResult, error) {
+	if repeats < 1 {
+		return nil, ErrInvalidRepeats
+	}
+	if len(X) != len(y) {
+		return nil, ErrLengthMismatch
+	}
+	if err := opts.validate(len(X)); err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]foldJob, 0, repeats*opts.Folds)
+	for r := range repeats {
+		seed := deriveSeed(opts.Seed, r)
+		folds, err := KFold(len(X), opts.Folds, seed)
+		if err != nil {
+			return nil, err
+		}
+		for f, fold := range folds {
+			foldCfg := cfg
+			foldCfg.Seed = deriveSeed(seed, f)
+			jobs = append(jobs, foldJob{cfg: foldCfg, fold: fold})
+		}
+	}
+	scores, err := scoreFolds(jobs, X, y, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RepeatedCVResult{
+		CVResult: CVResult{
+			Config: cfg,
+			Scores: scores,
+			Mean:   mean(scores),
+			Std:    math.Sqrt(variance(scores)),
+		},
+		RepeatMeans: make([]float64, repeats),
+		Folds:       opts.Folds,
+	}
+	for r := range repeats {
+		res.RepeatMeans[r] = mean(scores[r*opts.Folds : (r+1)*opts.Folds])
+	}
+	return res, nil
+}
+
+// ConfidenceInterval returns a two-sided confidence interval at level, such
+// as 0.95, for the score a model trained on cfg is expected to get. Fold
+// scores share training rows, so a plain t interval over them would be too
+// narrow; it uses the corrected resampled t statistic of Nadeau and Bengio
+// (2003) instead, which inflates the variance of the mean of the J = Folds ×
+// repeats scores to (1/J + 1/(Folds-1)) times their sample variance, with
+// J-1 degrees of freedom. The interval is conservative rather than exact.
+//
+// Both bounds are NaN if level is not in (0, 1).
+func (r *RepeatedCVResult) ConfidenceInterval(level float64) (low, high float64) {
+	if !(level > 0 && level < 1) {
+		return math.NaN(), math.NaN()
+	}
+	j := float64(len(r.Scores))
+	// variance is the population variance; rescale to the sample variance.
+	sampleVariance := variance(r.Scores) * j / (j - 1)
+	halfWidth := studentTQuantile((1+level)/2, j-1) *
+		math.Sqrt((1/j+1/float64(r.Folds-1))*sampleVariance)
+	return r.Mean - halfWidth, r.Mean + halfWidth
+}
+
+// GridSearch cross-validates every candidate configuration and reports which
+// one scored best. All candidates share the same folds, and the whole
+// candidates × folds workload runs on a single pool of opts.Workers goroutines,
+// so a 5-fold × 50-candidate search keeps every core busy.
+//
+// Use [ParamGrid.Expand] to build the candidates from value lists.
+func GridSearch(candidates []Config, X [][]float64, y []float64, opts CVOptions) (*SearchResult, error) {
+	if len(candidates) == 0 {
+		return nil, ErrEmptyGrid
+	}
+	if len(X) != len(y) {
+		return nil, ErrLengthMismatch
+	}
+	if err := opts.validate(len(X)); err != nil {
+		return nil, err
+	}
+	for _, cfg := range candidates {
+		if err := cfg.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	folds, err := KFold(len(X), opts.Folds, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]foldJob, 0, len(candidates)*len(folds))
+	for _, cfg := range candidates {
+		for f, fold := range folds {
+			cfg.Seed = deriveSeed(opts.Seed, f)
+			jobs = append(jobs, foldJob{cfg: cfg, fold: fold})
+		}
+	}
+	scores, err := scoreFolds(jobs, X, y, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CVResult, len(candidates))
+	for c, cfg := range candidates {
+		results[c] = CVResult{Config: cfg, Scores: scores[c*len(folds) : (c+1)*len(folds) : (c+1)*len(folds)]}
+	}
+
+	best := 0
+	for c := range results {
+		results[c].Mean = mean(results[c].Scores)
+		results[c].Std = math.Sqrt(variance(results[c].Scores))
+
+		better := results[c].Mean < results[best].Mean
+		if opts.GreaterIsBetter {
+			better = results[c].Mean > results[best].Mean
+		}
+		if better {
+			best = c
+		}
+	}
+
+	return &SearchResult{Results: results, Best: best}, nil
+}
+
+// ParamGrid lists candidate values per hyperparameter. [ParamGrid.Expand]
+// produces the cartesian product of all non-empty lists; empty lists keep the
+// base configuration's value.
+type ParamGrid struct {
+	NEstimators    []int     `json:"n_estimators,omitempty"`
+	LearningRate   []float64 `json:"learning_rate,omitempty"`
+	MaxDepth       []int     `json:"max_depth,omitempty"`
+	MinSamplesLeaf []int     `json:"min_samples_leaf,omitempty"`
+	SubsampleRatio []float64 `json:"subsample_ratio,omitempty"`
+}
+
+// Expand returns one Config per combination of grid values, each starting
+// from base.
+func (p ParamGrid) Expand(base Config) []Config {
+	configs := []Config{base}
+	configs = expandParam(configs, p.NEstimators, func(c *Config, v int) { c.NEstimators = v })
+	configs = expandParam(configs, p.LearningRate, func(c *Config, v float64) { c.LearningRate = v })
+	configs = expandParam(configs, p.MaxDepth, func(c *Config, v int) { c.MaxDepth = v })
+	configs = expandParam(configs, p.MinSamplesLeaf, func(c *Config, v int) { c.MinSamplesLeaf = v })
+	configs = expandParam(configs, p.SubsampleRatio, func(c *Config, v float64) { c.SubsampleRatio = v })
+	return configs
+}
+
+// Sample returns n configurations drawn without replacement from the grid's
+// expansion, for random search over large grids. The draw is deterministic
+// for a given seed. If n is at least the grid size, every combination is
+// returned in expansion order.
+func (p ParamGrid) Sample(base Config, n int, seed int64) []Config {
+	all := p.Expand(base)
+	if n >= len(all) {
+		return all
+	}
+	perm := newRNG(seed).Perm(len(all))
+	res := make([]Config, max(n, 0))
+	for i := range res {
+		res[i] = all[perm[i]]
+	}
+	return res
+}
+
+func expandParam[T any](configs []Config, values []T, set func(*Config, T)) []Config {
+	if len(values) == 0 {
+		return configs
+	}
+	res := make([]Config, 0, len(configs)*len(values))
+	for _, cfg := range configs {
+		for _, v := range values {
+			c := cfg
+			set(&c, v)
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// foldJob is one model trained and scored by scoreFolds.
+type foldJob struct {
+	cfg  Config
+	fold Fold
+}
+
+// scoreFolds trains and scores every job on a single pool of opts.Workers
+// goroutines and returns the scores in job order, or the first error in job
+// order.
+func scoreFolds(jobs []foldJob, X [][]float64, y []float64, opts CVOptions) ([]float64, error) {
+	scores := make([]float64, len(jobs))
+	errs := make([]error, len(jobs))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(opts.workers(), len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range next {
+				scores[j], errs[j] = scoreFold(jobs[j].cfg, X, y, jobs[j].fold, opts.Metric)
+			}
+		}()
+	}
+	for j := range jobs {
+		next <- j
+	}
+	close(next)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return scores, nil
+}
+
+func scoreFold(cfg Config, X [][]float64, y []float64, fold Fold, metric Metric) (float64, error) {
+	model := New(cfg)
+	if err := model.Fit(extractRows(X, fold.Train), extractRows(y, fold.Train)); err != nil {
+		return 0, err
+	}
+	XTest := extractRows(X, fold.Test)
+	return metric(extractRows(y, fold.Test), model.predictOutput(XTest)), nil
+}
+
+// predictOutput returns the model output metrics are computed on:
+// probabilities for classification, expected values for the gamma loss, and
+// raw predictions otherwise.
+func (g *GBM) predictOutput(X [][]float64) []float64 {
+	res := g.Predict(X)
+	for i, raw := range res {
+		res[i] = inverseLink(g.Config.Loss, raw)
+	}
+	return res
+}
+
+// CVCurve holds the cross-validated score after each boosting round, as
+// computed by [FitCVEarlyStop].
+type CVCurve struct {
+	Mean       []float64 // Mean over folds of the score after rounds 1..NEstimators.
+	Std        []float64 // Standard deviation over folds, per round.
+	BestRounds int       // Number of rounds with the best mean score.
+}
+
+// FitCVEarlyStop picks the number of boosting rounds for cfg by k-fold
+// cross-validation, then trains on all of X and y with that many rounds,
+// as xgb.cv followed by a refit does. Each fold trains cfg.NEstimators
+// rounds, the ceiling of the search, and scores its held-out rows with
+// opts.Metric after every round; the round count with the best mean score,
+// the earliest on ties, is used for the final model.
+//
+// Unlike early stopping with Config.ValidationFraction, the final model
+// trains on every row, and the choice of rounds averages over all folds
+// instead of one validation split. ValidationFraction is ignored. A fold
+// that stops early or skips rounds, through Config.MinGainFraction,
+// MaxSingleLeafTrees, or SkipSingleLeafTrees, scores those rounds as its
+// last model.
+//
+// Returns [ErrInvalidLRSchedule] if cfg.LRPatience is positive, since
+// there is no validation set to detect a plateau on, an error wrapping
+// [ErrInvalidSnapshotBlend] if cfg.SnapshotBlend is above 1, since a blend
+// rescales the trees that the per-round scores are summed from, a
+// validation error for other invalid options or data, or the first error
+// returned while fitting a model.
+func FitCVEarlyStop(cfg Config, X [][]float64, y []float64, opts CVOptions) (*GBM, *CVCurve, error) {
+	if len(X) != len(y) {
+		return nil, nil, ErrLengthMismatch
+	}
+	if err := opts.validate(len(X)); err != nil {
+		return nil, nil, err
+	}
+	if cfg.LRPatience > 0 {
+		return nil, nil, fmt.Errorf("%w: FitCVEarlyStop has no validation set, so LRPatience must be 0", ErrInvalidLRSchedule)
+	}
+	if cfg.SnapshotBlend > 1 {
+		return nil, nil, fmt.Errorf("%w: FitCVEarlyStop scores every round count, so SnapshotBlend must be 0 or 1", ErrInvalidSnapshotBlend)
+	}
+	cfg.ValidationFraction = 0
+	if err := cfg.validate(); err != nil {
+		return nil, nil, err
+	}
+	folds, err := KFold(len(X), opts.Folds, opts.Seed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scores := make([][]float64, len(folds)) // fold → round → score
+	errs := make([]error, len(folds))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(opts.workers(), len(folds)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range jobs {
+				foldCfg := cfg
+				foldCfg.Seed = deriveSeed(opts.Seed, f)
+				scores[f], errs[f] = scoreFoldRounds(foldCfg, X, y, folds[f], opts.Metric)
+			}
+		}()
+	}
+	for f := range folds {
+		jobs <- f
+	}
+	close(jobs)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	curve := &CVCurve{Mean: make([]float64, cfg.NEstimators), Std: make([]float64, cfg.NEstimators)}
+	perRound := make([]float64, len(folds))
+	for r := range cfg.NEstimators {
+		for f := range folds {
+			perRound[f] = scores[f][r]
+		}
+		curve.Mean[r] = mean(perRound)
+		curve.Std[r] = math.Sqrt(variance(perRound))
+
+		better := func(a, b float64) bool { return a < b }
+		if opts.GreaterIsBetter {
+			better = func(a, b float64) bool { return a > b }
+		}
+		if r == 0 || better(curve.Mean[r], curve.Mean[curve.BestRounds-1]) {
+			curve.BestRounds = r + 1
+		}
+	}
+
+	cfg.NEstimators = curve.BestRounds
+	model := New(cfg)
+	if err := model.Fit(X, y); err != nil {
+		return nil, nil, err
+	}
+	return model, curve, nil
+}
+
+// scoreFoldRounds trains cfg on the fold's training rows and returns the
+// metric on its test rows after each of cfg.NEstimators rounds. Rounds that
+// added no tree, because they were skipped or training stopped before
+// them, repeat the score of the model so far.
+func scoreFoldRounds(cfg Config, X [][]float64, y []float64, fold Fold, metric Metric) ([]float64, error) {
+	model := New(cfg)
+	if err := model.Fit(extractRows(X, fold.Train), extractRows(y, fold.Train)); err != nil {
+		return nil, err
+	}
+	XTest, yTest := extractRows(X, fold.Test), extractRows(y, fold.Test)
+	raw := make([]float64, len(XTest))
+	output := make([]float64, len(XTest))
+	for i := range raw {
+		raw[i] = model.initialPrediction
+		output[i] = inverseLink(cfg.Loss, raw[i])
+	}
+	score := metric(yTest, output)
+	scores := make([]float64, cfg.NEstimators)
+	trees := model.trees
+	for r := range scores {
+		if r < len(model.history) && !model.history[r].Skipped {
+			for i, x := range XTest {
+				raw[i] += float64(cfg.LearningRate * trees[0].predict(x))
+				output[i] = inverseLink(cfg.Loss, raw[i])
+			}
+			score = metric(yTest, output)
+			trees = trees[1:]
+		}
+		scores[r] = score
+	}
+	return scores, nil
+}
@@ -0,0 +1,502 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost/metrics"
+)
+
+func TestKFoldPartitionsIndices(t *testing.T) {
+	folds, err := KFold(10, 3, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folds) != 3 {
+		t.Fatalf("expected 3 folds, got %d", len(folds))
+	}
+
+	seen := make([]int, 10)
+	for i, f := range folds {
+		if len(f.Train)+len(f.Test) != 10 {
+			t.Errorf("fold %d: train+test = %d, want 10", i, len(f.Train)+len(f.Test))
+		}
+		for _, idx := range f.Test {
+			seen[idx]++
+			if slices.Contains(f.Train, idx) {
+				t.Errorf("fold %d: index %d in both train and test", i, idx)
+			}
+		}
+	}
+	for idx, n := range seen {
+		if n != 1 {
+			t.Errorf("index %d appears in %d test sets, want 1", idx, n)
+		}
+	}
+
+	// 10 = 4 + 3 + 3
+	if len(folds[0].Test) != 4 || len(folds[1].Test) != 3 || len(folds[2].Test) != 3 {
+		t.Errorf("unexpected fold sizes: %d, %d, %d", len(folds[0].Test), len(folds[1].Test), len(folds[2].Test))
+	}
+}
+
+func TestKFoldReproducible(t *testing.T) {
+	a, _ := KFold(20, 4, 7)
+	b, _ := KFold(20, 4, 7)
+	for i := range a {
+		if !slices.Equal(a[i].Test, b[i].Test) {
+			t.Errorf("fold %d differs between runs with the same seed", i)
+		}
+	}
+}
+
+func TestKFoldInvalid(t *testing.T) {
+	if _, err := KFold(5, 1, 0); !errors.Is(err, ErrInvalidFolds) {
+		t.Errorf("k=1: expected ErrInvalidFolds, got %v", err)
+	}
+	if _, err := KFold(5, 6, 0); !errors.Is(err, ErrInvalidFolds) {
+		t.Errorf("k>n: expected ErrInvalidFolds, got %v", err)
+	}
+}
+
+func TestSaveLoadFolds(t *testing.T) {
+	folds, err := KFold(20, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "folds.json")
+	if err := SaveFolds(path, folds...); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFolds(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(folds) {
+		t.Fatalf("loaded %d folds, want %d", len(loaded), len(folds))
+	}
+	for i := range folds {
+		if !slices.Equal(loaded[i].Train, folds[i].Train) || !slices.Equal(loaded[i].Test, folds[i].Test) {
+			t.Errorf("fold %d changed in the round trip", i)
+		}
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `"folds"`) || !strings.Contains(string(raw), `"train"`) {
+		t.Errorf("unexpected file layout: %s", raw)
+	}
+}
+
+func TestFoldSplit(t *testing.T) {
+	X := [][]float64{{0}, {1}, {2}, {3}}
+	y := []float64{0, 10, 20, 30}
+
+	XTrain, XTest, yTrain, yTest, err := Fold{Train: []int{3, 0}, Test: []int{2}}.Split(X, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(yTrain, []float64{30, 0}) || !slices.Equal(yTest, []float64{20}) {
+		t.Errorf("yTrain, yTest = %v, %v, want [30 0], [20]", yTrain, yTest)
+	}
+	if XTrain[0][0] != 3 || XTest[0][0] != 2 {
+		t.Errorf("XTrain, XTest = %v, %v", XTrain, XTest)
+	}
+
+	invalid := []Fold{
+		{Train: []int{0, 1}},
+		{Train: []int{0, 1}, Test: []int{1}},
+		{Train: []int{0}, Test: []int{4}},
+		{Train: []int{-1}, Test: []int{2}},
+	}
+	for _, f := range invalid {
+		if _, _, _, _, err := f.Split(X, y); !errors.Is(err, ErrInvalidFold) {
+			t.Errorf("%+v: err = %v, want ErrInvalidFold", f, err)
+		}
+	}
+	if err := SaveFolds(filepath.Join(t.TempDir(), "bad.json"), invalid[1]); !errors.Is(err, ErrInvalidFold) {
+		t.Errorf("SaveFolds with overlap: err = %v, want ErrInvalidFold", err)
+	}
+	if _, _, _, _, err := (Fold{Train: []int{0}, Test: []int{1}}).Split(X, y[:3]); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("length mismatch: err = %v, want ErrLengthMismatch", err)
+	}
+}
+
+func cvTestConfig() Config {
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	cfg.MaxDepth = 3
+	return cfg
+}
+
+func TestCrossValidateRegression(t *testing.T) {
+	X, y := generateDataWithFunc(func(x1, x2 float64) float64 { return 2*x1 + 3*x2 })
+
+	res, err := CrossValidate(cvTestConfig(), X, y, CVOptions{Folds: 5, Metric: metrics.RMSE, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Scores) != 5 {
+		t.Fatalf("expected 5 fold scores, got %d", len(res.Scores))
+	}
+	if res.Mean != mean(res.Scores) {
+		t.Errorf("Mean = %v, want mean of scores %v", res.Mean, mean(res.Scores))
+	}
+	if res.Std < 0 {
+		t.Errorf("Std = %v, want >= 0", res.Std)
+	}
+}
+
+func TestCrossValidateClassificationReceivesProbabilities(t *testing.T) {
+	X, y := generateBinaryData(0.5)
+	cfg := cvTestConfig()
+	cfg.Loss = "logloss"
+
+	inRange := func(y, pred []float64) float64 {
+		for _, p := range pred {
+			if p < 0 || p > 1 {
+				return 0
+			}
+		}
+		return 1
+	}
+
+	res, err := CrossValidate(cfg, X, y, CVOptions{Folds: 3, Metric: inRange})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Mean != 1 {
+		t.Error("expected classification metrics to receive probabilities in [0, 1]")
+	}
+}
+
+func TestCrossValidateDeterministicAcrossWorkers(t *testing.T) {
+	X, y := generateDataWithFunc(func(x1, x2 float64) float64 { return x1 * x2 })
+	cfg := cvTestConfig()
+	cfg.SubsampleRatio = 0.7
+
+	serial, err := CrossValidate(cfg, X, y, CVOptions{Folds: 4, Metric: metrics.MSE, Seed: 9, Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := CrossValidate(cfg, X, y, CVOptions{Folds: 4, Metric: metrics.MSE, Seed: 9, Workers: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(serial.Scores, parallel.Scores) {
+		t.Errorf("scores differ between 1 and 4 workers: %v vs %v", serial.Scores, parallel.Scores)
+	}
+}
+
+func TestCrossValidateOptionErrors(t *testing.T) {
+	X, y := generateLinearDataWithSingleFeature()
+	cfg := cvTestConfig()
+
+	tests := []struct {
+		name string
+		opts CVOptions
+		want error
+	}{
+		{"too few folds", CVOptions{Folds: 1, Metric: metrics.MSE}, ErrInvalidFolds},
+		{"nil metric", CVOptions{Folds: 2}, ErrNilMetric},
+		{"negative workers", CVOptions{Folds: 2, Metric: metrics.MSE, Workers: -1}, ErrInvalidWorkers},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CrossValidate(cfg, X, y, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	bad := cfg
+	bad.LearningRate = 0
+	if _, err := CrossValidate(bad, X, y, CVOptions{Folds: 2, Metric: metrics.MSE}); !errors.Is(err, ErrInvalidLearningRate) {
+		t.Errorf("expected ErrInvalidLearningRate, got %v", err)
+	}
+	if _, err := CrossValidate(cfg, X, y[1:], CVOptions{Folds: 2, Metric: metrics.MSE}); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+func TestCrossValidatePropagatesFitError(t *testing.T) {
+	X, y := generateLinearDataWithSingleFeature()
+	cfg := cvTestConfig()
+	wantErr := errors.New("stop")
+	cfg.OnRoundEnd = func(round, total int) error { return wantErr }
+
+	if _, err := CrossValidate(cfg, X, y, CVOptions{Folds: 2, Metric: metrics.MSE}); !errors.Is(err, wantErr) {
+		t.Errorf("expected callback error, got %v", err)
+	}
+}
+
+func TestGridSearchPicksBest(t *testing.T) {
+	X, y := generateDataWithFunc(func(x1, x2 float64) float64 { return 2*x1 + 3*x2 })
+
+	weak := cvTestConfig()
+	weak.NEstimators = 1
+	weak.MaxDepth = 1
+	strong := cvTestConfig()
+	strong.NEstimators = 50
+
+	res, err := GridSearch([]Config{weak, strong}, X, y, CVOptions{Folds: 3, Metric: metrics.RMSE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Best != 1 {
+		t.Errorf("Best = %d, want 1 (lower RMSE)", res.Best)
+	}
+	if res.BestConfig().NEstimators != 50 {
+		t.Errorf("BestConfig().NEstimators = %d, want 50", res.BestConfig().NEstimators)
+	}
+
+	// With GreaterIsBetter the worse-RMSE candidate wins.
+	res, err = GridSearch([]Config{weak, strong}, X, y, CVOptions{Folds: 3, Metric: metrics.RMSE, GreaterIsBetter: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Best != 0 {
+		t.Errorf("Best = %d, want 0 with GreaterIsBetter", res.Best)
+	}
+}
+
+func TestGridSearchRunsEveryFoldOfEveryCandidate(t *testing.T) {
+	X, y := generateLinearDataWithSingleFeature()
+	var calls atomic.Int64
+	cfg := cvTestConfig()
+	cfg.NEstimators = 1
+	cfg.OnRoundEnd = func(round, total int) error {
+		calls.Add(1)
+		return nil
+	}
+
+	candidates := ParamGrid{MaxDepth: []int{1, 2, 3}}.Expand(cfg)
+	if _, err := GridSearch(candidates, X, y, CVOptions{Folds: 4, Metric: metrics.MSE, Workers: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 12 {
+		t.Errorf("expected 12 fits (3 candidates x 4 folds), got %d", calls.Load())
+	}
+}
+
+func TestGridSearchEmpty(t *testing.T) {
+	X, y := generateLinearDataWithSingleFeature()
+	if _, err := GridSearch(nil, X, y, CVOptions{Folds: 2, Metric: metrics.MSE}); !errors.Is(err, ErrEmptyGrid) {
+		t.Errorf("expected ErrEmptyGrid, got %v", err)
+	}
+}
+
+func TestRepeatedCV(t *testing.T) {
+	X, y := generateNoisyData()
+	opts := CVOptions{Folds: 5, Metric: metrics.RMSE, Seed: 3}
+
+	res, err := RepeatedCV(cvTestConfig(), X, y, 3, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Scores) != 15 || len(res.RepeatMeans) != 3 || res.Folds != 5 {
+		t.Fatalf("%d scores, %d repeat means, %d folds", len(res.Scores), len(res.RepeatMeans), res.Folds)
+	}
+	if res.Mean != mean(res.Scores) {
+		t.Errorf("Mean = %v, want mean of scores %v", res.Mean, mean(res.Scores))
+	}
+	if res.Config.Seed != cvTestConfig().Seed {
+		t.Errorf("Config.Seed = %d, want the caller's", res.Config.Seed)
+	}
+
+	// Each repeat is the k-fold CrossValidate runs with a derived seed.
+	for r := range 3 {
+		repeatOpts := opts
+		repeatOpts.Seed = deriveSeed(opts.Seed, r)
+		cv, err := CrossValidate(cvTestConfig(), X, y, repeatOpts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := res.Scores[r*5 : (r+1)*5]; !slices.Equal(got, cv.Scores) {
+			t.Errorf("repeat %d scores %v, CrossValidate %v", r, got, cv.Scores)
+		}
+		if res.RepeatMeans[r] != cv.Mean {
+			t.Errorf("repeat %d mean %v, want %v", r, res.RepeatMeans[r], cv.Mean)
+		}
+	}
+
+	low, high := res.ConfidenceInterval(0.95)
+	if !(low < res.Mean && res.Mean < high) {
+		t.Fatalf("95%% interval [%v, %v] misses the mean %v", low, high, res.Mean)
+	}
+	// The corrected variance is (1/15 + 1/4) s², with a t quantile on 14
+	// degrees of freedom.
+	j := 15.0
+	s2 := variance(res.Scores) * j / (j - 1)
+	want := 2.1448 * math.Sqrt((1/j+1.0/4)*s2)
+	if math.Abs((high-low)/2-want) > 1e-4*want {
+		t.Errorf("half-width = %v, want %v", (high-low)/2, want)
+	}
+	if l50, h50 := res.ConfidenceInterval(0.5); !(l50 > low && h50 < high) {
+		t.Errorf("50%% interval [%v, %v] not inside 95%% interval [%v, %v]", l50, h50, low, high)
+	}
+	if l, h := res.ConfidenceInterval(1); !math.IsNaN(l) || !math.IsNaN(h) {
+		t.Errorf("level 1: [%v, %v], want NaN", l, h)
+	}
+
+	if _, err := RepeatedCV(cvTestConfig(), X, y, 0, opts); !errors.Is(err, ErrInvalidRepeats) {
+		t.Errorf("repeats 0: err = %v, want ErrInvalidRepeats", err)
+	}
+	if _, err := RepeatedCV(cvTestConfig(), X, y, 2, CVOptions{Folds: 1, Metric: metrics.RMSE}); !errors.Is(err, ErrInvalidFolds) {
+		t.Errorf("1 fold: err = %v, want ErrInvalidFolds", err)
+	}
+}
+
+func TestParamGridExpand(t *testing.T) {
+	base := DefaultConfig()
+	grid := ParamGrid{
+		NEstimators:  []int{10, 20},
+		LearningRate: []float64{0.05, 0.1, 0.2},
+	}
+
+	configs := grid.Expand(base)
+	if len(configs) != 6 {
+		t.Fatalf("expected 6 configs, got %d", len(configs))
+	}
+	for _, c := range configs {
+		if c.MaxDepth != base.MaxDepth {
+			t.Errorf("MaxDepth = %d, want base value %d", c.MaxDepth, base.MaxDepth)
+		}
+	}
+	if configs[0].NEstimators != 10 || configs[0].LearningRate != 0.05 {
+		t.Errorf("configs[0] = %+v, want NEstimators=10 LearningRate=0.05", configs[0])
+	}
+	if configs[5].NEstimators != 20 || configs[5].LearningRate != 0.2 {
+		t.Errorf("configs[5] = %+v, want NEstimators=20 LearningRate=0.2", configs[5])
+	}
+
+	if got := (ParamGrid{}).Expand(base); len(got) != 1 {
+		t.Errorf("empty grid should expand to the base config only, got %d", len(got))
+	}
+}
+
+func TestParamGridSample(t *testing.T) {
+	grid := ParamGrid{MaxDepth: []int{1, 2, 3, 4}, NEstimators: []int{10, 20, 30}}
+	base := DefaultConfig()
+
+	a := grid.Sample(base, 5, 3)
+	b := grid.Sample(base, 5, 3)
+	if len(a) != 5 {
+		t.Fatalf("expected 5 samples, got %d", len(a))
+	}
+	if !slices.EqualFunc(a, b, func(x, y Config) bool {
+		return x.MaxDepth == y.MaxDepth && x.NEstimators == y.NEstimators
+	}) {
+		t.Error("Sample is not deterministic for a fixed seed")
+	}
+
+	type combo struct{ depth, trees int }
+	seen := make(map[combo]bool)
+	for _, c := range a {
+		k := combo{c.MaxDepth, c.NEstimators}
+		if seen[k] {
+			t.Errorf("combination %+v sampled twice", k)
+		}
+		seen[k] = true
+	}
+
+	if all := grid.Sample(base, 100, 3); len(all) != 12 {
+		t.Errorf("oversized sample: expected all 12 combinations, got %d", len(all))
+	}
+}
+
+func TestFitCVEarlyStop(t *testing.T) {
+	X, y := generateNoisyFeatures(80)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 60
+	cfg.LearningRate = 0.5
+	cfg.MaxDepth = 3
+	cfg.ValidationFraction = 0.2 // ignored
+	opts := CVOptions{Folds: 3, Metric: metrics.RMSE, Seed: 4}
+
+	model, curve, err := FitCVEarlyStop(cfg, X, y, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curve.Mean) != 60 || len(curve.Std) != 60 {
+		t.Fatalf("curve has %d means and %d stds, want one per round", len(curve.Mean), len(curve.Std))
+	}
+	if curve.BestRounds < 2 || curve.BestRounds >= 60 {
+		t.Errorf("BestRounds = %d, want an interior optimum with a fast learning rate", curve.BestRounds)
+	}
+	if got := slices.Min(curve.Mean); curve.Mean[curve.BestRounds-1] != got {
+		t.Errorf("mean at BestRounds %v, minimum %v", curve.Mean[curve.BestRounds-1], got)
+	}
+	if model.NumTrees() != curve.BestRounds || model.Config.NEstimators != curve.BestRounds {
+		t.Errorf("refit model has %d trees, want %d", model.NumTrees(), curve.BestRounds)
+	}
+
+	// The last round of the curve is the mean fold score of a full-size
+	// CrossValidate with the same folds and seeds.
+	cv, err := CrossValidate(func() Config { c := cfg; c.ValidationFraction = 0; return c }(), X, y, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(cv.Mean-curve.Mean[59]) > 1e-9 {
+		t.Errorf("curve ends at %v, CrossValidate mean %v", curve.Mean[59], cv.Mean)
+	}
+
+	if _, _, err := FitCVEarlyStop(cfg, X, y, CVOptions{Folds: 1, Metric: metrics.RMSE}); err != ErrInvalidFolds {
+		t.Errorf("err = %v, want ErrInvalidFolds", err)
+	}
+	cfg.LRPatience = 5
+	if _, _, err := FitCVEarlyStop(cfg, X, y, opts); !errors.Is(err, ErrInvalidLRSchedule) {
+		t.Errorf("err = %v, want ErrInvalidLRSchedule", err)
+	}
+	cfg.LRPatience = 0
+	cfg.SnapshotBlend = 5
+	if _, _, err := FitCVEarlyStop(cfg, X, y, opts); !errors.Is(err, ErrInvalidSnapshotBlend) {
+		t.Errorf("err = %v, want ErrInvalidSnapshotBlend", err)
+	}
+}
+
+func TestFitCVEarlyStopShortFolds(t *testing.T) {
+	X, y := generateNoisyFeatures(80)
+	opts := CVOptions{Folds: 3, Metric: metrics.RMSE, Seed: 4}
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{"SkipSingleLeafTrees", func(c *Config) { c.SkipSingleLeafTrees = true; c.MinSamplesLeaf = len(y) }},
+		{"MaxSingleLeafTrees", func(c *Config) { c.MaxSingleLeafTrees = 2; c.MinSamplesLeaf = len(y) }},
+		{"MinGainFraction", func(c *Config) { c.MinGainFraction = 0.5 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.NEstimators = 30
+			cfg.LearningRate = 0.5
+			cfg.MaxDepth = 3
+			tt.mutate(&cfg)
+
+			_, curve, err := FitCVEarlyStop(cfg, X, y, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(curve.Mean) != 30 {
+				t.Fatalf("curve has %d rounds, want 30", len(curve.Mean))
+			}
+			// Every fold has stopped or skipped by the last rounds, so the
+			// curve ends flat.
+			if curve.Mean[28] != curve.Mean[29] || math.IsNaN(curve.Mean[29]) {
+				t.Errorf("curve ends %v, %v, want equal scores", curve.Mean[28], curve.Mean[29])
+			}
+		})
+	}
+}
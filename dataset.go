@@ -3,7 +3,7 @@ package gboost
 import (
 	"encoding/csv"
 	"fmt"
-	"math/rand"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -16,6 +16,7 @@ type Dataset struct {
 	Encodings      map[int]map[string]float64 // featureIndex → (stringValue → numericValue)
 	TargetEncoding map[string]float64         // target column encoding, nil if target is numeric
 	Header         []string
+	FeatureNames   []string // header names of the feature columns, nil if the CSV has no header
 }
 
 // LoadCSV reads a CSV file into memory and returns a Dataset. The targetColumn
@@ -28,8 +29,13 @@ func LoadCSV(path string, targetColumn int, hasHeader bool) (*Dataset, error) {
 		return nil, fmt.Errorf("open csv: %w", err)
 	}
 	defer f.Close()
+	return ReadCSV(f, targetColumn, hasHeader)
+}
 
-	reader := csv.NewReader(f)
+// ReadCSV is like [LoadCSV] but reads the CSV data from r, such as an
+// embedded file or an HTTP response body.
+func ReadCSV(r io.Reader, targetColumn int, hasHeader bool) (*Dataset, error) {
+	reader := csv.NewReader(r)
 	records, err := reader.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("read csv: %w", err)
@@ -133,6 +139,15 @@ func LoadCSV(path string, targetColumn int, hasHeader bool) (*Dataset, error) {
 		ds.X[i] = features
 	}
 
+	if ds.Header != nil {
+		ds.FeatureNames = make([]string, 0, nCols-1)
+		for col, name := range ds.Header {
+			if col != targetColumn {
+				ds.FeatureNames = append(ds.FeatureNames, strings.TrimSpace(name))
+			}
+		}
+	}
+
 	// Build exported encodings keyed by feature index (not csv column index).
 	featureIdx := 0
 	for col := 0; col < nCols; col++ {
@@ -157,19 +172,39 @@ func LoadCSV(path string, targetColumn int, hasHeader bool) (*Dataset, error) {
 	return ds, nil
 }
 
+// Transform encodes raw feature rows, such as records read at prediction
+// time, exactly as the dataset's features were encoded, applying policy to
+// categories that the dataset does not contain. Rows hold the feature
+// columns only, in the order of FeatureNames. It is shorthand for
+// ds.CategoryEncoder(policy).Encode(rows); see [CategoryEncoder.Encode].
+func (ds *Dataset) Transform(rows [][]string, policy UnseenPolicy) ([][]float64, error) {
+	return ds.CategoryEncoder(policy).Encode(rows)
+}
+
 // TrainTestSplit splits features and targets into training and testing sets.
 // testRatio is the fraction of data used for testing (must be between 0 and 1
-// exclusive). seed controls the random shuffle for reproducibility.
+// exclusive). seed controls the random shuffle for reproducibility. The rows
+// chosen are those of [TrainTestSplitIndices] with the same arguments.
 func TrainTestSplit(X [][]float64, y []float64, testRatio float64, seed int64) (XTrain, XTest [][]float64, yTrain, yTest []float64, err error) {
-	n := len(X)
-	if n != len(y) {
+	if len(X) != len(y) {
 		return nil, nil, nil, nil, ErrLengthMismatch
 	}
+	fold, err := TrainTestSplitIndices(len(X), testRatio, seed)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return fold.Split(X, y)
+}
+
+// TrainTestSplitIndices returns the row indices [TrainTestSplit] assigns to
+// the training and test sets, in the order it returns the rows. Persist them
+// with [SaveFolds] to reproduce the split without the data or the seed.
+func TrainTestSplitIndices(n int, testRatio float64, seed int64) (Fold, error) {
 	if n < 2 {
-		return nil, nil, nil, nil, fmt.Errorf("need at least 2 samples to split, got %d", n)
+		return Fold{}, fmt.Errorf("need at least 2 samples to split, got %d", n)
 	}
 	if testRatio <= 0 || testRatio >= 1 {
-		return nil, nil, nil, nil, fmt.Errorf("testRatio must be between 0 and 1 exclusive, got %f", testRatio)
+		return Fold{}, fmt.Errorf("testRatio must be between 0 and 1 exclusive, got %f", testRatio)
 	}
 
 	indices := make([]int, n)
@@ -177,7 +212,7 @@ func TrainTestSplit(X [][]float64, y []float64, testRatio float64, seed int64) (
 		indices[i] = i
 	}
 
-	rng := rand.New(rand.NewSource(seed))
+	rng := newRNG(seed)
 	rng.Shuffle(n, func(i, j int) {
 		indices[i], indices[j] = indices[j], indices[i]
 	})
@@ -190,21 +225,7 @@ func TrainTestSplit(X [][]float64, y []float64, testRatio float64, seed int64) (
 		split = n - 1
 	}
 
-	XTrain = make([][]float64, split)
-	yTrain = make([]float64, split)
-	XTest = make([][]float64, n-split)
-	yTest = make([]float64, n-split)
-
-	for i, idx := range indices[:split] {
-		XTrain[i] = X[idx]
-		yTrain[i] = y[idx]
-	}
-	for i, idx := range indices[split:] {
-		XTest[i] = X[idx]
-		yTest[i] = y[idx]
-	}
-
-	return XTrain, XTest, yTrain, yTest, nil
+	return Fold{Train: indices[:split], Test: indices[split:]}, nil
 }
 
 // Split is a convenience method that calls TrainTestSplit on the Dataset's X and Y.
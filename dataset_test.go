@@ -3,6 +3,7 @@ package gboost
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -38,6 +39,19 @@ func TestLoadCSVNumeric(t *testing.T) {
 	}
 }
 
+func TestReadCSV(t *testing.T) {
+	ds, err := ReadCSV(strings.NewReader("a,b,label\n1,x,yes\n2,y,no\n"), -1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.X) != 2 || ds.X[1][0] != 2 || len(ds.Encodings[1]) != 2 || len(ds.TargetEncoding) != 2 {
+		t.Errorf("X = %v, Encodings = %v, TargetEncoding = %v", ds.X, ds.Encodings, ds.TargetEncoding)
+	}
+	if len(ds.FeatureNames) != 2 || ds.FeatureNames[1] != "b" {
+		t.Errorf("FeatureNames = %v, want [a b]", ds.FeatureNames)
+	}
+}
+
 func TestLoadCSVWithHeader(t *testing.T) {
 	path := writeTestCSV(t, "header.csv", `a,b,target
 1.0,2.0,3.0
@@ -53,6 +67,9 @@ func TestLoadCSVWithHeader(t *testing.T) {
 	if ds.Header[0] != "a" || ds.Header[2] != "target" {
 		t.Fatalf("unexpected header: %v", ds.Header)
 	}
+	if len(ds.FeatureNames) != 2 || ds.FeatureNames[0] != "a" || ds.FeatureNames[1] != "b" {
+		t.Fatalf("unexpected feature names: %v", ds.FeatureNames)
+	}
 	if len(ds.X) != 2 {
 		t.Fatalf("expected 2 rows, got %d", len(ds.X))
 	}
@@ -302,6 +319,37 @@ func TestTrainTestSplitShuffles(t *testing.T) {
 	}
 }
 
+func TestTrainTestSplitIndicesMatchesTrainTestSplit(t *testing.T) {
+	X := make([][]float64, 30)
+	y := make([]float64, 30)
+	for i := range X {
+		X[i] = []float64{float64(i)}
+		y[i] = float64(i)
+	}
+
+	fold, err := TrainTestSplitIndices(len(X), 0.3, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, yTrain, yTest, err := TrainTestSplit(X, y, 0.3, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fold.Train) != len(yTrain) || len(fold.Test) != len(yTest) {
+		t.Fatalf("index sizes %d/%d, want %d/%d", len(fold.Train), len(fold.Test), len(yTrain), len(yTest))
+	}
+	for k, i := range fold.Train {
+		if y[i] != yTrain[k] {
+			t.Fatalf("train position %d: index %d, but TrainTestSplit returned row %v", k, i, yTrain[k])
+		}
+	}
+	for k, i := range fold.Test {
+		if y[i] != yTest[k] {
+			t.Fatalf("test position %d: index %d, but TrainTestSplit returned row %v", k, i, yTest[k])
+		}
+	}
+}
+
 func TestTrainTestSplitLengthMismatch(t *testing.T) {
 	X := [][]float64{{1}, {2}, {3}}
 	y := []float64{1, 2}
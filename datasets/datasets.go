@@ -0,0 +1,72 @@
+// Package datasets provides small datasets for examples, tests, and quick
+// experiments, so that code using gboost runs without sourcing CSV files.
+//
+// Real datasets are embedded in the binary; synthetic ones are generated
+// deterministically from a seed. Every dataset is returned as a fresh
+// [gboost.Dataset] that the caller may modify.
+package datasets
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+//go:embed iris_binary.csv
+var irisCSV []byte
+
+// LoadIris returns Fisher's Iris data restricted to its two overlapping
+// species, versicolor (label 0) and virginica (label 1): 100 rows, 50 per
+// class, of four features in centimetres, sepal_length, sepal_width,
+// petal_length, and petal_width. It is a small binary classification
+// problem that is not perfectly separable.
+func LoadIris() *gboost.Dataset {
+	ds, err := gboost.ReadCSV(bytes.NewReader(irisCSV), -1, true)
+	if err != nil {
+		panic(fmt.Sprintf("datasets: embedded iris data: %v", err))
+	}
+	return ds
+}
+
+// LoadFriedman returns 500 rows of [Friedman1] with unit noise and seed 1,
+// a fixed regression dataset for examples.
+func LoadFriedman() *gboost.Dataset {
+	return Friedman1(500, 1, 1)
+}
+
+// Friedman1 generates n rows of Friedman's first regression benchmark
+// (Friedman, 1991). Each row has ten features drawn uniformly from [0, 1],
+// x1 to x10, of which only the first five affect the target:
+//
+//	y = 10 sin(π x1 x2) + 20 (x3 - 0.5)² + 10 x4 + 5 x5 + ε
+//
+// where ε is Gaussian noise with standard deviation noise. The sine term
+// makes x1 and x2 interact, the squared term is non-monotone, and the five
+// irrelevant features test feature selection. The same n, noise, and seed
+// always produce the same rows.
+func Friedman1(n int, noise float64, seed int64) *gboost.Dataset {
+	rnd := rand.New(rand.NewSource(seed))
+	ds := &gboost.Dataset{
+		X:         make([][]float64, n),
+		Y:         make([]float64, n),
+		Encodings: make(map[int]map[string]float64),
+	}
+	for j := range 10 {
+		ds.FeatureNames = append(ds.FeatureNames, fmt.Sprintf("x%d", j+1))
+	}
+	ds.Header = append(append([]string(nil), ds.FeatureNames...), "y")
+
+	for i := range ds.X {
+		x := make([]float64, 10)
+		for j := range x {
+			x[j] = rnd.Float64()
+		}
+		ds.X[i] = x
+		ds.Y[i] = 10*math.Sin(math.Pi*x[0]*x[1]) + 20*(x[2]-0.5)*(x[2]-0.5) + 10*x[3] + 5*x[4] + noise*rnd.NormFloat64()
+	}
+	return ds
+}
@@ -0,0 +1,74 @@
+package datasets_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/datasets"
+)
+
+func TestLoadIris(t *testing.T) {
+	ds := datasets.LoadIris()
+	if len(ds.X) != 100 || len(ds.X[0]) != 4 {
+		t.Fatalf("shape = %d x %d, want 100 x 4", len(ds.X), len(ds.X[0]))
+	}
+	want := []string{"sepal_length", "sepal_width", "petal_length", "petal_width"}
+	if !slices.Equal(ds.FeatureNames, want) {
+		t.Errorf("FeatureNames = %v, want %v", ds.FeatureNames, want)
+	}
+	positives := 0
+	for _, y := range ds.Y {
+		if y == 1 {
+			positives++
+		} else if y != 0 {
+			t.Fatalf("label %v, want 0 or 1", y)
+		}
+	}
+	if positives != 50 {
+		t.Errorf("%d virginica rows, want 50", positives)
+	}
+
+	// Each call returns a fresh copy.
+	ds.X[0][0] = -1
+	if datasets.LoadIris().X[0][0] == -1 {
+		t.Error("LoadIris returned shared data")
+	}
+}
+
+func TestFriedman1(t *testing.T) {
+	a := datasets.Friedman1(200, 0.5, 3)
+	b := datasets.Friedman1(200, 0.5, 3)
+	if len(a.X) != 200 || len(a.X[0]) != 10 || len(a.FeatureNames) != 10 || len(a.Header) != 11 {
+		t.Fatalf("shape = %d x %d, names %v", len(a.X), len(a.X[0]), a.FeatureNames)
+	}
+	for i := range a.X {
+		if !slices.Equal(a.X[i], b.X[i]) || a.Y[i] != b.Y[i] {
+			t.Fatalf("row %d differs between calls with the same seed", i)
+		}
+	}
+	if c := datasets.Friedman1(200, 0.5, 4); slices.Equal(a.Y, c.Y) {
+		t.Error("different seeds produced the same targets")
+	}
+
+	// Without noise the target is exactly Friedman's function; its largest
+	// terms depend on x4, so x4 carries more importance than x10.
+	ds := datasets.Friedman1(300, 0, 1)
+	cfg := gboost.DefaultConfig()
+	cfg.NEstimators = 30
+	cfg.MaxDepth = 3
+	gbm := gboost.New(cfg)
+	if err := gbm.Fit(ds.X, ds.Y); err != nil {
+		t.Fatal(err)
+	}
+	if imp := gbm.FeatureImportance(); imp[3] <= imp[9] {
+		t.Errorf("importance of x4 = %v, of irrelevant x10 = %v", imp[3], imp[9])
+	}
+}
+
+func TestLoadFriedman(t *testing.T) {
+	ds := datasets.LoadFriedman()
+	if len(ds.X) != 500 || ds.Y[0] != datasets.LoadFriedman().Y[0] {
+		t.Errorf("LoadFriedman is not a fixed 500-row dataset")
+	}
+}
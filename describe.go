@@ -0,0 +1,338 @@
+package gboost
+
+import (
+	"math"
+	"slices"
+)
+
+// DescribeOptions controls [Describe] and [Dataset.Describe].
+type DescribeOptions struct {
+	// Bins is the number of equal-width histogram bins per feature, spanning
+	// the feature's observed range. Zero means 10.
+	Bins int
+
+	// Quantiles lists the quantiles, each in [0, 1], reported for every
+	// feature. Nil means 0.05, 0.25, 0.5, 0.75, and 0.95.
+	Quantiles []float64
+
+	// MaxClasses is the largest number of distinct target values for which
+	// the target is treated as a class label. An integer-valued target with
+	// at most MaxClasses distinct values gets class-conditional
+	// distributions; any other target is treated as continuous. Zero means 10.
+	MaxClasses int
+
+	// Leakage controls the check for features that leak the target; see
+	// [DetectLeakage]. It runs whenever a target is given, unless SkipLeakage
+	// is set.
+	Leakage     LeakageOptions
+	SkipLeakage bool
+}
+
+func (o DescribeOptions) validate() error {
+	switch {
+	case o.Bins < 0:
+		return ErrInvalidBins
+	case o.MaxClasses < 0:
+		return ErrInvalidMaxClasses
+	}
+	for _, q := range o.Quantiles {
+		if !(q >= 0 && q <= 1) {
+			return ErrInvalidQuantile
+		}
+	}
+	return o.Leakage.validate()
+}
+
+func (o DescribeOptions) bins() int {
+	if o.Bins == 0 {
+		return 10
+	}
+	return o.Bins
+}
+
+func (o DescribeOptions) quantiles() []float64 {
+	if o.Quantiles == nil {
+		return []float64{0.05, 0.25, 0.5, 0.75, 0.95}
+	}
+	return o.Quantiles
+}
+
+func (o DescribeOptions) maxClasses() int {
+	if o.MaxClasses == 0 {
+		return 10
+	}
+	return o.MaxClasses
+}
+
+// DatasetProfile summarizes the distribution of every feature of a dataset,
+// overall and, for a classification target, within each class.
+type DatasetProfile struct {
+	// Rows is the number of rows described.
+	Rows int
+
+	// Quantiles holds the quantile levels reported in every
+	// [Distribution.Quantiles].
+	Quantiles []float64
+
+	// Classes holds the sorted distinct target values and ClassCounts their
+	// row counts. Both are nil when the target is continuous or absent.
+	Classes     []float64
+	ClassCounts []int
+
+	// Features holds one profile per feature column, in column order.
+	Features []FeatureProfile
+
+	// Leakage lists the features that predict the target almost perfectly
+	// on their own, most predictive first, as found by [DetectLeakage]. It
+	// is nil when none does, the target is absent, or the check is skipped.
+	Leakage []LeakageSuspect
+}
+
+// FeatureProfile describes one feature column.
+type FeatureProfile struct {
+	// Index is the column index, and Name its name if known.
+	Index int
+	Name  string
+
+	// Distribution summarizes the column over all rows.
+	Distribution
+
+	// ByClass holds one distribution per [DatasetProfile.Classes] entry, nil
+	// when the target is continuous. Their histograms share the edges of the
+	// overall histogram, so class histograms can be drawn side by side or
+	// back to back and compared bin by bin.
+	ByClass []Distribution
+}
+
+// Distribution summarizes the values of one feature in a set of rows. NaN
+// values are counted in Missing and excluded from everything else; the
+// statistics are NaN when no value is present.
+type Distribution struct {
+	Count     int
+	Missing   int
+	Mean      float64
+	Std       float64
+	Min       float64
+	Max       float64
+	Quantiles []float64
+	Histogram Histogram
+}
+
+// Histogram counts values in ascending bins. Bin b covers
+// [Edges[b], Edges[b+1]), except the last bin, which also includes its upper
+// edge. A constant feature has a single bin whose edges are equal.
+type Histogram struct {
+	Edges  []float64
+	Counts []int
+}
+
+// Describe profiles each column of X: its missing count, mean, standard
+// deviation, range, quantiles, and an equal-width histogram. If y is an
+// integer-valued target with at most opts.MaxClasses distinct values, each
+// profile also includes the column's distribution within every class, which
+// shows at a glance how well a feature separates the classes. y may be nil;
+// otherwise each feature is also checked for target leakage, as by
+// [DetectLeakage] with opts.Leakage, unless opts.SkipLeakage is set.
+//
+// Returns [ErrEmptyDataset] if X is empty, [ErrLengthMismatch] if y is not
+// nil and differs in length from X, [ErrFeatureCountMismatch] if rows differ
+// in length, or [ErrInvalidBins], [ErrInvalidQuantile],
+// [ErrInvalidMaxClasses], [ErrInvalidNEstimators], or
+// [ErrInvalidLeakageThreshold] for invalid options.
+func Describe(X [][]float64, y []float64, opts DescribeOptions) (*DatasetProfile, error) {
+	switch {
+	case len(X) == 0:
+		return nil, ErrEmptyDataset
+	case y != nil && len(X) != len(y):
+		return nil, ErrLengthMismatch
+	case !hasSimilarLength(X):
+		return nil, ErrFeatureCountMismatch
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	qs := slices.Clone(opts.quantiles())
+	bins := opts.bins()
+	profile := &DatasetProfile{
+		Rows:      len(X),
+		Quantiles: qs,
+		Features:  make([]FeatureProfile, len(X[0])),
+	}
+
+	// Row indices of each class, in the order of profile.Classes.
+	var classRows [][]int
+	if classes, ok := targetClasses(y, opts.maxClasses()); ok {
+		profile.Classes = classes
+		profile.ClassCounts = make([]int, len(classes))
+		classRows = make([][]int, len(classes))
+		for i, v := range y {
+			c, _ := slices.BinarySearch(classes, v)
+			classRows[c] = append(classRows[c], i)
+			profile.ClassCounts[c]++
+		}
+	}
+
+	col := make([]float64, len(X))
+	for j := range profile.Features {
+		for i, row := range X {
+			col[i] = row[j]
+		}
+		fp := FeatureProfile{Index: j, Distribution: describeValues(col, nil, qs)}
+		edges := histogramEdges(fp.Min, fp.Max, bins)
+		fp.Histogram = histogram(col, nil, edges)
+		if classRows != nil {
+			fp.ByClass = make([]Distribution, len(classRows))
+			for c, rows := range classRows {
+				d := describeValues(col, rows, qs)
+				d.Histogram = histogram(col, rows, edges)
+				fp.ByClass[c] = d
+			}
+		}
+		profile.Features[j] = fp
+	}
+
+	if y != nil && !opts.SkipLeakage {
+		suspects, err := DetectLeakage(X, y, opts.Leakage)
+		if err != nil {
+			return nil, err
+		}
+		profile.Leakage = suspects
+	}
+	return profile, nil
+}
+
+// Describe profiles the dataset's features against its target, naming each
+// feature after FeatureNames when the CSV had a header. See [Describe].
+func (ds *Dataset) Describe(opts DescribeOptions) (*DatasetProfile, error) {
+	profile, err := Describe(ds.X, ds.Y, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(ds.FeatureNames) == len(profile.Features) {
+		for j := range profile.Features {
+			profile.Features[j].Name = ds.FeatureNames[j]
+		}
+		for k, s := range profile.Leakage {
+			profile.Leakage[k].Name = ds.FeatureNames[s.Feature]
+		}
+	}
+	return profile, nil
+}
+
+// targetClasses returns the sorted distinct values of y if y is a class
+// label: integer-valued with at most maxClasses distinct values.
+func targetClasses(y []float64, maxClasses int) ([]float64, bool) {
+	if len(y) == 0 {
+		return nil, false
+	}
+	seen := make(map[float64]struct{})
+	for _, v := range y {
+		if v != math.Trunc(v) || math.IsInf(v, 0) {
+			return nil, false
+		}
+		seen[v] = struct{}{}
+		if len(seen) > maxClasses {
+			return nil, false
+		}
+	}
+	classes := make([]float64, 0, len(seen))
+	for v := range seen {
+		classes = append(classes, v)
+	}
+	slices.Sort(classes)
+	return classes, true
+}
+
+// describeValues summarizes col at rows, or at every row if rows is nil,
+// leaving the histogram empty.
+func describeValues(col []float64, rows []int, qs []float64) Distribution {
+	var values []float64
+	missing := 0
+	visit := func(v float64) {
+		if math.IsNaN(v) {
+			missing++
+			return
+		}
+		values = append(values, v)
+	}
+	if rows == nil {
+		for _, v := range col {
+			visit(v)
+		}
+	} else {
+		for _, i := range rows {
+			visit(col[i])
+		}
+	}
+
+	d := Distribution{
+		Count:     len(values),
+		Missing:   missing,
+		Quantiles: make([]float64, len(qs)),
+	}
+	slices.Sort(values)
+	for k, q := range qs {
+		d.Quantiles[k] = quantileSorted(values, q)
+	}
+	if len(values) == 0 {
+		d.Mean, d.Std, d.Min, d.Max = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+		return d
+	}
+	d.Mean = mean(values)
+	d.Std = math.Sqrt(variance(values))
+	d.Min, d.Max = values[0], values[len(values)-1]
+	return d
+}
+
+// histogramEdges returns bins+1 equal-width edges from lo to hi, a single
+// zero-width bin if lo == hi, or nil if the range is NaN (no values).
+func histogramEdges(lo, hi float64, bins int) []float64 {
+	switch {
+	case math.IsNaN(lo):
+		return nil
+	case lo == hi:
+		return []float64{lo, hi}
+	}
+	edges := make([]float64, bins+1)
+	width := (hi - lo) / float64(bins)
+	for b := range edges {
+		edges[b] = lo + float64(b)*width
+	}
+	edges[bins] = hi
+	return edges
+}
+
+// histogram counts the non-NaN values of col at rows, or at every row if
+// rows is nil, into the bins delimited by edges. Values outside the edges
+// are clamped into the first or last bin.
+func histogram(col []float64, rows []int, edges []float64) Histogram {
+	if edges == nil {
+		return Histogram{}
+	}
+	h := Histogram{Edges: edges, Counts: make([]int, len(edges)-1)}
+	last := len(h.Counts) - 1
+	add := func(v float64) {
+		if math.IsNaN(v) {
+			return
+		}
+		// Index of the first edge above v, less one, is v's bin.
+		b, _ := slices.BinarySearchFunc(edges[1:], v, func(e, v float64) int {
+			if e > v {
+				return 1
+			}
+			return -1
+		})
+		h.Counts[min(b, last)]++
+	}
+	if rows == nil {
+		for _, v := range col {
+			add(v)
+		}
+	} else {
+		for _, i := range rows {
+			add(col[i])
+		}
+	}
+	return h
+}
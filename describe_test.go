@@ -0,0 +1,159 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestDescribeSummaryStatistics(t *testing.T) {
+	X := [][]float64{{1, 5}, {2, 5}, {3, 5}, {4, 5}, {math.NaN(), 5}}
+	y := []float64{0.5, 1.5, 2.5, 3.5, 4.5}
+
+	p, err := Describe(X, y, DescribeOptions{Bins: 3, Quantiles: []float64{0, 0.5, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rows != 5 || len(p.Features) != 2 {
+		t.Fatalf("Rows = %d, features = %d, want 5 and 2", p.Rows, len(p.Features))
+	}
+	if p.Classes != nil || p.Features[0].ByClass != nil {
+		t.Errorf("continuous target should have no classes, got %v", p.Classes)
+	}
+
+	f := p.Features[0]
+	if f.Count != 4 || f.Missing != 1 {
+		t.Errorf("Count, Missing = %d, %d, want 4, 1", f.Count, f.Missing)
+	}
+	if f.Mean != 2.5 || f.Min != 1 || f.Max != 4 {
+		t.Errorf("Mean, Min, Max = %v, %v, %v, want 2.5, 1, 4", f.Mean, f.Min, f.Max)
+	}
+	if want := math.Sqrt(1.25); math.Abs(f.Std-want) > 1e-12 {
+		t.Errorf("Std = %v, want %v", f.Std, want)
+	}
+	if !slices.Equal(f.Quantiles, []float64{1, 2.5, 4}) {
+		t.Errorf("Quantiles = %v, want [1 2.5 4]", f.Quantiles)
+	}
+	if !slices.Equal(f.Histogram.Edges, []float64{1, 2, 3, 4}) {
+		t.Errorf("Edges = %v, want [1 2 3 4]", f.Histogram.Edges)
+	}
+	// The maximum falls in the last, closed bin.
+	if !slices.Equal(f.Histogram.Counts, []int{1, 1, 2}) {
+		t.Errorf("Counts = %v, want [1 1 2]", f.Histogram.Counts)
+	}
+
+	constant := p.Features[1].Histogram
+	if !slices.Equal(constant.Edges, []float64{5, 5}) || !slices.Equal(constant.Counts, []int{5}) {
+		t.Errorf("constant feature histogram = %+v, want one bin of 5", constant)
+	}
+}
+
+func TestDescribeClassConditional(t *testing.T) {
+	X, y := generateBinaryData(0.5)
+
+	p, err := Describe(X, y, DescribeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(p.Classes, []float64{0, 1}) {
+		t.Fatalf("Classes = %v, want [0 1]", p.Classes)
+	}
+	if p.ClassCounts[0]+p.ClassCounts[1] != len(y) {
+		t.Errorf("ClassCounts %v do not sum to %d", p.ClassCounts, len(y))
+	}
+	if len(p.Quantiles) != 5 {
+		t.Errorf("default quantiles = %v, want 5 levels", p.Quantiles)
+	}
+
+	for _, f := range p.Features {
+		if len(f.ByClass) != 2 {
+			t.Fatalf("feature %d: %d class distributions, want 2", f.Index, len(f.ByClass))
+		}
+		if len(f.Histogram.Counts) != 10 {
+			t.Errorf("feature %d: %d bins, want 10", f.Index, len(f.Histogram.Counts))
+		}
+		for b, n := range f.Histogram.Counts {
+			if got := f.ByClass[0].Histogram.Counts[b] + f.ByClass[1].Histogram.Counts[b]; got != n {
+				t.Errorf("feature %d bin %d: class counts sum to %d, want %d", f.Index, b, got, n)
+			}
+		}
+		for c, d := range f.ByClass {
+			if !slices.Equal(d.Histogram.Edges, f.Histogram.Edges) {
+				t.Errorf("feature %d class %d: edges differ from the overall histogram", f.Index, c)
+			}
+		}
+	}
+
+	// The target is driven by feature 0, so its class-conditional means differ.
+	if byClass := p.Features[0].ByClass; byClass[1].Mean <= byClass[0].Mean {
+		t.Errorf("feature 0 class means = %v, %v, want class 1 higher", byClass[0].Mean, byClass[1].Mean)
+	}
+}
+
+func TestDescribeMaxClasses(t *testing.T) {
+	X := [][]float64{{1}, {2}, {3}, {4}}
+	y := []float64{0, 1, 2, 3}
+
+	p, err := Describe(X, y, DescribeOptions{MaxClasses: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Classes != nil {
+		t.Errorf("4 distinct targets with MaxClasses 3: Classes = %v, want nil", p.Classes)
+	}
+
+	p, err = Describe(X, nil, DescribeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Classes != nil {
+		t.Errorf("nil target: Classes = %v, want nil", p.Classes)
+	}
+}
+
+func TestDatasetDescribeNames(t *testing.T) {
+	path := writeTestCSV(t, "describe.csv", `size,color,label
+1.0,red,0
+2.0,blue,1
+3.0,red,1
+`)
+	ds, err := LoadCSV(path, -1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := ds.Describe(DescribeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Features[0].Name != "size" || p.Features[1].Name != "color" {
+		t.Errorf("names = %q, %q, want size, color", p.Features[0].Name, p.Features[1].Name)
+	}
+	if !slices.Equal(p.ClassCounts, []int{1, 2}) {
+		t.Errorf("ClassCounts = %v, want [1 2]", p.ClassCounts)
+	}
+}
+
+func TestDescribeErrors(t *testing.T) {
+	X := [][]float64{{1}, {2}}
+	tests := []struct {
+		name string
+		X    [][]float64
+		y    []float64
+		opts DescribeOptions
+		want error
+	}{
+		{"empty", nil, nil, DescribeOptions{}, ErrEmptyDataset},
+		{"length mismatch", X, []float64{0}, DescribeOptions{}, ErrLengthMismatch},
+		{"ragged", [][]float64{{1}, {1, 2}}, nil, DescribeOptions{}, ErrFeatureCountMismatch},
+		{"negative bins", X, nil, DescribeOptions{Bins: -1}, ErrInvalidBins},
+		{"quantile above 1", X, nil, DescribeOptions{Quantiles: []float64{1.5}}, ErrInvalidQuantile},
+		{"NaN quantile", X, nil, DescribeOptions{Quantiles: []float64{math.NaN()}}, ErrInvalidQuantile},
+		{"negative MaxClasses", X, nil, DescribeOptions{MaxClasses: -1}, ErrInvalidMaxClasses},
+	}
+	for _, tt := range tests {
+		if _, err := Describe(tt.X, tt.y, tt.opts); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package gboost
+
+import (
+	"cmp"
+	"slices"
+)
+
+// TrainingDiagnostics holds the final fit of every training row, recorded
+// when Config.KeepDiagnostics is set. Rows the model fits worst are often
+// label errors, duplicates with conflicting targets, or genuinely unusual
+// cases worth a closer look.
+type TrainingDiagnostics struct {
+	// Residuals[i] is the final negative gradient of row i: y - prediction
+	// for MSE, or y - P(y=1) for logloss.
+	Residuals []float64
+
+	// Losses[i] is the final loss of row i: squared error for MSE, or
+	// binary cross-entropy for logloss.
+	Losses []float64
+
+	// Validation[i] reports whether row i was held out by
+	// Config.ValidationFraction rather than fitted.
+	Validation []bool
+}
+
+// TrainingDiagnostics returns the per-sample diagnostics of the last call to
+// [GBM.Fit], or nil if Config.KeepDiagnostics was not set. Models loaded
+// from disk have no diagnostics.
+func (g *GBM) TrainingDiagnostics() *TrainingDiagnostics {
+	return g.diagnostics
+}
+
+func (g *GBM) newTrainingDiagnostics(X [][]float64, y []float64, valIndices []int) *TrainingDiagnostics {
+	pred := g.Predict(X)
+	d := &TrainingDiagnostics{
+		Residuals:  g.loss.NegativeGradient(y, pred),
+		Losses:     make([]float64, len(y)),
+		Validation: make([]bool, len(y)),
+	}
+	for i := range y {
+		d.Losses[i] = evalLoss(g.Config.Loss, y[i:i+1], pred[i:i+1], nil)
+	}
+	for _, i := range valIndices {
+		d.Validation[i] = true
+	}
+	return d
+}
+
+// WorstFit returns the indices of the k training rows with the highest
+// loss, worst first. Ties are broken by row index.
+func (d *TrainingDiagnostics) WorstFit(k int) []int {
+	order := make([]int, len(d.Losses))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(d.Losses[b], d.Losses[a])
+	})
+	return order[:min(max(k, 0), len(order))]
+}
+
+// ResidualQuantiles returns the given quantiles, each in [0, 1], of the
+// residuals, interpolating linearly between order statistics. For example
+// ResidualQuantiles(0.01, 0.5, 0.99) summarizes the center and both tails.
+func (d *TrainingDiagnostics) ResidualQuantiles(qs ...float64) []float64 {
+	sorted := slices.Sorted(slices.Values(d.Residuals))
+	res := make([]float64, len(qs))
+	for i, q := range qs {
+		res[i] = quantileSorted(sorted, q)
+	}
+	return res
+}
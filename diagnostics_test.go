@@ -0,0 +1,109 @@
+package gboost
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestTrainingDiagnosticsFindsLabelErrors(t *testing.T) {
+	X, y := generateBinaryData(5)
+	// Flip a few labels far from the decision boundary.
+	flipped := []int{}
+	for i, x := range X {
+		if len(flipped) < 3 && (x[0] < 1 || x[0] > 9) {
+			y[i] = 1 - y[i]
+			flipped = append(flipped, i)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 20
+	cfg.MaxDepth = 2
+	cfg.MinSamplesLeaf = 10
+	cfg.KeepDiagnostics = true
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	d := gbm.TrainingDiagnostics()
+	if d == nil {
+		t.Fatal("TrainingDiagnostics() = nil with KeepDiagnostics set")
+	}
+	if len(d.Residuals) != len(y) || len(d.Losses) != len(y) {
+		t.Fatalf("got %d residuals and %d losses for %d rows", len(d.Residuals), len(d.Losses), len(y))
+	}
+	worst := d.WorstFit(3)
+	slices.Sort(worst)
+	if !slices.Equal(worst, flipped) {
+		t.Errorf("WorstFit(3) = %v, want the flipped rows %v", worst, flipped)
+	}
+
+	x := X[0]
+	if want := y[0] - gbm.PredictProba(x); math.Abs(d.Residuals[0]-want) > 1e-12 {
+		t.Errorf("Residuals[0] = %v, want %v", d.Residuals[0], want)
+	}
+}
+
+func TestTrainingDiagnosticsEarlyStopping(t *testing.T) {
+	X, y := generateNoisyData()
+	cfg := DefaultConfig()
+	cfg.NEstimators = 100
+	cfg.ValidationFraction = 0.25
+	cfg.Patience = 5
+	cfg.KeepDiagnostics = true
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	d := gbm.TrainingDiagnostics()
+	held := 0
+	for _, v := range d.Validation {
+		if v {
+			held++
+		}
+	}
+	if held != len(y)/4 {
+		t.Errorf("%d rows marked as validation, want %d", held, len(y)/4)
+	}
+	// Diagnostics reflect the truncated ensemble, not the last round trained.
+	pred := gbm.Predict(X)
+	for i := range y {
+		if math.Abs(d.Residuals[i]-(y[i]-pred[i])) > 1e-12 {
+			t.Fatalf("Residuals[%d] = %v, want %v", i, d.Residuals[i], y[i]-pred[i])
+		}
+		if math.Abs(d.Losses[i]-d.Residuals[i]*d.Residuals[i]) > 1e-12 {
+			t.Fatalf("Losses[%d] = %v, want the squared residual", i, d.Losses[i])
+		}
+	}
+}
+
+func TestTrainingDiagnosticsDisabled(t *testing.T) {
+	if d := fitStumpModel(t).TrainingDiagnostics(); d != nil {
+		t.Errorf("TrainingDiagnostics() = %+v without KeepDiagnostics, want nil", d)
+	}
+}
+
+func TestResidualQuantilesAndWorstFit(t *testing.T) {
+	d := &TrainingDiagnostics{
+		Residuals: []float64{3, -1, 0, 2, 1},
+		Losses:    []float64{9, 1, 0, 4, 1},
+	}
+	got := d.ResidualQuantiles(0, 0.5, 1, 0.125)
+	want := []float64{-1, 1, 3, -0.5}
+	if !slices.Equal(got, want) {
+		t.Errorf("ResidualQuantiles = %v, want %v", got, want)
+	}
+	if got := d.WorstFit(3); !slices.Equal(got, []int{0, 3, 1}) {
+		t.Errorf("WorstFit(3) = %v, want [0 3 1]", got)
+	}
+	if got := d.WorstFit(10); len(got) != 5 {
+		t.Errorf("WorstFit(10) returned %d rows, want 5", len(got))
+	}
+	if q := (&TrainingDiagnostics{}).ResidualQuantiles(0.5); !math.IsNaN(q[0]) {
+		t.Errorf("empty ResidualQuantiles = %v, want NaN", q)
+	}
+}
@@ -0,0 +1,149 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+)
+
+// Teacher is a model whose predictions [Distill] teaches to a student. Its
+// Predict returns raw outputs in the student's output space: regression
+// values for an "mse" student, log-odds for a "logloss" one, and logs of
+// expected values for a "gamma" one. [GBM], [Predictor], [QuantizedModel],
+// [HurdleModel], and [TransformedTargetRegressor] are teachers, including
+// GBMs imported with [LoadONNX] or [LoadSklearn]. Wrap any other model, such
+// as an XGBoost booster scored out of process, in a [TeacherFunc].
+type Teacher interface {
+	Predict(X [][]float64) []float64
+}
+
+// TeacherFunc adapts a function to the [Teacher] interface. A function that
+// returns probabilities must return their log-odds, log(p / (1-p)), instead.
+type TeacherFunc func(X [][]float64) []float64
+
+// Predict returns f(X).
+func (f TeacherFunc) Predict(X [][]float64) []float64 {
+	return f(X)
+}
+
+// Fidelity measures how closely a student reproduces its teacher on a set
+// of rows. Differences are between raw outputs.
+type Fidelity struct {
+	// RMSE is the root mean squared difference between the outputs, and
+	// MaxAbsDiff the largest absolute difference.
+	RMSE       float64
+	MaxAbsDiff float64
+
+	// R2 is the share of the variance of the teacher's outputs that the
+	// student reproduces, NaN if the teacher's outputs are constant.
+	R2 float64
+
+	// For a logloss student, ProbabilityMAE is the mean absolute difference
+	// between the predicted probabilities, and Agreement the fraction of
+	// rows both put on the same side of 0.5. Both are NaN otherwise.
+	ProbabilityMAE float64
+	Agreement      float64
+}
+
+// Distill trains a student model with cfg to mimic teacher on the rows of
+// X, typically a smaller model, with fewer or shallower trees, that is
+// cheap enough for edge serving. The student is fitted to the teacher's
+// outputs rather than to true labels: to its regression values for an "mse"
+// cfg, to its probabilities, as soft labels, for a "logloss" one, and to its
+// expected values for a "gamma" one. Rows without labels work as well as
+// labeled ones, so X can be enlarged with unlabeled or synthetic rows to
+// transfer more of the teacher.
+//
+// It returns the student and its [Fidelity] on X. Use [MeasureFidelity] to
+// measure it on held-out rows as well.
+//
+// Returns an error wrapping [ErrInvalidTeacherOutput] if teacher returns
+// the wrong number of outputs or a non-finite one, or any error of
+// [GBM.Fit].
+func Distill(teacher Teacher, X [][]float64, cfg Config) (*GBM, Fidelity, error) {
+	if len(X) == 0 {
+		return nil, Fidelity{}, ErrEmptyDataset
+	}
+	targets, err := teacherOutputs(teacher, X)
+	if err != nil {
+		return nil, Fidelity{}, err
+	}
+	for i, v := range targets {
+		targets[i] = inverseLink(cfg.Loss, v)
+	}
+	student := New(cfg)
+	if err := student.Fit(X, targets); err != nil {
+		return nil, Fidelity{}, err
+	}
+	fidelity, err := MeasureFidelity(teacher, student, X)
+	if err != nil {
+		return nil, Fidelity{}, err
+	}
+	return student, fidelity, nil
+}
+
+// MeasureFidelity scores X with teacher and student and measures how
+// closely the student reproduces the teacher, as [Distill] does on its
+// training rows.
+//
+// Returns [ErrModelNotFitted] if the student has not been trained,
+// [ErrFeatureCountMismatch] if a row of X does not match it, or an error
+// wrapping [ErrInvalidTeacherOutput].
+func MeasureFidelity(teacher Teacher, student *GBM, X [][]float64) (Fidelity, error) {
+	switch {
+	case !student.isFitted:
+		return Fidelity{}, ErrModelNotFitted
+	case len(X) == 0:
+		return Fidelity{}, ErrEmptyDataset
+	}
+	for _, x := range X {
+		if len(x) != student.numFeatures {
+			return Fidelity{}, ErrFeatureCountMismatch
+		}
+	}
+	want, err := teacherOutputs(teacher, X)
+	if err != nil {
+		return Fidelity{}, err
+	}
+	got := student.Predict(X)
+
+	f := Fidelity{R2: math.NaN(), ProbabilityMAE: math.NaN(), Agreement: math.NaN()}
+	squared := make([]float64, len(X))
+	for i := range X {
+		d := got[i] - want[i]
+		squared[i] = d * d
+		f.MaxAbsDiff = max(f.MaxAbsDiff, math.Abs(d))
+	}
+	f.RMSE = math.Sqrt(mean(squared))
+	if v := variance(want); v > 0 {
+		f.R2 = 1 - mean(squared)/v
+	}
+	if student.Config.Loss == "logloss" {
+		diffs := make([]float64, len(X))
+		agree := 0
+		for i := range X {
+			p, q := sigmoid(got[i]), sigmoid(want[i])
+			diffs[i] = math.Abs(p - q)
+			if (p >= 0.5) == (q >= 0.5) {
+				agree++
+			}
+		}
+		f.ProbabilityMAE = mean(diffs)
+		f.Agreement = float64(agree) / float64(len(X))
+	}
+	return f, nil
+}
+
+// teacherOutputs scores X with teacher, checking that it returns one finite
+// output per row.
+func teacherOutputs(teacher Teacher, X [][]float64) ([]float64, error) {
+	out := teacher.Predict(X)
+	if len(out) != len(X) {
+		return nil, fmt.Errorf("%w: %d outputs for %d rows", ErrInvalidTeacherOutput, len(out), len(X))
+	}
+	for i, v := range out {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("%w: row %d: %v", ErrInvalidTeacherOutput, i, v)
+		}
+	}
+	return out, nil
+}
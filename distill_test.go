@@ -0,0 +1,113 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDistill(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	teacherCfg := DefaultConfig()
+	teacherCfg.NEstimators = 200
+	teacherCfg.MaxDepth = 5
+	teacher := New(teacherCfg)
+	if err := teacher.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.NEstimators = 50
+	cfg.MaxDepth = 3
+	student, fidelity, err := Distill(teacher, X, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(student.trees) >= len(teacher.trees) {
+		t.Errorf("student has %d trees, teacher %d", len(student.trees), len(teacher.trees))
+	}
+	if fidelity.R2 < 0.8 || fidelity.RMSE <= 0 || fidelity.MaxAbsDiff < fidelity.RMSE {
+		t.Errorf("fidelity = %+v", fidelity)
+	}
+	if !math.IsNaN(fidelity.ProbabilityMAE) || !math.IsNaN(fidelity.Agreement) {
+		t.Errorf("regression fidelity has probability metrics: %+v", fidelity)
+	}
+
+	self, err := MeasureFidelity(teacher, teacher, X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if self.RMSE != 0 || self.MaxAbsDiff != 0 || self.R2 != 1 {
+		t.Errorf("self fidelity = %+v", self)
+	}
+}
+
+func TestDistillLogLoss(t *testing.T) {
+	X, y := generateBinaryData(0.5)
+	teacher := fitBinaryModel(t, X, y)
+
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 30
+	cfg.MaxDepth = 2
+	_, fidelity, err := Distill(teacher, X, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fidelity.Agreement < 0.9 || fidelity.ProbabilityMAE > 0.1 {
+		t.Errorf("fidelity = %+v", fidelity)
+	}
+
+	// A function teacher returning log-odds of a fixed rule.
+	rule := TeacherFunc(func(X [][]float64) []float64 {
+		out := make([]float64, len(X))
+		for i, x := range X {
+			out[i] = 4 * (x[0] - 0.5)
+		}
+		return out
+	})
+	if _, fidelity, err = Distill(rule, X, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if fidelity.Agreement < 0.9 {
+		t.Errorf("fidelity to function teacher = %+v", fidelity)
+	}
+}
+
+func TestDistillErrors(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	model := New(DefaultConfig())
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	short := TeacherFunc(func(X [][]float64) []float64 { return make([]float64, len(X)-1) })
+	nan := TeacherFunc(func(X [][]float64) []float64 {
+		out := make([]float64, len(X))
+		out[3] = math.NaN()
+		return out
+	})
+
+	tests := []struct {
+		name    string
+		teacher Teacher
+		X       [][]float64
+		cfg     Config
+		want    error
+	}{
+		{"empty", model, nil, DefaultConfig(), ErrEmptyDataset},
+		{"short", short, X, DefaultConfig(), ErrInvalidTeacherOutput},
+		{"nan", nan, X, DefaultConfig(), ErrInvalidTeacherOutput},
+		{"config", model, X, Config{NEstimators: -1}, ErrInvalidNEstimators},
+	}
+	for _, tt := range tests {
+		if _, _, err := Distill(tt.teacher, tt.X, tt.cfg); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if _, err := MeasureFidelity(model, New(DefaultConfig()), X); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("unfitted student: err = %v", err)
+	}
+	if _, err := MeasureFidelity(model, model, [][]float64{{1}}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("row width: err = %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+"""Train sklearn gradient boosting models on iris data, dump them for gboost.LoadSklearn, and output their predictions as JSON."""
+
+import argparse
+import json
+import os
+import sys
+
+import pandas as pd
+from sklearn.ensemble import (
+    GradientBoostingClassifier,
+    GradientBoostingRegressor,
+    HistGradientBoostingClassifier,
+    HistGradientBoostingRegressor,
+)
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), "..", "scripts"))
+from sklearn_dump import dump  # noqa: E402
+
+
+def main():
+    parser = argparse.ArgumentParser()
+    parser.add_argument("--data-dir", default="data", help="directory containing iris_train.csv and iris_test.csv")
+    parser.add_argument("--out-dir", required=True, help="directory to write the model dumps to")
+    args = parser.parse_args()
+
+    train_df = pd.read_csv(os.path.join(args.data_dir, "iris_train.csv"))
+    test_df = pd.read_csv(os.path.join(args.data_dir, "iris_test.csv"))
+    X_train = train_df.drop(columns=["label"]).values
+    y_train = train_df["label"].values
+    X_test = test_df.drop(columns=["label"]).values
+
+    # Regressors predict the last feature from the others.
+    Xr_train, yr_train = X_train[:, :-1], X_train[:, -1]
+    Xr_test = X_test[:, :-1]
+
+    models = {
+        "gb_classifier": (GradientBoostingClassifier(n_estimators=50, max_depth=3, random_state=42), X_train, y_train, X_test),
+        "hgb_classifier": (HistGradientBoostingClassifier(max_iter=50, random_state=42), X_train, y_train, X_test),
+        "gb_regressor": (GradientBoostingRegressor(n_estimators=50, max_depth=3, random_state=42), Xr_train, yr_train, Xr_test),
+        "hgb_regressor": (HistGradientBoostingRegressor(max_iter=50, random_state=42), Xr_train, yr_train, Xr_test),
+    }
+    result = {}
+    for name, (model, X, y, X_eval) in models.items():
+        model.fit(X, y)
+        # Trained without missing values, so no row is ever routed by them.
+        dump(model, os.path.join(args.out_dir, name + ".json"), missing=False)
+        if hasattr(model, "decision_function"):
+            raw = model.decision_function(X_eval)
+        else:
+            raw = model.predict(X_eval)
+        result[name] = {"X": X_eval.tolist(), "raw": raw.tolist()}
+    print(json.dumps(result))
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,137 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// UnseenPolicy selects how a [CategoryEncoder] encodes a value of a
+// categorical feature that was not in the training encodings.
+type UnseenPolicy string
+
+const (
+	UnseenError        UnseenPolicy = "error"         // Fail with ErrUnseenCategory; the default.
+	UnseenOther        UnseenPolicy = "other"         // The feature's OtherCategory code; an error if it has none.
+	UnseenMostFrequent UnseenPolicy = "most_frequent" // The feature's most frequent training code; ties go to the smallest.
+	UnseenMissing      UnseenPolicy = "missing"       // NaN, routed like any missing value.
+)
+
+// CategoryEncoder turns raw string feature rows into model input using the
+// label encodings of a training [Dataset], so that prediction-time data is
+// encoded exactly as the training data was. Build one with
+// [Dataset.CategoryEncoder] and persist it in [Pipeline.Encoder].
+type CategoryEncoder struct {
+	// NumFeatures is the number of cells in every row.
+	NumFeatures int `json:"num_features"`
+
+	// Encodings maps each categorical feature's values to codes, as in
+	// Dataset.Encodings. Other features are numeric.
+	Encodings map[int]map[string]float64 `json:"encodings,omitempty"`
+
+	// MostFrequent holds the most frequent training code of each
+	// categorical feature, used by [UnseenMostFrequent].
+	MostFrequent map[int]float64 `json:"most_frequent,omitempty"`
+
+	// Unseen is the policy for values missing from Encodings. Empty means
+	// [UnseenError].
+	Unseen UnseenPolicy `json:"unseen,omitempty"`
+}
+
+// CategoryEncoder returns an encoder for rows shaped like the dataset's
+// features, applying policy to unseen categories.
+func (ds *Dataset) CategoryEncoder(policy UnseenPolicy) *CategoryEncoder {
+	e := &CategoryEncoder{
+		Encodings:    make(map[int]map[string]float64, len(ds.Encodings)),
+		MostFrequent: make(map[int]float64, len(ds.Encodings)),
+		Unseen:       policy,
+	}
+	if len(ds.X) > 0 {
+		e.NumFeatures = len(ds.X[0])
+	} else {
+		e.NumFeatures = len(ds.FeatureNames)
+	}
+	for j, enc := range ds.Encodings {
+		e.Encodings[j] = enc
+		counts := make(map[float64]int, len(enc))
+		for _, row := range ds.X {
+			if !math.IsNaN(row[j]) {
+				counts[row[j]]++
+			}
+		}
+		best, bestCount := math.NaN(), 0
+		for v, n := range counts {
+			if n > bestCount || n == bestCount && v < best {
+				best, bestCount = v, n
+			}
+		}
+		if bestCount > 0 {
+			e.MostFrequent[j] = best
+		}
+	}
+	return e
+}
+
+// Encode converts rows of raw feature cells into model input. Cells are
+// trimmed; empty cells are missing and encode as NaN. Numeric features are
+// parsed as floats, and categorical features map through Encodings, with
+// values not seen in training encoded according to the Unseen policy.
+//
+// Returns [ErrFeatureCountMismatch] if a row's length differs from
+// NumFeatures, or an error wrapping [ErrInvalidNumericValue],
+// [ErrUnseenCategory], or [ErrInvalidUnseenPolicy] that names the row and
+// feature.
+func (e *CategoryEncoder) Encode(rows [][]string) ([][]float64, error) {
+	X := make([][]float64, len(rows))
+	for i, row := range rows {
+		if len(row) != e.NumFeatures {
+			return nil, ErrFeatureCountMismatch
+		}
+		X[i] = make([]float64, len(row))
+		for j, cell := range row {
+			v, err := e.encodeCell(j, strings.TrimSpace(cell))
+			if err != nil {
+				return nil, fmt.Errorf("row %d, feature %d: %w", i, j, err)
+			}
+			X[i][j] = v
+		}
+	}
+	return X, nil
+}
+
+func (e *CategoryEncoder) encodeCell(j int, cell string) (float64, error) {
+	if cell == "" {
+		return math.NaN(), nil
+	}
+	enc, categorical := e.Encodings[j]
+	if !categorical {
+		v, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q: %w", cell, ErrInvalidNumericValue)
+		}
+		return v, nil
+	}
+	if v, ok := enc[cell]; ok {
+		return v, nil
+	}
+
+	switch e.Unseen {
+	case UnseenError, "":
+		return 0, fmt.Errorf("%q: %w", cell, ErrUnseenCategory)
+	case UnseenOther:
+		if v, ok := enc[OtherCategory]; ok {
+			return v, nil
+		}
+		return 0, fmt.Errorf("%q: %w, and the feature has no %q category", cell, ErrUnseenCategory, OtherCategory)
+	case UnseenMostFrequent:
+		if v, ok := e.MostFrequent[j]; ok {
+			return v, nil
+		}
+		return math.NaN(), nil
+	case UnseenMissing:
+		return math.NaN(), nil
+	default:
+		return 0, ErrInvalidUnseenPolicy
+	}
+}
@@ -0,0 +1,112 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// encoderDataset has a numeric feature and a categorical one in which "b"
+// is the most frequent of three cities plus the grouped OtherCategory.
+func encoderDataset() *Dataset {
+	return &Dataset{
+		X: [][]float64{{1, 0}, {2, 1}, {3, 1}, {4, 2}, {5, 3}, {6, 1}},
+		Y: []float64{0, 1, 1, 0, 1, 1},
+		Encodings: map[int]map[string]float64{
+			1: {"a": 0, "b": 1, "c": 2, OtherCategory: 3},
+		},
+		FeatureNames: []string{"age", "city"},
+	}
+}
+
+func TestDatasetTransformUnseenPolicies(t *testing.T) {
+	ds := encoderDataset()
+	rows := [][]string{{" 7 ", "c"}, {"", "zzz"}}
+
+	tests := []struct {
+		policy UnseenPolicy
+		want   float64 // encoding of the unseen "zzz"
+	}{
+		{UnseenOther, 3},
+		{UnseenMostFrequent, 1},
+		{UnseenMissing, math.NaN()},
+	}
+	for _, tt := range tests {
+		X, err := ds.Transform(rows, tt.policy)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.policy, err)
+		}
+		if X[0][0] != 7 || X[0][1] != 2 || !math.IsNaN(X[1][0]) {
+			t.Errorf("%s: seen values encoded as %v", tt.policy, X)
+		}
+		if got := X[1][1]; got != tt.want && !(math.IsNaN(got) && math.IsNaN(tt.want)) {
+			t.Errorf("%s: unseen category encoded as %v, want %v", tt.policy, got, tt.want)
+		}
+	}
+
+	for _, policy := range []UnseenPolicy{"", UnseenError} {
+		if _, err := ds.Transform(rows, policy); !errors.Is(err, ErrUnseenCategory) {
+			t.Errorf("policy %q: err = %v, want ErrUnseenCategory", policy, err)
+		}
+	}
+}
+
+func TestCategoryEncoderErrors(t *testing.T) {
+	ds := encoderDataset()
+	delete(ds.Encodings[1], OtherCategory)
+
+	tests := []struct {
+		name   string
+		policy UnseenPolicy
+		rows   [][]string
+		want   error
+	}{
+		{"no other category", UnseenOther, [][]string{{"1", "zzz"}}, ErrUnseenCategory},
+		{"invalid policy", "ignore", [][]string{{"1", "zzz"}}, ErrInvalidUnseenPolicy},
+		{"text in numeric feature", UnseenMissing, [][]string{{"old", "a"}}, ErrInvalidNumericValue},
+		{"short row", UnseenMissing, [][]string{{"1"}}, ErrFeatureCountMismatch},
+	}
+	for _, tt := range tests {
+		if _, err := ds.Transform(tt.rows, tt.policy); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestPipelineRecordsUseEncoder(t *testing.T) {
+	ds := encoderDataset()
+	config := DefaultConfig()
+	config.Loss = "logloss"
+	config.NEstimators = 5
+	config.MinSamplesLeaf = 1
+	p := NewPipeline(New(config))
+	p.Encoder = ds.CategoryEncoder(UnseenMostFrequent)
+	if err := p.Fit(ds.X, ds.Y); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "pipeline.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPipeline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An unseen city predicts like the most frequent one, "b".
+	want, _ := p.PredictProbaAll([][]float64{{2, 1}})
+	got, err := loaded.PredictProbaRecords([][]string{{"2", "zzz"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != want[0] {
+		t.Errorf("PredictProbaRecords = %v, want %v", got[0], want[0])
+	}
+
+	p.Encoder = nil
+	if _, err := p.PredictRecords([][]string{{"2", "b"}}); !errors.Is(err, ErrInvalidNumericValue) {
+		t.Errorf("without an encoder: err = %v, want ErrInvalidNumericValue", err)
+	}
+}
@@ -2,23 +2,198 @@ package gboost
 
 import "errors"
 
-// Errors returned by [GBM.Fit] for invalid input data.
+// Errors returned by [GBM.Fit] and [GBM.FitWeighted] for invalid input data.
 var (
 	ErrEmptyDataset         = errors.New("empty dataset")
 	ErrEmptyFeatures        = errors.New("empty features")
 	ErrLengthMismatch       = errors.New("mismatch length of input matrix")
 	ErrFeatureCountMismatch = errors.New("feature count mismatch")
+	ErrInvalidSampleWeights = errors.New("sample weights must be finite and >= 0, one per sample, with a positive total")
+	ErrInvalidLabels        = errors.New("logloss targets must be in [0, 1], and 0 or 1 with a CostMatrix")
+	ErrInvalidGammaTargets  = errors.New("gamma targets must be finite and > 0")
 )
 
-// ErrModelNotFitted is returned by [GBM.Save] when the model has not been trained.
+// ErrModelNotFitted is returned by [GBM.Save] and other methods when the
+// model or transformer has not been trained.
 var ErrModelNotFitted = errors.New("model not fitted")
 
 // Errors returned by [GBM.Fit] for invalid [Config] values.
 var (
-	ErrInvalidNEstimators    = errors.New("NEstimators must be >= 0")
-	ErrInvalidLearningRate   = errors.New("LearningRate must be > 0")
-	ErrInvalidMaxDepth       = errors.New("MaxDepth must be >= 1")
-	ErrInvalidMinSamplesLeaf = errors.New("MinSamplesLeaf must be >= 1")
-	ErrInvalidSubsampleRatio = errors.New("SubsampleRatio must be in (0, 1]")
-	ErrInvalidLoss           = errors.New("Loss must be \"mse\" or \"logloss\"")
+	ErrInvalidNEstimators           = errors.New("NEstimators must be >= 0")
+	ErrInvalidLearningRate          = errors.New("LearningRate must be > 0")
+	ErrInvalidMaxDepth              = errors.New("MaxDepth must be >= 1")
+	ErrInvalidMinSamplesLeaf        = errors.New("MinSamplesLeaf must be >= 1")
+	ErrInvalidHierarchicalShrinkage = errors.New("HierarchicalShrinkage must be >= 0")
+	ErrInvalidHonestFraction        = errors.New("HonestFraction must be in [0, 1)")
+	ErrInvalidSubsampleRatio        = errors.New("SubsampleRatio must be in (0, 1]")
+	ErrInvalidSamplingMethod        = errors.New("SamplingMethod must be \"shuffle\", \"bernoulli\", or \"bootstrap\"")
+	ErrInvalidLoss                  = errors.New("Loss must be \"mse\", \"logloss\", or \"gamma\"")
+	ErrInvalidMinHessian            = errors.New("MinHessian must be finite and >= 0")
+	ErrInvalidValidationFraction    = errors.New("ValidationFraction must be in [0, 1)")
+	ErrInvalidPatience              = errors.New("Patience must be >= 0, and >= 1 when ValidationFraction > 0")
+	ErrInvalidMinDelta              = errors.New("MinDelta must be >= 0")
+	ErrInvalidGainStop              = errors.New("MinGainFraction must be in [0, 1) and MaxSingleLeafTrees >= 0")
+	ErrInvalidSnapshotBlend         = errors.New("SnapshotBlend must be >= 0")
+	ErrInvalidLRSchedule            = errors.New("LRPatience must be >= 0 and needs ValidationFraction > 0, LRFactor must be in [0, 1), and MinLearningRate in [0, LearningRate]")
+	ErrInvalidGOSSRates             = errors.New("GOSSTopRate and GOSSOtherRate must be in (0, 1) with a sum of at most 1")
+	ErrGOSSWithSubsampling          = errors.New("GOSS cannot be combined with SubsampleRatio < 1, bootstrap sampling, or NegativeSampleRatio")
+	ErrInvalidColsampleByTree       = errors.New("ColsampleByTree must be in [0, 1]")
+	ErrInvalidNegativeSampleRatio   = errors.New("NegativeSampleRatio must be in [0, 1], and below 1 only with logloss")
+	ErrInvalidSplitWorkers          = errors.New("SplitWorkers must be >= 0")
+	ErrInvalidSplitThresholds       = errors.New("SplitThresholds must map feature indices to finite thresholds")
+	ErrInvalidLeafTransform         = errors.New("LeafTransform must have valid parameters and return finite values")
+	ErrInvalidEvalSlices            = errors.New("EvalSlices need unique, non-empty names, one target per row, and Every >= 0")
+	ErrInvalidCostMatrix            = errors.New("CostMatrix must be 2x2 with finite costs >= 0, errors costing more than correct predictions, and is only valid with logloss")
 )
+
+// Errors returned by [CrossValidate], [GridSearch], and [RepeatedCV] for
+// invalid [CVOptions] or arguments.
+var (
+	ErrInvalidFolds   = errors.New("Folds must be >= 2 and <= number of samples")
+	ErrNilMetric      = errors.New("Metric must not be nil")
+	ErrInvalidWorkers = errors.New("Workers must be >= 0")
+	ErrEmptyGrid      = errors.New("no candidate configurations")
+	ErrInvalidRepeats = errors.New("repeats must be >= 1")
+)
+
+// ErrInvalidFold is returned by [Fold.Split], [SaveFolds], and [LoadFolds]
+// for a fold with an empty side or with an index that is repeated or out of
+// range.
+var ErrInvalidFold = errors.New("fold needs non-empty train and test sets of distinct, in-range indices")
+
+// Errors returned by [SelectFeaturesRFE].
+var (
+	ErrInvalidRFEKeep = errors.New("keep must be >= 1 and <= number of features")
+	ErrInvalidRFEStep = errors.New("Step must be >= 0")
+)
+
+// ErrInvalidBorutaOptions is returned by [SelectFeaturesBoruta] for a
+// negative MaxRuns or an Alpha outside [0, 1).
+var ErrInvalidBorutaOptions = errors.New("MaxRuns must be >= 0 and Alpha in [0, 1)")
+
+// ErrInvalidSeedCount is returned by [SeedImportance] when fewer than two
+// seeds are requested.
+var ErrInvalidSeedCount = errors.New("Seeds must be >= 2")
+
+// Errors returned by [GBM.Counterfactual].
+var (
+	ErrNotClassifier    = errors.New("model was not trained with logloss")
+	ErrNoCounterfactual = errors.New("no counterfactual found")
+)
+
+// Errors returned by [UpliftModel.Fit].
+var (
+	ErrInvalidUpliftMethod  = errors.New("uplift method must be \"two-model\" or \"transformed-outcome\"")
+	ErrNoTreatmentVariation = errors.New("need both treated and control samples")
+)
+
+// Errors returned by [TransformedTargetRegressor.Fit].
+var (
+	ErrInvalidTargetTransform = errors.New("target transform must be \"log1p\", \"box-cox\", or \"custom\" with a function and its inverse")
+	ErrTargetOutOfDomain      = errors.New("target outside the domain of the transform")
+)
+
+// Errors returned by transformers and [Pipeline].
+var (
+	ErrInvalidImputeStrategy  = errors.New("impute strategy must be \"mean\", \"median\", \"most_frequent\", or \"constant\"")
+	ErrUnsupportedTransformer = errors.New("transformer cannot be persisted")
+	ErrNonBinaryTarget        = errors.New("target must contain both 0 and 1 and no other values")
+)
+
+// Errors returned by [CategoryEncoder.Encode] and [Dataset.Transform].
+var (
+	ErrUnseenCategory      = errors.New("category not seen in training")
+	ErrInvalidUnseenPolicy = errors.New("unseen category policy must be \"error\", \"other\", \"most_frequent\", or \"missing\"")
+	ErrInvalidNumericValue = errors.New("value of a numeric feature is not a number")
+)
+
+// Errors returned by [GBM.PredictNamed], [GBM.NamedRow], and
+// [Node.PredictNamed] for a sample given by feature name.
+var (
+	ErrUnknownFeature = errors.New("unknown feature name")
+	ErrMissingFeature = errors.New("missing value for feature")
+)
+
+// ErrInvalidAliases is wrapped by the errors of [GBM.AliasFeatureNames],
+// [GBM.UnaliasFeatureNames], and [Dataset.AliasFeatureNames] for a mapping
+// that does not give every feature name its own alias.
+var ErrInvalidAliases = errors.New("invalid feature name aliases")
+
+// Errors returned by [ParseONNX] and [LoadONNX].
+var (
+	ErrInvalidONNX     = errors.New("invalid ONNX model")
+	ErrUnsupportedONNX = errors.New("unsupported ONNX tree ensemble")
+)
+
+// Errors returned by [ParseSklearn] and [LoadSklearn].
+var (
+	ErrInvalidSklearnDump = errors.New("invalid scikit-learn model dump")
+	ErrUnsupportedSklearn = errors.New("unsupported scikit-learn model")
+)
+
+// ErrInvalidCacheSize is returned by [GBM.NewPredictor] for a negative cache size.
+var ErrInvalidCacheSize = errors.New("cache size must be >= 0")
+
+// ErrInvalidChunkSize is returned by [GBM.PredictBatch] for a negative [BatchOptions.ChunkSize].
+var ErrInvalidChunkSize = errors.New("ChunkSize must be >= 0")
+
+// Errors returned by [Describe] for invalid [DescribeOptions].
+var (
+	ErrInvalidBins       = errors.New("Bins must be >= 0")
+	ErrInvalidQuantile   = errors.New("quantiles must be in [0, 1]")
+	ErrInvalidMaxClasses = errors.New("MaxClasses must be >= 0")
+)
+
+// ErrIncompatibleModels is returned by [CompareExplanations] for models
+// whose outputs are not in the same units.
+var ErrIncompatibleModels = errors.New("models differ in loss")
+
+// ErrInvalidTeacherOutput is wrapped by the errors of [Distill] and
+// [MeasureFidelity] when a teacher returns the wrong number of outputs or a
+// non-finite one.
+var ErrInvalidTeacherOutput = errors.New("invalid teacher output")
+
+// Errors returned by [GroupedModel.Fit] and [GroupedModel.Predict].
+var (
+	ErrInvalidGroupColumn = errors.New("invalid group column")
+	ErrUnknownGroup       = errors.New("no model for group")
+)
+
+// ErrInvalidExposure is returned by [FrequencySeverityModel.Fit] and
+// [FrequencySeverityModel.Predict] for exposures that are not finite and
+// positive, one per sample.
+var ErrInvalidExposure = errors.New("exposures must be finite and > 0, one per sample")
+
+// ErrInvalidBacktest is returned by [Backtest] and [RollingOriginFolds] for
+// a window, horizon, or step below 1, or a window and horizon longer than
+// the data.
+var ErrInvalidBacktest = errors.New("window, horizon, and step must be >= 1, with window + horizon <= number of samples")
+
+// ErrInvalidTimeSeriesFeatures is wrapped by the errors of
+// [TimeSeriesFeatures.Fit] for a configuration that does not fit the data.
+var ErrInvalidTimeSeriesFeatures = errors.New("invalid time series features")
+
+// ErrNoModels is returned by [LoadRegistryReport] for a directory that
+// holds no saved model.
+var ErrNoModels = errors.New("no saved models in directory")
+
+// Errors returned by [CheckOverfit].
+var (
+	ErrInvalidOverfitCheck = errors.New("overfit check rows must be >= 0 and tolerance in [0, 1)")
+	ErrOverfitCheckFailed  = errors.New("model cannot overfit a small sample")
+)
+
+// ErrInvalidLeakageThreshold is returned by [DetectLeakage] and [Describe]
+// for a [LeakageOptions.Threshold] outside (0, 1].
+var ErrInvalidLeakageThreshold = errors.New("leakage threshold must be in (0, 1]")
+
+// ErrInvalidSchema is wrapped by the errors of [LoadSchema] and
+// [CSVSchema.LoadCSV] when a schema is inconsistent or a file does not
+// match it.
+var ErrInvalidSchema = errors.New("invalid csv schema")
+
+// ErrInvalidGradients is returned by [GBM.BoostOneRound] for a gradient or
+// Hessian that is not finite, a Hessian that is not positive after
+// [Config.MinHessian] flooring, or derivatives large enough to overflow a
+// leaf value. It is wrapped with the offending row and values.
+var ErrInvalidGradients = errors.New("gradients must be finite and Hessians finite and > 0")
@@ -0,0 +1,89 @@
+package gboost
+
+import "fmt"
+
+// EvalSlice is a named data segment, such as the most recent quarter or the
+// high-value customers, scored during training by [GBM.Fit]. A model can
+// improve on the validation set as a whole while it overfits one segment;
+// scoring the segments separately every few rounds shows it early. Scores
+// appear in [RoundStats.Slices].
+type EvalSlice struct {
+	Name string
+	X    [][]float64
+	Y    []float64
+
+	// Metrics score the slice, by name. Like [CrossValidate] metrics they
+	// receive probabilities for logloss models, expected values for gamma
+	// models, and raw predictions otherwise. Empty means the training loss,
+	// reported as "loss".
+	Metrics map[string]Metric
+
+	// Every is the interval, in rounds, between evaluations: the slice is
+	// scored after rounds Every, 2·Every, and so on. Zero means every round.
+	Every int
+}
+
+// validEvalSlices reports whether every slice has a unique, non-empty name,
+// one target per row, and a non-negative interval.
+func (c Config) validEvalSlices() bool {
+	seen := make(map[string]bool, len(c.EvalSlices))
+	for _, s := range c.EvalSlices {
+		if s.Name == "" || seen[s.Name] || len(s.X) != len(s.Y) || s.Every < 0 {
+			return false
+		}
+		seen[s.Name] = true
+	}
+	return true
+}
+
+// sliceScorer tracks the raw predictions of one [EvalSlice] as trees are
+// added, so that evaluating it costs one tree traversal per row and round.
+type sliceScorer struct {
+	slice EvalSlice
+	raw   []float64
+}
+
+// newSliceScorers checks the slices' width against the training data and
+// starts their predictions at initialPrediction.
+func newSliceScorers(slices []EvalSlice, numFeatures int, initialPrediction float64) ([]*sliceScorer, error) {
+	scorers := make([]*sliceScorer, len(slices))
+	for k, s := range slices {
+		for _, row := range s.X {
+			if len(row) != numFeatures {
+				return nil, fmt.Errorf("eval slice %q: %w", s.Name, ErrFeatureCountMismatch)
+			}
+		}
+		raw := make([]float64, len(s.X))
+		for i := range raw {
+			raw[i] = initialPrediction
+		}
+		scorers[k] = &sliceScorer{slice: s, raw: raw}
+	}
+	return scorers, nil
+}
+
+// add updates the predictions with the round's tree and returns the slice's
+// scores if round is due for evaluation, or nil.
+func (s *sliceScorer) add(tree *Node, cfg Config, round int) map[string]float64 {
+	for i, x := range s.slice.X {
+		s.raw[i] += float64(cfg.LearningRate * tree.predict(x))
+	}
+	if round%max(s.slice.Every, 1) != 0 {
+		return nil
+	}
+	if len(s.slice.Metrics) == 0 {
+		return map[string]float64{"loss": evalLoss(cfg.Loss, s.slice.Y, s.raw, nil)}
+	}
+	output := s.raw
+	if cfg.Loss != "mse" {
+		output = make([]float64, len(s.raw))
+		for i, v := range s.raw {
+			output[i] = inverseLink(cfg.Loss, v)
+		}
+	}
+	scores := make(map[string]float64, len(s.slice.Metrics))
+	for name, metric := range s.slice.Metrics {
+		scores[name] = metric(s.slice.Y, output)
+	}
+	return scores
+}
@@ -0,0 +1,167 @@
+package gboost
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Explanation holds the SHAP explanations of a scored batch, ready to be
+// written with [Explanation.WriteCSV] or [Explanation.WriteJSON] for
+// dashboards and other tools that do not read gboost models.
+type Explanation struct {
+	// Features names the columns: [GBM.FeatureNames], or f0, f1, ... for
+	// models without names.
+	Features []string
+
+	// BaseValue is [GBM.BaseValue], from which every row's contributions are
+	// measured.
+	BaseValue float64
+
+	// Rows holds the explained inputs, with NaN for missing values.
+	Rows [][]float64
+
+	// Predictions holds the raw prediction for each row (log-odds for
+	// logloss), and Probabilities P(y=1) for logloss models only.
+	Predictions   []float64
+	Probabilities []float64
+
+	// Contributions holds the SHAP value of each row and feature, so that
+	// BaseValue plus the row's contributions is its raw prediction.
+	Contributions [][]float64
+}
+
+// Explain scores X and explains each prediction with [GBM.ShapValues].
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or
+// [ErrFeatureCountMismatch] if any row of X does not have numFeatures columns.
+func (g *GBM) Explain(X [][]float64) (*Explanation, error) {
+	shap, err := g.ShapValues(X)
+	if err != nil {
+		return nil, err
+	}
+	e := &Explanation{
+		Features:      g.names(),
+		BaseValue:     g.BaseValue(),
+		Rows:          X,
+		Predictions:   g.Predict(X),
+		Contributions: shap,
+	}
+	if g.Config.Loss == "logloss" {
+		e.Probabilities = make([]float64, len(X))
+		for i, p := range e.Predictions {
+			e.Probabilities[i] = sigmoid(p)
+		}
+	}
+	return e, nil
+}
+
+// WriteCSV writes one line per row under a header of stable column names:
+//
+//	row,prediction[,probability],base_value,value_<feature>...,shap_<feature>...
+//
+// with the value and SHAP columns in feature order, and the probability
+// column only for logloss models. Missing values are empty cells, as
+// [LoadCSV] reads them. Numbers are written in the shortest form that reads
+// back exactly.
+func (e *Explanation) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"row", "prediction"}
+	if e.Probabilities != nil {
+		header = append(header, "probability")
+	}
+	header = append(header, "base_value")
+	for _, name := range e.Features {
+		header = append(header, "value_"+name)
+	}
+	for _, name := range e.Features {
+		header = append(header, "shap_"+name)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, 0, len(header))
+	for i, row := range e.Rows {
+		record = append(record[:0], strconv.Itoa(i), formatCSVFloat(e.Predictions[i]))
+		if e.Probabilities != nil {
+			record = append(record, formatCSVFloat(e.Probabilities[i]))
+		}
+		record = append(record, formatCSVFloat(e.BaseValue))
+		for _, v := range row {
+			record = append(record, formatCSVFloat(v))
+		}
+		for _, v := range e.Contributions[i] {
+			record = append(record, formatCSVFloat(v))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatCSVFloat formats v exactly, and NaN as an empty cell.
+func formatCSVFloat(v float64) string {
+	if math.IsNaN(v) {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type explanationJSON struct {
+	BaseValue float64              `json:"base_value"`
+	Features  []string             `json:"features"`
+	Rows      []explanationRowJSON `json:"rows"`
+}
+
+type explanationRowJSON struct {
+	Row           int                 `json:"row"`
+	Prediction    float64             `json:"prediction"`
+	Probability   *float64            `json:"probability,omitempty"`
+	Values        map[string]*float64 `json:"values"`
+	Contributions map[string]float64  `json:"contributions"`
+}
+
+// WriteJSON writes the explanation as one JSON document:
+//
+//	{"base_value": ..., "features": [...], "rows": [
+//	  {"row": 0, "prediction": ..., "probability": ...,
+//	   "values": {"<feature>": ...}, "contributions": {"<feature>": ...}}, ...]}
+//
+// Values and contributions are keyed by feature name, with keys sorted, and
+// "features" lists the names in column order. Missing values are null, and
+// "probability" is present for logloss models only.
+func (e *Explanation) WriteJSON(w io.Writer) error {
+	out := explanationJSON{
+		BaseValue: e.BaseValue,
+		Features:  e.Features,
+		Rows:      make([]explanationRowJSON, len(e.Rows)),
+	}
+	for i, row := range e.Rows {
+		r := explanationRowJSON{
+			Row:           i,
+			Prediction:    e.Predictions[i],
+			Values:        make(map[string]*float64, len(row)),
+			Contributions: make(map[string]float64, len(row)),
+		}
+		if e.Probabilities != nil {
+			r.Probability = &e.Probabilities[i]
+		}
+		for j, name := range e.Features {
+			var v *float64
+			if !math.IsNaN(row[j]) {
+				v = &row[j]
+			}
+			r.Values[name] = v
+			r.Contributions[name] = e.Contributions[i][j]
+		}
+		out.Rows[i] = r
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,128 @@
+package gboost
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func fitExplainModel(t *testing.T, loss string) *GBM {
+	t.Helper()
+	X, y := generateDataWithFunc(linearFunc)
+	if loss == "logloss" {
+		for i, v := range y {
+			y[i] = 0
+			if v > 0 {
+				y[i] = 1
+			}
+		}
+	}
+	cfg := DefaultConfig()
+	cfg.Loss = loss
+	cfg.NEstimators = 10
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	return gbm
+}
+
+func TestExplanationWriteCSV(t *testing.T) {
+	gbm := fitExplainModel(t, "logloss")
+	if err := gbm.SetFeatureNames([]string{"age", "income, net"}); err != nil {
+		t.Fatal(err)
+	}
+	X := [][]float64{{0.2, 0.7}, {math.NaN(), 0.1}}
+	e, err := gbm.Explain(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := e.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"row", "prediction", "probability", "base_value", "value_age", "value_income, net", "shap_age", "shap_income, net"}
+	if !slices.Equal(records[0], want) {
+		t.Fatalf("header = %q, want %q", records[0], want)
+	}
+	if len(records) != 3 {
+		t.Fatalf("%d records, want a header and 2 rows", len(records))
+	}
+	for i, rec := range records[1:] {
+		parse := func(k int) float64 {
+			v, err := strconv.ParseFloat(rec[k], 64)
+			if err != nil {
+				t.Fatalf("row %d, column %s: %v", i, want[k], err)
+			}
+			return v
+		}
+		if pred := parse(1); pred != gbm.PredictSingle(X[i]) || parse(2) != gbm.PredictProba(X[i]) {
+			t.Errorf("row %d: prediction %s, probability %s", i, rec[1], rec[2])
+		}
+		if total := parse(3) + parse(6) + parse(7); math.Abs(total-parse(1)) > 1e-9 {
+			t.Errorf("row %d: base value and contributions sum to %v, prediction %v", i, total, parse(1))
+		}
+	}
+	if records[2][4] != "" {
+		t.Errorf("missing value written as %q, want an empty cell", records[2][4])
+	}
+}
+
+func TestExplanationWriteJSON(t *testing.T) {
+	gbm := fitExplainModel(t, "mse")
+	X := [][]float64{{0.2, math.NaN()}}
+	e, err := gbm.Explain(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := e.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		BaseValue float64  `json:"base_value"`
+		Features  []string `json:"features"`
+		Rows      []map[string]json.RawMessage
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.BaseValue != gbm.BaseValue() || !slices.Equal(got.Features, []string{"f0", "f1"}) || len(got.Rows) != 1 {
+		t.Fatalf("document = %s", buf.String())
+	}
+	row := got.Rows[0]
+	if _, ok := row["probability"]; ok {
+		t.Error("regression explanation has a probability")
+	}
+	var values map[string]*float64
+	var contributions map[string]float64
+	json.Unmarshal(row["values"], &values)
+	json.Unmarshal(row["contributions"], &contributions)
+	if values["f0"] == nil || *values["f0"] != 0.2 || values["f1"] != nil {
+		t.Errorf("values = %s, want f0 0.2 and f1 null", row["values"])
+	}
+	phi, _ := gbm.ShapValuesSingle(X[0])
+	if contributions["f0"] != phi[0] || contributions["f1"] != phi[1] {
+		t.Errorf("contributions = %v, want %v", contributions, phi)
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	if _, err := New(DefaultConfig()).Explain([][]float64{{1}}); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("untrained: err = %v, want ErrModelNotFitted", err)
+	}
+	if _, err := fitExplainModel(t, "mse").Explain([][]float64{{1}}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("short row: err = %v, want ErrFeatureCountMismatch", err)
+	}
+}
@@ -0,0 +1,143 @@
+package gboost
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TreeStats summarizes the shape of a single trained tree.
+type TreeStats struct {
+	Depth  int     // Longest root-to-leaf path, in edges. A single-leaf tree has depth 0.
+	Leaves int     // Number of leaf nodes.
+	Gain   float64 // Sum of sample-weighted split gains, in feature importance units.
+}
+
+// NumTrees returns the number of trees in the ensemble.
+func (g *GBM) NumTrees() int {
+	return len(g.trees)
+}
+
+// NumFeatures returns the number of features the model was trained on,
+// or 0 if the model has not been trained.
+func (g *GBM) NumFeatures() int {
+	return g.numFeatures
+}
+
+// SetFeatureNames attaches column names to the model. They are persisted by
+// [GBM.Save] and used by the tree exporters. On a trained model, names must
+// have one entry per feature; on an untrained model, [GBM.Fit] checks the
+// length against the training data. Passing nil removes the names. The new
+// names are not aliases; see [GBM.AliasFeatureNames].
+func (g *GBM) SetFeatureNames(names []string) error {
+	if names != nil && g.isFitted && len(names) != g.numFeatures {
+		return ErrFeatureCountMismatch
+	}
+	g.featureNames = names
+	g.aliasedNames = false
+	return nil
+}
+
+// FeatureNames returns the names set with [GBM.SetFeatureNames], or nil.
+func (g *GBM) FeatureNames() []string {
+	return g.featureNames
+}
+
+// featureName returns the display name of feature j: its configured name,
+// or "f<j>" if the model has none.
+func (g *GBM) featureName(j int) string {
+	if g.featureNames != nil {
+		return g.featureNames[j]
+	}
+	return fmt.Sprintf("f%d", j)
+}
+
+// TreeStats returns the depth, leaf count, and total gain of each tree in
+// boosting order.
+func (g *GBM) TreeStats() []TreeStats {
+	stats := make([]TreeStats, len(g.trees))
+	for i, tree := range g.trees {
+		stats[i] = tree.stats()
+	}
+	return stats
+}
+
+func (n *Node) stats() TreeStats {
+	return TreeStats{
+		Depth:  n.depth(),
+		Leaves: n.numLeaves(),
+		Gain:   n.totalGain(),
+	}
+}
+
+// DumpTrees writes a human-readable, indented dump of every tree to w. Split
+// lines read "[feature < threshold]"; samples failing the test go to the
+// second child. Leaf values are shown before learning-rate scaling.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained.
+func (g *GBM) DumpTrees(w io.Writer) error {
+	if !g.isFitted {
+		return ErrModelNotFitted
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, tree := range g.trees {
+		fmt.Fprintf(bw, "tree %d\n", i)
+		g.dumpNode(bw, tree, 1)
+	}
+	return bw.Flush()
+}
+
+func (g *GBM) dumpNode(w io.Writer, n *Node, indent int) {
+	pad := strings.Repeat("  ", indent)
+	if n.isLeaf() {
+		fmt.Fprintf(w, "%sleaf=%g samples=%d\n", pad, n.Value, n.NSamples)
+		return
+	}
+	fmt.Fprintf(w, "%s[%s < %g] samples=%d gain=%g\n", pad, g.featureName(n.FeatureIndex), n.Threshold, n.NSamples, n.Gain)
+	g.dumpNode(w, n.Left, indent+1)
+	g.dumpNode(w, n.Right, indent+1)
+}
+
+// WriteDot writes tree number tree in Graphviz DOT format to w. Render it
+// with e.g. "dot -Tsvg tree.dot -o tree.svg".
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or an error
+// if tree is out of range.
+func (g *GBM) WriteDot(w io.Writer, tree int) error {
+	if !g.isFitted {
+		return ErrModelNotFitted
+	}
+	if tree < 0 || tree >= len(g.trees) {
+		return fmt.Errorf("tree index %d out of range for %d trees", tree, len(g.trees))
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph tree%d {\n", tree)
+	fmt.Fprintln(bw, "  node [shape=box, fontname=\"Helvetica\"];")
+	next := 0
+	g.dotNode(bw, g.trees[tree], &next)
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotNode emits n and its subtree, numbering nodes in pre-order, and returns
+// the ID assigned to n.
+func (g *GBM) dotNode(w io.Writer, n *Node, next *int) int {
+	id := *next
+	*next++
+
+	if n.isLeaf() {
+		fmt.Fprintf(w, "  n%d [label=\"leaf=%g\\nsamples=%d\", style=filled, fillcolor=\"#e8f0fe\"];\n", id, n.Value, n.NSamples)
+		return id
+	}
+
+	name := strings.ReplaceAll(g.featureName(n.FeatureIndex), `"`, `\"`)
+	fmt.Fprintf(w, "  n%d [label=\"%s < %g\\nsamples=%d\\ngain=%g\"];\n", id, name, n.Threshold, n.NSamples, n.Gain)
+	left := g.dotNode(w, n.Left, next)
+	right := g.dotNode(w, n.Right, next)
+	fmt.Fprintf(w, "  n%d -> n%d [label=\"yes\"];\n", id, left)
+	fmt.Fprintf(w, "  n%d -> n%d [label=\"no\"];\n", id, right)
+	return id
+}
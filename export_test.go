@@ -0,0 +1,157 @@
+package gboost
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func fitStumpModel(t *testing.T) *GBM {
+	t.Helper()
+	X := [][]float64{{1}, {2}, {3}, {4}}
+	y := []float64{1, 1, 10, 10}
+
+	cfg := DefaultConfig()
+	cfg.NEstimators = 2
+	cfg.MaxDepth = 1
+
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	return gbm
+}
+
+func TestNumTreesAndFeatures(t *testing.T) {
+	gbm := fitStumpModel(t)
+	if gbm.NumTrees() != 2 {
+		t.Errorf("NumTrees() = %d, want 2", gbm.NumTrees())
+	}
+	if gbm.NumFeatures() != 1 {
+		t.Errorf("NumFeatures() = %d, want 1", gbm.NumFeatures())
+	}
+}
+
+func TestTreeStats(t *testing.T) {
+	gbm := fitStumpModel(t)
+	stats := gbm.TreeStats()
+
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 tree stats, got %d", len(stats))
+	}
+	for i, s := range stats {
+		if s.Depth != 1 || s.Leaves != 2 {
+			t.Errorf("tree %d: depth=%d leaves=%d, want depth=1 leaves=2", i, s.Depth, s.Leaves)
+		}
+		if s.Gain <= 0 {
+			t.Errorf("tree %d: gain=%v, want > 0", i, s.Gain)
+		}
+	}
+}
+
+func TestSetFeatureNames(t *testing.T) {
+	gbm := fitStumpModel(t)
+
+	if err := gbm.SetFeatureNames([]string{"a", "b"}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+	if err := gbm.SetFeatureNames([]string{"age"}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(gbm.FeatureNames(), []string{"age"}) {
+		t.Errorf("FeatureNames() = %v, want [age]", gbm.FeatureNames())
+	}
+}
+
+func TestFitRejectsFeatureNamesLengthMismatch(t *testing.T) {
+	gbm := New(DefaultConfig())
+	if err := gbm.SetFeatureNames([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gbm.Fit([][]float64{{1}, {2}}, []float64{1, 2}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+}
+
+func TestSaveLoadPreservesFeatureNamesAndGain(t *testing.T) {
+	gbm := fitStumpModel(t)
+	gbm.SetFeatureNames([]string{"age"})
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := gbm.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(loaded.FeatureNames(), []string{"age"}) {
+		t.Errorf("FeatureNames() = %v, want [age]", loaded.FeatureNames())
+	}
+	if !slices.Equal(loaded.TreeStats(), gbm.TreeStats()) {
+		t.Errorf("TreeStats() = %v, want %v", loaded.TreeStats(), gbm.TreeStats())
+	}
+}
+
+func TestDumpTrees(t *testing.T) {
+	gbm := fitStumpModel(t)
+	gbm.SetFeatureNames([]string{"age"})
+
+	var buf bytes.Buffer
+	if err := gbm.DumpTrees(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"tree 0\n", "tree 1\n", "[age < 3]", "leaf="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dump missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDumpTreesDefaultFeatureNames(t *testing.T) {
+	gbm := fitStumpModel(t)
+
+	var buf bytes.Buffer
+	gbm.DumpTrees(&buf)
+	if !strings.Contains(buf.String(), "[f0 < 3]") {
+		t.Errorf("expected default name f0 in dump:\n%s", buf.String())
+	}
+}
+
+func TestWriteDot(t *testing.T) {
+	gbm := fitStumpModel(t)
+	gbm.SetFeatureNames([]string{`say "hi"`})
+
+	var buf bytes.Buffer
+	if err := gbm.WriteDot(&buf, 1); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"digraph tree1 {", `say \"hi\" < 3`, "n0 -> n1", "n0 -> n2", "}\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dot output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExportersErrors(t *testing.T) {
+	var buf bytes.Buffer
+	unfitted := New(DefaultConfig())
+
+	if err := unfitted.DumpTrees(&buf); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("DumpTrees: expected ErrModelNotFitted, got %v", err)
+	}
+	if err := unfitted.WriteDot(&buf, 0); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("WriteDot: expected ErrModelNotFitted, got %v", err)
+	}
+	if err := fitStumpModel(t).WriteDot(&buf, 2); err == nil {
+		t.Error("WriteDot: expected error for out-of-range tree")
+	}
+}
@@ -0,0 +1,49 @@
+package gboost
+
+// Reweigh returns sample weights that make the label statistically
+// independent of the group in the weighted training data, the reweighing
+// method of Kamiran and Calders. groups[i] names the group of sample i, such
+// as the value of a protected attribute. A sample with group g and label c
+// gets the weight
+//
+//	P(g) · P(c) / P(g, c) = n_g · n_c / (n · n_gc),
+//
+// the frequency its group and label would have if they were independent,
+// divided by the frequency they have. Combinations that are rarer than
+// independence predicts, such as positives in a group with a low positive
+// rate, are weighted up, and every group ends up with the overall positive
+// rate while the total weight stays n. Pass the weights to [GBM.FitWeighted].
+//
+// Reweighing only changes the training distribution; check its effect on
+// held-out data with metrics.GroupReport.
+//
+// Returns [ErrEmptyDataset] if y is empty, or [ErrLengthMismatch] if groups
+// and y differ in length.
+func Reweigh(groups []string, y []float64) ([]float64, error) {
+	switch {
+	case len(y) == 0:
+		return nil, ErrEmptyDataset
+	case len(groups) != len(y):
+		return nil, ErrLengthMismatch
+	}
+
+	type cell struct {
+		group string
+		label float64
+	}
+	byGroup := make(map[string]int)
+	byLabel := make(map[float64]int)
+	byCell := make(map[cell]int)
+	for i, g := range groups {
+		byGroup[g]++
+		byLabel[y[i]]++
+		byCell[cell{g, y[i]}]++
+	}
+
+	n := float64(len(y))
+	weights := make([]float64, len(y))
+	for i, g := range groups {
+		weights[i] = float64(byGroup[g]) * float64(byLabel[y[i]]) / (n * float64(byCell[cell{g, y[i]}]))
+	}
+	return weights, nil
+}
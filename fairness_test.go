@@ -0,0 +1,98 @@
+package gboost
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReweigh(t *testing.T) {
+	// Group a is 3/4 positive and group b 1/4; overall half are positive.
+	groups := []string{"a", "a", "a", "a", "b", "b", "b", "b"}
+	y := []float64{1, 1, 1, 0, 1, 0, 0, 0}
+	w, err := Reweigh(groups, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// n_g · n_c / (n · n_gc): 4·4 / (8·3) for the majority label of each
+	// group, 4·4 / (8·1) for the minority label.
+	want := []float64{2.0 / 3, 2.0 / 3, 2.0 / 3, 2, 2, 2.0 / 3, 2.0 / 3, 2.0 / 3}
+	assert.InDeltaSlice(t, want, w, 1e-12)
+
+	// Weighted, each group has the overall positive rate, and the total
+	// weight is unchanged.
+	for _, g := range []string{"a", "b"} {
+		var pos, total float64
+		for i := range y {
+			if groups[i] == g {
+				pos += w[i] * y[i]
+				total += w[i]
+			}
+		}
+		assert.InDelta(t, 0.5, pos/total, 1e-12, "weighted positive rate of group %s", g)
+	}
+	assert.InDelta(t, 8, sum(w), 1e-12)
+
+	_, err = Reweigh(groups[:3], y)
+	assert.Equal(t, ErrLengthMismatch, err)
+	_, err = Reweigh(nil, nil)
+	assert.Equal(t, ErrEmptyDataset, err)
+}
+
+func TestReweighReducesSelectionGap(t *testing.T) {
+	// The label depends on the feature x and, through a historical bias, on
+	// the group, which the model sees as feature 1.
+	rnd := rand.New(rand.NewSource(1))
+	n := 400
+	X := make([][]float64, n)
+	y := make([]float64, n)
+	groups := make([]string, n)
+	for i := range X {
+		g := rnd.Intn(2)
+		x := rnd.Float64()
+		X[i] = []float64{x, float64(g)}
+		groups[i] = []string{"a", "b"}[g]
+		p := 0.2 + 0.5*x
+		if g == 1 {
+			p -= 0.15
+		}
+		if rnd.Float64() < p {
+			y[i] = 1
+		}
+	}
+
+	selectionGap := func(m *GBM) float64 {
+		var selected, count [2]float64
+		for _, x := range X {
+			g := int(x[1])
+			count[g]++
+			selected[g] += m.PredictProba(x)
+		}
+		return math.Abs(selected[0]/count[0] - selected[1]/count[1])
+	}
+
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 30
+	cfg.MaxDepth = 2
+	plain := New(cfg)
+	if err := plain.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	w, err := Reweigh(groups, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reweighed := New(cfg)
+	if err := reweighed.FitWeighted(X, y, w); err != nil {
+		t.Fatal(err)
+	}
+
+	before, after := selectionGap(plain), selectionGap(reweighed)
+	if after > before/2 {
+		t.Errorf("mean score gap between groups %.3f after reweighing, want well below %.3f", after, before)
+	}
+}
@@ -0,0 +1,68 @@
+package gboost
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"math"
+)
+
+// Fingerprint returns a hex SHA-256 digest of the trained model, bit for bit:
+// the loss, learning rate, feature count, initial prediction, and every
+// tree's splits, thresholds, leaf values, gains, covers, and sample counts.
+// Two models with the same fingerprint make identical predictions and
+// explanations; a single differing bit in any float changes it.
+//
+// Record it when a model is approved, and retrain from the same data,
+// Config, and Seed to reproduce it: training is deterministic and its
+// floating-point kernels are written to round identically on every
+// platform Go supports, so the fingerprint must match wherever and whenever
+// the model is retrained. A saved and reloaded model keeps its fingerprint.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained.
+func (g *GBM) Fingerprint() (string, error) {
+	if !g.isFitted {
+		return "", ErrModelNotFitted
+	}
+	h := sha256.New()
+	h.Write([]byte(g.Config.Loss))
+	h.Write([]byte{0})
+	writeFloat(h, g.Config.LearningRate)
+	writeInt(h, g.numFeatures)
+	writeFloat(h, g.initialPrediction)
+	writeInt(h, len(g.trees))
+	for _, tree := range g.trees {
+		writeNode(h, tree)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeNode hashes a subtree in preorder. Leaves and splits are tagged so
+// that different shapes cannot produce the same byte stream.
+func writeNode(h hash.Hash, n *Node) {
+	if n.isLeaf() {
+		h.Write([]byte{'L'})
+		writeFloat(h, n.Value)
+		writeInt(h, n.NSamples)
+		writeFloat(h, n.Cover)
+		return
+	}
+	h.Write([]byte{'S'})
+	writeInt(h, n.FeatureIndex)
+	writeFloat(h, n.Threshold)
+	writeFloat(h, n.Value)
+	writeFloat(h, n.Gain)
+	writeInt(h, n.NSamples)
+	writeFloat(h, n.Cover)
+	writeNode(h, n.Left)
+	writeNode(h, n.Right)
+}
+
+func writeFloat(h hash.Hash, v float64) {
+	h.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(v)))
+}
+
+func writeInt(h hash.Hash, v int) {
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(int64(v))))
+}
@@ -0,0 +1,73 @@
+package gboost
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	X, y := generateBinaryData(3)
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 10
+	cfg.SubsampleRatio = 0.7
+	cfg.Seed = 5
+	fingerprint := func(cfg Config) string {
+		t.Helper()
+		gbm := New(cfg)
+		if err := gbm.Fit(X, y); err != nil {
+			t.Fatal(err)
+		}
+		fp, err := gbm.Fingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fp
+	}
+
+	if _, err := New(cfg).Fingerprint(); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("unfitted model: err = %v, want ErrModelNotFitted", err)
+	}
+
+	want := fingerprint(cfg)
+	if len(want) != 64 {
+		t.Errorf("fingerprint %q is not a hex SHA-256 digest", want)
+	}
+	if got := fingerprint(cfg); got != want {
+		t.Errorf("retraining gave fingerprint %s, want %s", got, want)
+	}
+	parallel := cfg
+	parallel.SplitWorkers = 4
+	if got := fingerprint(parallel); got != want {
+		t.Errorf("parallel split search gave fingerprint %s, want %s", got, want)
+	}
+
+	for name, mutate := range map[string]func(*Config){
+		"seed":          func(c *Config) { c.Seed = 6 },
+		"learning rate": func(c *Config) { c.LearningRate = 0.2 },
+		"depth":         func(c *Config) { c.MaxDepth = 2 },
+	} {
+		other := cfg
+		mutate(&other)
+		if fingerprint(other) == want {
+			t.Errorf("changing the %s kept the fingerprint", name)
+		}
+	}
+
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := gbm.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loaded.Fingerprint(); got != want {
+		t.Errorf("loaded model has fingerprint %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ahmedaabouzied/gboost/internal/fpmath"
+)
+
+// FrequencySeverityModel is the two-part model of insurance pricing. A
+// frequency classifier estimates the probability of a claim over a
+// policy's exposure, such as the fraction of a year it was in force, and a
+// severity regressor with the gamma loss estimates the amount of a claim
+// given that one occurs. The expected cost of a policy is their product:
+//
+//	E[cost | x, e] = P(claim | x, e) * E[amount | claim, x]
+//
+// The frequency model's raw prediction F(x) is the log-odds of a claim in
+// one unit of exposure, and log(e) is added to it for exposure e, so the
+// odds of a claim grow in proportion to the exposure.
+//
+// Both parts are ordinary [GBM] models and can be inspected, explained, or
+// saved individually.
+type FrequencySeverityModel struct {
+	Frequency *GBM // Trained with logloss on the indicator y > 0, offset by log exposure.
+	Severity  *GBM // Trained with the gamma loss on the samples with y > 0.
+}
+
+// NewFrequencySeverity creates an untrained [FrequencySeverityModel]. The
+// frequency config must use the "logloss" loss and the severity config the
+// "gamma" loss.
+func NewFrequencySeverity(frequency, severity Config) *FrequencySeverityModel {
+	return &FrequencySeverityModel{
+		Frequency: New(frequency),
+		Severity:  New(severity),
+	}
+}
+
+// Fit trains the frequency model on all of X, with log(exposure) as a fixed
+// offset to each sample's log-odds, and the severity model on the rows with
+// a claim. y holds the claim amount of each sample, 0 if it had none. A nil
+// exposure gives every sample an exposure of 1.
+//
+// The offset makes the frequency model learn the odds of a claim per unit
+// of exposure: a policy observed for half a year is expected to have half
+// the odds of a claim of one observed for a full year, rather than merely
+// counting half as much.
+//
+// Returns [ErrInvalidLoss] if either config uses the wrong loss,
+// [ErrLengthMismatch] if X and y differ in length, [ErrInvalidExposure],
+// an error wrapping [ErrInvalidGammaTargets] for a negative or non-finite
+// amount, [ErrEmptyDataset] if no sample has a claim, and otherwise any
+// error from [GBM.Fit] on either part.
+func (m *FrequencySeverityModel) Fit(X [][]float64, y, exposure []float64) error {
+	switch {
+	case m.Frequency.Config.Loss != "logloss":
+		return fmt.Errorf("frequency model: %w", ErrInvalidLoss)
+	case m.Severity.Config.Loss != "gamma":
+		return fmt.Errorf("severity model: %w", ErrInvalidLoss)
+	case len(X) != len(y):
+		return ErrLengthMismatch
+	}
+	e, err := exposures(exposure, len(y))
+	if err != nil {
+		return err
+	}
+
+	hasClaim := make([]float64, len(y))
+	var claimX [][]float64
+	var claimY []float64
+	for i, v := range y {
+		if !(v >= 0) || math.IsInf(v, 1) {
+			return fmt.Errorf("%w: claim amount %v at row %d", ErrInvalidGammaTargets, v, i)
+		}
+		if v > 0 {
+			hasClaim[i] = 1
+			claimX = append(claimX, X[i])
+			claimY = append(claimY, v)
+		}
+	}
+	if len(claimY) == 0 {
+		return fmt.Errorf("severity model: %w", ErrEmptyDataset)
+	}
+
+	offset := make([]float64, len(e))
+	for i, v := range e {
+		offset[i] = fpmath.Log(v)
+	}
+	freq := m.Frequency
+	if err := freq.tracked(func() error { return freq.fit(X, hasClaim, nil, offset) }); err != nil {
+		return fmt.Errorf("frequency model: %w", err)
+	}
+	if err := m.Severity.Fit(claimX, claimY); err != nil {
+		return fmt.Errorf("severity model: %w", err)
+	}
+	return nil
+}
+
+// Predict returns the expected cost of each sample in X over its exposure.
+// A nil exposure predicts the cost of one unit of exposure.
+//
+// Returns [ErrInvalidExposure] if exposure does not hold one finite,
+// positive value per sample.
+func (m *FrequencySeverityModel) Predict(X [][]float64, exposure []float64) ([]float64, error) {
+	e, err := exposures(exposure, len(X))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(X))
+	for i, x := range X {
+		p := sigmoid(m.Frequency.PredictSingle(x) + fpmath.Log(e[i]))
+		res[i] = p * m.ExpectedSeverity(x)
+	}
+	return res, nil
+}
+
+// PurePremium returns the expected cost of one unit of exposure for x:
+// the claim probability times the expected claim amount.
+func (m *FrequencySeverityModel) PurePremium(x []float64) float64 {
+	return m.ClaimProbability(x) * m.ExpectedSeverity(x)
+}
+
+// ClaimProbability returns the frequency model's probability of a claim in
+// one unit of exposure.
+func (m *FrequencySeverityModel) ClaimProbability(x []float64) float64 {
+	return m.Frequency.PredictProba(x)
+}
+
+// ExpectedSeverity returns the severity model's expected claim amount given
+// a claim.
+func (m *FrequencySeverityModel) ExpectedSeverity(x []float64) float64 {
+	return inverseLink("gamma", m.Severity.PredictSingle(x))
+}
+
+// exposures returns exposure, or n exposures of 1 if it is nil, checking
+// that it holds n finite, positive values.
+func exposures(exposure []float64, n int) ([]float64, error) {
+	if exposure == nil {
+		e := make([]float64, n)
+		for i := range e {
+			e[i] = 1
+		}
+		return e, nil
+	}
+	if len(exposure) != n {
+		return nil, ErrInvalidExposure
+	}
+	for _, v := range exposure {
+		if !(v > 0) || math.IsInf(v, 1) {
+			return nil, ErrInvalidExposure
+		}
+	}
+	return exposure, nil
+}
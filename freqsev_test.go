@@ -0,0 +1,206 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// generateClaimsData returns policies whose claim probability per year is
+// 0.1 when x1 < 0.5 and 0.4 otherwise, with gamma-distributed claim
+// amounts averaging 1000 when x2 < 0.5 and 3000 otherwise, and exposures
+// of half or one year. Features are on a grid of 0.05 to keep training
+// fast.
+func generateClaimsData() (X [][]float64, y, exposure []float64) {
+	rnd := rand.New(rand.NewSource(0))
+	for range 1500 {
+		x1, x2 := math.Floor(rnd.Float64()*20)/20, math.Floor(rnd.Float64()*20)/20
+		e := 1.0
+		if rnd.Float64() < 0.3 {
+			e = 0.5
+		}
+		rate, severity := 0.1, 1000.0
+		if x1 >= 0.5 {
+			rate = 0.4
+		}
+		if x2 >= 0.5 {
+			severity = 3000
+		}
+		amount := 0.0
+		if rnd.Float64() < rate*e {
+			// A gamma(2) draw, as the sum of two exponentials, scaled to the mean.
+			amount = severity * (rnd.ExpFloat64() + rnd.ExpFloat64()) / 2
+		}
+		X = append(X, []float64{x1, x2})
+		y = append(y, amount)
+		exposure = append(exposure, e)
+	}
+	return X, y, exposure
+}
+
+func newTestFrequencySeverity() *FrequencySeverityModel {
+	frequency := DefaultConfig()
+	frequency.Loss = "logloss"
+	frequency.NEstimators = 50
+	frequency.MaxDepth = 2
+	severity := DefaultConfig()
+	severity.Loss = "gamma"
+	severity.NEstimators = 50
+	severity.MaxDepth = 2
+	return NewFrequencySeverity(frequency, severity)
+}
+
+func TestFrequencySeverityModel(t *testing.T) {
+	X, y, exposure := generateClaimsData()
+	m := newTestFrequencySeverity()
+	if err := m.Fit(X, y, exposure); err != nil {
+		t.Fatal(err)
+	}
+
+	low, high := []float64{0.2, 0.2}, []float64{0.8, 0.8}
+	if p := m.ClaimProbability(low); math.Abs(p-0.1) > 0.05 {
+		t.Errorf("claim probability for x1=0.2 is %v, want about 0.1", p)
+	}
+	if p := m.ClaimProbability(high); math.Abs(p-0.4) > 0.1 {
+		t.Errorf("claim probability for x1=0.8 is %v, want about 0.4", p)
+	}
+	if s := m.ExpectedSeverity(low); math.Abs(s-1000) > 300 {
+		t.Errorf("severity for x2=0.2 is %v, want about 1000", s)
+	}
+	if s := m.ExpectedSeverity(high); math.Abs(s-3000) > 900 {
+		t.Errorf("severity for x2=0.8 is %v, want about 3000", s)
+	}
+
+	pred, err := m.Predict([][]float64{high, high}, []float64{1, 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Half the exposure halves the odds of a claim.
+	p := m.ClaimProbability(high)
+	half := p / (2 - p)
+	if want := m.PurePremium(high); pred[0] != want || math.Abs(pred[1]-half*m.ExpectedSeverity(high)) > 1e-9 {
+		t.Errorf("predictions %v, want %v and the premium at half the odds", pred, want)
+	}
+	if unit, _ := m.Predict([][]float64{high}, nil); unit[0] != pred[0] {
+		t.Errorf("prediction without exposure = %v, want %v", unit[0], pred[0])
+	}
+}
+
+// TestFrequencySeverityModelExposure checks that the frequency model
+// recovers the claim probability per unit of exposure when exposures vary
+// widely. Weighting samples by exposure instead estimates an
+// exposure-weighted average of the per-policy probabilities, about twice
+// the per-unit one here.
+func TestFrequencySeverityModelExposure(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var X [][]float64
+	var y, exposure []float64
+	for range 20000 {
+		x := float64(rnd.Intn(2))
+		e := 0.1 + 2.9*rnd.Float64()
+		// The odds of a claim are proportional to the exposure, with a
+		// probability of 0.05 or 0.15 over one unit.
+		unit := 0.05 + 0.1*x
+		odds := e * unit / (1 - unit)
+		amount := 0.0
+		if rnd.Float64() < odds/(1+odds) {
+			amount = 1000 * rnd.ExpFloat64()
+		}
+		X = append(X, []float64{x})
+		y = append(y, amount)
+		exposure = append(exposure, e)
+	}
+
+	m := newTestFrequencySeverity()
+	m.Frequency.Config.MaxDepth = 1
+	if err := m.Fit(X, y, exposure); err != nil {
+		t.Fatal(err)
+	}
+	for x, want := range []float64{0.05, 0.15} {
+		if p := m.ClaimProbability([]float64{float64(x)}); math.Abs(p-want) > 0.15*want {
+			t.Errorf("claim probability for x=%d is %v, want about %v", x, p, want)
+		}
+	}
+}
+
+// TestFrequencySeverityModelConstantClaims checks that a frequency model
+// whose every policy has a claim still trains trees when exposures differ,
+// since the offsets give the rows different predictions to correct.
+func TestFrequencySeverityModelConstantClaims(t *testing.T) {
+	var X [][]float64
+	var y, exposure []float64
+	for i := range 200 {
+		x := float64(i % 2)
+		X = append(X, []float64{x})
+		y = append(y, 100+float64(i))
+		exposure = append(exposure, 0.5+1.5*x)
+	}
+	m := newTestFrequencySeverity()
+	if err := m.Fit(X, y, exposure); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.Frequency.NumTrees(); n == 0 {
+		t.Errorf("frequency model trained no trees; warnings %q", m.Frequency.Warnings())
+	}
+	if w := m.Frequency.Warnings(); len(w) > 0 {
+		t.Errorf("unexpected warnings %q", w)
+	}
+}
+
+func TestFrequencySeverityModelErrors(t *testing.T) {
+	X, y, exposure := generateClaimsData()
+
+	wrongSeverity := newTestFrequencySeverity()
+	wrongSeverity.Severity.Config.Loss = "mse"
+	if err := wrongSeverity.Fit(X, y, exposure); !errors.Is(err, ErrInvalidLoss) {
+		t.Errorf("mse severity: err = %v", err)
+	}
+	wrongFrequency := newTestFrequencySeverity()
+	wrongFrequency.Frequency.Config.Loss = "mse"
+	if err := wrongFrequency.Fit(X, y, exposure); !errors.Is(err, ErrInvalidLoss) {
+		t.Errorf("mse frequency: err = %v", err)
+	}
+
+	m := newTestFrequencySeverity()
+	if err := m.Fit(X, y, exposure[1:]); !errors.Is(err, ErrInvalidExposure) {
+		t.Errorf("short exposure: err = %v", err)
+	}
+	if err := m.Fit(X, make([]float64, len(y)), nil); !errors.Is(err, ErrEmptyDataset) {
+		t.Errorf("no claims: err = %v", err)
+	}
+	negative := append([]float64{-5}, y[1:]...)
+	if err := m.Fit(X, negative, nil); !errors.Is(err, ErrInvalidGammaTargets) {
+		t.Errorf("negative amount: err = %v", err)
+	}
+	if err := m.Fit(X, y, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Predict(X[:2], []float64{1, 0}); !errors.Is(err, ErrInvalidExposure) {
+		t.Errorf("zero exposure: err = %v", err)
+	}
+}
+
+func TestGammaLossFit(t *testing.T) {
+	X, _ := generateDataWithFunc(linearFunc)
+	y := make([]float64, len(X))
+	for i, x := range X {
+		y[i] = math.Exp(0.1*x[0] + 1)
+	}
+	cfg := DefaultConfig()
+	cfg.Loss = "gamma"
+	model := New(cfg)
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	for i, x := range X {
+		if got := math.Exp(model.PredictSingle(x)); math.Abs(got-y[i])/y[i] > 0.1 {
+			t.Errorf("exp(prediction) for row %d = %v, want about %v", i, got, y[i])
+		}
+	}
+
+	y[0] = 0
+	if err := model.Fit(X, y); !errors.Is(err, ErrInvalidGammaTargets) {
+		t.Errorf("zero target: err = %v", err)
+	}
+}
@@ -1,22 +1,39 @@
 package gboost
 
 import (
+	"fmt"
 	"math"
-	"math/rand"
+	"slices"
+	"sync/atomic"
+
+	"github.com/ahmedaabouzied/gboost/internal/fpmath"
 )
 
 // GBM is a gradient boosting machine model. Create one with [New], train it
 // with [GBM.Fit], and make predictions with [GBM.Predict] or [GBM.PredictProba].
 type GBM struct {
 	Config            Config
-	rnd               *rand.Rand
 	isFitted          bool
 	trees             []*Node
+	maxDepth          int // Of any tree; selects the unrolled predictor.
 	initialPrediction float64
 	loss              Loss
 
 	featureImportance []float64
 	numFeatures       int
+	featureNames      []string
+	aliasedNames      bool // featureNames are aliases; see AliasFeatureNames
+	featureMin        []float64
+	featureMax        []float64
+	history           []RoundStats
+	diagnostics       *TrainingDiagnostics
+	snapshotWeights   []float64
+	leafTransform     string
+	warnings          []string
+
+	// forest caches the trees remapped for SHAP and Quantize; see
+	// GBM.layoutForest. Copies of a GBM share it.
+	forest *atomic.Pointer[layoutForest]
 }
 
 // New creates an untrained GBM model with the given configuration.
@@ -25,17 +42,58 @@ func New(cfg Config) *GBM {
 	return &GBM{
 		Config:   cfg,
 		isFitted: false,
+		forest:   new(atomic.Pointer[layoutForest]),
 	}
 }
 
 // Fit trains the model on the given feature matrix X and target values y.
 // X is a slice of samples where each sample is a slice of feature values.
 // For regression (Loss="mse"), y contains continuous target values.
-// For classification (Loss="logloss"), y must contain only 0.0 and 1.0.
+// For classification (Loss="logloss"), y holds the probability of the
+// positive class: 0.0 or 1.0 for hard labels, or any value in between for
+// soft labels, such as a teacher model's predictions when distilling it into
+// a smaller model, or the share of annotators who chose the positive class.
+// Soft labels cannot be combined with Config.CostMatrix. For Loss="gamma",
+// y must be positive.
 //
 // Fit validates the configuration and input data, returning an error if
 // either is invalid. Calling Fit on an already-trained model retrains from scratch.
+//
+// If the target is constant on the training rows, such as a single class,
+// Fit trains no trees: the model predicts its initial prediction, and
+// [GBM.Warnings] says so.
 func (g *GBM) Fit(X [][]float64, y []float64) error {
+	return g.tracked(func() error { return g.fit(X, y, nil, nil) })
+}
+
+// FitWeighted is like [GBM.Fit] but weights sample i by weights[i]: its
+// gradient and Hessian are scaled by the weight, so a sample with weight 2
+// counts like two copies of it in every leaf value, and the initial
+// prediction and the training and validation losses in [GBM.History] are
+// weighted means. Samples with weight 0 are left out of training. Weights
+// combine multiplicatively with the class weights of Config.CostMatrix and
+// are not saved with the model. Use them for survey or importance weights,
+// or for fairness reweighing with [Reweigh].
+//
+// Returns [ErrInvalidSampleWeights] unless there is one finite, non-negative
+// weight per sample and the samples used for training have a positive
+// total weight, and otherwise any error [GBM.Fit] returns.
+func (g *GBM) FitWeighted(X [][]float64, y, weights []float64) error {
+	if len(weights) != len(y) {
+		return ErrInvalidSampleWeights
+	}
+	for _, w := range weights {
+		if !(w >= 0) || math.IsInf(w, 1) {
+			return ErrInvalidSampleWeights
+		}
+	}
+	return g.tracked(func() error { return g.fit(X, y, weights, nil) })
+}
+
+// fit trains the model, weighting samples by weights unless it is nil.
+// Unless it is nil, offset[i] is added to row i's raw prediction during
+// training but is not part of the model, as with a log-exposure offset.
+func (g *GBM) fit(X [][]float64, y, weights, offset []float64) error {
 	if err := g.Config.validate(); err != nil {
 		return err
 	}
@@ -49,65 +107,279 @@ func (g *GBM) Fit(X [][]float64, y []float64) error {
 		return ErrLengthMismatch
 	case !hasSimilarLength(X):
 		return ErrFeatureCountMismatch
+	case g.featureNames != nil && len(g.featureNames) != len(X[0]):
+		return ErrFeatureCountMismatch
+	case g.Config.Loss == "logloss" && !validLabels(y, g.Config.CostMatrix == nil):
+		return ErrInvalidLabels
+	case g.Config.Loss == "gamma" && !positiveTargets(y):
+		return ErrInvalidGammaTargets
+	case !g.Config.validSplitThresholds(len(X[0])):
+		return ErrInvalidSplitThresholds
+	case g.Config.ValidationFraction > 0 && len(X) < 2:
+		return fmt.Errorf("need at least 2 samples to hold out a validation set, got %d", len(X))
 	}
 
 	// Reset state for re-fitting
 	g.trees = nil
-	g.rnd = rand.New(rand.NewSource(g.Config.Seed))
+	g.maxDepth = 0
+	g.history = nil
+	g.diagnostics = nil
+	g.leafTransform = ""
+	g.warnings = nil
 
 	// Set the number of features from the X set.
 	g.numFeatures = len(X[0])
+	g.featureMin, g.featureMax = featureRanges(X)
 
 	//
 	// 1. Create loss function based on cfg.Loss
 	lossFunc := createLossFunction(g.Config)
 	g.loss = lossFunc
 
-	// 2. Get the basic initial prediction
-	initialPrediction := lossFunc.InitialPrediction(y)
+	// 2. Hold out a validation set for early stopping
+	allIndices := make([]int, len(y))
+	for i := range allIndices {
+		allIndices[i] = i
+	}
+	fitIndices, valIndices := allIndices, []int(nil)
+	if g.Config.ValidationFraction > 0 {
+		fitIndices, valIndices = validationSplit(len(y), g.Config.ValidationFraction, g.Config.Seed)
+	}
+	if weights != nil {
+		// A leaf of zero-weight rows has no Hessian to divide by, and the rows
+		// would not move any leaf value anyway.
+		fitIndices = slices.DeleteFunc(slices.Clone(fitIndices), func(i int) bool { return weights[i] == 0 })
+	}
+	yFit := extractRows(y, fitIndices)
+	yVal := extractRows(y, valIndices)
+	var wFit, wVal []float64
+	if weights != nil {
+		wFit, wVal = extractRows(weights, fitIndices), extractRows(weights, valIndices)
+		if !(sum(wFit) > 0) {
+			return ErrInvalidSampleWeights
+		}
+		if valIndices != nil && !(sum(wVal) > 0) {
+			wVal = nil // an unweighted validation loss still tracks progress
+		}
+	}
+
+	// 3. Get the basic initial prediction
+	initialPrediction := startingPrediction(lossFunc, g.Config.Loss, yFit, wFit)
+	var offsetFit []float64
+	if offset != nil {
+		// Start from the constant that matches the data once the offsets,
+		// on average, are added back.
+		offsetFit = extractRows(offset, fitIndices)
+		initialPrediction -= mean(offsetFit)
+	}
 	g.initialPrediction = initialPrediction
 
-	// 3. Initial predictions slice
+	// A constant target leaves the trees nothing to fit, so the initial
+	// prediction is the whole model. Offsets that differ between rows leave
+	// the predictions something to correct even then.
+	rounds := g.Config.NEstimators
+	if len(yFit) > 0 && allEqual(yFit) && allEqual(offsetFit) {
+		rounds = 0
+		g.warnings = append(g.warnings, fmt.Sprintf("constant target %v: trained no trees; the model always predicts %v", yFit[0], initialPrediction))
+	}
+
+	// 4. Initial predictions slice
 	predictions := make([]float64, len(y))
 	for i := range predictions {
 		predictions[i] = initialPrediction
+		if offset != nil {
+			predictions[i] += offset[i]
+		}
 	}
 
-	// 4. All indices
-	allIndices := make([]int, len(y))
-	for i := range allIndices {
-		allIndices[i] = i
+	// 5. Data segments scored as training progresses
+	scorers, err := newSliceScorers(g.Config.EvalSlices, g.numFeatures, initialPrediction)
+	if err != nil {
+		return err
+	}
+
+	// 6. Features eligible for splitting
+	features := allFeatures(g.numFeatures)
+	if g.Config.DropRedundantFeatures {
+		features = nonRedundantFeatures(X)
 	}
 
 	// Training ...
-	for i := range g.Config.NEstimators {
-		trainIndices := allIndices
-		if g.Config.SubsampleRatio > 0 && g.Config.SubsampleRatio < 1.0 {
-			trainIndices = g.sampleIndices(allIndices)
+	bestTrees, bestLoss, stale := 0, math.Inf(1), 0
+	// The learning rate is reduced by scaling later trees' values, so the
+	// model keeps a single LearningRate and predicts as before.
+	lrScale, lrStale := 1.0, 0
+	// Gain-based stopping compares each tree with the first one and counts
+	// consecutive single-leaf trees.
+	firstGain, singleLeaves := 0.0, 0
+	for i := range rounds {
+		meter := startMeter(g.Config.TrackResources)
+		// Each round draws from its own stream derived from the seed, so a
+		// round's sample does not depend on how earlier rounds used randomness.
+		roundSeed := deriveSeed(g.Config.Seed, i)
+		rnd := newRNG(roundSeed)
+		residuals, hessians := g.gradients(lossFunc, y, predictions)
+		for j, w := range weights {
+			residuals[j] *= w
+			hessians[j] *= w
+		}
+		trainIndices, sampleWeights := g.sampleRows(rnd, fitIndices, y, residuals)
+		for _, j := range trainIndices {
+			residuals[j] *= sampleWeights[j]
+			hessians[j] *= sampleWeights[j]
+		}
+		treeFeatures := features
+		if r := g.Config.ColsampleByTree; r > 0 && r < 1.0 {
+			treeFeatures = g.sampleColumns(newRNG(roundSeed, 0), features)
+		}
+		structureIndices, leafIndices := trainIndices, trainIndices
+		if g.Config.HonestFraction > 0 && len(trainIndices) >= 2 {
+			structureIndices, leafIndices = honestSplit(newRNG(roundSeed, 1), trainIndices, g.Config.HonestFraction)
+		}
+		tree := buildTree(X, residuals, hessians, structureIndices, treeFeatures, 0, g.Config)
+		if g.Config.HierarchicalShrinkage > 0 || g.Config.HonestFraction > 0 {
+			shrinkTree(tree, X, residuals, hessians, leafIndices, g.Config.HierarchicalShrinkage)
 		}
-		residuals := lossFunc.NegativeGradient(y, predictions)
-		hessians := lossFunc.Hessian(y, predictions)
-		tree := buildTree(X, residuals, hessians, trainIndices, 0, g.Config)
-		for j := range predictions {
-			predictions[j] += g.Config.LearningRate * tree.predict(X[j])
+		if lrScale != 1 {
+			scaleTree(tree, lrScale)
+		}
+		if err := g.transformLeaves(tree); err != nil {
+			return err
+		}
+		treeStats := tree.stats()
+		skipped := g.Config.SkipSingleLeafTrees && tree.isLeaf()
+		if skipped {
+			// Score the round as if its tree were a leaf of value 0.
+			tree = &Node{}
+		} else {
+			for j := range predictions {
+				predictions[j] += float64(g.Config.LearningRate * tree.predict(X[j]))
+			}
+			g.appendTree(tree)
 		}
 
-		g.trees = append(g.trees, tree)
+		stats := RoundStats{
+			Round:               i + 1,
+			SampleSize:          len(trainIndices),
+			EffectiveSampleSize: effectiveSampleSize(trainIndices, sampleWeights),
+			Features:            len(treeFeatures),
+			TreeStats:           treeStats,
+			Skipped:             skipped,
+			TrainLoss:           evalLoss(g.Config.Loss, yFit, extractRows(predictions, fitIndices), wFit),
+			ValidationLoss:      math.NaN(),
+			LearningRate:        float64(g.Config.LearningRate * lrScale),
+		}
+		for _, s := range scorers {
+			if scores := s.add(tree, g.Config, i+1); scores != nil {
+				if stats.Slices == nil {
+					stats.Slices = make(map[string]map[string]float64, len(scorers))
+				}
+				stats.Slices[s.slice.Name] = scores
+			}
+		}
+		if valIndices != nil {
+			stats.ValidationLoss = evalLoss(g.Config.Loss, yVal, extractRows(predictions, valIndices), wVal)
+		}
+		stats.Resources = meter.finish()
+		g.history = append(g.history, stats)
 
 		if err := g.fireRoundEndCallback(i + 1); err != nil {
 			return err
 		}
+
+		if valIndices != nil {
+			if stats.ValidationLoss < bestLoss-g.Config.MinDelta {
+				bestTrees, bestLoss, stale, lrStale = len(g.trees), stats.ValidationLoss, 0, 0
+			} else {
+				stale++
+				if stale >= g.Config.Patience {
+					break
+				}
+				lrStale++
+				if g.Config.LRPatience > 0 && lrStale >= g.Config.LRPatience {
+					lrScale, lrStale = g.Config.reducedLRScale(lrScale), 0
+				}
+			}
+		}
+
+		if i == 0 {
+			firstGain = stats.Gain
+		}
+		singleLeaves++
+		if stats.Leaves > 1 {
+			singleLeaves = 0
+		}
+		if g.Config.gainExhausted(stats.Gain, firstGain, singleLeaves) {
+			break
+		}
+	}
+	if valIndices != nil {
+		// Keep the trees up to the last round that improved.
+		g.trees = g.trees[:bestTrees]
 	}
+	g.warnSingleLeafTrees()
+	g.blendSnapshots()
+
 	// Calculate the featureImportance
 	g.calculateFeatureImportance()
 
+	if g.Config.KeepDiagnostics {
+		g.diagnostics = g.newTrainingDiagnostics(X, y, valIndices)
+	}
+
 	g.isFitted = true
 	return nil
 }
 
+// gradients returns the negative gradients and Hessians of the loss at the
+// current predictions. With a CostMatrix, the model is trained on scores
+// shifted by log(w1/w0), the log-odds offset that class weighting induces,
+// and each row's derivatives are scaled by its class weight. predictions
+// themselves stay unshifted, so they, the training history, and the final
+// model all predict calibrated log-odds.
+func (g *GBM) gradients(lossFunc Loss, y, predictions []float64) (residuals, hessians []float64) {
+	if g.Config.CostMatrix == nil {
+		residuals, hessians = lossFunc.NegativeGradient(y, predictions), lossFunc.Hessian(y, predictions)
+		floorHessians(hessians, g.Config.MinHessian)
+		return residuals, hessians
+	}
+	w0, w1 := g.Config.classWeights()
+	offset := fpmath.Log(w1 / w0)
+	scores := make([]float64, len(predictions))
+	for i, p := range predictions {
+		scores[i] = p + offset
+	}
+	residuals = lossFunc.NegativeGradient(y, scores)
+	hessians = lossFunc.Hessian(y, scores)
+	floorHessians(hessians, g.Config.MinHessian)
+	for i := range y {
+		w := w0
+		if y[i] == 1 {
+			w = w1
+		}
+		residuals[i] *= w
+		hessians[i] *= w
+	}
+	return residuals, hessians
+}
+
+// DecisionThreshold returns the probability from [GBM.PredictProba] at or
+// above which a sample should be labeled 1. Without a Config.CostMatrix it
+// is 0.5. With one, it is the threshold that minimizes the expected cost,
+// (C01-C00) / (C01-C00 + C10-C11) in terms of the matrix entries: the more
+// a missed positive costs relative to a false alarm, the lower it is.
+func (g *GBM) DecisionThreshold() float64 {
+	if g.Config.CostMatrix == nil {
+		return 0.5
+	}
+	w0, w1 := g.Config.classWeights()
+	return w0 / (w0 + w1)
+}
+
 // Predict returns raw predictions for each sample in X.
 // For regression, these are the predicted target values.
 // For classification, these are log-odds; use [GBM.PredictProbaAll] for probabilities.
+// For the gamma loss, these are the logs of the expected values.
 func (g *GBM) Predict(X [][]float64) []float64 {
 	results := make([]float64, len(X))
 	for i, x := range X {
@@ -118,14 +390,50 @@ func (g *GBM) Predict(X [][]float64) []float64 {
 
 // PredictSingle returns the raw prediction for a single sample.
 // For regression, this is the predicted value. For classification, this is the log-odds.
+// For the gamma loss, this is the log of the expected value.
+// Models whose trees are at most 8 levels deep, as with the default
+// MaxDepth, are scored without recursion.
 func (g *GBM) PredictSingle(x []float64) float64 {
 	prediction := g.initialPrediction
+	if g.maxDepth <= unrolledMaxDepth {
+		for _, tree := range g.trees {
+			prediction += float64(g.Config.LearningRate * tree.predictUnrolled(x, g.maxDepth))
+		}
+		return prediction
+	}
 	for _, tree := range g.trees {
-		prediction += g.Config.LearningRate * tree.predict(x)
+		prediction += float64(g.Config.LearningRate * tree.predict(x))
 	}
 	return prediction
 }
 
+// warnSingleLeafTrees adds a warning summarizing the rounds of the last fit
+// whose trees found no split, if there were any.
+func (g *GBM) warnSingleLeafTrees() {
+	n, skipped := 0, 0
+	for _, r := range g.history {
+		if r.Leaves == 1 {
+			n++
+		}
+		if r.Skipped {
+			skipped++
+		}
+	}
+	switch {
+	case n == 0:
+	case skipped > 0:
+		g.warnings = append(g.warnings, fmt.Sprintf("%d of %d rounds found no split; their single-leaf trees were skipped", n, len(g.history)))
+	default:
+		g.warnings = append(g.warnings, fmt.Sprintf("%d of %d rounds found no split and added single-leaf trees; set SkipSingleLeafTrees to leave them out or MaxSingleLeafTrees to stop early", n, len(g.history)))
+	}
+}
+
+// appendTree adds a trained tree to the ensemble.
+func (g *GBM) appendTree(tree *Node) {
+	g.trees = append(g.trees, tree)
+	g.maxDepth = max(g.maxDepth, tree.depth())
+}
+
 // PredictProba returns P(y=1) for a single sample by applying the sigmoid
 // function to the raw log-odds prediction. Only meaningful for classification (Loss="logloss").
 func (g *GBM) PredictProba(x []float64) float64 {
@@ -142,6 +450,31 @@ func (g *GBM) PredictProbaAll(X [][]float64) []float64 {
 	return results
 }
 
+// PredictWithStd returns the raw prediction for a single sample, as
+// [GBM.PredictSingle] does, together with the standard deviation of the
+// trees' contributions to it, each scaled by the learning rate. With row
+// subsampling, each tree is fitted to its own bag of rows, so the spread
+// also reflects how much the bags disagree about x.
+//
+// The spread is a cheap heuristic rather than a calibrated interval: trees
+// that agree on x give a low value, while rows the trees keep correcting in
+// opposite directions, or that fall in sparsely covered regions, give a high
+// one. Compare it across rows of the same model, for example to abstain or
+// route the most uncertain rows to a human. It is 0 for a model with fewer
+// than two trees, and in raw units (log-odds for classification).
+func (g *GBM) PredictWithStd(x []float64) (prediction, std float64) {
+	if len(g.trees) < 2 {
+		return g.PredictSingle(x), 0
+	}
+	contributions := make([]float64, len(g.trees))
+	prediction = g.initialPrediction
+	for i, tree := range g.trees {
+		contributions[i] = float64(g.Config.LearningRate * tree.predict(x))
+		prediction += contributions[i]
+	}
+	return prediction, math.Sqrt(variance(contributions))
+}
+
 // FeatureImportance returns the gain-based feature importance scores, normalized
 // to sum to 1.0. Each value represents the fraction of total variance reduction
 // contributed by that feature across all splits in all trees.
@@ -167,17 +500,15 @@ func (g *GBM) FeatureImportance() []float64 {
 // Returns [ErrModelNotFitted] if the model has not been trained, or
 // [ErrFeatureCountMismatch] if any row of X does not have numFeatures columns.
 func (g *GBM) ShapValues(X [][]float64) ([][]float64, error) {
-	result := make([][]float64, len(X))
-
-	for i, x := range X {
-		contrib, err := g.ShapValuesSingle(x)
-		if err != nil {
-			return nil, err
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+	for _, x := range X {
+		if len(x) != g.numFeatures {
+			return nil, ErrFeatureCountMismatch
 		}
-		result[i] = contrib
 	}
-
-	return result, nil
+	return g.shapValues(X), nil
 }
 
 // BaseValue returns the expected model output over the training distribution,
@@ -198,7 +529,7 @@ func (g *GBM) BaseValue() float64 {
 
 	v := g.initialPrediction
 	for _, tree := range g.trees {
-		v += g.Config.LearningRate * tree.expectedValue()
+		v += float64(g.Config.LearningRate * tree.expectedValue())
 	}
 
 	return v
@@ -225,23 +556,40 @@ func (g *GBM) ShapValuesSingle(x []float64) ([]float64, error) {
 	if len(x) != g.numFeatures {
 		return nil, ErrFeatureCountMismatch
 	}
+	return g.shapValues([][]float64{x})[0], nil
+}
 
-	phi := make([]float64, g.numFeatures)
-	phiTmp := make([]float64, g.numFeatures)
+// shapValues computes the SHAP contributions of validated rows. The trees
+// are walked in a [featureLayout], so the per-tree contributions cover only
+// the features the model splits on, hottest first, rather than all of them.
+func (g *GBM) shapValues(X [][]float64) [][]float64 {
+	forest := g.layoutForest()
+	layout, trees := forest.layout, forest.trees
+
+	used := len(layout.features)
+	xs := make([]float64, used)
+	phi := make([]float64, used)
+	phiTmp := make([]float64, used)
+	result := make([][]float64, len(X))
+	for r, x := range X {
+		layout.gather(xs, x)
+		clear(phi)
+		for _, tree := range trees {
+			clear(phiTmp)
 
-	for _, tree := range g.trees {
-		for i := range phiTmp {
-			phiTmp[i] = 0
-		}
+			treeShap(tree, xs, phiTmp, newPath(g.Config.MaxDepth))
 
-		treeShap(tree, x, phiTmp, newPath(g.Config.MaxDepth))
+			for i := range phi {
+				phi[i] += g.Config.LearningRate * phiTmp[i]
+			}
+		}
 
-		for i := range phi {
-			phi[i] += g.Config.LearningRate * phiTmp[i]
+		result[r] = make([]float64, g.numFeatures)
+		for k, j := range layout.features {
+			result[r][j] = phi[k]
 		}
 	}
-
-	return phi, nil
+	return result
 }
 
 // ShapImportance returns SHAP-based global feature importance computed over X.
@@ -286,17 +634,16 @@ func (g *GBM) ShapImportance(X [][]float64) ([]float64, error) {
 	return importance, nil
 }
 
-func (g *GBM) sampleIndices(indices []int) []int {
-	sampleRatio := g.Config.SubsampleRatio
-
-	n := len(indices)
-	sampleSize := int(float64(n) * sampleRatio)
-	shuffled := make([]int, n)
-	copy(shuffled, indices)
-	g.rnd.Shuffle(n, func(i, j int) {
-		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
-	})
-	return shuffled[0:sampleSize]
+// validationSplit shuffles the row indices 0..n-1 and holds out
+// fraction of them (at least one, and leaving at least one) for validation.
+// The shuffle uses its own stream of the seed, separate from the rounds'.
+func validationSplit(n int, fraction float64, seed int64) (fit, val []int) {
+	perm := newRNG(seed, -1).Perm(n)
+	nVal := min(max(int(float64(n)*fraction), 1), n-1)
+	fit, val = perm[nVal:], perm[:nVal]
+	slices.Sort(fit)
+	slices.Sort(val)
+	return fit, val
 }
 
 func (g *GBM) calculateFeatureImportance() {
@@ -315,6 +662,9 @@ func (g *GBM) calculateFeatureImportance() {
 }
 
 func (g *GBM) fireRoundEndCallback(round int) error {
+	if err := g.trackRound(); err != nil {
+		return err
+	}
 	if g.Config.OnRoundEnd == nil {
 		return nil
 	}
@@ -327,6 +677,8 @@ func createLossFunction(cfg Config) Loss {
 		return &MSELoss{}
 	case "logloss":
 		return &LogLoss{}
+	case "gamma":
+		return &GammaLoss{}
 	default:
 		panic("unreachable: config.validate() should reject invalid loss")
 	}
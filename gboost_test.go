@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math"
 	"math/rand"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -264,6 +265,196 @@ func TestConfigValidation(t *testing.T) {
 			mutate:  func(c *Config) { c.Loss = "" },
 			wantErr: ErrInvalidLoss,
 		},
+		{
+			name:    "negative MinHessian",
+			mutate:  func(c *Config) { c.MinHessian = -1e-6 },
+			wantErr: ErrInvalidMinHessian,
+		},
+		{
+			name:    "NaN MinHessian",
+			mutate:  func(c *Config) { c.MinHessian = math.NaN() },
+			wantErr: ErrInvalidMinHessian,
+		},
+		{
+			name:    "negative HierarchicalShrinkage",
+			mutate:  func(c *Config) { c.HierarchicalShrinkage = -1 },
+			wantErr: ErrInvalidHierarchicalShrinkage,
+		},
+		{
+			name:    "unknown SamplingMethod",
+			mutate:  func(c *Config) { c.SamplingMethod = "stratified" },
+			wantErr: ErrInvalidSamplingMethod,
+		},
+		{
+			name:    "negative SplitWorkers",
+			mutate:  func(c *Config) { c.SplitWorkers = -1 },
+			wantErr: ErrInvalidSplitWorkers,
+		},
+		{
+			name: "GOSS with bootstrap",
+			mutate: func(c *Config) {
+				c.GOSSTopRate, c.GOSSOtherRate = 0.2, 0.1
+				c.SamplingMethod = "bootstrap"
+			},
+			wantErr: ErrGOSSWithSubsampling,
+		},
+		{
+			name:    "negative HonestFraction",
+			mutate:  func(c *Config) { c.HonestFraction = -0.1 },
+			wantErr: ErrInvalidHonestFraction,
+		},
+		{
+			name:    "HonestFraction of 1",
+			mutate:  func(c *Config) { c.HonestFraction = 1 },
+			wantErr: ErrInvalidHonestFraction,
+		},
+		{
+			name:    "negative ValidationFraction",
+			mutate:  func(c *Config) { c.ValidationFraction = -0.1 },
+			wantErr: ErrInvalidValidationFraction,
+		},
+		{
+			name:    "ValidationFraction of 1",
+			mutate:  func(c *Config) { c.ValidationFraction = 1 },
+			wantErr: ErrInvalidValidationFraction,
+		},
+		{
+			name:    "ValidationFraction without Patience",
+			mutate:  func(c *Config) { c.ValidationFraction = 0.2 },
+			wantErr: ErrInvalidPatience,
+		},
+		{
+			name:    "negative Patience",
+			mutate:  func(c *Config) { c.Patience = -1 },
+			wantErr: ErrInvalidPatience,
+		},
+		{
+			name:    "negative MinDelta",
+			mutate:  func(c *Config) { c.MinDelta = -0.1 },
+			wantErr: ErrInvalidMinDelta,
+		},
+		{
+			name:    "GOSSTopRate without GOSSOtherRate",
+			mutate:  func(c *Config) { c.GOSSTopRate = 0.2 },
+			wantErr: ErrInvalidGOSSRates,
+		},
+		{
+			name:    "GOSS rates above 1",
+			mutate:  func(c *Config) { c.GOSSTopRate, c.GOSSOtherRate = 0.6, 0.5 },
+			wantErr: ErrInvalidGOSSRates,
+		},
+		{
+			name:    "GOSS with SubsampleRatio",
+			mutate:  func(c *Config) { c.GOSSTopRate, c.GOSSOtherRate, c.SubsampleRatio = 0.2, 0.1, 0.5 },
+			wantErr: ErrGOSSWithSubsampling,
+		},
+		{
+			name: "GOSS with NegativeSampleRatio",
+			mutate: func(c *Config) {
+				c.Loss = "logloss"
+				c.GOSSTopRate, c.GOSSOtherRate, c.NegativeSampleRatio = 0.2, 0.1, 0.5
+			},
+			wantErr: ErrGOSSWithSubsampling,
+		},
+		{
+			name:    "ColsampleByTree > 1",
+			mutate:  func(c *Config) { c.ColsampleByTree = 1.5 },
+			wantErr: ErrInvalidColsampleByTree,
+		},
+		{
+			name:    "negative NegativeSampleRatio",
+			mutate:  func(c *Config) { c.NegativeSampleRatio = -0.1 },
+			wantErr: ErrInvalidNegativeSampleRatio,
+		},
+		{
+			name:    "NegativeSampleRatio > 1",
+			mutate:  func(c *Config) { c.NegativeSampleRatio = 1.5 },
+			wantErr: ErrInvalidNegativeSampleRatio,
+		},
+		{
+			name:    "NegativeSampleRatio with mse",
+			mutate:  func(c *Config) { c.NegativeSampleRatio = 0.5 },
+			wantErr: ErrInvalidNegativeSampleRatio,
+		},
+		{
+			name: "CostMatrix with mse",
+			mutate: func(c *Config) {
+				c.CostMatrix = [][]float64{{0, 1}, {5, 0}}
+			},
+			wantErr: ErrInvalidCostMatrix,
+		},
+		{
+			name: "CostMatrix not 2x2",
+			mutate: func(c *Config) {
+				c.Loss = "logloss"
+				c.CostMatrix = [][]float64{{0, 1, 1}, {5, 0, 1}}
+			},
+			wantErr: ErrInvalidCostMatrix,
+		},
+		{
+			name: "CostMatrix with negative cost",
+			mutate: func(c *Config) {
+				c.Loss = "logloss"
+				c.CostMatrix = [][]float64{{0, 1}, {5, -1}}
+			},
+			wantErr: ErrInvalidCostMatrix,
+		},
+		{
+			name: "CostMatrix rewarding an error",
+			mutate: func(c *Config) {
+				c.Loss = "logloss"
+				c.CostMatrix = [][]float64{{2, 1}, {5, 0}}
+			},
+			wantErr: ErrInvalidCostMatrix,
+		},
+		{
+			name:    "MinGainFraction of 1",
+			mutate:  func(c *Config) { c.MinGainFraction = 1 },
+			wantErr: ErrInvalidGainStop,
+		},
+		{
+			name:    "negative MaxSingleLeafTrees",
+			mutate:  func(c *Config) { c.MaxSingleLeafTrees = -1 },
+			wantErr: ErrInvalidGainStop,
+		},
+		{
+			name:    "LRPatience without validation",
+			mutate:  func(c *Config) { c.LRPatience = 2 },
+			wantErr: ErrInvalidLRSchedule,
+		},
+		{
+			name: "LRFactor of 1",
+			mutate: func(c *Config) {
+				c.ValidationFraction, c.Patience, c.LRPatience, c.LRFactor = 0.2, 5, 2, 1
+			},
+			wantErr: ErrInvalidLRSchedule,
+		},
+		{
+			name:    "MinLearningRate above LearningRate",
+			mutate:  func(c *Config) { c.MinLearningRate = c.LearningRate * 2 },
+			wantErr: ErrInvalidLRSchedule,
+		},
+		{
+			name: "EvalSlices with duplicate names",
+			mutate: func(c *Config) {
+				c.EvalSlices = []EvalSlice{{Name: "recent"}, {Name: "recent"}}
+			},
+			wantErr: ErrInvalidEvalSlices,
+		},
+		{
+			name: "EvalSlice with mismatched targets",
+			mutate: func(c *Config) {
+				c.EvalSlices = []EvalSlice{{Name: "recent", X: [][]float64{{1}}, Y: []float64{1, 2}}}
+			},
+			wantErr: ErrInvalidEvalSlices,
+		},
+		{
+			name: "EvalSlice with negative interval",
+			mutate: func(c *Config) {
+				c.EvalSlices = []EvalSlice{{Name: "recent", Every: -1}}
+			},
+			wantErr: ErrInvalidEvalSlices,
+		},
 		{
 			name:   "valid default config",
 			mutate: func(c *Config) {},
@@ -439,6 +630,44 @@ func TestGBMPredictProbaBounds(t *testing.T) {
 	}
 }
 
+func TestPredictWithStd(t *testing.T) {
+	// y is exactly 0 below x = 0.5 and pure noise above, so bags drawn by
+	// subsampling agree on the left half and disagree on the right.
+	rnd := rand.New(rand.NewSource(1))
+	X := make([][]float64, 400)
+	y := make([]float64, len(X))
+	for i := range X {
+		X[i] = []float64{rnd.Float64()}
+		if X[i][0] >= 0.5 {
+			y[i] = 3 * rnd.NormFloat64()
+		}
+	}
+	cfg := DefaultConfig()
+	cfg.NEstimators = 30
+	cfg.MaxDepth = 3
+	cfg.SubsampleRatio = 0.5
+	gbm := New(cfg)
+	assert.NoError(t, gbm.Fit(X, y))
+
+	x := []float64{0.7}
+	pred, std := gbm.PredictWithStd(x)
+	assert.Equal(t, gbm.PredictSingle(x), pred)
+	contributions := make([]float64, len(gbm.trees))
+	for i, tree := range gbm.trees {
+		contributions[i] = cfg.LearningRate * tree.predict(x)
+	}
+	assert.InDelta(t, math.Sqrt(variance(contributions)), std, 1e-12)
+
+	_, clean := gbm.PredictWithStd([]float64{0.2})
+	assert.Less(t, clean, std)
+
+	cfg.NEstimators = 1
+	single := New(cfg)
+	assert.NoError(t, single.Fit(X, y))
+	_, std = single.PredictWithStd(x)
+	assert.Equal(t, 0.0, std)
+}
+
 func TestFeatureImportanceNotFitted(t *testing.T) {
 	gbm := New(DefaultConfig())
 	imp := gbm.FeatureImportance()
@@ -674,6 +903,32 @@ func TestDifferentSeedDifferentModel(t *testing.T) {
 	}
 }
 
+func TestRoundsUseIsolatedSeedStreams(t *testing.T) {
+	X, y := generateBinaryData(8.0)
+
+	cfg := DefaultConfig()
+	cfg.Seed = 7
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 5
+	cfg.MaxDepth = 2
+	cfg.NegativeSampleRatio = 0.5
+
+	gbm := New(cfg)
+	assert.NoError(t, gbm.Fit(X, y))
+
+	// Round i keeps exactly the negatives its own derived stream selects,
+	// independently of how many draws earlier rounds made.
+	indices := make([]int, len(y))
+	for i := range indices {
+		indices[i] = i
+	}
+	for i, tree := range gbm.trees {
+		rnd := rand.New(rand.NewSource(deriveSeed(cfg.Seed, i)))
+		kept := gbm.downsampleNegatives(rnd, indices, y, make([]float64, len(y)))
+		assert.Equal(t, len(kept), tree.NSamples, "round %d", i)
+	}
+}
+
 func TestRefitResetsSeed(t *testing.T) {
 	X := [][]float64{
 		{1.0, 2.0}, {2.0, 3.0}, {3.0, 4.0}, {4.0, 5.0},
@@ -803,6 +1058,79 @@ func TestIdenticalTargets(t *testing.T) {
 	for _, pred := range preds {
 		assert.Equal(t, 5.0, pred)
 	}
+	assert.Equal(t, 0, model.NumTrees())
+	assert.Empty(t, model.History())
+	if assert.Len(t, model.Warnings(), 1) {
+		assert.Contains(t, model.Warnings()[0], "constant target 5")
+	}
+
+	// Refitting on a varying target clears the warning.
+	assert.NoError(t, model.Fit(X_test, y))
+	assert.Nil(t, model.Warnings())
+	assert.Equal(t, DefaultConfig().NEstimators, model.NumTrees())
+}
+
+func TestSoftLabels(t *testing.T) {
+	X, y := generateBinaryData(0.5)
+	teacher := fitBinaryModel(t, X, y)
+	soft := teacher.PredictProbaAll(X)
+
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.MaxDepth = 2
+	cfg.NEstimators = 50
+	student := New(cfg)
+	assert.NoError(t, student.Fit(X, soft))
+
+	p := mean(soft)
+	assert.InDelta(t, math.Log(p/(1-p)), student.initialPrediction, 1e-12)
+	var gap float64
+	for i, q := range student.PredictProbaAll(X) {
+		gap += math.Abs(q-soft[i]) / float64(len(X))
+	}
+	assert.Less(t, gap, 0.05, "student strays from the teacher's probabilities")
+}
+
+func TestInvalidLabels(t *testing.T) {
+	X, _ := generateBinaryData(0.5)
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	for _, bad := range []float64{-0.1, 1.5, math.NaN()} {
+		y := make([]float64, len(X))
+		y[0] = bad
+		assert.ErrorIs(t, New(cfg).Fit(X, y), ErrInvalidLabels, "label %v", bad)
+	}
+
+	y := make([]float64, len(X))
+	y[0] = 0.5
+	cfg.CostMatrix = [][]float64{{0, 1}, {5, 0}}
+	assert.ErrorIs(t, New(cfg).Fit(X, y), ErrInvalidLabels, "soft label with a CostMatrix")
+
+	cfg.CostMatrix = nil
+	batches := make(chan Batch, 1)
+	batches <- Batch{X: X[:1], Y: []float64{2}}
+	close(batches)
+	assert.ErrorIs(t, New(cfg).FitStream(batches), ErrInvalidLabels)
+}
+
+func TestSingleClassTarget(t *testing.T) {
+	X, _ := generateBinaryData(0.5)
+	y := make([]float64, len(X))
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.ValidationFraction = 0.2
+	cfg.Patience = 5
+	model := New(cfg)
+	assert.NoError(t, model.Fit(X, y))
+	assert.Equal(t, 0, model.NumTrees())
+	assert.Len(t, model.Warnings(), 1)
+	assert.InDelta(t, 0.001, model.PredictProba(X[0]), 1e-9)
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	assert.NoError(t, model.Save(path))
+	loaded, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Warnings(), loaded.Warnings())
 }
 
 func TestIdenticalFeatures(t *testing.T) {
@@ -877,6 +1205,272 @@ func TestExtremeClassImbalance(t *testing.T) {
 	assert.True(t, trueNegatives > 1)
 }
 
+func TestNegativeDownsampling(t *testing.T) {
+	X, y := generateBinaryData(9.0) // about 10% positives
+	positives := 0
+	for _, v := range y {
+		if v == 1 {
+			positives++
+		}
+	}
+
+	config := DefaultConfig()
+	config.Loss = "logloss"
+	config.NEstimators = 30
+	config.MaxDepth = 3
+	config.NegativeSampleRatio = 0.2
+
+	model := New(config)
+	assert.NoError(t, model.Fit(X, y))
+
+	// Each tree sees every positive but only a fraction of the negatives.
+	for _, tree := range model.trees {
+		assert.Less(t, tree.NSamples, len(X))
+		assert.GreaterOrEqual(t, tree.NSamples, positives)
+	}
+
+	// Reweighting keeps the model calibrated and accurate.
+	pred := model.PredictProbaAll(X)
+	correct := 0
+	for i := range pred {
+		if (pred[i] > 0.5) == (y[i] == 1) {
+			correct++
+		}
+	}
+	assert.InDelta(t, float64(positives)/float64(len(y)), mean(pred), 0.05)
+	assert.Greater(t, float64(correct)/float64(len(y)), 0.95)
+
+	// Downsampling draws from the seeded RNG, so training stays reproducible.
+	again := New(config)
+	assert.NoError(t, again.Fit(X, y))
+	assert.Equal(t, pred, again.PredictProbaAll(X))
+}
+
+func TestFitWeighted(t *testing.T) {
+	X, y := generateBinaryData(5)
+	config := DefaultConfig()
+	config.Loss = "logloss"
+	config.NEstimators = 10
+
+	// Unit weights train exactly the unweighted model.
+	ones := make([]float64, len(y))
+	for i := range ones {
+		ones[i] = 1
+	}
+	plain, weighted := New(config), New(config)
+	if err := plain.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if err := weighted.FitWeighted(X, y, ones); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := plain.Fingerprint()
+	got, _ := weighted.Fingerprint()
+	assert.Equal(t, want, got, "unit weights changed the model")
+
+	// Weighting the positives up moves the initial log-odds and the losses
+	// in the history to their weighted versions.
+	w := make([]float64, len(y))
+	var pos, total float64
+	for i := range y {
+		w[i] = 1 + 2*y[i]
+		pos += w[i] * y[i]
+		total += w[i]
+	}
+	if err := weighted.FitWeighted(X, y, w); err != nil {
+		t.Fatal(err)
+	}
+	p := pos / total
+	assert.InDelta(t, math.Log(p/(1-p)), weighted.initialPrediction, 1e-12)
+	pred := make([]float64, len(y))
+	for i := range X {
+		pred[i] = weighted.PredictSingle(X[i])
+	}
+	history := weighted.History()
+	assert.InDelta(t, weightedMean(logLosses(y, pred), w), history[len(history)-1].TrainLoss, 1e-12)
+	assert.Greater(t, mean(weighted.PredictProbaAll(X)), mean(plain.PredictProbaAll(X)), "upweighted positives should raise predicted probabilities")
+}
+
+func TestFitWeightedZeroWeights(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	w := make([]float64, len(y))
+	for i := range w {
+		w[i] = 1
+	}
+	// Rows 0-19 would otherwise form leaves with no Hessian.
+	for i := range 20 {
+		w[i] = 0
+	}
+	config := DefaultConfig()
+	config.NEstimators = 20
+	model := New(config)
+	if err := model.FitWeighted(X, y, w); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range model.Predict(X) {
+		if math.IsNaN(p) {
+			t.Fatalf("prediction %d is NaN", i)
+		}
+	}
+
+	// Zero-weight rows do not affect the model at all.
+	kept := New(config)
+	if err := kept.Fit(X[20:], y[20:]); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := kept.Fingerprint()
+	got, _ := model.Fingerprint()
+	assert.Equal(t, want, got, "zero-weight rows changed the model")
+}
+
+// logLosses returns the per-sample binary cross-entropy of raw predictions.
+func logLosses(y, pred []float64) []float64 {
+	res := make([]float64, len(y))
+	for i := range y {
+		p := sigmoid(pred[i])
+		res[i] = -(y[i]*math.Log(p) + (1-y[i])*math.Log(1-p))
+	}
+	return res
+}
+
+func TestFitWeightedInvalidWeights(t *testing.T) {
+	X := [][]float64{{1}, {2}, {3}}
+	y := []float64{1, 2, 3}
+	tests := []struct {
+		name    string
+		weights []float64
+	}{
+		{"too few", []float64{1, 1}},
+		{"negative", []float64{1, -1, 1}},
+		{"NaN", []float64{1, math.NaN(), 1}},
+		{"infinite", []float64{1, math.Inf(1), 1}},
+		{"all zero", []float64{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		if err := New(DefaultConfig()).FitWeighted(X, y, tt.weights); err != ErrInvalidSampleWeights {
+			t.Errorf("%s: err = %v, want ErrInvalidSampleWeights", tt.name, err)
+		}
+	}
+}
+
+func TestCostMatrix(t *testing.T) {
+	// P(y = 1) = x, so no model separates the classes and the threshold
+	// decides which errors are made.
+	rnd := rand.New(rand.NewSource(3))
+	X := make([][]float64, 1000)
+	y := make([]float64, len(X))
+	for i := range X {
+		X[i] = []float64{rnd.Float64()}
+		if rnd.Float64() < X[i][0] {
+			y[i] = 1
+		}
+	}
+	cost := [][]float64{{0, 1}, {4, 0}}
+	totalCost := func(proba []float64, threshold float64) float64 {
+		total := 0.0
+		for i, p := range proba {
+			predicted := 0
+			if p >= threshold {
+				predicted = 1
+			}
+			total += cost[int(y[i])][predicted]
+		}
+		return total
+	}
+
+	config := DefaultConfig()
+	config.Loss = "logloss"
+	config.NEstimators = 30
+	config.MaxDepth = 2
+	plain := New(config)
+	assert.NoError(t, plain.Fit(X, y))
+	assert.Equal(t, 0.5, plain.DecisionThreshold())
+
+	config.CostMatrix = cost
+	model := New(config)
+	assert.NoError(t, model.Fit(X, y))
+	assert.InDelta(t, 0.2, model.DecisionThreshold(), 1e-12)
+
+	// The initial prediction is corrected for the class weights, so the
+	// probabilities stay calibrated, and thresholding them at the
+	// cost-minimizing threshold beats the default of 0.5.
+	pred := model.PredictProbaAll(X)
+	assert.InDelta(t, mean(y), mean(pred), 0.05)
+	assert.Less(t, totalCost(pred, model.DecisionThreshold()), totalCost(pred, 0.5))
+
+	// Symmetric costs weight both classes equally.
+	config.CostMatrix = [][]float64{{0, 3}, {3, 0}}
+	symmetric := New(config)
+	assert.NoError(t, symmetric.Fit(X, y))
+	assert.Equal(t, plain.PredictProbaAll(X), symmetric.PredictProbaAll(X))
+	assert.Equal(t, 0.5, symmetric.DecisionThreshold())
+}
+
+func TestHierarchicalShrinkageSmoothsPredictions(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	config := DefaultConfig()
+	config.NEstimators = 10
+	config.MaxDepth = 6
+
+	plain := New(config)
+	assert.NoError(t, plain.Fit(X, y))
+
+	config.HierarchicalShrinkage = 20
+	shrunk := New(config)
+	assert.NoError(t, shrunk.Fit(X, y))
+
+	// Shrinking deep leaves toward their ancestors pulls predictions together.
+	assert.Less(t, variance(shrunk.Predict(X)), variance(plain.Predict(X)))
+}
+
+func TestHonestTreesReduceOverfitting(t *testing.T) {
+	// On pure noise every split is spurious. Leaf values estimated on the
+	// rows that chose the splits chase that noise; honest ones do not.
+	testMSE := func(fraction float64) float64 {
+		rnd := rand.New(rand.NewSource(0))
+		X := make([][]float64, 400)
+		y := make([]float64, 400)
+		for i := range X {
+			X[i] = []float64{rnd.Float64(), rnd.Float64()}
+			y[i] = rnd.NormFloat64()
+		}
+		config := DefaultConfig()
+		config.NEstimators = 10
+		config.LearningRate = 0.5
+		config.MaxDepth = 4
+		config.HonestFraction = fraction
+		model := New(config)
+		assert.NoError(t, model.Fit(X, y))
+
+		var sse float64
+		for range 2000 {
+			x := []float64{rnd.Float64(), rnd.Float64()}
+			d := model.PredictSingle(x) - rnd.NormFloat64()
+			sse += d * d
+		}
+		return sse / 2000
+	}
+	assert.Less(t, testMSE(0.5), testMSE(0))
+}
+
+func TestSplitWorkersMatchesSerialFit(t *testing.T) {
+	X, y := generateNoisyData()
+	config := DefaultConfig()
+	config.NEstimators = 10
+	config.MaxDepth = 4
+	config.ColsampleByTree = 0.8
+
+	serial := New(config)
+	assert.NoError(t, serial.Fit(X, y))
+
+	config.SplitWorkers = 4
+	parallel := New(config)
+	assert.NoError(t, parallel.Fit(X, y))
+
+	assert.Equal(t, serial.Predict(X), parallel.Predict(X))
+	assert.Equal(t, serial.FeatureImportance(), parallel.FeatureImportance())
+}
+
 func TestMinimalRegressionModel(t *testing.T) {
 	X, y := generateDataWithFunc(linearFunc)
 
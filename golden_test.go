@@ -0,0 +1,213 @@
+package gboost
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "rewrite the golden model files in testdata/golden")
+
+// goldenTolerance bounds the relative difference allowed between a golden
+// float and a freshly trained one. Split structure must match exactly.
+const goldenTolerance = 1e-9
+
+// goldenModel is the part of a trained model pinned by a golden file: the
+// initial prediction, every tree, and the model's [GBM.Fingerprint]. The
+// Config is left out so that adding a field does not invalidate the files.
+type goldenModel struct {
+	Fingerprint       string          `json:"fingerprint"`
+	InitialPrediction float64         `json:"initial_prediction"`
+	Trees             []*ExportedNode `json:"trees"`
+}
+
+// goldenCases trains small reference models on checked-in datasets with
+// fixed seeds. Cases that share a golden file must train identical models:
+// they pin options, such as SplitWorkers, that must not change the result.
+var goldenCases = []struct {
+	name   string
+	golden string
+	data   string
+	config func(*Config)
+}{
+	{
+		name:   "iris logloss",
+		golden: "iris_logloss.json",
+		data:   "data/iris_binary.csv",
+		config: func(c *Config) {
+			c.Loss = "logloss"
+			c.NEstimators = 20
+			c.MaxDepth = 3
+		},
+	},
+	{
+		name:   "iris logloss, parallel split search",
+		golden: "iris_logloss.json",
+		data:   "data/iris_binary.csv",
+		config: func(c *Config) {
+			c.Loss = "logloss"
+			c.NEstimators = 20
+			c.MaxDepth = 3
+			c.SplitWorkers = 3
+		},
+	},
+	{
+		name:   "friedman mse, row and column sampling",
+		golden: "friedman_sampled.json",
+		data:   "testdata/golden/friedman.csv",
+		config: func(c *Config) {
+			c.NEstimators = 20
+			c.MaxDepth = 4
+			c.MinSamplesLeaf = 3
+			c.SubsampleRatio = 0.8
+			c.ColsampleByTree = 0.75
+			c.Seed = 7
+		},
+	},
+	{
+		name:   "friedman mse, honest hierarchical shrinkage",
+		golden: "friedman_honest.json",
+		data:   "testdata/golden/friedman.csv",
+		config: func(c *Config) {
+			c.NEstimators = 10
+			c.MaxDepth = 3
+			c.HonestFraction = 0.5
+			c.HierarchicalShrinkage = 5
+			c.Seed = 11
+		},
+	},
+}
+
+// TestGoldenModels trains each reference model and compares every tree,
+// node by node, against its golden file, then requires the fingerprint to
+// match exactly: training must reproduce the model bit for bit on every
+// platform, which CI checks across operating systems and architectures.
+// The tolerant node comparison runs first to locate differences that the
+// fingerprint alone would not. After an intentional change to the
+// training algorithm, regenerate the files with
+//
+//	go test -run TestGoldenModels -update-golden
+//
+// and review the diff.
+func TestGoldenModels(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ds, err := LoadCSV(tc.data, -1, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := DefaultConfig()
+			tc.config(&cfg)
+			gbm := New(cfg)
+			if err := gbm.Fit(ds.X, ds.Y); err != nil {
+				t.Fatal(err)
+			}
+			exported := gbm.toExported()
+			fingerprint, err := gbm.Fingerprint()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := goldenModel{Fingerprint: fingerprint, InitialPrediction: exported.InitialPrediction, Trees: exported.Trees}
+
+			path := filepath.Join("testdata", "golden", tc.golden)
+			if *updateGolden {
+				raw, err := json.MarshalIndent(got, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, append(raw, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var want goldenModel
+			if err := readJSON(path, &want); err != nil {
+				t.Fatalf("%v (run with -update-golden to create it)", err)
+			}
+			if err := compareGolden(want, got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Fingerprint != want.Fingerprint {
+				t.Errorf("fingerprint = %s, want %s: the model matches within %g but not bit for bit on %s/%s",
+					got.Fingerprint, want.Fingerprint, goldenTolerance, runtime.GOOS, runtime.GOARCH)
+			}
+		})
+	}
+}
+
+// compareGolden returns an error describing the first difference between
+// two golden models.
+func compareGolden(want, got goldenModel) error {
+	if !goldenClose(want.InitialPrediction, got.InitialPrediction) {
+		return fmt.Errorf("initial prediction = %v, want %v", got.InitialPrediction, want.InitialPrediction)
+	}
+	if len(got.Trees) != len(want.Trees) {
+		return fmt.Errorf("%d trees, want %d", len(got.Trees), len(want.Trees))
+	}
+	for i := range want.Trees {
+		if err := compareGoldenNode(want.Trees[i], got.Trees[i], "root"); err != nil {
+			return fmt.Errorf("tree %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// compareGoldenNode compares two subtrees. path names the node by the
+// branches taken from the root, such as "root.L.R".
+func compareGoldenNode(want, got *ExportedNode, path string) error {
+	switch {
+	case (want == nil) != (got == nil):
+		return fmt.Errorf("%s: node presence differs", path)
+	case want == nil:
+		return nil
+	case got.IsLeaf != want.IsLeaf:
+		return fmt.Errorf("%s: leaf = %v, want %v", path, got.IsLeaf, want.IsLeaf)
+	case got.NSamples != want.NSamples:
+		return fmt.Errorf("%s: n_samples = %d, want %d", path, got.NSamples, want.NSamples)
+	case !goldenClose(got.Value, want.Value):
+		return fmt.Errorf("%s: value = %v, want %v", path, got.Value, want.Value)
+	}
+	if want.IsLeaf {
+		return nil
+	}
+	switch {
+	case got.FeatureIndex != want.FeatureIndex:
+		return fmt.Errorf("%s: splits on feature %d, want %d", path, got.FeatureIndex, want.FeatureIndex)
+	case !goldenClose(got.Threshold, want.Threshold):
+		return fmt.Errorf("%s: threshold = %v, want %v", path, got.Threshold, want.Threshold)
+	case !goldenClose(got.Gain, want.Gain):
+		return fmt.Errorf("%s: gain = %v, want %v", path, got.Gain, want.Gain)
+	case !goldenClose(got.Cover, want.Cover):
+		return fmt.Errorf("%s: cover = %v, want %v", path, got.Cover, want.Cover)
+	}
+	if err := compareGoldenNode(want.Left, got.Left, path+".L"); err != nil {
+		return err
+	}
+	return compareGoldenNode(want.Right, got.Right, path+".R")
+}
+
+func goldenClose(a, b float64) bool {
+	return math.Abs(a-b) <= goldenTolerance*max(1, math.Abs(a), math.Abs(b))
+}
+
+func TestCompareGoldenReportsPath(t *testing.T) {
+	leaf := func(v float64) *ExportedNode { return &ExportedNode{IsLeaf: true, Value: v, NSamples: 1} }
+	tree := func(right float64) goldenModel {
+		root := &ExportedNode{FeatureIndex: 1, Threshold: 0.5, NSamples: 2, Left: leaf(1), Right: leaf(right)}
+		return goldenModel{Trees: []*ExportedNode{root}}
+	}
+
+	if err := compareGolden(tree(2), tree(2+1e-12)); err != nil {
+		t.Errorf("difference within tolerance reported: %v", err)
+	}
+	err := compareGolden(tree(2), tree(2.1))
+	if err == nil || err.Error() != "tree 0: root.R: value = 2.1, want 2" {
+		t.Errorf("err = %v, want the path of the differing leaf", err)
+	}
+}
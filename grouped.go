@@ -0,0 +1,272 @@
+package gboost
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// GroupedModel is a panel of models sharing one [Config], one per value of
+// a group column such as a store or region, for data whose groups behave
+// too differently for a single model to serve them all well. The group
+// column stays in the rows given to Fit and Predict, which route each row to
+// its group's model; the models themselves are trained and predict without
+// it.
+type GroupedModel struct {
+	Config Config
+
+	// Column is the index of the group column in the rows of the dataset.
+	Column int
+
+	// Workers bounds the number of group models trained concurrently. Zero
+	// means runtime.GOMAXPROCS(0). Config.OnRoundEnd, if set, must be safe
+	// for concurrent use when Workers != 1.
+	Workers int
+
+	// Models maps each group key to its model, set by Fit. A key is the
+	// group's category in a label-encoded column, and its value formatted
+	// with strconv.FormatFloat otherwise.
+	Models map[string]*GBM
+
+	// keys maps the values of the group column to the keys of Models.
+	keys map[float64]string
+}
+
+// NewGrouped creates an untrained [GroupedModel] that trains one model with
+// cfg per value of the given feature column.
+func NewGrouped(cfg Config, column int) *GroupedModel {
+	return &GroupedModel{Config: cfg, Column: column}
+}
+
+// Fit splits the rows of ds by their group column and trains each group's
+// model on its rows, without the group column. Feature names, if ds has
+// them, are set on every model. Models are trained concurrently on up to
+// Workers goroutines, and the result does not depend on scheduling.
+//
+// Returns [ErrEmptyDataset] if ds has no rows, [ErrLengthMismatch] if X and
+// Y differ in length, [ErrInvalidWorkers] for a negative Workers, an error
+// wrapping [ErrInvalidGroupColumn] if Column is not a feature column or has
+// a missing value, and otherwise the first error from [GBM.Fit], in key
+// order, naming its group.
+func (m *GroupedModel) Fit(ds *Dataset) error {
+	switch {
+	case len(ds.X) == 0:
+		return ErrEmptyDataset
+	case len(ds.X) != len(ds.Y):
+		return ErrLengthMismatch
+	case m.Workers < 0:
+		return ErrInvalidWorkers
+	case m.Column < 0 || m.Column >= len(ds.X[0]):
+		return fmt.Errorf("%w: column %d out of range for %d features", ErrInvalidGroupColumn, m.Column, len(ds.X[0]))
+	}
+
+	categories := make(map[float64]string, len(ds.Encodings[m.Column]))
+	for category, v := range ds.Encodings[m.Column] {
+		categories[v] = category
+	}
+	keys := make(map[float64]string)
+	rows := make(map[string][]int)
+	for i, x := range ds.X {
+		v := x[m.Column]
+		if math.IsNaN(v) {
+			return fmt.Errorf("%w: missing group at row %d", ErrInvalidGroupColumn, i)
+		}
+		key, ok := keys[v]
+		if !ok {
+			key, ok = categories[v]
+			if !ok {
+				key = strconv.FormatFloat(v, 'g', -1, 64)
+			}
+			keys[v] = key
+		}
+		rows[key] = append(rows[key], i)
+	}
+
+	var names []string
+	if ds.FeatureNames != nil {
+		names = withoutColumn(ds.FeatureNames, m.Column)
+	}
+	order := make([]string, 0, len(rows))
+	for key := range rows {
+		order = append(order, key)
+	}
+	slices.Sort(order)
+
+	models := make([]*GBM, len(order))
+	errs := make([]error, len(order))
+	next := make(chan int)
+	workers := m.Workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var wg sync.WaitGroup
+	for range min(workers, len(order)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range next {
+				X := make([][]float64, len(rows[order[k]]))
+				for r, i := range rows[order[k]] {
+					X[r] = withoutColumn(ds.X[i], m.Column)
+				}
+				model := New(m.Config)
+				if errs[k] = model.Fit(X, extractRows(ds.Y, rows[order[k]])); errs[k] == nil {
+					errs[k] = model.SetFeatureNames(names)
+				}
+				models[k] = model
+			}
+		}()
+	}
+	for k := range order {
+		next <- k
+	}
+	close(next)
+	wg.Wait()
+
+	for k, err := range errs {
+		if err != nil {
+			return fmt.Errorf("group %q: %w", order[k], err)
+		}
+	}
+	m.Models = make(map[string]*GBM, len(order))
+	for k, key := range order {
+		m.Models[key] = models[k]
+	}
+	m.keys = keys
+	return nil
+}
+
+// Groups returns the keys of the trained group models, sorted.
+func (m *GroupedModel) Groups() []string {
+	keys := make([]string, 0, len(m.Models))
+	for key := range m.Models {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Predict routes each row of X to its group's model and returns the raw
+// predictions (log-odds for logloss).
+//
+// Returns [ErrModelNotFitted] if the model has not been trained,
+// [ErrFeatureCountMismatch] if a row does not have the training width, or
+// an error wrapping [ErrUnknownGroup] if a row's group had no training rows.
+func (m *GroupedModel) Predict(X [][]float64) ([]float64, error) {
+	res := make([]float64, len(X))
+	for i, x := range X {
+		p, err := m.PredictSingle(x)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		res[i] = p
+	}
+	return res, nil
+}
+
+// PredictSingle returns the raw prediction of x's group model. It returns
+// the errors of [GroupedModel.Predict].
+func (m *GroupedModel) PredictSingle(x []float64) (float64, error) {
+	model, err := m.route(x)
+	if err != nil {
+		return 0, err
+	}
+	return model.PredictSingle(withoutColumn(x, m.Column)), nil
+}
+
+// PredictProba returns P(y=1) from x's group model, which must use logloss.
+// It returns the errors of [GroupedModel.Predict].
+func (m *GroupedModel) PredictProba(x []float64) (float64, error) {
+	model, err := m.route(x)
+	if err != nil {
+		return 0, err
+	}
+	return model.PredictProba(withoutColumn(x, m.Column)), nil
+}
+
+// route returns the model of x's group.
+func (m *GroupedModel) route(x []float64) (*GBM, error) {
+	if m.Models == nil {
+		return nil, ErrModelNotFitted
+	}
+	if len(x) <= m.Column {
+		return nil, ErrFeatureCountMismatch
+	}
+	key, ok := m.keys[x[m.Column]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownGroup, x[m.Column])
+	}
+	model := m.Models[key]
+	if len(x) != model.numFeatures+1 {
+		return nil, ErrFeatureCountMismatch
+	}
+	return model, nil
+}
+
+// withoutColumn returns a copy of row without its element j.
+func withoutColumn[T any](row []T, j int) []T {
+	out := make([]T, 0, len(row)-1)
+	out = append(out, row[:j]...)
+	return append(out, row[j+1:]...)
+}
+
+// exportedGroupedModel is the JSON form of a [GroupedModel].
+type exportedGroupedModel struct {
+	Column int             `json:"column"`
+	Groups []exportedGroup `json:"groups"`
+}
+
+// exportedGroup is one group of an [exportedGroupedModel], with the value of
+// the group column that routes to it.
+type exportedGroup struct {
+	Key   string         `json:"key"`
+	Value float64        `json:"value"`
+	Model *ExportedModel `json:"model"`
+}
+
+// Save writes every group model, with its key and the value of the group
+// column that selects it, to a single JSON file at path. The file can be
+// restored with [LoadGrouped].
+//
+// Returns [ErrModelNotFitted] if the model has not been trained.
+func (m *GroupedModel) Save(path string) error {
+	if m.Models == nil {
+		return ErrModelNotFitted
+	}
+	exported := exportedGroupedModel{Column: m.Column}
+	for v, key := range m.keys {
+		exported.Groups = append(exported.Groups, exportedGroup{Key: key, Value: v, Model: m.Models[key].toExported()})
+	}
+	slices.SortFunc(exported.Groups, func(a, b exportedGroup) int { return cmp.Compare(a.Key, b.Key) })
+	return writeJSON(path, exported)
+}
+
+// LoadGrouped reads a grouped model previously written by
+// [GroupedModel.Save]. Its Config is that of the first group's model.
+func LoadGrouped(path string) (*GroupedModel, error) {
+	var exported exportedGroupedModel
+	if err := readJSON(path, &exported); err != nil {
+		return nil, err
+	}
+	if len(exported.Groups) == 0 {
+		return nil, fmt.Errorf("grouped model file has no groups")
+	}
+	m := &GroupedModel{
+		Column: exported.Column,
+		Models: make(map[string]*GBM, len(exported.Groups)),
+		keys:   make(map[float64]string, len(exported.Groups)),
+	}
+	for _, g := range exported.Groups {
+		if g.Model == nil {
+			return nil, fmt.Errorf("group %q has no model", g.Key)
+		}
+		m.Models[g.Key] = fromExported(g.Model)
+		m.keys[g.Value] = g.Key
+	}
+	m.Config = m.Models[exported.Groups[0].Key].Config
+	return m, nil
+}
@@ -0,0 +1,110 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// generatePanelData returns a dataset whose label-encoded column 1 is a
+// store, with a target of 10*x for store "north" and -10*x for "south".
+func generatePanelData() *Dataset {
+	rnd := rand.New(rand.NewSource(0))
+	ds := &Dataset{
+		Encodings:    map[int]map[string]float64{1: {"north": 0, "south": 1}},
+		FeatureNames: []string{"x", "store"},
+	}
+	for i := range 200 {
+		x, store := rnd.Float64(), float64(i%2)
+		ds.X = append(ds.X, []float64{x, store})
+		ds.Y = append(ds.Y, 10*x*(1-2*store))
+	}
+	return ds
+}
+
+func TestGroupedModel(t *testing.T) {
+	ds := generatePanelData()
+	cfg := DefaultConfig()
+	cfg.NEstimators = 50
+	m := NewGrouped(cfg, 1)
+	if err := m.Fit(ds); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Groups(); !slices.Equal(got, []string{"north", "south"}) {
+		t.Fatalf("Groups() = %v", got)
+	}
+	north := m.Models["north"]
+	if north.NumFeatures() != 1 || !slices.Equal(north.FeatureNames(), []string{"x"}) {
+		t.Errorf("group model has %d features named %v, want only x", north.NumFeatures(), north.FeatureNames())
+	}
+
+	X := [][]float64{{0.8, 0}, {0.8, 1}}
+	pred, err := m.Predict(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(pred[0]-8) > 1 || math.Abs(pred[1]+8) > 1 {
+		t.Errorf("predictions = %v, want about 8 and -8", pred)
+	}
+
+	// Training does not depend on the number of workers.
+	serial := NewGrouped(cfg, 1)
+	serial.Workers = 1
+	if err := serial.Fit(ds); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := serial.Predict(X); !slices.Equal(got, pred) {
+		t.Errorf("serial predictions %v, parallel %v", got, pred)
+	}
+
+	path := filepath.Join(t.TempDir(), "grouped.json")
+	if err := m.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadGrouped(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loaded.Predict(X); !slices.Equal(got, pred) {
+		t.Errorf("loaded predictions %v, want %v", got, pred)
+	}
+	if loaded.Column != 1 || loaded.Config.NEstimators != 50 {
+		t.Errorf("loaded column %d, config %+v", loaded.Column, loaded.Config)
+	}
+}
+
+func TestGroupedModelErrors(t *testing.T) {
+	ds := generatePanelData()
+	m := NewGrouped(DefaultConfig(), 1)
+	if _, err := m.Predict([][]float64{{0.5, 0}}); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("unfitted: err = %v", err)
+	}
+	if err := m.Save(filepath.Join(t.TempDir(), "m.json")); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("save unfitted: err = %v", err)
+	}
+	if err := NewGrouped(DefaultConfig(), 2).Fit(ds); !errors.Is(err, ErrInvalidGroupColumn) {
+		t.Errorf("column out of range: err = %v", err)
+	}
+	bad := NewGrouped(Config{NEstimators: -1}, 1)
+	if err := bad.Fit(ds); !errors.Is(err, ErrInvalidNEstimators) {
+		t.Errorf("bad config: err = %v", err)
+	}
+
+	if err := m.Fit(ds); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.PredictSingle([]float64{0.5, 2}); !errors.Is(err, ErrUnknownGroup) {
+		t.Errorf("unseen group: err = %v", err)
+	}
+	if _, err := m.PredictSingle([]float64{0.5, 0, 1}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("row width: err = %v", err)
+	}
+
+	ds.X[3][1] = math.NaN()
+	if err := m.Fit(ds); !errors.Is(err, ErrInvalidGroupColumn) {
+		t.Errorf("missing group: err = %v", err)
+	}
+}
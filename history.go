@@ -0,0 +1,64 @@
+package gboost
+
+// RoundStats records the state of training after one boosting round.
+type RoundStats struct {
+	Round     int     // 1-based round number.
+	TrainLoss float64 // Mean loss on the rows used for fitting.
+
+	// SampleSize is the number of rows the round's tree was built on, after
+	// row sampling.
+	SampleSize int
+
+	// EffectiveSampleSize is Kish's effective sample size of those rows,
+	// (sum w)^2 / sum w^2, where w are the weights row sampling applied to
+	// gradients and Hessians. It equals SampleSize without reweighting and
+	// shows how much GOSS or negative downsampling concentrates the tree on
+	// fewer rows.
+	EffectiveSampleSize float64
+
+	// Features is the number of features the tree was allowed to split on.
+	Features int
+
+	// TreeStats is the shape of the round's tree. Rounds whose trees shrink
+	// to stumps or single leaves have run out of signal to fit.
+	TreeStats
+
+	// Skipped reports that the round's tree was a single leaf and, under
+	// Config.SkipSingleLeafTrees, was not added to the model.
+	Skipped bool
+
+	// ValidationLoss is the mean loss on the rows held out by
+	// Config.ValidationFraction, or NaN when there is no validation set.
+	ValidationLoss float64
+
+	// LearningRate is the effective learning rate of the round's tree:
+	// Config.LearningRate, reduced after each plateau under
+	// Config.LRPatience.
+	LearningRate float64
+
+	// Slices holds the scores of the Config.EvalSlices due in this round,
+	// by slice name and then metric name. It is nil in rounds where no
+	// slice is due.
+	Slices map[string]map[string]float64
+
+	// Resources is the round's wall time and memory use when
+	// Config.TrackResources is set, and zero otherwise.
+	Resources RoundResources
+}
+
+// History returns per-round statistics from the last call to [GBM.Fit], in
+// round order. With early stopping it covers every round that was trained,
+// including those after the best round whose trees were discarded, and
+// rounds whose trees were skipped under Config.SkipSingleLeafTrees, so
+// len(History()) can exceed [GBM.NumTrees]. Models loaded from disk have no
+// history.
+func (g *GBM) History() []RoundStats {
+	return g.history
+}
+
+// Warnings returns the problems [GBM.Fit] worked around while training the
+// model, such as a constant target, or nil if there were none. They are
+// saved with the model.
+func (g *GBM) Warnings() []string {
+	return g.warnings
+}
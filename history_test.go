@@ -0,0 +1,292 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost/metrics"
+)
+
+// generateNoisyData returns a small regression problem with heavy noise, on
+// which deep trees start overfitting within a few rounds.
+func generateNoisyData() ([][]float64, []float64) {
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 200)
+	y := make([]float64, 200)
+	for i := range X {
+		x1, x2 := rnd.Float64(), rnd.Float64()
+		X[i] = []float64{x1, x2}
+		y[i] = x1 + 2*rnd.NormFloat64()
+	}
+	return X, y
+}
+
+func TestHistoryWithoutValidation(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 20
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	history := gbm.History()
+	if len(history) != 20 {
+		t.Fatalf("expected 20 rounds of history, got %d", len(history))
+	}
+	for i, h := range history {
+		if h.Round != i+1 {
+			t.Errorf("history[%d].Round = %d, want %d", i, h.Round, i+1)
+		}
+		if !math.IsNaN(h.ValidationLoss) {
+			t.Errorf("history[%d].ValidationLoss = %v, want NaN", i, h.ValidationLoss)
+		}
+		if i > 0 && h.TrainLoss > history[i-1].TrainLoss {
+			t.Errorf("training loss increased at round %d: %v > %v", h.Round, h.TrainLoss, history[i-1].TrainLoss)
+		}
+	}
+}
+
+func TestHistoryTreeStats(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	cfg.MaxDepth = 3
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := gbm.TreeStats()
+	for i, h := range gbm.History() {
+		if h.TreeStats != stats[i] {
+			t.Errorf("round %d: history %+v, tree %+v", h.Round, h.TreeStats, stats[i])
+		}
+		if h.Depth < 1 || h.Leaves < 2 || h.Gain <= 0 {
+			t.Errorf("round %d: degenerate tree %+v on learnable data", h.Round, h.TreeStats)
+		}
+	}
+
+	// With nothing left to fit, every tree degenerates to a single leaf.
+	constant := make([]float64, len(y))
+	if err := gbm.Fit(X, constant); err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range gbm.History() {
+		if h.Depth != 0 || h.Leaves != 1 || h.Gain != 0 {
+			t.Errorf("round %d: %+v, want a single leaf", h.Round, h.TreeStats)
+		}
+	}
+}
+
+func TestEarlyStoppingKeepsBestRound(t *testing.T) {
+	X, y := generateNoisyData()
+	cfg := DefaultConfig()
+	cfg.NEstimators = 200
+	cfg.LearningRate = 0.3
+	cfg.ValidationFraction = 0.25
+	cfg.Patience = 5
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	history := gbm.History()
+	if len(history) == cfg.NEstimators {
+		t.Fatal("expected early stopping on noisy data")
+	}
+	if len(history) != gbm.NumTrees()+cfg.Patience {
+		t.Errorf("trained %d rounds and kept %d trees, want a gap of Patience=%d", len(history), gbm.NumTrees(), cfg.Patience)
+	}
+
+	best := history[gbm.NumTrees()-1].ValidationLoss
+	for _, h := range history {
+		if h.ValidationLoss < best {
+			t.Errorf("round %d has validation loss %v below the kept round's %v", h.Round, h.ValidationLoss, best)
+		}
+	}
+}
+
+func TestEarlyStoppingMinDelta(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 50
+	cfg.ValidationFraction = 0.2
+	cfg.Patience = 3
+	cfg.MinDelta = 1e6 // no improvement after the first round is large enough
+
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if gbm.NumTrees() != 1 || len(gbm.History()) != 4 {
+		t.Errorf("kept %d trees after %d rounds, want 1 tree after 4 rounds", gbm.NumTrees(), len(gbm.History()))
+	}
+}
+
+func TestReduceLROnPlateau(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 50
+	cfg.ValidationFraction = 0.2
+	cfg.Patience = 10
+	cfg.MinDelta = 1e6 // every round after the first is a plateau
+	cfg.LRPatience = 2
+	cfg.MinLearningRate = cfg.LearningRate / 8
+
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rate halves after rounds 3, 5, and 7, and then stays at the floor.
+	lr := cfg.LearningRate
+	want := []float64{lr, lr, lr, lr / 2, lr / 2, lr / 4, lr / 4, lr / 8, lr / 8, lr / 8, lr / 8}
+	history := gbm.History()
+	if len(history) != len(want) {
+		t.Fatalf("trained %d rounds, want %d", len(history), len(want))
+	}
+	for i, h := range history {
+		if math.Abs(h.LearningRate-want[i]) > 1e-12 {
+			t.Errorf("round %d: learning rate %v, want %v", h.Round, h.LearningRate, want[i])
+		}
+	}
+}
+
+func TestGainExhaustionStopping(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 200
+	cfg.MinGainFraction = 0.05
+
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	history := gbm.History()
+	if len(history) == cfg.NEstimators || gbm.NumTrees() != len(history) {
+		t.Fatalf("trained %d rounds and kept %d trees, want an early stop keeping every tree", len(history), gbm.NumTrees())
+	}
+	threshold := cfg.MinGainFraction * history[0].Gain
+	for _, h := range history[:len(history)-1] {
+		if h.Gain < threshold {
+			t.Errorf("round %d: gain %v below %v did not stop training", h.Round, h.Gain, threshold)
+		}
+	}
+	if last := history[len(history)-1]; last.Gain >= threshold {
+		t.Errorf("stopped after round %d with gain %v, above %v", last.Round, last.Gain, threshold)
+	}
+
+	// MinSamplesLeaf = len(y) makes every split infeasible, so each tree is a
+	// single leaf.
+	cfg.MinGainFraction = 0
+	cfg.MaxSingleLeafTrees = 3
+	cfg.MinSamplesLeaf = len(y)
+	gbm = New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if len(gbm.History()) != 3 {
+		t.Errorf("trained %d single-leaf trees, want 3", len(gbm.History()))
+	}
+}
+
+func TestSkipSingleLeafTrees(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+
+	// Every tree splits, so skipping changes nothing.
+	cfg.SkipSingleLeafTrees = true
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if gbm.NumTrees() != 10 || gbm.Warnings() != nil {
+		t.Errorf("kept %d trees with warnings %q, want 10 and none", gbm.NumTrees(), gbm.Warnings())
+	}
+
+	cfg.MinSamplesLeaf = len(y)
+	cfg.SkipSingleLeafTrees = false
+	gbm = New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if w := gbm.Warnings(); gbm.NumTrees() != 10 || len(w) != 1 || !strings.HasPrefix(w[0], "10 of 10 rounds found no split and added") {
+		t.Errorf("kept %d trees with warnings %q", gbm.NumTrees(), w)
+	}
+
+	cfg.SkipSingleLeafTrees = true
+	gbm = New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if w := gbm.Warnings(); gbm.NumTrees() != 0 || len(w) != 1 || !strings.Contains(w[0], "were skipped") {
+		t.Errorf("kept %d trees with warnings %q", gbm.NumTrees(), w)
+	}
+	history := gbm.History()
+	if len(history) != 10 || !history[9].Skipped || history[9].Leaves != 1 {
+		t.Errorf("history: %+v", history)
+	}
+	if p := gbm.PredictSingle(X[0]); p != gbm.BaseValue() {
+		t.Errorf("prediction %v, want the initial prediction %v", p, gbm.BaseValue())
+	}
+}
+
+func TestValidationSplit(t *testing.T) {
+	fit, val := validationSplit(10, 0.3, 1)
+	if len(fit) != 7 || len(val) != 3 {
+		t.Fatalf("split sizes = (%d, %d), want (7, 3)", len(fit), len(val))
+	}
+	seen := make(map[int]bool)
+	for _, i := range append(fit, val...) {
+		if seen[i] {
+			t.Fatalf("index %d in both sets", i)
+		}
+		seen[i] = true
+	}
+
+	// Tiny fractions still hold out one row and leave one for fitting.
+	if fit, val := validationSplit(2, 0.01, 1); len(fit) != 1 || len(val) != 1 {
+		t.Errorf("split sizes = (%d, %d), want (1, 1)", len(fit), len(val))
+	}
+}
+
+func TestHistoryEvalSlices(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 6
+	cfg.EvalSlices = []EvalSlice{
+		{Name: "head", X: X[:20], Y: y[:20]},
+		{Name: "tail", X: X[len(X)-20:], Y: y[len(y)-20:], Every: 3, Metrics: map[string]Metric{"mae": metrics.MAE}},
+	}
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, h := range gbm.History() {
+		_, tail := h.Slices["tail"]
+		if tail != (h.Round%3 == 0) {
+			t.Errorf("round %d: tail slice evaluated = %v", h.Round, tail)
+		}
+		prefix := *gbm
+		prefix.trees = gbm.trees[:h.Round]
+		want := evalLoss("mse", y[:20], prefix.Predict(X[:20]), nil)
+		if got := h.Slices["head"]["loss"]; math.Abs(got-want) > 1e-9 {
+			t.Errorf("round %d: head loss = %v, want %v", h.Round, got, want)
+		}
+	}
+	last := gbm.History()[5]
+	if want := metrics.MAE(y[len(y)-20:], gbm.Predict(X[len(X)-20:])); math.Abs(last.Slices["tail"]["mae"]-want) > 1e-9 {
+		t.Errorf("tail MAE = %v, want %v", last.Slices["tail"]["mae"], want)
+	}
+
+	cfg.EvalSlices = []EvalSlice{{Name: "narrow", X: [][]float64{{1}}, Y: []float64{1}}}
+	if err := New(cfg).Fit(X, y); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("narrow slice: err = %v, want ErrFeatureCountMismatch", err)
+	}
+}
@@ -0,0 +1,80 @@
+package gboost
+
+import "fmt"
+
+// HurdleModel is a two-part model for targets with a large spike at zero,
+// such as insurance claim amounts or product usage. A classifier estimates
+// P(y != 0) on every sample, and a regressor estimates E[y | y != 0] on the
+// non-zero samples only. The expected value is their product:
+//
+//	E[y | x] = P(y != 0 | x) * E[y | y != 0, x]
+//
+// Both parts are ordinary [GBM] models and can be inspected, explained, or
+// saved individually.
+type HurdleModel struct {
+	Classifier *GBM // Trained with logloss on the indicator y != 0.
+	Regressor  *GBM // Trained on the samples with y != 0.
+}
+
+// NewHurdle creates an untrained [HurdleModel]. The classifier config must
+// use the "logloss" loss; the regressor config is used as is.
+func NewHurdle(classifier, regressor Config) *HurdleModel {
+	return &HurdleModel{
+		Classifier: New(classifier),
+		Regressor:  New(regressor),
+	}
+}
+
+// Fit trains the classifier on all of X and the regressor on the rows whose
+// target is non-zero.
+//
+// Returns [ErrInvalidLoss] if the classifier config does not use logloss,
+// [ErrEmptyDataset] if y has no non-zero values, and otherwise any error from
+// [GBM.Fit] on either part.
+func (h *HurdleModel) Fit(X [][]float64, y []float64) error {
+	if h.Classifier.Config.Loss != "logloss" {
+		return fmt.Errorf("hurdle classifier: %w", ErrInvalidLoss)
+	}
+	if len(X) != len(y) {
+		return ErrLengthMismatch
+	}
+
+	isNonZero := make([]float64, len(y))
+	var nzX [][]float64
+	var nzY []float64
+	for i, v := range y {
+		if v != 0 {
+			isNonZero[i] = 1
+			nzX = append(nzX, X[i])
+			nzY = append(nzY, v)
+		}
+	}
+
+	if err := h.Classifier.Fit(X, isNonZero); err != nil {
+		return fmt.Errorf("hurdle classifier: %w", err)
+	}
+	if err := h.Regressor.Fit(nzX, nzY); err != nil {
+		return fmt.Errorf("hurdle regressor: %w", err)
+	}
+	return nil
+}
+
+// Predict returns the expected target value for each sample in X.
+func (h *HurdleModel) Predict(X [][]float64) []float64 {
+	res := make([]float64, len(X))
+	for i := range X {
+		res[i] = h.PredictSingle(X[i])
+	}
+	return res
+}
+
+// PredictSingle returns the expected target value for one sample:
+// P(y != 0 | x) times the regressor's prediction.
+func (h *HurdleModel) PredictSingle(x []float64) float64 {
+	return h.ProbaNonZero(x) * h.Regressor.PredictSingle(x)
+}
+
+// ProbaNonZero returns the classifier's estimate of P(y != 0 | x).
+func (h *HurdleModel) ProbaNonZero(x []float64) float64 {
+	return h.Classifier.PredictProba(x)
+}
@@ -0,0 +1,82 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// generateZeroInflatedData returns samples whose target is zero when
+// x1 < 5 and 2*x2 + 1 otherwise.
+func generateZeroInflatedData() ([][]float64, []float64) {
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 300)
+	y := make([]float64, 300)
+	for i := range X {
+		x1, x2 := rnd.Float64()*10, rnd.Float64()*10
+		X[i] = []float64{x1, x2}
+		if x1 >= 5 {
+			y[i] = 2*x2 + 1
+		}
+	}
+	return X, y
+}
+
+func newTestHurdle() *HurdleModel {
+	clf := DefaultConfig()
+	clf.Loss = "logloss"
+	clf.NEstimators = 30
+	clf.MaxDepth = 2
+	reg := DefaultConfig()
+	reg.NEstimators = 50
+	reg.MaxDepth = 3
+	return NewHurdle(clf, reg)
+}
+
+func TestHurdleModelFitPredict(t *testing.T) {
+	X, y := generateZeroInflatedData()
+	h := newTestHurdle()
+	if err := h.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := h.ProbaNonZero([]float64{2, 5}); p > 0.1 {
+		t.Errorf("P(y != 0) for x1=2 is %v, want near 0", p)
+	}
+	if p := h.ProbaNonZero([]float64{8, 5}); p < 0.9 {
+		t.Errorf("P(y != 0) for x1=8 is %v, want near 1", p)
+	}
+	if got := h.PredictSingle([]float64{2, 5}); math.Abs(got) > 1.5 {
+		t.Errorf("prediction in the zero region is %v, want near 0", got)
+	}
+	if got := h.PredictSingle([]float64{8, 5}); math.Abs(got-11) > 1.5 {
+		t.Errorf("prediction in the non-zero region is %v, want near 11", got)
+	}
+
+	// The regressor only ever saw non-zero targets.
+	if got := h.Regressor.PredictSingle([]float64{2, 5}); got < 5 {
+		t.Errorf("regressor predicted %v in the zero region, want a conditional mean well above 0", got)
+	}
+
+	preds := h.Predict(X[:3])
+	for i, p := range preds {
+		if p != h.PredictSingle(X[i]) {
+			t.Errorf("Predict[%d] = %v, PredictSingle = %v", i, p, h.PredictSingle(X[i]))
+		}
+	}
+}
+
+func TestHurdleModelErrors(t *testing.T) {
+	X, y := generateZeroInflatedData()
+
+	if err := NewHurdle(DefaultConfig(), DefaultConfig()).Fit(X, y); !errors.Is(err, ErrInvalidLoss) {
+		t.Errorf("expected ErrInvalidLoss for an mse classifier, got %v", err)
+	}
+	if err := newTestHurdle().Fit(X, y[1:]); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+	if err := newTestHurdle().Fit(X, make([]float64, len(X))); !errors.Is(err, ErrEmptyDataset) {
+		t.Errorf("expected ErrEmptyDataset for all-zero targets, got %v", err)
+	}
+}
@@ -0,0 +1,313 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// ImportanceType selects how [GBM.FeatureImportanceByType] scores features.
+type ImportanceType string
+
+// Importance types. They often disagree: a feature split on many times near
+// the leaves has a high split count but little gain or cover.
+const (
+	// ImportanceGain sums each split's sample-weighted variance reduction.
+	// This is what [GBM.FeatureImportance] returns.
+	ImportanceGain ImportanceType = "gain"
+
+	// ImportanceSplit counts how many times each feature is split on.
+	ImportanceSplit ImportanceType = "split"
+
+	// ImportanceCover sums the Hessians of the samples reaching each split,
+	// so splits affecting many (or, for logloss, many uncertain) samples
+	// weigh more.
+	ImportanceCover ImportanceType = "cover"
+)
+
+// FeatureImportanceByType returns feature importance of the given type,
+// normalized to sum to 1.0 (all zeros if the trees have no splits).
+//
+// Models saved before cover was recorded have no Hessian sums; for them,
+// [ImportanceCover] falls back to sample counts, which is exact for MSE.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or an error
+// if t is not a known [ImportanceType].
+func (g *GBM) FeatureImportanceByType(t ImportanceType) ([]float64, error) {
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+
+	res := make([]float64, g.numFeatures)
+	var visit func(n *Node)
+	switch t {
+	case ImportanceGain:
+		return append([]float64(nil), g.featureImportance...), nil
+	case ImportanceSplit:
+		visit = func(n *Node) { res[n.FeatureIndex]++ }
+	case ImportanceCover:
+		visit = func(n *Node) {
+			if n.Cover > 0 {
+				res[n.FeatureIndex] += n.Cover
+			} else {
+				res[n.FeatureIndex] += float64(n.NSamples)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown importance type %q (want gain, split, or cover)", t)
+	}
+
+	for _, tree := range g.trees {
+		walkSplits(tree, visit)
+	}
+	if total := sum(res); total > 0 {
+		for j := range res {
+			res[j] /= total
+		}
+	}
+	return res, nil
+}
+
+// walkSplits calls visit on every internal node of the tree rooted at n.
+func walkSplits(n *Node, visit func(*Node)) {
+	if n.isLeaf() {
+		return
+	}
+	visit(n)
+	walkSplits(n.Left, visit)
+	walkSplits(n.Right, visit)
+}
+
+// PermutationOptions controls [GBM.PermutationImportance].
+type PermutationOptions struct {
+	// Metric scores the model on the evaluation data.
+	Metric Metric
+
+	// GreaterIsBetter reports whether higher Metric values are better.
+	GreaterIsBetter bool
+
+	// Repeats is the number of shuffles averaged per feature. Zero means 5.
+	Repeats int
+
+	// Seed makes the shuffles reproducible.
+	Seed int64
+}
+
+// PermutationImportance measures how much the model's score on (X, y) drops
+// when each feature column is randomly shuffled, breaking its relationship
+// with the target. importance[j] is the mean score drop over opts.Repeats
+// shuffles of feature j, oriented so that positive values always mean the
+// feature helps, whatever the metric's direction.
+//
+// Unlike [GBM.FeatureImportance], it is computed on held-out data and in the
+// metric's units, so it reflects generalization rather than training splits.
+// Features the model relies on only through correlated twins can score near 0.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained,
+// [ErrEmptyDataset] if X is empty, [ErrLengthMismatch] if X and y differ in
+// length, [ErrFeatureCountMismatch] if rows of X do not have numFeatures
+// columns, or [ErrNilMetric] if opts.Metric is nil.
+func (g *GBM) PermutationImportance(X [][]float64, y []float64, opts PermutationOptions) ([]float64, error) {
+	switch {
+	case !g.isFitted:
+		return nil, ErrModelNotFitted
+	case len(X) == 0:
+		return nil, ErrEmptyDataset
+	case len(X) != len(y):
+		return nil, ErrLengthMismatch
+	case len(X[0]) != g.numFeatures || !hasSimilarLength(X):
+		return nil, ErrFeatureCountMismatch
+	case opts.Metric == nil:
+		return nil, ErrNilMetric
+	}
+
+	repeats := opts.Repeats
+	if repeats <= 0 {
+		repeats = 5
+	}
+
+	baseline := opts.Metric(y, g.predictOutput(X))
+	rnd := newRNG(opts.Seed)
+
+	// Work on a shallow copy of the rows so the caller's X is never modified.
+	shuffled := make([][]float64, len(X))
+	for i, row := range X {
+		shuffled[i] = append([]float64(nil), row...)
+	}
+
+	importance := make([]float64, g.numFeatures)
+	perm := make([]int, len(X))
+	for j := range g.numFeatures {
+		var drop float64
+		for range repeats {
+			for i := range perm {
+				perm[i] = i
+			}
+			rnd.Shuffle(len(perm), func(a, b int) { perm[a], perm[b] = perm[b], perm[a] })
+			for i, src := range perm {
+				shuffled[i][j] = X[src][j]
+			}
+
+			score := opts.Metric(y, g.predictOutput(shuffled))
+			if opts.GreaterIsBetter {
+				drop += baseline - score
+			} else {
+				drop += score - baseline
+			}
+		}
+		importance[j] = drop / float64(repeats)
+
+		// Restore the column before moving on to the next feature.
+		for i := range shuffled {
+			shuffled[i][j] = X[i][j]
+		}
+	}
+
+	return importance, nil
+}
+
+// SeedImportanceOptions controls [SeedImportance].
+type SeedImportanceOptions struct {
+	// Seeds is the number of models trained. Must be >= 2.
+	Seeds int
+
+	// Seed is the master seed; model i is trained with a seed derived from it.
+	Seed int64
+
+	// Type selects the importance computed for each model. Empty means
+	// [ImportanceGain].
+	Type ImportanceType
+
+	// Workers bounds the number of models trained concurrently.
+	// Zero means runtime.GOMAXPROCS(0). Config.OnRoundEnd, if set, must be
+	// safe for concurrent use when Workers != 1.
+	Workers int
+}
+
+// ImportanceSpread summarizes feature importance across models trained with
+// different seeds.
+type ImportanceSpread struct {
+	Mean []float64   // Mean importance per feature.
+	Std  []float64   // Standard deviation of importance per feature.
+	Runs [][]float64 // Importance of each model, in seed order.
+}
+
+// SeedImportance trains opts.Seeds models on (X, y) that differ only in
+// their seed, on up to opts.Workers goroutines, and reports the mean and
+// standard deviation of each feature's importance. A feature whose mean is
+// small relative to its standard deviation owes its rank to the draw of the
+// random sample rather than to a stable signal.
+//
+// The seed only changes the model when cfg uses randomness: SubsampleRatio
+// below 1, GOSS, NegativeSampleRatio, ColsampleByTree, HonestFraction, or a
+// validation split. Otherwise every run is identical and Std is zero.
+//
+// Returns [ErrInvalidSeedCount] if opts.Seeds < 2, [ErrInvalidWorkers] if
+// opts.Workers < 0, or the first error from training or scoring a model.
+func SeedImportance(cfg Config, X [][]float64, y []float64, opts SeedImportanceOptions) (*ImportanceSpread, error) {
+	switch {
+	case opts.Seeds < 2:
+		return nil, ErrInvalidSeedCount
+	case opts.Workers < 0:
+		return nil, ErrInvalidWorkers
+	}
+	importanceType := opts.Type
+	if importanceType == "" {
+		importanceType = ImportanceGain
+	}
+	workers := opts.Workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	runs := make([][]float64, opts.Seeds)
+	errs := make([]error, opts.Seeds)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, opts.Seeds) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				runCfg := cfg
+				runCfg.Seed = deriveSeed(opts.Seed, i)
+				model := New(runCfg)
+				if errs[i] = model.Fit(X, y); errs[i] != nil {
+					continue
+				}
+				runs[i], errs[i] = model.FeatureImportanceByType(importanceType)
+			}
+		}()
+	}
+	for i := range opts.Seeds {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	numFeatures := len(runs[0])
+	spread := &ImportanceSpread{
+		Mean: make([]float64, numFeatures),
+		Std:  make([]float64, numFeatures),
+		Runs: runs,
+	}
+	column := make([]float64, len(runs))
+	for j := range numFeatures {
+		for i, run := range runs {
+			column[i] = run[j]
+		}
+		spread.Mean[j] = mean(column)
+		spread.Std[j] = math.Sqrt(variance(column))
+	}
+	return spread, nil
+}
+
+// FeatureUse is one split on a feature, as reported by [GBM.FeatureUsage].
+type FeatureUse struct {
+	Tree      int     // Index of the tree in boosting order.
+	Depth     int     // Depth of the split node; the root has depth 0.
+	Gain      float64 // Sample-weighted gain, in feature importance units.
+	Threshold float64 // Rows with a value below Threshold go left.
+	NSamples  int     // Training rows that reached the node.
+}
+
+// FeatureUsage returns every split of the model, grouped by feature: element
+// j lists the splits on feature j by tree, and within a tree in pre-order,
+// and is empty for features the model never splits on. It shows where in the
+// ensemble a feature matters: a feature used only deep in late trees, with
+// little gain, is often fitting noise, while one at the roots of early trees
+// carries the main signal.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained.
+func (g *GBM) FeatureUsage() ([][]FeatureUse, error) {
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+	usage := make([][]FeatureUse, g.numFeatures)
+	var visit func(n *Node, tree, depth int)
+	visit = func(n *Node, tree, depth int) {
+		if n.isLeaf() {
+			return
+		}
+		usage[n.FeatureIndex] = append(usage[n.FeatureIndex], FeatureUse{
+			Tree:      tree,
+			Depth:     depth,
+			Gain:      float64(float64(n.NSamples) * n.Gain),
+			Threshold: n.Threshold,
+			NSamples:  n.NSamples,
+		})
+		visit(n.Left, tree, depth+1)
+		visit(n.Right, tree, depth+1)
+	}
+	for t, tree := range g.trees {
+		visit(tree, t, 0)
+	}
+	return usage, nil
+}
@@ -0,0 +1,314 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost/metrics"
+)
+
+func TestPermutationImportanceRanksSignalFeature(t *testing.T) {
+	// y depends only on x1; x2 is noise.
+	X, y := generateDataWithFunc(func(x1, x2 float64) float64 { return 10 * x1 })
+	cfg := DefaultConfig()
+	cfg.NEstimators = 30
+	cfg.MaxDepth = 3
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	imp, err := gbm.PermutationImportance(X, y, PermutationOptions{Metric: metrics.RMSE, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(imp) != 2 {
+		t.Fatalf("expected 2 importances, got %d", len(imp))
+	}
+	if imp[0] <= imp[1] {
+		t.Errorf("expected x1 to dominate, got %v", imp)
+	}
+	if imp[0] <= 0 {
+		t.Errorf("expected positive importance for signal feature, got %v", imp[0])
+	}
+}
+
+func TestPermutationImportanceGreaterIsBetterOrientation(t *testing.T) {
+	X, y := generateBinaryData(5)
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 20
+	cfg.MaxDepth = 2
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	imp, err := gbm.PermutationImportance(X, y, PermutationOptions{Metric: metrics.AUC, GreaterIsBetter: true, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imp[0] <= 0 {
+		t.Errorf("AUC drop for the decisive feature should be positive, got %v", imp[0])
+	}
+}
+
+func TestPermutationImportanceDoesNotModifyInput(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	original := make([][]float64, len(X))
+	for i := range X {
+		original[i] = slices.Clone(X[i])
+	}
+
+	gbm := New(DefaultConfig())
+	gbm.Fit(X, y)
+	if _, err := gbm.PermutationImportance(X, y, PermutationOptions{Metric: metrics.MSE, Repeats: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range X {
+		if !slices.Equal(X[i], original[i]) {
+			t.Fatalf("row %d modified: %v, want %v", i, X[i], original[i])
+		}
+	}
+}
+
+func TestPermutationImportanceDeterministic(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	gbm := New(DefaultConfig())
+	gbm.Fit(X, y)
+
+	opts := PermutationOptions{Metric: metrics.MSE, Seed: 3}
+	a, _ := gbm.PermutationImportance(X, y, opts)
+	b, _ := gbm.PermutationImportance(X, y, opts)
+	if !slices.Equal(a, b) {
+		t.Errorf("same seed gave different importances: %v vs %v", a, b)
+	}
+}
+
+func TestPermutationImportanceErrors(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	opts := PermutationOptions{Metric: metrics.MSE}
+
+	if _, err := New(DefaultConfig()).PermutationImportance(X, y, opts); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+
+	gbm := New(DefaultConfig())
+	gbm.Fit(X, y)
+
+	tests := []struct {
+		name string
+		X    [][]float64
+		y    []float64
+		opts PermutationOptions
+		want error
+	}{
+		{"empty", nil, nil, opts, ErrEmptyDataset},
+		{"length mismatch", X, y[1:], opts, ErrLengthMismatch},
+		{"feature mismatch", [][]float64{{1}}, []float64{1}, opts, ErrFeatureCountMismatch},
+		{"nil metric", X, y, PermutationOptions{}, ErrNilMetric},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := gbm.PermutationImportance(tt.X, tt.y, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestFeatureImportanceByType(t *testing.T) {
+	// f0 splits once at the root, f1 splits twice below it with less cover.
+	gbm := New(DefaultConfig())
+	gbm.trees = []*Node{{
+		FeatureIndex: 0, Threshold: 1, NSamples: 10, Cover: 6, Gain: 1,
+		Left: &Node{
+			FeatureIndex: 1, Threshold: 1, NSamples: 6, Cover: 3, Gain: 0.1,
+			Left:  &Node{NSamples: 3, Cover: 1},
+			Right: &Node{NSamples: 3, Cover: 2},
+		},
+		Right: &Node{
+			FeatureIndex: 1, Threshold: 2, NSamples: 4, Cover: 3, Gain: 0.1,
+			Left:  &Node{NSamples: 2, Cover: 1},
+			Right: &Node{NSamples: 2, Cover: 2},
+		},
+	}}
+	gbm.numFeatures = 2
+	gbm.isFitted = true
+	gbm.calculateFeatureImportance()
+
+	tests := []struct {
+		typ  ImportanceType
+		want []float64
+	}{
+		{ImportanceGain, gbm.FeatureImportance()},
+		{ImportanceSplit, []float64{1.0 / 3, 2.0 / 3}},
+		{ImportanceCover, []float64{0.5, 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			got, err := gbm.FeatureImportanceByType(tt.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := range tt.want {
+				if math.Abs(got[j]-tt.want[j]) > 1e-12 {
+					t.Errorf("importance = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	// Without recorded cover (older saved models), sample counts are used.
+	walkSplits(gbm.trees[0], func(n *Node) { n.Cover = 0 })
+	got, _ := gbm.FeatureImportanceByType(ImportanceCover)
+	if math.Abs(got[0]-0.5) > 1e-12 || math.Abs(got[1]-0.5) > 1e-12 {
+		t.Errorf("fallback cover importance = %v, want [0.5 0.5]", got)
+	}
+}
+
+func TestFeatureImportanceByTypeErrors(t *testing.T) {
+	if _, err := New(DefaultConfig()).FeatureImportanceByType(ImportanceSplit); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+	if _, err := fitStumpModel(t).FeatureImportanceByType("weight"); err == nil {
+		t.Error("expected error for unknown importance type")
+	}
+}
+
+func TestFeatureImportanceByTypeSumsToOne(t *testing.T) {
+	X, y := generateBinaryData(5)
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 10
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	for _, typ := range []ImportanceType{ImportanceGain, ImportanceSplit, ImportanceCover} {
+		imp, err := gbm.FeatureImportanceByType(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := imp[0] + imp[1]; math.Abs(s-1) > 1e-9 {
+			t.Errorf("%s importances sum to %v, want 1", typ, s)
+		}
+	}
+}
+
+func TestSeedImportance(t *testing.T) {
+	X, y := generateDataWithFunc(func(x1, x2 float64) float64 { return 10*x1 + x2 })
+	cfg := DefaultConfig()
+	cfg.NEstimators = 20
+	cfg.MaxDepth = 3
+	cfg.SubsampleRatio = 0.5
+
+	opts := SeedImportanceOptions{Seeds: 4, Seed: 7, Workers: 2}
+	spread, err := SeedImportance(cfg, X, y, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spread.Runs) != 4 || len(spread.Mean) != 2 || len(spread.Std) != 2 {
+		t.Fatalf("got %d runs, %d means, %d stds", len(spread.Runs), len(spread.Mean), len(spread.Std))
+	}
+	if spread.Mean[0] <= spread.Mean[1] {
+		t.Errorf("expected x1 to dominate, got means %v", spread.Mean)
+	}
+	if spread.Std[0] == 0 || spread.Std[0] > spread.Mean[0]/4 {
+		t.Errorf("x1 importance %v ± %v, want a stable non-zero spread", spread.Mean[0], spread.Std[0])
+	}
+
+	// Each run matches a model trained alone with the derived seed, so the
+	// result does not depend on the number of workers.
+	cfg.Seed = deriveSeed(opts.Seed, 2)
+	model := New(cfg)
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if want := model.FeatureImportance(); !slices.Equal(spread.Runs[2], want) {
+		t.Errorf("run 2 = %v, want %v", spread.Runs[2], want)
+	}
+}
+
+func TestSeedImportanceDeterministicConfig(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 5
+	spread, err := SeedImportance(cfg, X, y, SeedImportanceOptions{Seeds: 3, Type: ImportanceSplit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j, s := range spread.Std {
+		if s != 0 {
+			t.Errorf("feature %d std = %v without sampling, want 0", j, s)
+		}
+	}
+}
+
+func TestSeedImportanceErrors(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	if _, err := SeedImportance(DefaultConfig(), X, y, SeedImportanceOptions{Seeds: 1}); !errors.Is(err, ErrInvalidSeedCount) {
+		t.Errorf("expected ErrInvalidSeedCount, got %v", err)
+	}
+	if _, err := SeedImportance(DefaultConfig(), X, y, SeedImportanceOptions{Seeds: 2, Workers: -1}); !errors.Is(err, ErrInvalidWorkers) {
+		t.Errorf("expected ErrInvalidWorkers, got %v", err)
+	}
+	if _, err := SeedImportance(DefaultConfig(), X, y[1:], SeedImportanceOptions{Seeds: 2}); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+func TestFeatureUsage(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	usage, err := gbm.FeatureUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != gbm.NumFeatures() {
+		t.Fatalf("len(usage) = %d, want %d", len(usage), gbm.NumFeatures())
+	}
+
+	// The uses add up to the split counts and gains of the trees.
+	splits, _ := gbm.FeatureImportanceByType(ImportanceSplit)
+	var total int
+	gains := make([]float64, len(usage))
+	for j, uses := range usage {
+		total += len(uses)
+		for k, u := range uses {
+			gains[j] += u.Gain
+			if k > 0 && u.Tree < uses[k-1].Tree {
+				t.Fatalf("feature %d: uses not in tree order: %+v", j, uses)
+			}
+			root, _ := gbm.Tree(u.Tree)
+			if u.Depth == 0 && (root.FeatureIndex != j || root.Threshold != u.Threshold || root.NSamples != u.NSamples) {
+				t.Errorf("feature %d: root use %+v does not match tree %d's root", j, u, u.Tree)
+			}
+			if u.Depth >= cfg.MaxDepth || u.Tree >= gbm.NumTrees() {
+				t.Errorf("feature %d: use %+v out of range", j, u)
+			}
+		}
+	}
+	for j := range usage {
+		if want := splits[j] * float64(total); math.Abs(float64(len(usage[j]))-want) > 1e-9 {
+			t.Errorf("feature %d: %d uses, want %v", j, len(usage[j]), want)
+		}
+		if want := gbm.FeatureImportance()[j] * sum(gains); math.Abs(gains[j]-want) > 1e-9*sum(gains) {
+			t.Errorf("feature %d: gain %v, want %v", j, gains[j], want)
+		}
+	}
+
+	if _, err := New(DefaultConfig()).FeatureUsage(); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("untrained: err = %v, want ErrModelNotFitted", err)
+	}
+}
@@ -0,0 +1,150 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ImputeStrategy selects how an [Imputer] fills missing values in a column.
+type ImputeStrategy string
+
+const (
+	ImputeMean         ImputeStrategy = "mean"          // Mean of the observed values.
+	ImputeMedian       ImputeStrategy = "median"        // Median of the observed values.
+	ImputeMostFrequent ImputeStrategy = "most_frequent" // Most common observed value; ties go to the smallest.
+	ImputeConstant     ImputeStrategy = "constant"      // Imputer.FillValue.
+)
+
+// Imputer replaces missing values, encoded as NaN, with per-column statistics
+// learned by [Imputer.Fit]. Learning the statistics once and persisting them
+// with the model, for example inside a [Pipeline], keeps missing-value
+// handling identical between training and serving.
+//
+// Columns that are entirely missing at fit time are filled with FillValue
+// whatever the strategy.
+type Imputer struct {
+	Strategy  ImputeStrategy         `json:"strategy"`
+	FillValue float64                `json:"fill_value"`
+	Columns   map[int]ImputeStrategy `json:"columns,omitempty"` // Per-column overrides of Strategy.
+
+	// Statistics holds the learned fill value of each column; nil until fitted.
+	Statistics []float64 `json:"statistics,omitempty"`
+}
+
+// NewImputer creates an unfitted [Imputer] using strategy for every column.
+func NewImputer(strategy ImputeStrategy) *Imputer {
+	return &Imputer{Strategy: strategy}
+}
+
+func (im *Imputer) strategy(j int) ImputeStrategy {
+	if s, ok := im.Columns[j]; ok {
+		return s
+	}
+	return im.Strategy
+}
+
+// Fit learns the fill value of each column of X. y is ignored; it is accepted
+// so that an Imputer satisfies [Transformer].
+//
+// Returns [ErrEmptyDataset] if X is empty, [ErrFeatureCountMismatch] if its
+// rows differ in length, or [ErrInvalidImputeStrategy] for an unknown strategy.
+func (im *Imputer) Fit(X [][]float64, _ []float64) error {
+	if len(X) == 0 {
+		return ErrEmptyDataset
+	}
+	if !hasSimilarLength(X) {
+		return ErrFeatureCountMismatch
+	}
+
+	stats := make([]float64, len(X[0]))
+	for j := range stats {
+		var observed []float64
+		for _, row := range X {
+			if !math.IsNaN(row[j]) {
+				observed = append(observed, row[j])
+			}
+		}
+		if len(observed) == 0 {
+			stats[j] = im.FillValue
+			continue
+		}
+
+		switch im.strategy(j) {
+		case ImputeMean:
+			stats[j] = mean(observed)
+		case ImputeMedian:
+			slices.Sort(observed)
+			mid := len(observed) / 2
+			stats[j] = observed[mid]
+			if len(observed)%2 == 0 {
+				stats[j] = (observed[mid-1] + observed[mid]) / 2
+			}
+		case ImputeMostFrequent:
+			slices.Sort(observed)
+			best, bestCount := observed[0], 0
+			for start := 0; start < len(observed); {
+				end := start
+				for end < len(observed) && observed[end] == observed[start] {
+					end++
+				}
+				if end-start > bestCount {
+					best, bestCount = observed[start], end-start
+				}
+				start = end
+			}
+			stats[j] = best
+		case ImputeConstant:
+			stats[j] = im.FillValue
+		default:
+			return fmt.Errorf("column %d: %w", j, ErrInvalidImputeStrategy)
+		}
+	}
+	im.Statistics = stats
+	return nil
+}
+
+// Transform returns a copy of X with every NaN replaced by its column's
+// learned fill value.
+//
+// Returns [ErrModelNotFitted] if the imputer has not been fitted, or
+// [ErrFeatureCountMismatch] if a row's length differs from the data it was
+// fitted on.
+func (im *Imputer) Transform(X [][]float64) ([][]float64, error) {
+	if im.Statistics == nil {
+		return nil, ErrModelNotFitted
+	}
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		if len(row) != len(im.Statistics) {
+			return nil, ErrFeatureCountMismatch
+		}
+		res[i] = slices.Clone(row)
+		for j, v := range res[i] {
+			if math.IsNaN(v) {
+				res[i][j] = im.Statistics[j]
+			}
+		}
+	}
+	return res, nil
+}
+
+// Save writes the imputer, including its learned statistics, to a JSON file
+// at path. The file can be restored with [LoadImputer].
+func (im *Imputer) Save(path string) error {
+	if im.Statistics == nil {
+		return ErrModelNotFitted
+	}
+	return writeJSON(path, im)
+}
+
+// LoadImputer reads an imputer previously written by [Imputer.Save].
+func LoadImputer(path string) (*Imputer, error) {
+	var im Imputer
+	if err := readJSON(path, &im); err != nil {
+		return nil, err
+	}
+	return &im, nil
+}
+
+func (im *Imputer) transformerKind() string { return "imputer" }
@@ -0,0 +1,110 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestImputerStrategies(t *testing.T) {
+	nan := math.NaN()
+	X := [][]float64{
+		{1, 4, nan},
+		{2, nan, nan},
+		{nan, 4, nan},
+		{6, 1, nan},
+		{2, 9, nan},
+	}
+
+	tests := []struct {
+		strategy ImputeStrategy
+		want     []float64
+	}{
+		{ImputeMean, []float64{2.75, 4.5, -1}},
+		{ImputeMedian, []float64{2, 4, -1}},
+		{ImputeMostFrequent, []float64{2, 4, -1}},
+		{ImputeConstant, []float64{-1, -1, -1}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			im := NewImputer(tt.strategy)
+			im.FillValue = -1
+			if err := im.Fit(X, nil); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(im.Statistics, tt.want) {
+				t.Errorf("Statistics = %v, want %v", im.Statistics, tt.want)
+			}
+
+			got, err := im.Transform(X)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got[2][0] != tt.want[0] || got[1][1] != tt.want[1] || got[0][2] != tt.want[2] {
+				t.Errorf("Transform filled %v, want %v", []float64{got[2][0], got[1][1], got[0][2]}, tt.want)
+			}
+			if got[0][0] != 1 || !math.IsNaN(X[2][0]) {
+				t.Error("Transform changed an observed value or its input")
+			}
+		})
+	}
+}
+
+func TestImputerColumnOverrides(t *testing.T) {
+	X := [][]float64{{1, 1}, {1, 2}, {7, 9}, {math.NaN(), math.NaN()}}
+	im := NewImputer(ImputeMean)
+	im.Columns = map[int]ImputeStrategy{1: ImputeMedian}
+	if err := im.Fit(X, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{3, 2}; !slices.Equal(im.Statistics, want) {
+		t.Errorf("Statistics = %v, want %v", im.Statistics, want)
+	}
+}
+
+func TestImputerErrors(t *testing.T) {
+	if _, err := NewImputer(ImputeMean).Transform([][]float64{{1}}); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+	if err := NewImputer(ImputeMean).Fit(nil, nil); !errors.Is(err, ErrEmptyDataset) {
+		t.Errorf("expected ErrEmptyDataset, got %v", err)
+	}
+	if err := NewImputer("mode").Fit([][]float64{{1}}, nil); !errors.Is(err, ErrInvalidImputeStrategy) {
+		t.Errorf("expected ErrInvalidImputeStrategy, got %v", err)
+	}
+
+	im := NewImputer(ImputeMean)
+	if err := im.Fit([][]float64{{1, 2}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := im.Transform([][]float64{{1}}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+}
+
+func TestImputerSaveLoad(t *testing.T) {
+	im := NewImputer(ImputeMedian)
+	im.Columns = map[int]ImputeStrategy{0: ImputeConstant}
+	im.FillValue = 5
+	if err := im.Fit([][]float64{{1, 2}, {3, 4}, {5, 9}}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "imputer.json")
+	if err := im.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadImputer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(loaded.Statistics, im.Statistics) || loaded.Columns[0] != ImputeConstant || loaded.Strategy != ImputeMedian {
+		t.Errorf("loaded %+v, want %+v", loaded, im)
+	}
+
+	if err := NewImputer(ImputeMean).Save(path); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+}
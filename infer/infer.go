@@ -0,0 +1,186 @@
+// Package infer scores gboost models with nothing but prediction code.
+//
+// It reads the JSON written by gboost's GBM.Save from a byte slice and keeps
+// each tree as a flat array of nodes. It imports neither os nor math/rand,
+// nor the gboost package itself, so it builds for js/wasm and for TinyGo
+// targets such as microcontrollers, where models are embedded in the binary
+// or fetched by the host. Predictions are identical to GBM.PredictSingle and
+// GBM.PredictProba, bit for bit, on every platform, and [Model.PredictValue]
+// applies the same inverse link as gboost's metrics and cross-validation.
+package infer
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ahmedaabouzied/gboost/internal/fpmath"
+)
+
+// Errors returned by [Parse].
+var (
+	ErrInvalidModel = errors.New("infer: invalid model")
+	ErrNoFeatures   = errors.New("infer: model has no features")
+)
+
+// Model is a trained gboost model ready for scoring. It is safe for
+// concurrent use.
+type Model struct {
+	initial      float64
+	learningRate float64
+	logloss      bool
+	gamma        bool
+	numFeatures  int
+	trees        [][]node
+}
+
+// node is one tree node. Leaves have feature -1; an internal node sends
+// x[feature] < threshold to left and everything else, NaN included, to right.
+type node struct {
+	feature     int32
+	left, right int32
+	threshold   float64
+	value       float64
+}
+
+// savedNode and savedModel mirror the parts of gboost's ExportedNode and
+// ExportedModel needed for prediction.
+type savedNode struct {
+	FeatureIndex int        `json:"feature_index"`
+	Threshold    float64    `json:"threshold"`
+	Value        float64    `json:"value"`
+	IsLeaf       bool       `json:"is_leaf"`
+	Left         *savedNode `json:"left"`
+	Right        *savedNode `json:"right"`
+}
+
+type savedModel struct {
+	Config struct {
+		LearningRate float64
+		Loss         string
+	} `json:"config"`
+	InitialPrediction float64      `json:"initial_prediction"`
+	Trees             []*savedNode `json:"trees"`
+	NumFeatures       int          `json:"num_features"`
+}
+
+// Parse reads a model from the JSON written by GBM.Save.
+//
+// Returns [ErrInvalidModel] if data is not such a model or a tree refers to
+// a feature outside the model, or [ErrNoFeatures] if the model was never
+// trained.
+func Parse(data []byte) (*Model, error) {
+	var saved savedModel
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, errors.Join(ErrInvalidModel, err)
+	}
+	if saved.NumFeatures < 1 {
+		return nil, ErrNoFeatures
+	}
+
+	m := &Model{
+		initial:      saved.InitialPrediction,
+		learningRate: saved.Config.LearningRate,
+		logloss:      saved.Config.Loss == "logloss",
+		gamma:        saved.Config.Loss == "gamma",
+		numFeatures:  saved.NumFeatures,
+		trees:        make([][]node, len(saved.Trees)),
+	}
+	for i, root := range saved.Trees {
+		var nodes []node
+		if _, ok := flatten(root, saved.NumFeatures, &nodes); !ok {
+			return nil, ErrInvalidModel
+		}
+		m.trees[i] = nodes
+	}
+	return m, nil
+}
+
+// flatten appends the subtree at n to nodes in preorder and returns its
+// index, or false if the subtree is malformed.
+func flatten(n *savedNode, numFeatures int, nodes *[]node) (int32, bool) {
+	if n == nil {
+		return 0, false
+	}
+	idx := int32(len(*nodes))
+	if n.Left == nil && n.Right == nil {
+		*nodes = append(*nodes, node{feature: -1, value: n.Value})
+		return idx, true
+	}
+	if n.Left == nil || n.Right == nil || n.FeatureIndex < 0 || n.FeatureIndex >= numFeatures {
+		return 0, false
+	}
+	*nodes = append(*nodes, node{feature: int32(n.FeatureIndex), threshold: n.Threshold})
+	left, ok := flatten(n.Left, numFeatures, nodes)
+	if !ok {
+		return 0, false
+	}
+	right, ok := flatten(n.Right, numFeatures, nodes)
+	if !ok {
+		return 0, false
+	}
+	(*nodes)[idx].left, (*nodes)[idx].right = left, right
+	return idx, true
+}
+
+// NumFeatures returns the number of features the model expects.
+func (m *Model) NumFeatures() int {
+	return m.numFeatures
+}
+
+// NumTrees returns the number of trees in the model.
+func (m *Model) NumTrees() int {
+	return len(m.trees)
+}
+
+// Classifier reports whether the model was trained with logloss, so that
+// [Model.PredictProba] is meaningful.
+func (m *Model) Classifier() bool {
+	return m.logloss
+}
+
+// Predict returns the raw prediction for x: the target value for
+// regression, log-odds for classification, or the log of the expected
+// value for the gamma loss. x must have [Model.NumFeatures] entries;
+// Predict panics on shorter input.
+func (m *Model) Predict(x []float64) float64 {
+	pred := m.initial
+	for _, tree := range m.trees {
+		i := int32(0)
+		for tree[i].feature >= 0 {
+			if x[tree[i].feature] < tree[i].threshold {
+				i = tree[i].left
+			} else {
+				i = tree[i].right
+			}
+		}
+		pred += float64(m.learningRate * tree[i].value)
+	}
+	return pred
+}
+
+// PredictProba returns P(y=1) for x. Only meaningful for classifiers.
+func (m *Model) PredictProba(x []float64) float64 {
+	return 1 / (1 + fpmath.Exp(-m.Predict(x)))
+}
+
+// PredictValue returns the prediction for x on the scale of the target:
+// P(y=1) for classifiers, the expected value for the gamma loss, and the
+// raw prediction otherwise.
+func (m *Model) PredictValue(x []float64) float64 {
+	switch {
+	case m.logloss:
+		return m.PredictProba(x)
+	case m.gamma:
+		return fpmath.Exp(m.Predict(x))
+	}
+	return m.Predict(x)
+}
+
+// PredictAll returns the raw prediction for each row of X.
+func (m *Model) PredictAll(X [][]float64) []float64 {
+	res := make([]float64, len(X))
+	for i, x := range X {
+		res[i] = m.Predict(x)
+	}
+	return res
+}
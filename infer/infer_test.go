@@ -0,0 +1,130 @@
+package infer_test
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/infer"
+	"github.com/ahmedaabouzied/gboost/internal/fpmath"
+)
+
+func savedModel(t *testing.T, loss string) (*gboost.GBM, []byte, [][]float64) {
+	t.Helper()
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 200)
+	y := make([]float64, 200)
+	for i := range X {
+		X[i] = []float64{rnd.Float64() * 10, rnd.Float64() * 10, rnd.Float64()}
+		y[i] = X[i][0] + 2*X[i][1]
+		if loss == "logloss" {
+			y[i] = 0
+			if X[i][0] > X[i][1] {
+				y[i] = 1
+			}
+		}
+	}
+	// NaN inputs follow the right branch in both implementations.
+	X[0][1] = math.NaN()
+
+	cfg := gboost.DefaultConfig()
+	cfg.NEstimators = 20
+	cfg.MaxDepth = 3
+	cfg.Loss = loss
+	model := gboost.New(cfg)
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := model.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model, data, X
+}
+
+func TestParityWithGBM(t *testing.T) {
+	for _, loss := range []string{"mse", "logloss", "gamma"} {
+		model, data, X := savedModel(t, loss)
+		m, err := infer.Parse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.NumFeatures() != 3 || m.NumTrees() != model.NumTrees() || m.Classifier() != (loss == "logloss") {
+			t.Errorf("%s: NumFeatures %d, NumTrees %d, Classifier %v", loss, m.NumFeatures(), m.NumTrees(), m.Classifier())
+		}
+		want := model.Predict(X)
+		got := m.PredictAll(X)
+		for i := range X {
+			if got[i] != want[i] {
+				t.Fatalf("%s row %d: infer predicts %v, GBM %v", loss, i, got[i], want[i])
+			}
+			if loss == "logloss" && m.PredictProba(X[i]) != model.PredictProba(X[i]) {
+				t.Fatalf("row %d: infer P(y=1) %v, GBM %v", i, m.PredictProba(X[i]), model.PredictProba(X[i]))
+			}
+			var value float64
+			switch loss {
+			case "mse":
+				value = want[i]
+			case "logloss":
+				value = model.PredictProba(X[i])
+			case "gamma":
+				value = fpmath.Exp(want[i])
+			}
+			if got := m.PredictValue(X[i]); got != value {
+				t.Fatalf("%s row %d: infer value %v, want %v", loss, i, got, value)
+			}
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name, data string
+		want       error
+	}{
+		{"not JSON", "{", infer.ErrInvalidModel},
+		{"untrained", `{"num_features": 0, "trees": []}`, infer.ErrNoFeatures},
+		{"feature out of range", `{"num_features": 1, "trees": [{"feature_index": 3, "left": {"is_leaf": true}, "right": {"is_leaf": true}}]}`, infer.ErrInvalidModel},
+		{"missing child", `{"num_features": 1, "trees": [{"feature_index": 0, "left": {"is_leaf": true}}]}`, infer.ErrInvalidModel},
+		{"null tree", `{"num_features": 1, "trees": [null]}`, infer.ErrInvalidModel},
+	}
+	for _, tt := range tests {
+		if _, err := infer.Parse([]byte(tt.data)); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+// TestMinimalImports keeps the package buildable for js/wasm and TinyGo.
+func TestMinimalImports(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	banned := map[string]bool{`"os"`: true, `"math/rand"`: true, `"github.com/ahmedaabouzied/gboost"`: true}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, imp := range f.Imports {
+			if banned[imp.Path.Value] {
+				t.Errorf("%s imports %s", file, imp.Path.Value)
+			}
+		}
+	}
+}
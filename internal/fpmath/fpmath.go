@@ -0,0 +1,114 @@
+// Package fpmath provides floating-point kernels that round identically on
+// every platform Go supports, so that gboost trains the same model, bit for
+// bit, wherever it runs.
+//
+// Two things in Go get in the way of that:
+//
+//   - The compiler may fuse x*y + z into one fused multiply-add instruction
+//     that skips the rounding of x*y. It does so on arm64, ppc64, s390x, and
+//     riscv64, and on amd64 when built with GOAMD64=v3 or higher. An
+//     explicit float64 conversion forces the rounding and prevents fusion,
+//     so gboost wraps the products that feed split gains, leaf values, and
+//     predictions in float64(...).
+//   - math.Exp and math.Log use assembly on some architectures, and
+//     math.Exp on amd64 picks an FMA code path at run time when the CPU
+//     supports it, so their results differ in the last bit between
+//     platforms. [Exp] and [Log] replace them wherever a result can reach a
+//     model.
+package fpmath
+
+import "math"
+
+// Exp returns e**x, rounding identically on every platform. It is the
+// algorithm of Go's pure-Go math.Exp (from FreeBSD's e_exp.c) with every
+// product explicitly rounded; special cases are the same as math.Exp's.
+func Exp(x float64) float64 {
+	const (
+		ln2Hi = 6.93147180369123816490e-01
+		ln2Lo = 1.90821492927058770002e-10
+		log2e = 1.44269504088896338700e+00
+
+		overflow  = 7.09782712893383973096e+02
+		underflow = -7.45133219101941108420e+02
+		nearZero  = 1.0 / (1 << 28) // 2**-28
+
+		p1 = 1.66666666666666657415e-01
+		p2 = -2.77777777770155933842e-03
+		p3 = 6.61375632143793436117e-05
+		p4 = -1.65339022054652515390e-06
+		p5 = 4.13813679705723846039e-08
+	)
+
+	switch {
+	case math.IsNaN(x):
+		return x
+	case x > overflow:
+		return math.Inf(1)
+	case x < underflow:
+		return 0
+	case -nearZero < x && x < nearZero:
+		return 1 + x
+	}
+
+	// Reduce x to r = hi - lo in [-ln2/2, ln2/2] with x = k*ln2 + r.
+	var k int
+	switch {
+	case x < 0:
+		k = int(float64(log2e*x) - 0.5)
+	case x > 0:
+		k = int(float64(log2e*x) + 0.5)
+	}
+	hi := x - float64(float64(k)*ln2Hi)
+	lo := float64(float64(k) * ln2Lo)
+
+	r := hi - lo
+	t := float64(r * r)
+	c := r - float64(t*(p1+float64(t*(p2+float64(t*(p3+float64(t*(p4+float64(t*p5)))))))))
+	y := 1 - ((lo - float64(r*c)/(2-c)) - hi)
+	return math.Ldexp(y, k)
+}
+
+// Log returns the natural logarithm of x, rounding identically on
+// every platform. It is the algorithm of Go's pure-Go math.Log (from
+// FreeBSD's e_log.c) with every product explicitly rounded; special cases
+// are the same as math.Log's.
+func Log(x float64) float64 {
+	const (
+		ln2Hi = 6.93147180369123816490e-01
+		ln2Lo = 1.90821492927058770002e-10
+		l1    = 6.666666666666735130e-01
+		l2    = 3.999999999940941908e-01
+		l3    = 2.857142874366239149e-01
+		l4    = 2.222219843214978396e-01
+		l5    = 1.818357216161805012e-01
+		l6    = 1.531383769920937332e-01
+		l7    = 1.479819860511658591e-01
+	)
+
+	switch {
+	case math.IsNaN(x) || math.IsInf(x, 1):
+		return x
+	case x < 0:
+		return math.NaN()
+	case x == 0:
+		return math.Inf(-1)
+	}
+
+	// Reduce x to f1 * 2**k with f1 in [sqrt(2)/2, sqrt(2)).
+	f1, ki := math.Frexp(x)
+	if f1 < math.Sqrt2/2 {
+		f1 *= 2
+		ki--
+	}
+	f := f1 - 1
+	k := float64(ki)
+
+	s := f / (2 + f)
+	s2 := float64(s * s)
+	s4 := float64(s2 * s2)
+	t1 := float64(s2 * (l1 + float64(s4*(l3+float64(s4*(l5+float64(s4*l7)))))))
+	t2 := float64(s4 * (l2 + float64(s4*(l4+float64(s4*l6)))))
+	R := t1 + t2
+	hfsq := float64(float64(0.5*f) * f)
+	return float64(k*ln2Hi) - ((hfsq - (float64(s*(hfsq+R)) + float64(k*ln2Lo))) - f)
+}
@@ -0,0 +1,68 @@
+package fpmath
+
+import (
+	"math"
+	"testing"
+)
+
+// The bits below were produced on linux/amd64 and must be reproduced
+// exactly on every other platform.
+func TestPinnedBits(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		f    func(float64) float64
+		x    float64
+		bits uint64
+	}{
+		{"Exp", Exp, -30, 0x3d3a56e0c2ac7f75},
+		{"Exp", Exp, -1.5, 0x3fcc8f87724b5c1d},
+		{"Exp", Exp, 0.1, 0x3ff1aec7b35a00d4},
+		{"Exp", Exp, 1, 0x4005bf0a8b145769},
+		{"Exp", Exp, 2.5, 0x40285d6fd931e0bb},
+		{"Exp", Exp, 700, 0x7f0d945df4f8ec8e},
+		{"Log", Log, 1e-300, 0xc085963447f87fb5},
+		{"Log", Log, 0.001, 0xc01ba18a998fffa0},
+		{"Log", Log, 0.5, 0xbfe62e42fefa39ef},
+		{"Log", Log, 2, 0x3fe62e42fefa39ef},
+		{"Log", Log, 1e10, 0x4037069e2aa2aa5b},
+	} {
+		if got := math.Float64bits(tc.f(tc.x)); got != tc.bits {
+			t.Errorf("%s(%v) bits = %#016x, want %#016x", tc.name, tc.x, got, tc.bits)
+		}
+	}
+}
+
+// Exp and Log stay within a few ulps of the math package, whose assembly
+// versions are not bit-identical to them.
+func TestMatchesMath(t *testing.T) {
+	for x := -40.0; x <= 40; x += 0.37 {
+		if got, want := Exp(x), math.Exp(x); math.Abs(got-want) > 1e-15*want {
+			t.Errorf("Exp(%v) = %v, want %v", x, got, want)
+		}
+		y := math.Exp(x)
+		if got, want := Log(y), math.Log(y); math.Abs(got-want) > 1e-15*max(1, math.Abs(want)) {
+			t.Errorf("Log(%v) = %v, want %v", y, got, want)
+		}
+	}
+}
+
+func TestSpecialCases(t *testing.T) {
+	switch {
+	case !math.IsNaN(Exp(math.NaN())):
+		t.Error("Exp(NaN) is not NaN")
+	case !math.IsInf(Exp(1000), 1) || !math.IsInf(Exp(math.Inf(1)), 1):
+		t.Error("Exp does not overflow to +Inf")
+	case Exp(-1000) != 0 || Exp(math.Inf(-1)) != 0:
+		t.Error("Exp does not underflow to 0")
+	case Exp(0) != 1:
+		t.Error("Exp(0) != 1")
+	case !math.IsNaN(Log(-1)) || !math.IsNaN(Log(math.NaN())):
+		t.Error("Log of a negative number or NaN is not NaN")
+	case !math.IsInf(Log(0), -1):
+		t.Error("Log(0) != -Inf")
+	case !math.IsInf(Log(math.Inf(1)), 1):
+		t.Error("Log(+Inf) != +Inf")
+	case Log(1) != 0:
+		t.Error("Log(1) != 0")
+	}
+}
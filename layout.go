@@ -0,0 +1,108 @@
+package gboost
+
+import "slices"
+
+// featureLayout is a compact internal numbering of the features a set of
+// trees splits on, ordered by how often predictions read them. Storing
+// per-feature data, such as bins or SHAP contributions, in this order keeps
+// the hot features together in a few cache lines and leaves the features no
+// tree uses out entirely, which matters for wide data where most columns are
+// never split on.
+type featureLayout struct {
+	// features[k] is the original index of internal feature k.
+	features []int
+
+	// column[j] is the internal index of original feature j, or -1 if no
+	// tree splits on it.
+	column []int
+}
+
+// newFeatureLayout profiles trees over numFeatures features. A feature's
+// access frequency is the number of training samples that reached its
+// splits, the best available estimate of how often prediction will read it;
+// splits without a recorded sample count, as in models saved by old
+// versions, count once. Ties go to the lower feature index.
+func newFeatureLayout(trees []*Node, numFeatures int) featureLayout {
+	visits := make([]int, numFeatures)
+	for _, tree := range trees {
+		walkSplits(tree, func(n *Node) {
+			visits[n.FeatureIndex] += max(n.NSamples, 1)
+		})
+	}
+
+	l := featureLayout{column: make([]int, numFeatures)}
+	for j, v := range visits {
+		l.column[j] = -1
+		if v > 0 {
+			l.features = append(l.features, j)
+		}
+	}
+	slices.SortStableFunc(l.features, func(a, b int) int { return visits[b] - visits[a] })
+	for k, j := range l.features {
+		l.column[j] = k
+	}
+	return l
+}
+
+// remap returns a copy of the tree rooted at n with each split's feature
+// replaced by its internal index.
+func (l featureLayout) remap(n *Node) *Node {
+	c := *n
+	if !n.isLeaf() {
+		c.FeatureIndex = l.column[n.FeatureIndex]
+		c.Left = l.remap(n.Left)
+		c.Right = l.remap(n.Right)
+	}
+	return &c
+}
+
+// layoutForest is a model's trees remapped to their [featureLayout].
+type layoutForest struct {
+	source []*Node // the model's trees when the forest was built
+	layout featureLayout
+	trees  []*Node
+}
+
+// layoutForest returns g's trees remapped to their feature layout. The
+// result is built on first use and cached until g.trees is replaced or
+// changes length; changes to the nodes themselves must call
+// [GBM.treesChanged].
+func (g *GBM) layoutForest() *layoutForest {
+	if g.forest != nil {
+		if f := g.forest.Load(); f != nil && f.builtFrom(g.trees) {
+			return f
+		}
+	}
+	f := &layoutForest{
+		source: g.trees,
+		layout: newFeatureLayout(g.trees, g.numFeatures),
+		trees:  make([]*Node, len(g.trees)),
+	}
+	for t, tree := range g.trees {
+		f.trees[t] = f.layout.remap(tree)
+	}
+	if g.forest != nil {
+		g.forest.Store(f)
+	}
+	return f
+}
+
+// builtFrom reports whether f was built from trees.
+func (f *layoutForest) builtFrom(trees []*Node) bool {
+	return len(trees) == len(f.source) && (len(trees) == 0 || &trees[0] == &f.source[0])
+}
+
+// treesChanged drops the cached [layoutForest] after the nodes of g.trees
+// were modified in place.
+func (g *GBM) treesChanged() {
+	if g.forest != nil {
+		g.forest.Store(nil)
+	}
+}
+
+// gather writes the features of x in internal order to dst.
+func (l featureLayout) gather(dst, x []float64) {
+	for k, j := range l.features {
+		dst[k] = x[j]
+	}
+}
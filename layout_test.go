@@ -0,0 +1,162 @@
+package gboost
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestFeatureLayout(t *testing.T) {
+	// f2 is reached by 10 samples, f0 by 6 + 4, and f1 and f3 never: f2 and
+	// f0 tie and the lower index goes first.
+	trees := []*Node{{
+		FeatureIndex: 2, Threshold: 1, NSamples: 10,
+		Left: &Node{
+			FeatureIndex: 0, Threshold: 1, NSamples: 6,
+			Left:  &Node{NSamples: 3},
+			Right: &Node{NSamples: 3},
+		},
+		Right: &Node{
+			FeatureIndex: 0, Threshold: 2, NSamples: 4,
+			Left:  &Node{NSamples: 2},
+			Right: &Node{NSamples: 2},
+		},
+	}, {
+		FeatureIndex: 2, Threshold: 3, NSamples: 1,
+		Left:  &Node{NSamples: 1},
+		Right: &Node{},
+	}}
+
+	l := newFeatureLayout(trees, 4)
+	if want := []int{2, 0}; !slices.Equal(l.features, want) {
+		t.Errorf("features = %v, want %v", l.features, want)
+	}
+	if want := []int{1, -1, 0, -1}; !slices.Equal(l.column, want) {
+		t.Errorf("column = %v, want %v", l.column, want)
+	}
+
+	remapped := l.remap(trees[0])
+	if remapped.FeatureIndex != 0 || remapped.Left.FeatureIndex != 1 || trees[0].FeatureIndex != 2 {
+		t.Errorf("remap: root feature %d, left feature %d, original root feature %d", remapped.FeatureIndex, remapped.Left.FeatureIndex, trees[0].FeatureIndex)
+	}
+	xs := make([]float64, 2)
+	if l.gather(xs, []float64{10, 11, 12, 13}); !slices.Equal(xs, []float64{12, 10}) {
+		t.Errorf("gather = %v, want [12 10]", xs)
+	}
+	for _, x := range [][]float64{{0, 0, 0, 0}, {1.5, 0, 0, 0}, {3, 0, 2, 0}} {
+		l.gather(xs, x)
+		if got, want := remapped.predict(xs), trees[0].predict(x); got != want {
+			t.Errorf("x=%v: remapped predicts %v, original %v", x, got, want)
+		}
+	}
+}
+
+func TestFeatureLayoutWideModel(t *testing.T) {
+	// Only the first two of 20 features carry signal, so most are never
+	// split on and are left out of the layout.
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 100)
+	y := make([]float64, len(X))
+	for i := range X {
+		X[i] = make([]float64, 20)
+		for j := range X[i] {
+			X[i][j] = rnd.Float64()
+		}
+		y[i] = math.Sin(6*X[i][0]) + X[i][1]
+	}
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	cfg.MaxDepth = 3
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := gbm.Quantize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.features) >= 20 || q.features[0] > 1 {
+		t.Errorf("internal features = %v, want a subset led by a signal feature", q.features)
+	}
+	got, want := q.Predict(X), gbm.Predict(X)
+	for i := range X {
+		if math.Abs(got[i]-want[i]) > 1e-5 {
+			t.Fatalf("row %d: quantized %v, model %v", i, got[i], want[i])
+		}
+	}
+
+	// Bin still returns one bin per input column.
+	bins := q.Bin(X[0])
+	if len(bins) != 20 {
+		t.Fatalf("Bin returned %d bins, want 20", len(bins))
+	}
+	for j, b := range bins {
+		if k := slices.Index(q.features, j); k < 0 && b != 0 {
+			t.Errorf("unused feature %d has bin %d, want 0", j, b)
+		} else if k >= 0 && b != q.binValue(k, X[0][j]) {
+			t.Errorf("feature %d has bin %d, want %d", j, b, q.binValue(k, X[0][j]))
+		}
+	}
+	if got := q.PredictBinned(bins); got != q.PredictSingle(X[0]) {
+		t.Errorf("PredictBinned = %v, PredictSingle = %v", got, q.PredictSingle(X[0]))
+	}
+
+	shap, err := gbm.ShapValues(X[:5])
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := gbm.BaseValue()
+	for i, phi := range shap {
+		if len(phi) != 20 {
+			t.Fatalf("row %d: %d contributions, want 20", i, len(phi))
+		}
+		for j, v := range phi {
+			if v != 0 && !slices.Contains(q.features, j) {
+				t.Errorf("row %d: unused feature %d contributes %v", i, j, v)
+			}
+		}
+		if total := base + sum(phi); math.Abs(total-want[i]) > 1e-9 {
+			t.Errorf("row %d: base + SHAP = %v, prediction %v", i, total, want[i])
+		}
+	}
+}
+
+func TestLayoutForestCache(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 5
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	f := gbm.layoutForest()
+	if gbm.layoutForest() != f {
+		t.Error("forest rebuilt without a change to the trees")
+	}
+	if _, err := gbm.ShapValuesSingle(X[0]); err != nil || gbm.layoutForest() != f {
+		t.Errorf("ShapValuesSingle did not reuse the cached forest (err %v)", err)
+	}
+
+	// A copy over fewer trees gets its own forest.
+	prefix := *gbm
+	prefix.trees = gbm.trees[:2]
+	if pf := prefix.layoutForest(); len(pf.trees) != 2 {
+		t.Errorf("prefix forest has %d trees, want 2", len(pf.trees))
+	}
+	if len(gbm.layoutForest().trees) != 5 {
+		t.Error("copy's forest replaced the original's")
+	}
+
+	// Refitting replaces the trees.
+	cfg.NEstimators = 3
+	gbm.Config = cfg
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if got := gbm.layoutForest(); got == f || len(got.trees) != 3 {
+		t.Errorf("forest not rebuilt after refit: %d trees", len(got.trees))
+	}
+}
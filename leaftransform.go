@@ -0,0 +1,99 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// LeafTransform post-processes the leaf values of every tree as it is
+// built, such as to clip them or round them to a pricing grid. Each leaf
+// contributes LearningRate times its value to a prediction, and later trees
+// are fitted to the gradients of the transformed ensemble, so they correct
+// what the transform changed. Config.SnapshotBlend rescales the last trees
+// after training, so it undoes a rounding transform on them.
+//
+// Set Config.LeafTransform to apply one. A function cannot be saved, so
+// String must describe the transform fully: it is recorded with the model
+// and returned by [GBM.LeafTransform], so that a reviewer can tell how the
+// leaves were altered and reproduce them.
+type LeafTransform interface {
+	Transform(value float64) float64
+	String() string
+}
+
+// ClipLeaves limits every leaf value to [Min, Max].
+type ClipLeaves struct {
+	Min, Max float64
+}
+
+// Transform clamps value to [c.Min, c.Max].
+func (c ClipLeaves) Transform(value float64) float64 {
+	return min(max(value, c.Min), c.Max)
+}
+
+func (c ClipLeaves) String() string {
+	return "clip(" + strconv.FormatFloat(c.Min, 'g', -1, 64) + ", " + strconv.FormatFloat(c.Max, 'g', -1, 64) + ")"
+}
+
+// RoundLeaves rounds every leaf value to the nearest multiple of Step,
+// halves away from zero. With a LearningRate of 1, a Step of 0.0001 rounds
+// each tree's contribution to a basis point.
+type RoundLeaves struct {
+	Step float64
+}
+
+// Transform rounds value to a multiple of r.Step.
+func (r RoundLeaves) Transform(value float64) float64 {
+	return math.Round(value/r.Step) * r.Step
+}
+
+func (r RoundLeaves) String() string {
+	return "round(" + strconv.FormatFloat(r.Step, 'g', -1, 64) + ")"
+}
+
+// validLeafTransform reports whether the parameters of a built-in transform
+// are usable. Other transforms are checked by their results.
+func validLeafTransform(t LeafTransform) bool {
+	switch t := t.(type) {
+	case ClipLeaves:
+		return t.Min <= t.Max && !math.IsInf(t.Min, 1) && !math.IsInf(t.Max, -1)
+	case RoundLeaves:
+		return t.Step > 0 && !math.IsInf(t.Step, 0)
+	}
+	return true
+}
+
+// transformLeaves applies Config.LeafTransform to the leaves of tree and
+// records its description. It fails if the transform returns a non-finite
+// value, leaving tree partly transformed.
+func (g *GBM) transformLeaves(tree *Node) error {
+	t := g.Config.LeafTransform
+	if t == nil {
+		return nil
+	}
+	g.leafTransform = t.String()
+	var visit func(n *Node) error
+	visit = func(n *Node) error {
+		if !n.isLeaf() {
+			if err := visit(n.Left); err != nil {
+				return err
+			}
+			return visit(n.Right)
+		}
+		v := t.Transform(n.Value)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: %s maps leaf value %v to %v", ErrInvalidLeafTransform, t, n.Value, v)
+		}
+		n.Value = v
+		return nil
+	}
+	return visit(tree)
+}
+
+// LeafTransform returns the description of the Config.LeafTransform that
+// post-processed the model's leaves, as recorded when it was trained and
+// saved with it, or "" if none did.
+func (g *GBM) LeafTransform() string {
+	return g.leafTransform
+}
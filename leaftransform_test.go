@@ -0,0 +1,110 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// leafValues returns the leaf values of every tree of the model.
+func leafValues(g *GBM) []float64 {
+	var values []float64
+	var visit func(n *Node)
+	visit = func(n *Node) {
+		if n.isLeaf() {
+			values = append(values, n.Value)
+			return
+		}
+		visit(n.Left)
+		visit(n.Right)
+	}
+	for _, tree := range g.trees {
+		visit(tree)
+	}
+	return values
+}
+
+func TestLeafTransform(t *testing.T) {
+	X, y := generateNoisyData()
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	cfg.LeafTransform = RoundLeaves{Step: 0.25}
+	rounded := New(cfg)
+	if err := rounded.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range leafValues(rounded) {
+		if v != math.Round(v*4)/4 {
+			t.Fatalf("leaf value %v is not a multiple of 0.25", v)
+		}
+	}
+	if got := rounded.LeafTransform(); got != "round(0.25)" {
+		t.Errorf("LeafTransform() = %q", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := rounded.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.LeafTransform() != "round(0.25)" {
+		t.Errorf("reloaded LeafTransform() = %q", loaded.LeafTransform())
+	}
+
+	cfg.LeafTransform = ClipLeaves{Min: -0.1, Max: 0.1}
+	clipped := New(cfg)
+	grads, hessians := squaredErrorDerivatives(y, make([]float64, len(y)))
+	if _, err := clipped.BoostOneRound(X, grads, hessians); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range leafValues(clipped) {
+		if v < -0.1 || v > 0.1 {
+			t.Errorf("leaf value %v outside [-0.1, 0.1]", v)
+		}
+	}
+	if got := clipped.LeafTransform(); got != "clip(-0.1, 0.1)" {
+		t.Errorf("LeafTransform() = %q", got)
+	}
+
+	cfg.LeafTransform = nil
+	plain := New(cfg)
+	if err := plain.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if plain.LeafTransform() != "" {
+		t.Errorf("LeafTransform() = %q without a transform", plain.LeafTransform())
+	}
+}
+
+// nanLeaves is a faulty transform.
+type nanLeaves struct{}
+
+func (nanLeaves) Transform(float64) float64 { return math.NaN() }
+func (nanLeaves) String() string            { return "nan" }
+
+func TestLeafTransformInvalid(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	for _, tr := range []LeafTransform{RoundLeaves{}, RoundLeaves{Step: -1}, ClipLeaves{Min: 1, Max: 0}, ClipLeaves{Min: math.NaN()}, nanLeaves{}} {
+		cfg := DefaultConfig()
+		cfg.LeafTransform = tr
+		gbm := New(cfg)
+		if err := gbm.Fit(X, y); !errors.Is(err, ErrInvalidLeafTransform) {
+			t.Errorf("%v: err = %v, want ErrInvalidLeafTransform", tr, err)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.LeafTransform = nanLeaves{}
+	gbm := New(cfg)
+	grads, hessians := squaredErrorDerivatives(y, make([]float64, len(y)))
+	if _, err := gbm.BoostOneRound(X, grads, hessians); !errors.Is(err, ErrInvalidLeafTransform) {
+		t.Errorf("BoostOneRound err = %v, want ErrInvalidLeafTransform", err)
+	}
+	if gbm.NumTrees() != 0 {
+		t.Errorf("failed round appended a tree")
+	}
+}
@@ -0,0 +1,201 @@
+package gboost
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// LeakageOptions controls [DetectLeakage] and the leakage check of
+// [Describe]. Zero values select the defaults.
+type LeakageOptions struct {
+	// Trees is the number of depth-1 trees in each single-feature model.
+	// Zero means 20.
+	Trees int
+
+	// Threshold is the score at or above which a feature is flagged, in
+	// (0, 1]. Zero means 0.95.
+	Threshold float64
+}
+
+func (o LeakageOptions) validate() error {
+	switch {
+	case o.Trees < 0:
+		return ErrInvalidNEstimators
+	case o.Threshold != 0 && !(o.Threshold > 0 && o.Threshold <= 1):
+		return ErrInvalidLeakageThreshold
+	}
+	return nil
+}
+
+func (o LeakageOptions) trees() int {
+	if o.Trees == 0 {
+		return 20
+	}
+	return o.Trees
+}
+
+func (o LeakageOptions) threshold() float64 {
+	if o.Threshold == 0 {
+		return 0.95
+	}
+	return o.Threshold
+}
+
+// LeakageSuspect is a feature that predicts the target almost perfectly on
+// its own, which usually means it was derived from the target or recorded
+// after it.
+type LeakageSuspect struct {
+	// Feature is the column index, and Name its name if known.
+	Feature int
+	Name    string
+
+	// Score is the single-feature model's fit: its AUC for a 0/1 target and
+	// its R² otherwise.
+	Score float64
+
+	// Metric names the score, "auc" or "r2".
+	Metric string
+}
+
+// DetectLeakage flags probable target leakage. It fits a model of
+// opts.Trees depth-1 trees on each column of X alone, against y, and
+// returns the columns whose model scores at least opts.Threshold, most
+// predictive first. A 0/1 target is fit with log loss and scored by AUC;
+// any other target is fit with squared error and scored by R².
+//
+// A model of T stumps is a step function of its feature with at most T+1
+// levels, too coarse to memorize rows, so it is scored on the rows it was
+// fit on. The same coarseness caps the R² of a continuous target: even a
+// copy of the target scores only about 0.97 with the default 20 trees.
+//
+// Rows where the feature is NaN are left out of its model. A feature with
+// fewer than two present rows, or a target constant on them, is never
+// flagged. Returns nil if no feature is.
+//
+// Returns [ErrEmptyDataset] if X is empty, [ErrLengthMismatch] if X and y
+// differ in length, [ErrFeatureCountMismatch] if rows differ in length, or
+// [ErrInvalidNEstimators] or [ErrInvalidLeakageThreshold] for invalid
+// options.
+func DetectLeakage(X [][]float64, y []float64, opts LeakageOptions) ([]LeakageSuspect, error) {
+	switch {
+	case len(X) == 0:
+		return nil, ErrEmptyDataset
+	case len(X) != len(y):
+		return nil, ErrLengthMismatch
+	case !hasSimilarLength(X):
+		return nil, ErrFeatureCountMismatch
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	binary := isBinaryTarget(y)
+	cfg := DefaultConfig()
+	cfg.NEstimators = opts.trees()
+	cfg.MaxDepth = 1
+	// Few trees at the default rate would fall well short of a perfect
+	// predictor's fit; full steps let them reach it.
+	cfg.LearningRate = 1
+	metric := "r2"
+	if binary {
+		cfg.Loss = "logloss"
+		metric = "auc"
+	}
+
+	var suspects []LeakageSuspect
+	for j := range X[0] {
+		var col [][]float64
+		var target []float64
+		for i, row := range X {
+			if !math.IsNaN(row[j]) {
+				col = append(col, []float64{row[j]})
+				target = append(target, y[i])
+			}
+		}
+		if len(col) < 2 {
+			continue
+		}
+		var score float64
+		if binary {
+			if !isBinaryTarget(target) {
+				continue
+			}
+			model := New(cfg)
+			if err := model.Fit(col, target); err != nil {
+				return nil, err
+			}
+			score = auc(target, model.PredictProbaAll(col))
+		} else {
+			if variance(target) == 0 {
+				continue
+			}
+			model := New(cfg)
+			if err := model.Fit(col, target); err != nil {
+				return nil, err
+			}
+			score = rSquared(target, model.Predict(col))
+		}
+		if score >= opts.threshold() {
+			suspects = append(suspects, LeakageSuspect{Feature: j, Score: score, Metric: metric})
+		}
+	}
+	slices.SortStableFunc(suspects, func(a, b LeakageSuspect) int { return cmp.Compare(b.Score, a.Score) })
+	return suspects, nil
+}
+
+// DetectLeakage flags the dataset's features that predict its target
+// almost perfectly, naming them after FeatureNames when the CSV had a
+// header. See [DetectLeakage].
+func (ds *Dataset) DetectLeakage(opts LeakageOptions) ([]LeakageSuspect, error) {
+	suspects, err := DetectLeakage(ds.X, ds.Y, opts)
+	if err != nil {
+		return nil, err
+	}
+	for k, s := range suspects {
+		if s.Feature < len(ds.FeatureNames) {
+			suspects[k].Name = ds.FeatureNames[s.Feature]
+		}
+	}
+	return suspects, nil
+}
+
+// auc returns the area under the ROC curve of scores against the 0/1
+// labels in y, counting ties as half. y must hold both classes.
+func auc(y, scores []float64) float64 {
+	order := make([]int, len(y))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int { return cmp.Compare(scores[a], scores[b]) })
+
+	// Mann-Whitney U from the average ranks of the positives.
+	var rankSum float64
+	nPos := 0
+	for i := 0; i < len(order); {
+		j := i
+		for j < len(order) && scores[order[j]] == scores[order[i]] {
+			j++
+		}
+		for _, k := range order[i:j] {
+			if y[k] == 1 {
+				rankSum += float64(i+j+1) / 2
+				nPos++
+			}
+		}
+		i = j
+	}
+	nNeg := len(y) - nPos
+	return (rankSum - float64(nPos*(nPos+1))/2) / float64(nPos*nNeg)
+}
+
+// rSquared returns the coefficient of determination of pred for y, whose
+// variance must be nonzero.
+func rSquared(y, pred []float64) float64 {
+	var sse float64
+	for i := range y {
+		d := y[i] - pred[i]
+		sse += d * d
+	}
+	return 1 - sse/(variance(y)*float64(len(y)))
+}
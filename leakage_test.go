@@ -0,0 +1,118 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// leakyData returns 200 rows whose feature 0 is noise, feature 1 a copy of
+// the target with a little noise, and feature 2 a weak signal with some
+// values missing.
+func leakyData(binary bool) ([][]float64, []float64) {
+	rnd := rand.New(rand.NewSource(3))
+	X := make([][]float64, 200)
+	y := make([]float64, 200)
+	for i := range X {
+		y[i] = rnd.NormFloat64()
+		if binary {
+			y[i] = float64(rnd.Intn(2))
+		}
+		weak := y[i] + 2*rnd.NormFloat64()
+		if i%10 == 0 {
+			weak = math.NaN()
+		}
+		X[i] = []float64{rnd.Float64(), 3*y[i] + 0.01*rnd.NormFloat64(), weak}
+	}
+	return X, y
+}
+
+func TestDetectLeakage(t *testing.T) {
+	for _, tt := range []struct {
+		binary bool
+		metric string
+	}{{true, "auc"}, {false, "r2"}} {
+		X, y := leakyData(tt.binary)
+		suspects, err := DetectLeakage(X, y, LeakageOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(suspects) != 1 {
+			t.Fatalf("%s: suspects = %+v, want feature 1 only", tt.metric, suspects)
+		}
+		if s := suspects[0]; s.Feature != 1 || s.Metric != tt.metric || s.Score < 0.95 || s.Score > 1 {
+			t.Errorf("%s: suspect = %+v", tt.metric, s)
+		}
+
+		// A low enough threshold also catches the weak feature, but never
+		// the noise at the top.
+		all, err := DetectLeakage(X, y, LeakageOptions{Threshold: 0.1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) < 2 || all[0].Feature != 1 || all[1].Score > all[0].Score {
+			t.Errorf("%s: threshold 0.1 suspects = %+v", tt.metric, all)
+		}
+	}
+}
+
+func TestDetectLeakageSkipsDegenerate(t *testing.T) {
+	X := [][]float64{{1, math.NaN()}, {2, 5}, {3, math.NaN()}}
+	suspects, err := DetectLeakage(X, []float64{4, 4, 4}, LeakageOptions{Threshold: 0.01})
+	if err != nil || suspects != nil {
+		t.Errorf("constant target: %+v, %v", suspects, err)
+	}
+	suspects, err = DetectLeakage(X, []float64{0, 1, 0}, LeakageOptions{Threshold: 0.01})
+	if err != nil || len(suspects) != 1 || suspects[0].Feature != 0 {
+		t.Errorf("one present row in feature 1: %+v, %v", suspects, err)
+	}
+}
+
+func TestDetectLeakageErrors(t *testing.T) {
+	X := [][]float64{{1}, {2}}
+	y := []float64{0, 1}
+	tests := []struct {
+		name string
+		X    [][]float64
+		y    []float64
+		opts LeakageOptions
+		want error
+	}{
+		{"empty", nil, nil, LeakageOptions{}, ErrEmptyDataset},
+		{"length mismatch", X, []float64{0}, LeakageOptions{}, ErrLengthMismatch},
+		{"ragged", [][]float64{{1}, {1, 2}}, y, LeakageOptions{}, ErrFeatureCountMismatch},
+		{"negative trees", X, y, LeakageOptions{Trees: -1}, ErrInvalidNEstimators},
+		{"threshold above 1", X, y, LeakageOptions{Threshold: 1.5}, ErrInvalidLeakageThreshold},
+		{"negative threshold", X, y, LeakageOptions{Threshold: -0.5}, ErrInvalidLeakageThreshold},
+	}
+	for _, tt := range tests {
+		if _, err := DetectLeakage(tt.X, tt.y, tt.opts); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestDescribeLeakage(t *testing.T) {
+	X, y := leakyData(true)
+	ds := &Dataset{X: X, Y: y, FeatureNames: []string{"noise", "outcome_code", "weak"}}
+
+	p, err := ds.Describe(DescribeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Leakage) != 1 || p.Leakage[0].Name != "outcome_code" {
+		t.Errorf("Leakage = %+v, want outcome_code", p.Leakage)
+	}
+
+	p, err = ds.Describe(DescribeOptions{SkipLeakage: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Leakage != nil {
+		t.Errorf("SkipLeakage: Leakage = %+v", p.Leakage)
+	}
+	if _, err := Describe(X, y, DescribeOptions{Leakage: LeakageOptions{Threshold: 2}}); !errors.Is(err, ErrInvalidLeakageThreshold) {
+		t.Errorf("err = %v, want ErrInvalidLeakageThreshold", err)
+	}
+}
@@ -1,6 +1,6 @@
 package gboost
 
-import "math"
+import "github.com/ahmedaabouzied/gboost/internal/fpmath"
 
 // Loss defines the interface for a loss function used by [GBM] during training.
 // It provides the initial constant prediction, first-order gradients, and
@@ -48,11 +48,13 @@ func (l *MSELoss) Hessian(y, pred []float64) []float64 {
 // for faster convergence and better probability calibration.
 type LogLoss struct{}
 
-// InitialPrediction returns the log-odds of the positive class: log(p / (1-p)).
+// InitialPrediction returns the log-odds of the positive class: log(p / (1-p)),
+// where p is the mean label, clipped to [0.001, 0.999]. With soft labels, p
+// is the mean probability of the positive class.
 func (l *LogLoss) InitialPrediction(y []float64) float64 {
 	p := mean(y)
 	p = max(0.001, min(0.999, p)) // clip to safe range
-	logOdds := math.Log(p / (1 - p))
+	logOdds := fpmath.Log(p / (1 - p))
 	return logOdds
 }
 
@@ -74,3 +76,104 @@ func (l *LogLoss) Hessian(y, pred []float64) []float64 {
 	}
 	return res
 }
+
+// GammaLoss implements the gamma deviance with a log link for positive,
+// right-skewed regression targets, such as insurance claim amounts:
+// L(y, F) = y*exp(-F) + F, up to terms in y alone. The model's raw
+// prediction F is the log of the expected target, so exp(F) is always
+// positive and errors are relative rather than absolute: a prediction off
+// by 10% costs the same on a small claim as on a large one. The Hessian is
+// y*exp(-F).
+type GammaLoss struct{}
+
+// InitialPrediction returns log(mean(y)).
+func (l *GammaLoss) InitialPrediction(y []float64) float64 {
+	return fpmath.Log(mean(y))
+}
+
+// NegativeGradient returns y*exp(-pred) - 1 for each sample.
+func (l *GammaLoss) NegativeGradient(y, pred []float64) []float64 {
+	res := make([]float64, len(y))
+	for i := range y {
+		res[i] = float64(y[i]*fpmath.Exp(-pred[i])) - 1
+	}
+	return res
+}
+
+// Hessian returns y*exp(-pred) for each sample.
+func (l *GammaLoss) Hessian(y, pred []float64) []float64 {
+	res := make([]float64, len(y))
+	for i := range y {
+		res[i] = y[i] * fpmath.Exp(-pred[i])
+	}
+	return res
+}
+
+// inverseLink maps a raw prediction under the named loss to the scale of
+// the target: a probability for "logloss", the expected value for "gamma",
+// and the prediction itself for "mse".
+func inverseLink(loss string, raw float64) float64 {
+	switch loss {
+	case "logloss":
+		return sigmoid(raw)
+	case "gamma":
+		return fpmath.Exp(raw)
+	}
+	return raw
+}
+
+// evalLoss returns the mean loss of raw predictions under the named loss:
+// mean squared error for "mse", binary cross-entropy of sigmoid(pred) for
+// "logloss", and the gamma deviance 2*(y/mu - log(y/mu) - 1) of
+// mu = exp(pred) for "gamma". It matches metrics.MSE and metrics.LogLoss,
+// including the clipping of probabilities to [1e-15, 1-1e-15], but rounds
+// identically on every platform, since early stopping compares its results.
+// With weights, one per sample, it returns the weighted mean instead.
+func evalLoss(loss string, y, pred, weights []float64) float64 {
+	const eps = 1e-15
+	losses := make([]float64, len(y))
+	for i := range y {
+		switch loss {
+		case "logloss":
+			p := max(eps, min(1-eps, sigmoid(pred[i])))
+			losses[i] = -(float64(y[i]*fpmath.Log(p)) + float64((1-y[i])*fpmath.Log(1-p)))
+		case "gamma":
+			r := y[i] * fpmath.Exp(-pred[i])
+			losses[i] = 2 * (r - fpmath.Log(r) - 1)
+		default:
+			d := y[i] - pred[i]
+			losses[i] = float64(d * d)
+		}
+	}
+	if weights == nil {
+		return mean(losses)
+	}
+	return weightedMean(losses, weights)
+}
+
+// startingPrediction returns the optimal constant prediction for y under the
+// named loss, weighting each sample by weights[i] when weights is not nil.
+func startingPrediction(lossFunc Loss, loss string, y, weights []float64) float64 {
+	if weights == nil {
+		return lossFunc.InitialPrediction(y)
+	}
+	m := weightedMean(y, weights)
+	switch loss {
+	case "gamma":
+		return fpmath.Log(m)
+	case "mse":
+		return m
+	}
+	p := max(0.001, min(0.999, m)) // clip to safe range, as LogLoss does
+	return fpmath.Log(p / (1 - p))
+}
+
+// weightedMean returns Σ weights[i]·data[i] / Σ weights[i].
+func weightedMean(data, weights []float64) float64 {
+	var num, den accumulator[float64]
+	for i, v := range data {
+		num.add(float64(weights[i] * v))
+		den.add(weights[i])
+	}
+	return num.value() / den.value()
+}
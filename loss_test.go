@@ -350,12 +350,51 @@ func TestLogLossHessian(t *testing.T) {
 	})
 }
 
+// ============ GammaLoss Tests ============
+
+func TestGammaLoss(t *testing.T) {
+	loss := &GammaLoss{}
+	y := []float64{1, 2, 4, 9}
+
+	if got := loss.InitialPrediction(y); math.Abs(got-math.Log(4)) > 1e-12 {
+		t.Errorf("InitialPrediction = %v, want log(4)", got)
+	}
+
+	// At pred = log(y), the prediction is exact: zero gradient, Hessian 1.
+	pred := []float64{0, math.Log(2), math.Log(4), math.Log(9)}
+	for i, g := range loss.NegativeGradient(y, pred) {
+		if math.Abs(g) > 1e-12 {
+			t.Errorf("NegativeGradient[%d] = %v at the exact prediction, want 0", i, g)
+		}
+	}
+	for i, h := range loss.Hessian(y, pred) {
+		if math.Abs(h-1) > 1e-12 {
+			t.Errorf("Hessian[%d] = %v at the exact prediction, want 1", i, h)
+		}
+	}
+
+	// Underpredicting pushes up, and the gradient is relative: the same
+	// ratio gives the same gradient on any scale.
+	g := loss.NegativeGradient([]float64{2, 2000}, []float64{0, math.Log(1000)})
+	if g[0] <= 0 || math.Abs(g[0]-g[1]) > 1e-9 {
+		t.Errorf("NegativeGradient = %v, want equal positive values", g)
+	}
+
+	if got := evalLoss("gamma", y, pred, nil); math.Abs(got) > 1e-12 {
+		t.Errorf("deviance of exact predictions = %v, want 0", got)
+	}
+	if got := startingPrediction(loss, "gamma", y, []float64{1, 1, 1, 1}); math.Abs(got-math.Log(4)) > 1e-12 {
+		t.Errorf("weighted starting prediction = %v, want log(4)", got)
+	}
+}
+
 // ============ Integration Tests ============
 
 func TestLossInterfaceCompliance(t *testing.T) {
 	// Ensure both loss types implement the Loss interface
 	var _ Loss = &MSELoss{}
 	var _ Loss = &LogLoss{}
+	var _ Loss = &GammaLoss{}
 }
 
 func TestCreateLossFunction(t *testing.T) {
@@ -374,6 +413,11 @@ func TestCreateLossFunction(t *testing.T) {
 			lossName: "logloss",
 			wantType: "*gboost.LogLoss",
 		},
+		{
+			name:     "gamma",
+			lossName: "gamma",
+			wantType: "*gboost.GammaLoss",
+		},
 	}
 
 	for _, tt := range tests {
@@ -2,6 +2,7 @@ package gboost
 
 import (
 	"math"
+	"math/big"
 	"testing"
 )
 
@@ -80,3 +81,70 @@ func TestSigmoidSymmetry(t *testing.T) {
 		}
 	}
 }
+
+func TestSumCompensated(t *testing.T) {
+	// Naive left-to-right summation loses the small terms entirely.
+	if got := sum([]float64{1, 1e100, 1, -1e100}); got != 2 {
+		t.Errorf("sum = %v, want 2", got)
+	}
+	tenths := make([]float64, 1000)
+	for i := range tenths {
+		tenths[i] = 0.1
+	}
+	if got := sum(tenths); got != 100 {
+		t.Errorf("sum of 1000 x 0.1 = %v, want 100", got)
+	}
+	if got := sum([]float64{1, math.Inf(1), 2}); !math.IsInf(got, 1) {
+		t.Errorf("sum with +Inf = %v, want +Inf", got)
+	}
+	if got := sum([]int{3, -1, 4}); got != 6 {
+		t.Errorf("integer sum = %v, want 6", got)
+	}
+}
+
+func TestVarianceMillionRowNode(t *testing.T) {
+	// Gradients with a large common offset, as at a node deep in a
+	// regression on unscaled targets.
+	data := make([]float64, 1_000_000)
+	for i := range data {
+		data[i] = 1e7 + float64(i%1000)*1e-3 + float64(i%7)*0.1
+	}
+
+	// Reference variance in 256-bit arithmetic.
+	prec := uint(256)
+	total := new(big.Float).SetPrec(prec)
+	for _, v := range data {
+		total.Add(total, big.NewFloat(v))
+	}
+	n := new(big.Float).SetPrec(prec).SetInt64(int64(len(data)))
+	m := new(big.Float).SetPrec(prec).Quo(total, n)
+	squares := new(big.Float).SetPrec(prec)
+	for _, v := range data {
+		d := new(big.Float).SetPrec(prec).Sub(big.NewFloat(v), m)
+		squares.Add(squares, d.Mul(d, d))
+	}
+	want, _ := squares.Quo(squares, n).Float64()
+
+	if got := variance(data); math.Abs(got-want) > 1e-14*want {
+		t.Errorf("variance = %.17g, want %.17g (relative error %.2g)", got, want, math.Abs(got-want)/want)
+	}
+}
+
+func TestStudentTQuantile(t *testing.T) {
+	// Two-sided 95% and 99% critical values from standard tables.
+	for _, tt := range []struct {
+		p, nu, want float64
+	}{
+		{0.975, 1, 12.7062},
+		{0.975, 4, 2.7764},
+		{0.975, 9, 2.2622},
+		{0.975, 29, 2.0452},
+		{0.995, 9, 3.2498},
+		{0.975, 1e6, 1.9600},
+		{0.025, 9, -2.2622},
+	} {
+		if got := studentTQuantile(tt.p, tt.nu); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("studentTQuantile(%v, %v) = %v, want %v", tt.p, tt.nu, got, tt.want)
+		}
+	}
+}
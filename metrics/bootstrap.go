@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// Interval is a metric's point estimate with a confidence interval.
+type Interval struct {
+	Estimate float64 `json:"estimate"` // The metric on all samples.
+	Low      float64 `json:"low"`
+	High     float64 `json:"high"`
+	Level    float64 `json:"level"` // Confidence level, such as 0.95.
+}
+
+// BootstrapOptions controls [BootstrapCI]. Zero values select the defaults.
+type BootstrapOptions struct {
+	// Resamples is the number of bootstrap resamples. Zero means 1000.
+	Resamples int
+
+	// Level is the confidence level of the interval. Zero means 0.95.
+	Level float64
+
+	// Seed makes the resampling deterministic.
+	Seed int64
+}
+
+// BootstrapCI estimates the uncertainty of metric on a held-out set, so that
+// a reported accuracy or AUC comes with bounds rather than a single number.
+// It recomputes metric on opts.Resamples resamples of the (y, pred) pairs,
+// drawn with replacement, and returns the percentile interval of the
+// results around the metric on all samples. Resamples on which metric is
+// NaN, such as the [TPR] of a resample without positives, are skipped; if
+// all are, the bounds are NaN.
+//
+// The interval reflects sampling noise in the evaluation set only, not the
+// variability of training; see gboost.RepeatedCV for that. It panics if y
+// and pred differ in length, or if opts.Resamples or opts.Level is invalid.
+func BootstrapCI(y, pred []float64, metric func(y, pred []float64) float64, opts BootstrapOptions) Interval {
+	checkLengths(y, pred)
+	resamples, level := opts.Resamples, opts.Level
+	if resamples == 0 {
+		resamples = 1000
+	}
+	if level == 0 {
+		level = 0.95
+	}
+	if resamples < 0 || !(level > 0 && level < 1) {
+		panic("metrics: invalid bootstrap options")
+	}
+
+	rnd := rand.New(rand.NewSource(opts.Seed))
+	yb := make([]float64, len(y))
+	pb := make([]float64, len(y))
+	scores := make([]float64, 0, resamples)
+	for r := 0; r < resamples && len(y) > 0; r++ {
+		for i := range yb {
+			j := rnd.Intn(len(y))
+			yb[i], pb[i] = y[j], pred[j]
+		}
+		if s := metric(yb, pb); !math.IsNaN(s) {
+			scores = append(scores, s)
+		}
+	}
+
+	res := Interval{Estimate: metric(y, pred), Low: math.NaN(), High: math.NaN(), Level: level}
+	if len(scores) > 0 {
+		slices.Sort(scores)
+		res.Low = quantile(scores, (1-level)/2)
+		res.High = quantile(scores, (1+level)/2)
+	}
+	return res
+}
+
+// quantile returns the q-quantile of sorted, interpolating linearly between
+// order statistics.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := min(lo+1, len(sorted)-1)
+	return sorted[lo] + (pos-float64(lo))*(sorted[hi]-sorted[lo])
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBootstrapCI(t *testing.T) {
+	// 1000 predictions of which 800 are right: the normal approximation
+	// gives 0.8 ± 1.96·sqrt(0.8·0.2/1000) ≈ 0.8 ± 0.025.
+	rnd := rand.New(rand.NewSource(1))
+	y := make([]float64, 1000)
+	proba := make([]float64, 1000)
+	for i := range y {
+		y[i] = float64(rnd.Intn(2))
+		proba[i] = y[i]
+		if i%5 == 0 {
+			proba[i] = 1 - y[i]
+		}
+	}
+
+	ci := BootstrapCI(y, proba, Accuracy, BootstrapOptions{Seed: 7})
+	if ci.Estimate != 0.8 || ci.Level != 0.95 {
+		t.Fatalf("estimate %v at level %v, want 0.8 at 0.95", ci.Estimate, ci.Level)
+	}
+	if !(ci.Low < 0.8 && 0.8 < ci.High) {
+		t.Errorf("interval [%v, %v] misses the estimate", ci.Low, ci.High)
+	}
+	if w := ci.High - ci.Low; w < 0.04 || w > 0.06 {
+		t.Errorf("interval width = %v, want about 0.05", w)
+	}
+
+	if again := BootstrapCI(y, proba, Accuracy, BootstrapOptions{Seed: 7}); again != ci {
+		t.Errorf("same seed gave %+v, then %+v", ci, again)
+	}
+	narrow := BootstrapCI(y, proba, Accuracy, BootstrapOptions{Seed: 7, Level: 0.5})
+	if !(narrow.Low > ci.Low && narrow.High < ci.High) {
+		t.Errorf("50%% interval [%v, %v] not inside 95%% interval [%v, %v]", narrow.Low, narrow.High, ci.Low, ci.High)
+	}
+}
+
+func TestBootstrapCISkipsUndefined(t *testing.T) {
+	// With one positive in 20 rows, many resamples draw no positive and
+	// have an undefined TPR; the others detect it.
+	y := make([]float64, 20)
+	proba := make([]float64, 20)
+	y[19], proba[19] = 1, 0.9
+
+	ci := BootstrapCI(y, proba, TPR, BootstrapOptions{Resamples: 200})
+	if ci.Estimate != 1 || ci.Low != 1 || ci.High != 1 {
+		t.Errorf("TPR interval = %+v, want 1 throughout", ci)
+	}
+
+	empty := BootstrapCI(nil, nil, MSE, BootstrapOptions{})
+	if !math.IsNaN(empty.Low) || !math.IsNaN(empty.High) {
+		t.Errorf("empty input: %+v, want NaN bounds", empty)
+	}
+}
+
+func TestBootstrapCIPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"length mismatch": func() { BootstrapCI([]float64{1}, nil, MSE, BootstrapOptions{}) },
+		"level":           func() { BootstrapCI([]float64{1}, []float64{1}, MSE, BootstrapOptions{Level: 1}) },
+		"resamples":       func() { BootstrapCI([]float64{1}, []float64{1}, MSE, BootstrapOptions{Resamples: -1}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
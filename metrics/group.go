@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"math"
+	"slices"
+)
+
+// NamedMetric is a metric reported by [GroupReport] under Name.
+type NamedMetric struct {
+	Name   string
+	Metric func(y, pred []float64) float64
+}
+
+// DefaultGroupMetrics are the classification metrics [GroupReport] computes
+// when none are given: the rates behind demographic parity (selection rate)
+// and equalized odds (TPR and FPR), and accuracy.
+var DefaultGroupMetrics = []NamedMetric{
+	{"selection_rate", SelectionRate},
+	{"tpr", TPR},
+	{"fpr", FPR},
+	{"accuracy", Accuracy},
+}
+
+// GroupMetrics holds the metrics of one subgroup.
+type GroupMetrics struct {
+	Group   string             `json:"group"`
+	Count   int                `json:"count"`
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// Disparity compares one metric across subgroups. Groups whose metric is
+// NaN, such as the TPR of a group without positives, are left out; if
+// fewer than two groups remain, Gap and Ratio are NaN.
+type Disparity struct {
+	Metric   string  `json:"metric"`
+	MinGroup string  `json:"min_group"`
+	Min      float64 `json:"min"`
+	MaxGroup string  `json:"max_group"`
+	Max      float64 `json:"max"`
+
+	// Gap is Max - Min, such as the TPR gap of equal opportunity.
+	Gap float64 `json:"gap"`
+
+	// Ratio is Min / Max, such as the disparate impact ratio of selection
+	// rates, which the four-fifths rule expects to be at least 0.8. It is
+	// NaN when Max is 0.
+	Ratio float64 `json:"ratio"`
+}
+
+// GroupEvaluation is the result of [GroupReport].
+type GroupEvaluation struct {
+	Groups      []GroupMetrics `json:"groups"` // Sorted by group name.
+	Disparities []Disparity    `json:"disparities"`
+}
+
+// GroupReport evaluates pred against y separately for each subgroup, where
+// groups[i] names the subgroup of sample i, such as the value of a protected
+// attribute, and compares each metric across the subgroups. It computes
+// [DefaultGroupMetrics] unless metrics are given; pass regression metrics
+// such as [MAE] to audit a regression model.
+//
+// A gap in a metric between groups is evidence to investigate, not proof of
+// unfairness: base rates, sample sizes, and the choice of metric all matter,
+// and common fairness criteria cannot in general all hold at once.
+func GroupReport(y, pred []float64, groups []string, metrics ...NamedMetric) GroupEvaluation {
+	checkLengths(y, pred)
+	if len(groups) != len(y) {
+		panic("metrics: mismatched slice lengths")
+	}
+	if len(metrics) == 0 {
+		metrics = DefaultGroupMetrics
+	}
+
+	rows := make(map[string][]int)
+	for i, g := range groups {
+		rows[g] = append(rows[g], i)
+	}
+	names := make([]string, 0, len(rows))
+	for g := range rows {
+		names = append(names, g)
+	}
+	slices.Sort(names)
+
+	var eval GroupEvaluation
+	for _, g := range names {
+		gy := make([]float64, len(rows[g]))
+		gp := make([]float64, len(rows[g]))
+		for j, i := range rows[g] {
+			gy[j], gp[j] = y[i], pred[i]
+		}
+		gm := GroupMetrics{Group: g, Count: len(gy), Metrics: make(map[string]float64, len(metrics))}
+		for _, m := range metrics {
+			gm.Metrics[m.Name] = m.Metric(gy, gp)
+		}
+		eval.Groups = append(eval.Groups, gm)
+	}
+
+	for _, m := range metrics {
+		d := Disparity{Metric: m.Name, Min: math.NaN(), Max: math.NaN()}
+		n := 0
+		for _, gm := range eval.Groups {
+			v := gm.Metrics[m.Name]
+			if math.IsNaN(v) {
+				continue
+			}
+			n++
+			if n == 1 || v < d.Min {
+				d.Min, d.MinGroup = v, gm.Group
+			}
+			if n == 1 || v > d.Max {
+				d.Max, d.MaxGroup = v, gm.Group
+			}
+		}
+		d.Gap, d.Ratio = math.NaN(), math.NaN()
+		if n >= 2 {
+			d.Gap = d.Max - d.Min
+			if d.Max != 0 {
+				d.Ratio = d.Min / d.Max
+			}
+		}
+		eval.Disparities = append(eval.Disparities, d)
+	}
+	return eval
+}
+
+// SelectionRate returns the fraction of samples whose probability,
+// thresholded at 0.5, predicts 1. y is ignored; it is accepted so that
+// SelectionRate has the signature of the other metrics.
+func SelectionRate(y, proba []float64) float64 {
+	checkLengths(y, proba)
+	if len(proba) == 0 {
+		return 0
+	}
+	selected := 0
+	for _, p := range proba {
+		if p >= 0.5 {
+			selected++
+		}
+	}
+	return float64(selected) / float64(len(proba))
+}
+
+// TPR returns the true positive rate, or recall: the fraction of samples
+// labelled 1 whose probability, thresholded at 0.5, predicts 1. Returns NaN
+// when y has no positives.
+func TPR(y, proba []float64) float64 {
+	return rateAmong(y, proba, 1)
+}
+
+// FPR returns the false positive rate: the fraction of samples labelled 0
+// whose probability, thresholded at 0.5, predicts 1. Returns NaN when y has
+// no negatives.
+func FPR(y, proba []float64) float64 {
+	return rateAmong(y, proba, 0)
+}
+
+// rateAmong returns the fraction of the samples labelled label that are
+// predicted 1.
+func rateAmong(y, proba []float64, label float64) float64 {
+	checkLengths(y, proba)
+	n, selected := 0, 0
+	for i := range y {
+		if y[i] != label {
+			continue
+		}
+		n++
+		if proba[i] >= 0.5 {
+			selected++
+		}
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return float64(selected) / float64(n)
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGroupReport(t *testing.T) {
+	y := []float64{1, 1, 0, 0, 1, 1, 0, 0}
+	proba := []float64{0.9, 0.8, 0.6, 0.1, 0.9, 0.2, 0.1, 0.3}
+	groups := []string{"a", "a", "a", "a", "b", "b", "b", "b"}
+
+	eval := GroupReport(y, proba, groups)
+	if len(eval.Groups) != 2 || eval.Groups[0].Group != "a" || eval.Groups[1].Count != 4 {
+		t.Fatalf("groups = %+v, want a and b with 4 samples each", eval.Groups)
+	}
+	// a: 3 of 4 selected, both positives and one of two negatives.
+	// b: 1 of 4 selected, one of two positives and no negatives.
+	want := map[string][2]float64{
+		"selection_rate": {0.75, 0.25},
+		"tpr":            {1, 0.5},
+		"fpr":            {0.5, 0},
+		"accuracy":       {0.75, 0.75},
+	}
+	for name, w := range want {
+		for g := range 2 {
+			if got := eval.Groups[g].Metrics[name]; !almostEqual(got, w[g]) {
+				t.Errorf("%s of group %s = %v, want %v", name, eval.Groups[g].Group, got, w[g])
+			}
+		}
+	}
+
+	tpr := eval.Disparities[1]
+	if tpr.Metric != "tpr" || tpr.MinGroup != "b" || tpr.MaxGroup != "a" || !almostEqual(tpr.Gap, 0.5) || !almostEqual(tpr.Ratio, 0.5) {
+		t.Errorf("TPR disparity = %+v, want a gap of 0.5 from b to a", tpr)
+	}
+	selection := eval.Disparities[0]
+	if !almostEqual(selection.Ratio, 1.0/3) {
+		t.Errorf("selection rate ratio = %v, want 1/3", selection.Ratio)
+	}
+	if fpr := eval.Disparities[2]; fpr.Ratio != 0 || !almostEqual(fpr.Gap, 0.5) {
+		t.Errorf("FPR disparity = %+v, want gap 0.5 and ratio 0", fpr)
+	}
+}
+
+func TestGroupReportCustomMetrics(t *testing.T) {
+	y := []float64{1, 2, 3, 0, 0}
+	pred := []float64{1, 2, 5, 1, 0}
+	groups := []string{"x", "x", "x", "y", "z"}
+
+	eval := GroupReport(y, pred, groups, NamedMetric{"mae", MAE}, NamedMetric{"tpr", TPR})
+	if got := eval.Groups[0].Metrics["mae"]; !almostEqual(got, 2.0/3) {
+		t.Errorf("MAE of x = %v, want 2/3", got)
+	}
+	mae := eval.Disparities[0]
+	if mae.MinGroup != "z" || mae.MaxGroup != "y" || !almostEqual(mae.Gap, 1) || !almostEqual(mae.Ratio, 0) {
+		t.Errorf("MAE disparity = %+v, want a gap of 1 from z to y", mae)
+	}
+	// Only x has positives, so TPR cannot be compared.
+	if tpr := eval.Disparities[1]; tpr.MinGroup != "x" || !math.IsNaN(tpr.Gap) {
+		t.Errorf("TPR disparity = %+v, want a single group and NaN gap", tpr)
+	}
+}
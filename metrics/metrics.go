@@ -0,0 +1,248 @@
+// Package metrics provides evaluation metrics for gboost models.
+//
+// Every metric takes the true targets y and the model output pred, in that
+// order, and panics if their lengths differ. Regression metrics expect raw
+// predictions; classification metrics expect P(y=1) probabilities, as returned
+// by gboost's PredictProbaAll. Ranking metrics take graded relevance labels
+// and arbitrary scores, where higher scores rank first. [GroupReport] breaks
+// any of them down by subgroup, and [BootstrapCI] puts a confidence interval
+// around any of them.
+package metrics
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// MSE returns the mean squared error between y and pred.
+func MSE(y, pred []float64) float64 {
+	checkLengths(y, pred)
+	if len(y) == 0 {
+		return 0
+	}
+	var s float64
+	for i := range y {
+		d := y[i] - pred[i]
+		s += d * d
+	}
+	return s / float64(len(y))
+}
+
+// RMSE returns the root mean squared error between y and pred.
+func RMSE(y, pred []float64) float64 {
+	return math.Sqrt(MSE(y, pred))
+}
+
+// MAE returns the mean absolute error between y and pred.
+func MAE(y, pred []float64) float64 {
+	checkLengths(y, pred)
+	if len(y) == 0 {
+		return 0
+	}
+	var s float64
+	for i := range y {
+		s += math.Abs(y[i] - pred[i])
+	}
+	return s / float64(len(y))
+}
+
+// Accuracy returns the fraction of samples whose probability, thresholded at
+// 0.5, matches the 0/1 label.
+func Accuracy(y, proba []float64) float64 {
+	checkLengths(y, proba)
+	if len(y) == 0 {
+		return 0
+	}
+	correct := 0
+	for i := range y {
+		label := 0.0
+		if proba[i] >= 0.5 {
+			label = 1.0
+		}
+		if label == y[i] {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(y))
+}
+
+// LogLoss returns the mean binary cross-entropy of the probabilities against
+// the 0/1 labels. Probabilities are clipped to [1e-15, 1-1e-15] to keep the
+// result finite.
+func LogLoss(y, proba []float64) float64 {
+	checkLengths(y, proba)
+	if len(y) == 0 {
+		return 0
+	}
+	const eps = 1e-15
+	var s float64
+	for i := range y {
+		p := max(eps, min(1-eps, proba[i]))
+		s -= y[i]*math.Log(p) + (1-y[i])*math.Log(1-p)
+	}
+	return s / float64(len(y))
+}
+
+// AUC returns the area under the ROC curve for the scores against the 0/1
+// labels, i.e. the probability that a random positive is scored above a random
+// negative. Tied scores count as half. Returns 0.5 when y contains only one class.
+func AUC(y, scores []float64) float64 {
+	checkLengths(y, scores)
+
+	order := make([]int, len(y))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		switch {
+		case scores[a] < scores[b]:
+			return -1
+		case scores[a] > scores[b]:
+			return 1
+		}
+		return 0
+	})
+
+	// Mann-Whitney U: sum the (average) ranks of the positives.
+	var rankSum float64
+	nPos := 0
+	for i := 0; i < len(order); {
+		j := i
+		for j < len(order) && scores[order[j]] == scores[order[i]] {
+			j++
+		}
+		avgRank := float64(i+j+1) / 2 // ranks are 1-based
+		for k := i; k < j; k++ {
+			if y[order[k]] == 1 {
+				rankSum += avgRank
+				nPos++
+			}
+		}
+		i = j
+	}
+
+	nNeg := len(y) - nPos
+	if nPos == 0 || nNeg == 0 {
+		return 0.5
+	}
+	u := rankSum - float64(nPos*(nPos+1))/2
+	return u / float64(nPos*nNeg)
+}
+
+// NDCG returns the normalized discounted cumulative gain at k of one query's
+// results: the DCG of the top k results ordered by score, with gain
+// 2^rel - 1 and discount 1/log2(rank+1), divided by the DCG of the ideal
+// ordering. Tied scores keep their input order. k <= 0 uses every result.
+// Returns 0 when no result has positive relevance.
+func NDCG(y, scores []float64, k int) float64 {
+	checkLengths(y, scores)
+	if k <= 0 || k > len(y) {
+		k = len(y)
+	}
+	ideal := slices.Clone(y)
+	slices.SortFunc(ideal, func(a, b float64) int { return cmp.Compare(b, a) })
+	idcg := dcg(ideal[:k])
+	if idcg == 0 {
+		return 0
+	}
+	ranked := make([]float64, len(y))
+	for r, i := range rankByScore(scores) {
+		ranked[r] = y[i]
+	}
+	return dcg(ranked[:k]) / idcg
+}
+
+// MeanNDCG returns the mean [NDCG] at k over the queries identified by
+// groups, where groups[i] is the query ID of sample i. Queries with no
+// relevant results are skipped; returns 0 if every query is skipped.
+func MeanNDCG(y, scores []float64, groups []int, k int) float64 {
+	return meanOverQueries(y, scores, groups, func(y, scores []float64) float64 {
+		return NDCG(y, scores, k)
+	})
+}
+
+// MAP returns the mean average precision over the queries identified by
+// groups, where groups[i] is the query ID of sample i and results with
+// y > 0 count as relevant. A query's average precision is the mean of the
+// precision at the rank of each relevant result, ordered by score with ties
+// in input order. Queries with no relevant results are skipped; returns 0 if
+// every query is skipped.
+func MAP(y, scores []float64, groups []int) float64 {
+	return meanOverQueries(y, scores, groups, averagePrecision)
+}
+
+func averagePrecision(y, scores []float64) float64 {
+	var sum float64
+	hits := 0
+	for r, i := range rankByScore(scores) {
+		if y[i] > 0 {
+			hits++
+			sum += float64(hits) / float64(r+1)
+		}
+	}
+	return sum / float64(hits)
+}
+
+// meanOverQueries averages metric over the queries in groups that have at
+// least one result with y > 0.
+func meanOverQueries(y, scores []float64, groups []int, metric func(y, scores []float64) float64) float64 {
+	checkLengths(y, scores)
+	if len(groups) != len(y) {
+		panic("metrics: mismatched slice lengths")
+	}
+
+	rows := make(map[int][]int)
+	var order []int
+	for i, g := range groups {
+		if _, ok := rows[g]; !ok {
+			order = append(order, g)
+		}
+		rows[g] = append(rows[g], i)
+	}
+
+	var total float64
+	n := 0
+	for _, g := range order {
+		qy := make([]float64, len(rows[g]))
+		qs := make([]float64, len(rows[g]))
+		relevant := false
+		for j, i := range rows[g] {
+			qy[j], qs[j] = y[i], scores[i]
+			relevant = relevant || y[i] > 0
+		}
+		if relevant {
+			total += metric(qy, qs)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return total / float64(n)
+}
+
+// rankByScore returns the indices of scores from highest to lowest, keeping
+// ties in input order.
+func rankByScore(scores []float64) []int {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(scores[b], scores[a]) })
+	return order
+}
+
+func dcg(rels []float64) float64 {
+	var s float64
+	for r, rel := range rels {
+		s += (math.Pow(2, rel) - 1) / math.Log2(float64(r+2))
+	}
+	return s
+}
+
+func checkLengths(y, pred []float64) {
+	if len(y) != len(pred) {
+		panic("metrics: mismatched slice lengths")
+	}
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRegressionMetrics(t *testing.T) {
+	y := []float64{1, 2, 3, 4}
+	pred := []float64{1, 3, 1, 4}
+
+	if got := MSE(y, pred); !almostEqual(got, 1.25) {
+		t.Errorf("MSE = %v, want 1.25", got)
+	}
+	if got := RMSE(y, pred); !almostEqual(got, math.Sqrt(1.25)) {
+		t.Errorf("RMSE = %v, want %v", got, math.Sqrt(1.25))
+	}
+	if got := MAE(y, pred); !almostEqual(got, 0.75) {
+		t.Errorf("MAE = %v, want 0.75", got)
+	}
+}
+
+func TestAccuracy(t *testing.T) {
+	y := []float64{0, 1, 1, 0}
+	proba := []float64{0.2, 0.7, 0.4, 0.5}
+
+	// 0.5 is thresholded to class 1, so the last sample is wrong.
+	if got := Accuracy(y, proba); !almostEqual(got, 0.5) {
+		t.Errorf("Accuracy = %v, want 0.5", got)
+	}
+}
+
+func TestLogLoss(t *testing.T) {
+	y := []float64{1, 0}
+	proba := []float64{0.8, 0.4}
+
+	want := -(math.Log(0.8) + math.Log(0.6)) / 2
+	if got := LogLoss(y, proba); !almostEqual(got, want) {
+		t.Errorf("LogLoss = %v, want %v", got, want)
+	}
+
+	// Saturated probabilities stay finite.
+	if got := LogLoss([]float64{1}, []float64{0}); math.IsInf(got, 0) || math.IsNaN(got) {
+		t.Errorf("LogLoss with p=0 = %v, want finite", got)
+	}
+}
+
+func TestAUC(t *testing.T) {
+	tests := []struct {
+		name   string
+		y      []float64
+		scores []float64
+		want   float64
+	}{
+		{"perfect", []float64{0, 0, 1, 1}, []float64{0.1, 0.2, 0.8, 0.9}, 1.0},
+		{"inverted", []float64{1, 1, 0, 0}, []float64{0.1, 0.2, 0.8, 0.9}, 0.0},
+		{"all tied", []float64{0, 1, 0, 1}, []float64{0.5, 0.5, 0.5, 0.5}, 0.5},
+		{"one of four pairs wrong", []float64{0, 1, 0, 1}, []float64{0.1, 0.3, 0.4, 0.9}, 0.75},
+		{"single class", []float64{1, 1}, []float64{0.1, 0.9}, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AUC(tt.y, tt.scores); !almostEqual(got, tt.want) {
+				t.Errorf("AUC = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNDCG(t *testing.T) {
+	y := []float64{3, 2, 3, 0, 1, 2}
+	scores := []float64{6, 5, 4, 3, 2, 1}
+
+	// DCG@6 = 7 + 3/log2(3) + 7/2 + 0 + 1/log2(6) + 3/log2(7);
+	// ideal order 3, 3, 2, 2, 1, 0.
+	dcg := 7 + 3/math.Log2(3) + 3.5 + 1/math.Log2(6) + 3/math.Log2(7)
+	idcg := 7 + 7/math.Log2(3) + 1.5 + 3/math.Log2(5) + 1/math.Log2(6)
+	if got := NDCG(y, scores, 0); !almostEqual(got, dcg/idcg) {
+		t.Errorf("NDCG@all = %v, want %v", got, dcg/idcg)
+	}
+	if got := NDCG(y, scores, 2); !almostEqual(got, (7+3/math.Log2(3))/(7+7/math.Log2(3))) {
+		t.Errorf("NDCG@2 = %v", got)
+	}
+
+	// A perfect ranking scores 1 at every k.
+	for k := 1; k <= 6; k++ {
+		if got := NDCG(y, y, k); !almostEqual(got, 1) {
+			t.Errorf("perfect NDCG@%d = %v, want 1", k, got)
+		}
+	}
+	if got := NDCG([]float64{0, 0}, []float64{1, 2}, 0); got != 0 {
+		t.Errorf("NDCG with no relevant results = %v, want 0", got)
+	}
+}
+
+func TestMeanNDCG(t *testing.T) {
+	y := []float64{1, 0, 0, 1, 0, 0}
+	scores := []float64{2, 1, 2, 1, 0, 0}
+	groups := []int{7, 7, 3, 3, 9, 9}
+	// Query 7 is perfect; query 3 ranks its relevant result second;
+	// query 9 has no relevant results and is skipped.
+	want := (1 + 1/math.Log2(3)) / 2
+	if got := MeanNDCG(y, scores, groups, 0); !almostEqual(got, want) {
+		t.Errorf("MeanNDCG = %v, want %v", got, want)
+	}
+}
+
+func TestMAP(t *testing.T) {
+	// Query 0 ranks relevant results 1st and 3rd: AP = (1/1 + 2/3) / 2.
+	// Query 1 ranks its relevant result 2nd: AP = 1/2.
+	y := []float64{1, 0, 1, 0, 1, 0}
+	scores := []float64{0.9, 0.8, 0.7, 0.9, 0.5, 0.1}
+	groups := []int{0, 0, 0, 1, 1, 1}
+	want := ((1+2.0/3)/2 + 0.5) / 2
+	if got := MAP(y, scores, groups); !almostEqual(got, want) {
+		t.Errorf("MAP = %v, want %v", got, want)
+	}
+
+	// Ties keep input order.
+	if got := MAP([]float64{0, 1}, []float64{1, 1}, []int{0, 0}); !almostEqual(got, 0.5) {
+		t.Errorf("MAP with tied scores = %v, want 0.5", got)
+	}
+	if got := MAP([]float64{0, 0}, []float64{1, 2}, []int{0, 0}); got != 0 {
+		t.Errorf("MAP with no relevant results = %v, want 0", got)
+	}
+}
+
+func TestEmptyInputs(t *testing.T) {
+	for name, f := range map[string]func(y, pred []float64) float64{
+		"MSE": MSE, "MAE": MAE, "Accuracy": Accuracy, "LogLoss": LogLoss,
+	} {
+		if got := f(nil, nil); got != 0 {
+			t.Errorf("%s(nil, nil) = %v, want 0", name, got)
+		}
+	}
+}
+
+func TestMismatchedLengthsPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on mismatched lengths")
+		}
+	}()
+	MSE([]float64{1}, []float64{1, 2})
+}
+
+func TestMismatchedGroupsPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on mismatched groups")
+		}
+	}()
+	MAP([]float64{1, 0}, []float64{1, 2}, []int{0})
+}
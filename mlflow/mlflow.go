@@ -0,0 +1,251 @@
+// Package mlflow records gboost training runs on an MLflow tracking server.
+//
+// A [Tracker] implements [gboost.Tracker] over MLflow's REST API, so that
+// runs trained with it as Config.Tracker appear in the MLflow UI:
+//
+//	cfg := gboost.DefaultConfig()
+//	cfg.Tracker = &mlflow.Tracker{URL: "http://localhost:5000", ExperimentID: "0"}
+//	err := gboost.New(cfg).Fit(X, y)
+//
+// Each Fit creates a run holding the Config as parameters, the per-round
+// losses and tree statistics as metrics stepped by round, and the trained
+// model as the artifact model.json. Other servers that implement the same
+// endpoints work too.
+package mlflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+// Errors returned by [Tracker] methods.
+var (
+	ErrNoRun                    = errors.New("mlflow: no active run")
+	ErrUnsupportedArtifactStore = errors.New("mlflow: artifact store is not served by the tracking server")
+)
+
+// maxBatch is the most parameters sent in one log-batch request, MLflow's
+// limit.
+const maxBatch = 100
+
+var _ gboost.Tracker = (*Tracker)(nil)
+
+// Tracker is a [gboost.Tracker] that logs to an MLflow tracking server. Set
+// its fields before the first Fit. A Tracker records one run at a time, so
+// do not share it between concurrent fits.
+type Tracker struct {
+	// URL is the base URL of the tracking server, such as
+	// "http://localhost:5000".
+	URL string
+
+	// ExperimentID is the experiment runs are created in. MLflow's
+	// default experiment is "0".
+	ExperimentID string
+
+	// RunName names the runs; empty lets the server choose.
+	RunName string
+
+	// Tags are set on every run.
+	Tags map[string]string
+
+	// Client sends the requests; nil means http.DefaultClient.
+	Client *http.Client
+
+	mu          sync.Mutex
+	runID       string
+	artifactURI string
+}
+
+// RunID returns the ID of the current or most recent run, or "" before the
+// first Fit.
+func (t *Tracker) RunID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.runID
+}
+
+type param struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type metric struct {
+	Key       string  `json:"key"`
+	Value     float64 `json:"value"`
+	Timestamp int64   `json:"timestamp"`
+	Step      int     `json:"step"`
+}
+
+// LogParams starts a new run and logs params to it.
+func (t *Tracker) LogParams(params map[string]string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	create := map[string]any{
+		"experiment_id": t.ExperimentID,
+		"start_time":    time.Now().UnixMilli(),
+		"tags":          sortedParams(t.Tags),
+	}
+	if t.RunName != "" {
+		create["run_name"] = t.RunName
+	}
+	var resp struct {
+		Run struct {
+			Info struct {
+				RunID       string `json:"run_id"`
+				ArtifactURI string `json:"artifact_uri"`
+			} `json:"info"`
+		} `json:"run"`
+	}
+	t.runID, t.artifactURI = "", ""
+	if err := t.post("runs/create", create, &resp); err != nil {
+		return err
+	}
+	t.runID, t.artifactURI = resp.Run.Info.RunID, resp.Run.Info.ArtifactURI
+
+	all := sortedParams(params)
+	for start := 0; start < len(all); start += maxBatch {
+		batch := all[start:min(start+maxBatch, len(all))]
+		if err := t.post("runs/log-batch", map[string]any{"run_id": t.runID, "params": batch}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogMetrics logs metrics at step to the current run. Non-finite values,
+// which JSON cannot carry, are skipped.
+func (t *Tracker) LogMetrics(step int, metrics map[string]float64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.runID == "" {
+		return ErrNoRun
+	}
+	now := time.Now().UnixMilli()
+	batch := make([]metric, 0, len(metrics))
+	for _, key := range sortedKeys(metrics) {
+		v := metrics[key]
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		batch = append(batch, metric{Key: key, Value: v, Timestamp: now, Step: step})
+	}
+	return t.post("runs/log-batch", map[string]any{"run_id": t.runID, "metrics": batch}, nil)
+}
+
+// LogArtifact uploads data as the file name in the current run's
+// artifacts. It needs a server that proxies artifact storage, the default
+// since MLflow 2.0, and returns [ErrUnsupportedArtifactStore] for runs
+// whose artifacts live elsewhere, such as in a bucket the client would
+// have to write to itself.
+func (t *Tracker) LogArtifact(name string, data []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.runID == "" {
+		return ErrNoRun
+	}
+	path, ok := strings.CutPrefix(t.artifactURI, "mlflow-artifacts:")
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedArtifactStore, t.artifactURI)
+	}
+	endpoint := strings.TrimSuffix(t.URL, "/") + "/api/2.0/mlflow-artifacts/artifacts/" +
+		strings.Trim(path, "/") + "/" + url.PathEscape(name)
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	return t.do(req, nil)
+}
+
+// End marks the current run FINISHED, or FAILED if err is not nil.
+func (t *Tracker) End(err error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.runID == "" {
+		return ErrNoRun
+	}
+	status := "FINISHED"
+	if err != nil {
+		status = "FAILED"
+	}
+	return t.post("runs/update", map[string]any{
+		"run_id":   t.runID,
+		"status":   status,
+		"end_time": time.Now().UnixMilli(),
+	}, nil)
+}
+
+// post sends body as JSON to the tracking API endpoint and decodes the
+// response into out unless it is nil.
+func (t *Tracker) post(endpoint string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(t.URL, "/")+"/api/2.0/mlflow/"+endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return t.do(req, out)
+}
+
+// do sends req and decodes a successful JSON response into out unless it
+// is nil. Error responses are returned with MLflow's error message.
+func (t *Tracker) do(req *http.Request, out any) error {
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			ErrorCode string `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("mlflow: %s %s: %s: %s", req.Method, req.URL.Path, apiErr.ErrorCode, apiErr.Message)
+		}
+		return fmt.Errorf("mlflow: %s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// sortedParams returns m as key-value pairs in key order.
+func sortedParams(m map[string]string) []param {
+	params := make([]param, 0, len(m))
+	for _, key := range sortedKeys(m) {
+		params = append(params, param{Key: key, Value: m[key]})
+	}
+	return params
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
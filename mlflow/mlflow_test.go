@@ -0,0 +1,159 @@
+package mlflow_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/mlflow"
+)
+
+// fakeServer is an in-memory stand-in for the MLflow tracking API.
+type fakeServer struct {
+	mu          sync.Mutex
+	artifactURI string
+	created     map[string]any
+	params      map[string]string
+	metrics     map[string][]float64 // values by key, in step order
+	artifacts   map[string][]byte
+	status      string
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Method == http.MethodPut {
+		path := strings.TrimPrefix(r.URL.Path, "/api/2.0/mlflow-artifacts/artifacts/")
+		f.artifacts[path], _ = io.ReadAll(r.Body)
+		w.Write([]byte("{}"))
+		return
+	}
+	var body map[string]any
+	json.NewDecoder(r.Body).Decode(&body)
+	switch r.URL.Path {
+	case "/api/2.0/mlflow/runs/create":
+		f.created = body
+		json.NewEncoder(w).Encode(map[string]any{"run": map[string]any{"info": map[string]any{
+			"run_id": "run1", "artifact_uri": f.artifactURI,
+		}}})
+		return
+	case "/api/2.0/mlflow/runs/log-batch":
+		if body["run_id"] != "run1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": "RESOURCE_DOES_NOT_EXIST", "message": "no such run"}`))
+			return
+		}
+		if params, ok := body["params"].([]any); ok {
+			for _, p := range params {
+				p := p.(map[string]any)
+				f.params[p["key"].(string)] = p["value"].(string)
+			}
+		}
+		if metrics, ok := body["metrics"].([]any); ok {
+			for _, m := range metrics {
+				m := m.(map[string]any)
+				f.metrics[m["key"].(string)] = append(f.metrics[m["key"].(string)], m["value"].(float64))
+			}
+		}
+	case "/api/2.0/mlflow/runs/update":
+		f.status = body["status"].(string)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write([]byte("{}"))
+}
+
+func newFakeServer(artifactURI string) (*fakeServer, *httptest.Server) {
+	f := &fakeServer{
+		artifactURI: artifactURI,
+		params:      make(map[string]string),
+		metrics:     make(map[string][]float64),
+		artifacts:   make(map[string][]byte),
+	}
+	return f, httptest.NewServer(f)
+}
+
+func trainData() ([][]float64, []float64) {
+	X := make([][]float64, 40)
+	y := make([]float64, 40)
+	for i := range X {
+		X[i] = []float64{float64(i), float64(i % 3)}
+		y[i] = float64(i%7) + float64(i)/4
+	}
+	return X, y
+}
+
+func TestTracker(t *testing.T) {
+	fake, srv := newFakeServer("mlflow-artifacts:/7/run1/artifacts")
+	defer srv.Close()
+
+	X, y := trainData()
+	cfg := gboost.DefaultConfig()
+	cfg.NEstimators = 4
+	tracker := &mlflow.Tracker{URL: srv.URL + "/", ExperimentID: "7", RunName: "baseline", Tags: map[string]string{"team": "risk"}}
+	cfg.Tracker = tracker
+	gbm := gboost.New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	if tracker.RunID() != "run1" || fake.created["experiment_id"] != "7" || fake.created["run_name"] != "baseline" {
+		t.Errorf("run %q created with %v", tracker.RunID(), fake.created)
+	}
+	if fake.params["NEstimators"] != "4" || fake.params["Loss"] != "mse" {
+		t.Errorf("params = %v", fake.params)
+	}
+	history := gbm.History()
+	losses := fake.metrics["train_loss"]
+	if len(losses) != len(history) || losses[3] != history[3].TrainLoss {
+		t.Errorf("train_loss = %v, want one per round", losses)
+	}
+	if _, ok := fake.metrics["validation_loss"]; ok {
+		t.Error("logged a validation loss without a validation set")
+	}
+	var model gboost.ExportedModel
+	if err := json.Unmarshal(fake.artifacts["7/run1/artifacts/model.json"], &model); err != nil || len(model.Trees) != 4 {
+		t.Errorf("model artifact: %d trees, err %v", len(model.Trees), err)
+	}
+	if fake.status != "FINISHED" {
+		t.Errorf("run status %q, want FINISHED", fake.status)
+	}
+}
+
+func TestTrackerUnsupportedArtifactStore(t *testing.T) {
+	fake, srv := newFakeServer("s3://bucket/7/run1/artifacts")
+	defer srv.Close()
+
+	X, y := trainData()
+	cfg := gboost.DefaultConfig()
+	cfg.NEstimators = 2
+	cfg.Tracker = &mlflow.Tracker{URL: srv.URL, ExperimentID: "7"}
+	if err := gboost.New(cfg).Fit(X, y); !errors.Is(err, mlflow.ErrUnsupportedArtifactStore) {
+		t.Errorf("err = %v, want ErrUnsupportedArtifactStore", err)
+	}
+	if fake.status != "FAILED" {
+		t.Errorf("run status %q, want FAILED", fake.status)
+	}
+}
+
+func TestTrackerErrors(t *testing.T) {
+	tracker := &mlflow.Tracker{}
+	if err := tracker.LogMetrics(1, map[string]float64{"loss": 1}); !errors.Is(err, mlflow.ErrNoRun) {
+		t.Errorf("LogMetrics before a run: err = %v, want ErrNoRun", err)
+	}
+
+	_, srv := newFakeServer("")
+	defer srv.Close()
+	tracker = &mlflow.Tracker{URL: srv.URL + "/missing"}
+	err := tracker.LogParams(map[string]string{"a": "1"})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("err = %v, want a 404 error", err)
+	}
+}
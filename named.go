@@ -0,0 +1,156 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Tree returns tree i of the ensemble, in boosting order, for inspection
+// with [Node.PredictNamed] or the Node fields. Leaf values are not scaled by
+// the learning rate. The tree is shared with the model and must not be
+// modified.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or an error
+// if i is out of range.
+func (g *GBM) Tree(i int) (*Node, error) {
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+	if i < 0 || i >= len(g.trees) {
+		return nil, fmt.Errorf("tree index %d out of range for %d trees", i, len(g.trees))
+	}
+	return g.trees[i], nil
+}
+
+// PredictNamed returns the value of the leaf that a sample, given as a map
+// from feature name to value, falls into. names[j] is the name of feature j,
+// as returned by [GBM.FeatureNames]; with nil names, features are called
+// "f0", "f1", and so on, as in [GBM.DumpTrees]. Only the features split on
+// along the sample's path are needed. NaN is a valid value and, as in
+// prediction, is sent right.
+//
+// Returns an error wrapping [ErrUnknownFeature] for a name that is not a
+// feature, so that typos are caught, or [ErrMissingFeature] for a feature the
+// path needs but features lacks.
+func (n *Node) PredictNamed(features map[string]float64, names []string) (float64, error) {
+	index, err := featureIndex(features, names)
+	if err != nil {
+		return 0, err
+	}
+	return n.predictNamed(features, index)
+}
+
+// predictNamed is [Node.PredictNamed] for features already checked by
+// featureIndex, which returned index.
+func (n *Node) predictNamed(features map[string]float64, index func(int) string) (float64, error) {
+	for !n.isLeaf() {
+		v, ok := features[index(n.FeatureIndex)]
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrMissingFeature, index(n.FeatureIndex))
+		}
+		if v < n.Threshold {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	return n.Value, nil
+}
+
+// featureIndex checks that every key of features names a feature and
+// returns the function mapping a feature index to its name.
+func featureIndex(features map[string]float64, names []string) (func(int) string, error) {
+	if names == nil {
+		for name := range features {
+			j, err := strconv.Atoi(strings.TrimPrefix(name, "f"))
+			if !strings.HasPrefix(name, "f") || err != nil || j < 0 || strconv.Itoa(j) != name[1:] {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownFeature, name)
+			}
+		}
+		return func(j int) string { return "f" + strconv.Itoa(j) }, nil
+	}
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+	for name := range features {
+		if !known[name] {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownFeature, name)
+		}
+	}
+	return func(j int) string { return names[j] }, nil
+}
+
+// NamedRow converts a sample given as a map from feature name to value into
+// a row for the prediction methods, using the model's feature names, or
+// "f0", "f1", and so on if it has none. Every feature must be present; use
+// NaN for a missing value.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or an error
+// wrapping [ErrUnknownFeature] or [ErrMissingFeature].
+func (g *GBM) NamedRow(features map[string]float64) ([]float64, error) {
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+	names := g.names()
+	if _, err := featureIndex(features, names); err != nil {
+		return nil, err
+	}
+	row := make([]float64, g.numFeatures)
+	for j, name := range names {
+		v, ok := features[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingFeature, name)
+		}
+		row[j] = v
+	}
+	return row, nil
+}
+
+// PredictNamed returns the raw prediction (a regression value or log-odds)
+// for a sample given as a map from feature name to value, using the first
+// numTrees trees, or all of them if numTrees is negative. Partial sums show
+// how the prediction builds up over the rounds. Unlike [GBM.NamedRow], only
+// the features the trees read along the sample's paths are needed.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, an error if
+// numTrees exceeds [GBM.NumTrees], or one wrapping [ErrUnknownFeature] or
+// [ErrMissingFeature].
+func (g *GBM) PredictNamed(features map[string]float64, numTrees int) (float64, error) {
+	if !g.isFitted {
+		return math.NaN(), ErrModelNotFitted
+	}
+	if numTrees < 0 {
+		numTrees = len(g.trees)
+	}
+	if numTrees > len(g.trees) {
+		return math.NaN(), fmt.Errorf("%d trees requested from a model with %d", numTrees, len(g.trees))
+	}
+	index, err := featureIndex(features, g.names())
+	if err != nil {
+		return math.NaN(), err
+	}
+	pred := g.initialPrediction
+	for _, tree := range g.trees[:numTrees] {
+		v, err := tree.predictNamed(features, index)
+		if err != nil {
+			return math.NaN(), err
+		}
+		pred += float64(g.Config.LearningRate * v)
+	}
+	return pred, nil
+}
+
+// names returns the display names of all features, as by featureName.
+func (g *GBM) names() []string {
+	if g.featureNames != nil {
+		return g.featureNames
+	}
+	names := make([]string, g.numFeatures)
+	for j := range names {
+		names[j] = g.featureName(j)
+	}
+	return names
+}
@@ -0,0 +1,72 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPredictNamed(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, x := range X[:10] {
+		sample := map[string]float64{"f0": x[0], "f1": x[1]}
+		got, err := gbm.PredictNamed(sample, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := gbm.PredictSingle(x); got != want {
+			t.Errorf("PredictNamed = %v, want %v", got, want)
+		}
+		partial, err := gbm.PredictNamed(sample, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prefix := *gbm
+		prefix.trees = gbm.trees[:3]
+		if want := prefix.PredictSingle(x); math.Abs(partial-want) > 1e-12 {
+			t.Errorf("PredictNamed over 3 trees = %v, want %v", partial, want)
+		}
+		row, err := gbm.NamedRow(sample)
+		if err != nil || row[0] != x[0] || row[1] != x[1] {
+			t.Errorf("NamedRow = %v, %v, want %v", row, err, x)
+		}
+	}
+
+	if err := gbm.SetFeatureNames([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := gbm.Tree(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := tree.PredictNamed(map[string]float64{"a": X[0][0], "b": X[0][1]}, gbm.FeatureNames())
+	if err != nil || leaf != tree.predict(X[0]) {
+		t.Errorf("Node.PredictNamed = %v, %v, want %v", leaf, err, tree.predict(X[0]))
+	}
+
+	if _, err := gbm.PredictNamed(map[string]float64{"a": 1, "c": 2}, -1); !errors.Is(err, ErrUnknownFeature) {
+		t.Errorf("typo: err = %v, want ErrUnknownFeature", err)
+	}
+	if _, err := gbm.NamedRow(map[string]float64{"a": 1}); !errors.Is(err, ErrMissingFeature) {
+		t.Errorf("missing feature: err = %v, want ErrMissingFeature", err)
+	}
+	if _, err := tree.PredictNamed(map[string]float64{"f0": 1, "f1": 2}, gbm.FeatureNames()); !errors.Is(err, ErrUnknownFeature) {
+		t.Errorf("default names on a named model: err = %v, want ErrUnknownFeature", err)
+	}
+	if _, err := gbm.PredictNamed(map[string]float64{"a": 1, "b": 2}, 11); err == nil {
+		t.Error("expected an error for more trees than the model has")
+	}
+	if _, err := gbm.Tree(10); err == nil {
+		t.Error("expected an error for an out-of-range tree")
+	}
+	if _, err := New(cfg).Tree(0); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("untrained: err = %v, want ErrModelNotFitted", err)
+	}
+}
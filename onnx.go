@@ -0,0 +1,532 @@
+package gboost
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// LoadONNX reads the ONNX model file at path with [ParseONNX].
+func LoadONNX(path string) (*GBM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseONNX(data)
+}
+
+// ParseONNX converts an ONNX model holding a TreeEnsembleRegressor or
+// TreeEnsembleClassifier operator (domain ai.onnx.ml), as written by
+// skl2onnx for scikit-learn's GradientBoosting and HistGradientBoosting
+// estimators or by onnxmltools, into a trained [GBM], so that models trained
+// elsewhere can be scored, saved for the infer package, and served like
+// native ones. Other operators in the graph, such as the casts and ZipMap
+// around a classifier, are ignored.
+//
+// The model predicts the ensemble's raw score: the base value plus the sum
+// of the tree weights. A regressor becomes an "mse" model. A binary
+// classifier with a single score column and the LOGISTIC post-transform
+// becomes a "logloss" model whose [GBM.PredictProba] is the probability of
+// the second class label. The learning rate is 1, since ONNX leaf weights
+// are already scaled, and the feature count is taken from the graph input,
+// or from the highest feature index used. Thresholds of "<=" and ">"
+// comparisons are moved to the next float64 so that every split becomes
+// gboost's "x < threshold goes left". Predictions match an ONNX runtime up
+// to its float32 arithmetic: the runtime rounds inputs and sums to float32,
+// gboost computes in float64.
+//
+// ONNX stores no split gains or sample counts, so gain-based
+// [GBM.FeatureImportance] is zero and [GBM.ShapValues] is not meaningful
+// for the result; split-count importance works.
+//
+// Returns an error wrapping [ErrInvalidONNX] if data is not a well-formed
+// ONNX model with consistent tree attributes, or [ErrUnsupportedONNX] for
+// ensembles that gboost cannot represent: multiclass or multi-target
+// models, post-transforms other than NONE for regression and LOGISTIC for
+// classification, aggregate functions other than SUM, and missing values
+// routed against gboost's convention of sending NaN to the right child.
+func ParseONNX(data []byte) (*GBM, error) {
+	model, err := parseONNXModel(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidONNX, err)
+	}
+	var ensemble *onnxNode
+	for i := range model.nodes {
+		n := &model.nodes[i]
+		if n.domain == "ai.onnx.ml" && (n.opType == "TreeEnsembleRegressor" || n.opType == "TreeEnsembleClassifier") {
+			if ensemble != nil {
+				return nil, fmt.Errorf("%w: more than one tree ensemble", ErrUnsupportedONNX)
+			}
+			ensemble = n
+		}
+	}
+	if ensemble == nil {
+		return nil, fmt.Errorf("%w: no TreeEnsembleRegressor or TreeEnsembleClassifier", ErrUnsupportedONNX)
+	}
+	g, err := ensemble.toGBM()
+	if err != nil {
+		return nil, err
+	}
+	g.numFeatures = max(g.numFeatures, model.inputWidth)
+	g.calculateFeatureImportance()
+	return g, nil
+}
+
+// onnxModel is the part of an ONNX ModelProto that ParseONNX reads.
+type onnxModel struct {
+	nodes      []onnxNode
+	inputWidth int // last dimension of the first graph input, or 0
+}
+
+// onnxNode is an ONNX NodeProto with its attributes by name.
+type onnxNode struct {
+	opType, domain string
+	attrs          map[string]onnxAttr
+}
+
+// onnxAttr is an ONNX AttributeProto. Tensor attributes are decoded into
+// floats or ints.
+type onnxAttr struct {
+	i       int64
+	s       string
+	floats  []float64
+	ints    []int64
+	strings []string
+}
+
+// toGBM builds the model described by a tree ensemble node.
+func (n *onnxNode) toGBM() (*GBM, error) {
+	classifier := n.opType == "TreeEnsembleClassifier"
+	prefix := "target_"
+	if classifier {
+		prefix = "class_"
+	}
+	treeIDs := n.attrs["nodes_treeids"].ints
+	nodeIDs := n.attrs["nodes_nodeids"].ints
+	features := n.attrs["nodes_featureids"].ints
+	values := n.attrs["nodes_values"].floats
+	modes := n.attrs["nodes_modes"].strings
+	trueIDs := n.attrs["nodes_truenodeids"].ints
+	falseIDs := n.attrs["nodes_falsenodeids"].ints
+	missingTrue := n.attrs["nodes_missing_value_tracks_true"].ints
+	leafTrees := n.attrs[prefix+"treeids"].ints
+	leafNodes := n.attrs[prefix+"nodeids"].ints
+	leafIDs := n.attrs[prefix+"ids"].ints
+	weights := n.attrs[prefix+"weights"].floats
+
+	count := len(nodeIDs)
+	switch {
+	case count == 0:
+		return nil, fmt.Errorf("%w: ensemble has no nodes", ErrInvalidONNX)
+	case len(treeIDs) != count || len(features) != count || len(modes) != count ||
+		len(trueIDs) != count || len(falseIDs) != count ||
+		len(values) != count || (missingTrue != nil && len(missingTrue) != count):
+		return nil, fmt.Errorf("%w: node attributes differ in length", ErrInvalidONNX)
+	case len(leafTrees) != len(weights) || len(leafNodes) != len(weights) || len(leafIDs) != len(weights):
+		return nil, fmt.Errorf("%w: leaf attributes differ in length", ErrInvalidONNX)
+	}
+	if agg, ok := n.attrs["aggregate_function"]; ok && agg.s != "SUM" {
+		return nil, fmt.Errorf("%w: aggregate function %s", ErrUnsupportedONNX, agg.s)
+	}
+	for _, id := range leafIDs {
+		if id != leafIDs[0] {
+			return nil, fmt.Errorf("%w: more than one output column", ErrUnsupportedONNX)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.LearningRate = 1
+	post := n.attrs["post_transform"].s
+	if classifier {
+		labels := max(len(n.attrs["classlabels_int64s"].ints), len(n.attrs["classlabels_strings"].strings))
+		if labels != 2 || post != "LOGISTIC" {
+			return nil, fmt.Errorf("%w: classifier with %d classes and post-transform %q, want 2 and LOGISTIC", ErrUnsupportedONNX, labels, post)
+		}
+		cfg.Loss = "logloss"
+	} else {
+		if targets := n.attrs["n_targets"].i; targets > 1 {
+			return nil, fmt.Errorf("%w: %d targets", ErrUnsupportedONNX, targets)
+		}
+		if post != "" && post != "NONE" {
+			return nil, fmt.Errorf("%w: post-transform %s", ErrUnsupportedONNX, post)
+		}
+	}
+
+	g := &GBM{Config: cfg, forest: new(atomic.Pointer[layoutForest])}
+	if base := n.attrs["base_values"].floats; len(base) > 1 {
+		return nil, fmt.Errorf("%w: %d base values", ErrUnsupportedONNX, len(base))
+	} else if len(base) == 1 {
+		g.initialPrediction = base[0]
+	}
+
+	// Create every node, then link them.
+	type key struct{ tree, node int64 }
+	nodes := make(map[key]*Node, count)
+	var roots []int64 // tree IDs in order of first appearance
+	treeRoots := make(map[int64]*Node)
+	for k := range count {
+		id := key{treeIDs[k], nodeIDs[k]}
+		if nodes[id] != nil {
+			return nil, fmt.Errorf("%w: tree %d repeats node %d", ErrInvalidONNX, id.tree, id.node)
+		}
+		nodes[id] = &Node{}
+		if _, ok := treeRoots[id.tree]; !ok {
+			treeRoots[id.tree] = nil
+			roots = append(roots, id.tree)
+		}
+	}
+	children := make(map[*Node]bool, count)
+	for k := range count {
+		node := nodes[key{treeIDs[k], nodeIDs[k]}]
+		if modes[k] == "LEAF" {
+			continue
+		}
+		left, right := nodes[key{treeIDs[k], trueIDs[k]}], nodes[key{treeIDs[k], falseIDs[k]}]
+		if left == nil || right == nil || left == right || children[left] || children[right] {
+			return nil, fmt.Errorf("%w: tree %d node %d has invalid children", ErrInvalidONNX, treeIDs[k], nodeIDs[k])
+		}
+		if features[k] < 0 {
+			return nil, fmt.Errorf("%w: negative feature index", ErrInvalidONNX)
+		}
+		children[left], children[right] = true, true
+
+		// gboost sends x left when x < threshold and NaN right. "true" is
+		// left for "<" and "<=" and right for ">=" and ">"; NaN fails every
+		// comparison and so takes the false branch unless tracked true.
+		threshold := values[k]
+		nanTrue := missingTrue != nil && missingTrue[k] != 0
+		switch modes[k] {
+		case "BRANCH_LT":
+		case "BRANCH_LEQ":
+			threshold = math.Nextafter(threshold, math.Inf(1))
+		case "BRANCH_GTE":
+			left, right = right, left
+			nanTrue = !nanTrue
+		case "BRANCH_GT":
+			threshold = math.Nextafter(threshold, math.Inf(1))
+			left, right = right, left
+			nanTrue = !nanTrue
+		default:
+			return nil, fmt.Errorf("%w: node mode %s", ErrUnsupportedONNX, modes[k])
+		}
+		if nanTrue {
+			return nil, fmt.Errorf("%w: tree %d node %d sends missing values left", ErrUnsupportedONNX, treeIDs[k], nodeIDs[k])
+		}
+		node.FeatureIndex = int(features[k])
+		node.Threshold = threshold
+		node.Left, node.Right = left, right
+		g.numFeatures = max(g.numFeatures, node.FeatureIndex+1)
+	}
+	for k, w := range weights {
+		leaf := nodes[key{leafTrees[k], leafNodes[k]}]
+		if leaf == nil || !leaf.isLeaf() {
+			return nil, fmt.Errorf("%w: weight for tree %d node %d, which is not a leaf", ErrInvalidONNX, leafTrees[k], leafNodes[k])
+		}
+		leaf.Value += w
+	}
+
+	// A tree's root is its only node that is no other node's child.
+	for k := range count {
+		node := nodes[key{treeIDs[k], nodeIDs[k]}]
+		if children[node] {
+			continue
+		}
+		if treeRoots[treeIDs[k]] != nil {
+			return nil, fmt.Errorf("%w: tree %d is not connected", ErrInvalidONNX, treeIDs[k])
+		}
+		treeRoots[treeIDs[k]] = node
+	}
+	for _, tree := range roots {
+		if treeRoots[tree] == nil {
+			return nil, fmt.Errorf("%w: tree %d has no root", ErrInvalidONNX, tree)
+		}
+		g.appendTree(treeRoots[tree])
+	}
+	cfg.NEstimators = len(g.trees)
+	if g.maxDepth > 0 {
+		cfg.MaxDepth = g.maxDepth
+	}
+	g.Config = cfg
+	g.loss = createLossFunction(cfg)
+	g.isFitted = true
+	return g, nil
+}
+
+// parseONNXModel decodes the graph nodes and input width of an ONNX
+// ModelProto. Field numbers are those of onnx.proto3.
+func parseONNXModel(data []byte) (*onnxModel, error) {
+	model := &onnxModel{}
+	var graph []byte
+	firstInput := true
+	err := protoFields(data, func(field int, wire int, v uint64, b []byte) error {
+		if field == 7 && wire == protoBytes {
+			graph = b
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if graph == nil {
+		return nil, fmt.Errorf("no graph")
+	}
+	err = protoFields(graph, func(field int, wire int, v uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == protoBytes:
+			n, err := parseONNXNode(b)
+			if err != nil {
+				return err
+			}
+			model.nodes = append(model.nodes, *n)
+		case field == 11 && wire == protoBytes && firstInput:
+			firstInput = false
+			width, err := parseONNXInputWidth(b)
+			if err != nil {
+				return err
+			}
+			model.inputWidth = width
+		}
+		return nil
+	})
+	return model, err
+}
+
+// parseONNXNode decodes a NodeProto.
+func parseONNXNode(data []byte) (*onnxNode, error) {
+	n := &onnxNode{attrs: make(map[string]onnxAttr)}
+	err := protoFields(data, func(field int, wire int, v uint64, b []byte) error {
+		switch {
+		case field == 4 && wire == protoBytes:
+			n.opType = string(b)
+		case field == 7 && wire == protoBytes:
+			n.domain = string(b)
+		case field == 5 && wire == protoBytes:
+			name, a, err := parseONNXAttr(b)
+			if err != nil {
+				return err
+			}
+			n.attrs[name] = a
+		}
+		return nil
+	})
+	return n, err
+}
+
+// parseONNXAttr decodes an AttributeProto. Tensor-valued attributes, such
+// as nodes_values_as_tensor, are stored under the name without the
+// "_as_tensor" suffix.
+func parseONNXAttr(data []byte) (string, onnxAttr, error) {
+	var name string
+	var a onnxAttr
+	err := protoFields(data, func(field int, wire int, v uint64, b []byte) error {
+		var err error
+		switch {
+		case field == 1 && wire == protoBytes:
+			name = string(b)
+		case field == 3 && wire == protoVarint:
+			a.i = int64(v)
+		case field == 4 && wire == protoBytes:
+			a.s = string(b)
+		case field == 5 && wire == protoBytes:
+			a.floats, a.ints, err = parseONNXTensor(b)
+		case field == 7:
+			a.floats, err = appendFloats32(a.floats, wire, v, b)
+		case field == 8:
+			a.ints, err = appendVarints(a.ints, wire, v, b)
+		case field == 9 && wire == protoBytes:
+			a.strings = append(a.strings, string(b))
+		}
+		return err
+	})
+	name = strings.TrimSuffix(name, "_as_tensor")
+	return name, a, err
+}
+
+// parseONNXTensor decodes the values of a float, double, or int64
+// TensorProto.
+func parseONNXTensor(data []byte) (floats []float64, ints []int64, err error) {
+	var dataType uint64
+	var raw []byte
+	err = protoFields(data, func(field int, wire int, v uint64, b []byte) error {
+		var err error
+		switch field {
+		case 2:
+			dataType = v
+		case 4:
+			floats, err = appendFloats32(floats, wire, v, b)
+		case 7:
+			ints, err = appendVarints(ints, wire, v, b)
+		case 9:
+			raw = b
+		case 10:
+			floats, err = appendFloats64(floats, wire, v, b)
+		}
+		return err
+	})
+	if err != nil || raw == nil {
+		return floats, ints, err
+	}
+	// raw_data is little-endian, element by element.
+	switch dataType {
+	case 1: // FLOAT
+		for ; len(raw) >= 4; raw = raw[4:] {
+			floats = append(floats, float64(math.Float32frombits(binary.LittleEndian.Uint32(raw))))
+		}
+	case 11: // DOUBLE
+		for ; len(raw) >= 8; raw = raw[8:] {
+			floats = append(floats, math.Float64frombits(binary.LittleEndian.Uint64(raw)))
+		}
+	case 7: // INT64
+		for ; len(raw) >= 8; raw = raw[8:] {
+			ints = append(ints, int64(binary.LittleEndian.Uint64(raw)))
+		}
+	default:
+		return nil, nil, fmt.Errorf("tensor data type %d", dataType)
+	}
+	if len(raw) != 0 {
+		return nil, nil, fmt.Errorf("truncated tensor")
+	}
+	return floats, ints, nil
+}
+
+// parseONNXInputWidth returns the last dimension of a graph input's
+// ValueInfoProto, or 0 if it is not a fixed size.
+func parseONNXInputWidth(data []byte) (int, error) {
+	width := 0
+	// ValueInfoProto.type -> TypeProto.tensor_type -> shape -> dim.
+	path := []int{2, 1, 2}
+	var walk func(data []byte, depth int) error
+	walk = func(data []byte, depth int) error {
+		return protoFields(data, func(field int, wire int, v uint64, b []byte) error {
+			if wire != protoBytes {
+				return nil
+			}
+			if depth < len(path) {
+				if field == path[depth] {
+					return walk(b, depth+1)
+				}
+				return nil
+			}
+			if field != 1 {
+				return nil
+			}
+			width = 0
+			return protoFields(b, func(field int, wire int, v uint64, _ []byte) error {
+				if field == 1 && wire == protoVarint {
+					width = int(v)
+				}
+				return nil
+			})
+		})
+	}
+	return width, walk(data, 0)
+}
+
+// Protocol buffer wire types.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// protoFields calls visit for each field of a protocol buffer message with
+// its number and wire type. Varint and fixed-width values are passed in v,
+// length-delimited ones in b.
+func protoFields(data []byte, visit func(field, wire int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("malformed protobuf tag")
+		}
+		data = data[n:]
+		field, wire := int(tag>>3), int(tag&7)
+		var v uint64
+		var b []byte
+		switch wire {
+		case protoVarint:
+			v, n = binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("malformed protobuf varint")
+			}
+			data = data[n:]
+		case protoFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("truncated protobuf")
+			}
+			v, data = binary.LittleEndian.Uint64(data), data[8:]
+		case protoFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("truncated protobuf")
+			}
+			v, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case protoBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || size > uint64(len(data)-n) {
+				return fmt.Errorf("truncated protobuf")
+			}
+			b, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", wire)
+		}
+		if err := visit(field, wire, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendFloats32 appends a repeated float field, packed or not.
+func appendFloats32(dst []float64, wire int, v uint64, b []byte) ([]float64, error) {
+	switch wire {
+	case protoFixed32:
+		return append(dst, float64(math.Float32frombits(uint32(v)))), nil
+	case protoBytes:
+		if len(b)%4 != 0 {
+			return nil, fmt.Errorf("truncated packed floats")
+		}
+		for ; len(b) > 0; b = b[4:] {
+			dst = append(dst, float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+		}
+		return dst, nil
+	}
+	return nil, fmt.Errorf("float field with wire type %d", wire)
+}
+
+// appendFloats64 appends a repeated double field, packed or not.
+func appendFloats64(dst []float64, wire int, v uint64, b []byte) ([]float64, error) {
+	switch wire {
+	case protoFixed64:
+		return append(dst, math.Float64frombits(v)), nil
+	case protoBytes:
+		if len(b)%8 != 0 {
+			return nil, fmt.Errorf("truncated packed doubles")
+		}
+		for ; len(b) > 0; b = b[8:] {
+			dst = append(dst, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		}
+		return dst, nil
+	}
+	return nil, fmt.Errorf("double field with wire type %d", wire)
+}
+
+// appendVarints appends a repeated int64 field, packed or not.
+func appendVarints(dst []int64, wire int, v uint64, b []byte) ([]int64, error) {
+	switch wire {
+	case protoVarint:
+		return append(dst, int64(v)), nil
+	case protoBytes:
+		for len(b) > 0 {
+			x, n := binary.Uvarint(b)
+			if n <= 0 {
+				return nil, fmt.Errorf("malformed packed varint")
+			}
+			dst, b = append(dst, int64(x)), b[n:]
+		}
+		return dst, nil
+	}
+	return nil, fmt.Errorf("int field with wire type %d", wire)
+}
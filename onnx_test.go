@@ -0,0 +1,185 @@
+package gboost
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// protoMsg builds protocol buffer messages for ONNX test models.
+type protoMsg []byte
+
+func (m protoMsg) varint(field int, v uint64) protoMsg {
+	m = binary.AppendUvarint(m, uint64(field<<3|protoVarint))
+	return binary.AppendUvarint(m, v)
+}
+
+func (m protoMsg) bytes(field int, b []byte) protoMsg {
+	m = binary.AppendUvarint(m, uint64(field<<3|protoBytes))
+	m = binary.AppendUvarint(m, uint64(len(b)))
+	return append(m, b...)
+}
+
+func onnxInts(name string, v ...int64) protoMsg {
+	var packed []byte
+	for _, x := range v {
+		packed = binary.AppendUvarint(packed, uint64(x))
+	}
+	return protoMsg{}.bytes(1, []byte(name)).bytes(8, packed)
+}
+
+func onnxFloats(name string, v ...float32) protoMsg {
+	var packed []byte
+	for _, x := range v {
+		packed = binary.LittleEndian.AppendUint32(packed, math.Float32bits(x))
+	}
+	return protoMsg{}.bytes(1, []byte(name)).bytes(7, packed)
+}
+
+func onnxStrings(name string, v ...string) protoMsg {
+	m := protoMsg{}.bytes(1, []byte(name))
+	for _, s := range v {
+		m = m.bytes(9, []byte(s))
+	}
+	return m
+}
+
+func onnxString(name, v string) protoMsg {
+	return protoMsg{}.bytes(1, []byte(name)).bytes(4, []byte(v))
+}
+
+// onnxModelBytes wraps a tree ensemble node with the given attributes in a
+// model whose graph input has width features.
+func onnxModelBytes(opType string, width int, attrs ...protoMsg) []byte {
+	node := protoMsg{}.bytes(4, []byte(opType)).bytes(7, []byte("ai.onnx.ml"))
+	for _, a := range attrs {
+		node = node.bytes(5, a)
+	}
+	shape := protoMsg{}.bytes(1, protoMsg{}.bytes(2, []byte("N"))).bytes(1, protoMsg{}.varint(1, uint64(width)))
+	input := protoMsg{}.bytes(1, []byte("X")).bytes(2, protoMsg{}.bytes(1, protoMsg{}.varint(1, 1).bytes(2, shape)))
+	cast := protoMsg{}.bytes(4, []byte("Cast"))
+	graph := protoMsg{}.bytes(1, cast).bytes(1, node).bytes(11, input)
+	return protoMsg{}.varint(1, 8).bytes(7, graph)
+}
+
+// onnxTwoTrees returns the attributes of two trees over 3 features:
+//
+//	tree 0: x0 <= 1 ? 10 : (x2 > 5 ? 20 : 30)
+//	tree 1: x2 < 0 ? -1 : 1
+//
+// Missing values take the false branch, except at x2 > 5, which tracks
+// them as true, and at the root if missingTrue is 1.
+func onnxTwoTrees(prefix string, missingTrue int64) []protoMsg {
+	return []protoMsg{
+		onnxInts("nodes_treeids", 0, 0, 0, 0, 0, 1, 1, 1),
+		onnxInts("nodes_nodeids", 0, 1, 2, 3, 4, 0, 1, 2),
+		onnxInts("nodes_featureids", 0, 0, 2, 0, 0, 2, 0, 0),
+		onnxFloats("nodes_values", 1, 0, 5, 0, 0, 0, 0, 0),
+		onnxStrings("nodes_modes", "BRANCH_LEQ", "LEAF", "BRANCH_GT", "LEAF", "LEAF", "BRANCH_LT", "LEAF", "LEAF"),
+		onnxInts("nodes_truenodeids", 1, 0, 3, 0, 0, 1, 0, 0),
+		onnxInts("nodes_falsenodeids", 2, 0, 4, 0, 0, 2, 0, 0),
+		onnxInts("nodes_missing_value_tracks_true", missingTrue, 0, 1, 0, 0, 0, 0, 0),
+		onnxInts(prefix+"treeids", 0, 0, 0, 1, 1),
+		onnxInts(prefix+"nodeids", 1, 3, 4, 1, 2),
+		onnxInts(prefix+"ids", 0, 0, 0, 0, 0),
+		onnxFloats(prefix+"weights", 10, 20, 30, -1, 1),
+		onnxFloats("base_values", 0.5),
+	}
+}
+
+func TestParseONNXRegressor(t *testing.T) {
+	g, err := ParseONNX(onnxModelBytes("TreeEnsembleRegressor", 4, onnxTwoTrees("target_", 0)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NumTrees() != 2 || g.NumFeatures() != 4 || g.Config.Loss != "mse" {
+		t.Errorf("%d trees over %d features with %s loss, want 2 over 4 with mse", g.NumTrees(), g.NumFeatures(), g.Config.Loss)
+	}
+	tests := []struct {
+		x    []float64
+		want float64
+	}{
+		{[]float64{1, 0, -1, 0}, 0.5 + 10 - 1},                   // x0 == 1 goes true under "<="
+		{[]float64{math.Nextafter(1, 2), 0, 5, 0}, 0.5 + 30 + 1}, // x2 == 5 is not > 5
+		{[]float64{2, 0, 6, 0}, 0.5 + 20 + 1},
+		{[]float64{math.NaN(), 0, math.NaN(), 0}, 0.5 + 20 + 1},
+	}
+	for _, tt := range tests {
+		if got := g.PredictSingle(tt.x); got != tt.want {
+			t.Errorf("PredictSingle(%v) = %v, want %v", tt.x, got, tt.want)
+		}
+	}
+
+	// The model round-trips through Save and Load.
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := g.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil || loaded.PredictSingle(tests[2].x) != tests[2].want {
+		t.Errorf("loaded model: err %v", err)
+	}
+}
+
+func TestParseONNXClassifier(t *testing.T) {
+	attrs := append(onnxTwoTrees("class_", 0),
+		onnxInts("classlabels_int64s", 0, 1),
+		onnxString("post_transform", "LOGISTIC"))
+	path := filepath.Join(t.TempDir(), "model.onnx")
+	if err := os.WriteFile(path, onnxModelBytes("TreeEnsembleClassifier", 3, attrs...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := LoadONNX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := []float64{0, 0, 1}
+	if want := sigmoid(0.5 + 10 + 1); g.Config.Loss != "logloss" || g.PredictProba(x) != want {
+		t.Errorf("%s model: PredictProba = %v, want %v", g.Config.Loss, g.PredictProba(x), want)
+	}
+}
+
+func TestParseONNXTensorAttributes(t *testing.T) {
+	attrs := onnxTwoTrees("target_", 0)
+	var raw []byte
+	for _, v := range []float64{1, 0, 5, 0, 0, 0, 0, 0} {
+		raw = binary.LittleEndian.AppendUint64(raw, math.Float64bits(v))
+	}
+	tensor := protoMsg{}.varint(1, 8).varint(2, 11).bytes(9, raw)
+	attrs[3] = protoMsg{}.bytes(1, []byte("nodes_values_as_tensor")).bytes(5, tensor)
+	g, err := ParseONNX(onnxModelBytes("TreeEnsembleRegressor", 3, attrs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.PredictSingle([]float64{2, 0, 6}); got != 0.5+20+1 {
+		t.Errorf("PredictSingle = %v, want %v", got, 0.5+20+1)
+	}
+}
+
+func TestParseONNXErrors(t *testing.T) {
+	regressor := func(extra ...protoMsg) []byte {
+		return onnxModelBytes("TreeEnsembleRegressor", 3, append(onnxTwoTrees("target_", 0), extra...)...)
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"garbage", []byte{0xff, 0xff, 0xff}, ErrInvalidONNX},
+		{"no ensemble", protoMsg{}.bytes(7, protoMsg{}.bytes(1, protoMsg{}.bytes(4, []byte("Add")))), ErrUnsupportedONNX},
+		{"missing values sent left", onnxModelBytes("TreeEnsembleRegressor", 3, onnxTwoTrees("target_", 1)...), ErrUnsupportedONNX},
+		{"average", regressor(onnxString("aggregate_function", "AVERAGE")), ErrUnsupportedONNX},
+		{"post-transform", regressor(onnxString("post_transform", "SOFTMAX")), ErrUnsupportedONNX},
+		{"short attribute", regressor(onnxInts("nodes_featureids", 0)), ErrInvalidONNX},
+		{"multiclass", onnxModelBytes("TreeEnsembleClassifier", 3, append(onnxTwoTrees("class_", 0),
+			onnxInts("classlabels_int64s", 0, 1, 2), onnxString("post_transform", "LOGISTIC"))...), ErrUnsupportedONNX},
+	}
+	for _, tt := range tests {
+		if _, err := ParseONNX(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
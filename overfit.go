@@ -0,0 +1,215 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// OverfitCheckOptions controls [CheckOverfit]. Zero values select the
+// defaults.
+type OverfitCheckOptions struct {
+	// Rows is the number of rows sampled from the data. Zero means 100.
+	Rows int
+
+	// Tolerance is the largest ratio of the final training loss to the
+	// loss of the best constant prediction that passes, in [0, 1). Zero
+	// means 0.01.
+	Tolerance float64
+}
+
+func (o OverfitCheckOptions) validate() error {
+	if o.Rows < 0 || !(o.Tolerance >= 0 && o.Tolerance < 1) {
+		return ErrInvalidOverfitCheck
+	}
+	return nil
+}
+
+func (o OverfitCheckOptions) rows() int {
+	if o.Rows == 0 {
+		return 100
+	}
+	return o.Rows
+}
+
+func (o OverfitCheckOptions) tolerance() float64 {
+	if o.Tolerance == 0 {
+		return 0.01
+	}
+	return o.Tolerance
+}
+
+// OverfitCheckResult reports how well [CheckOverfit] memorized its sample,
+// with the likely reasons when it did not.
+type OverfitCheckResult struct {
+	// Rows holds the indices of the sampled rows into X, in increasing
+	// order.
+	Rows []int
+
+	// BaselineLoss is the mean loss of the best constant prediction on the
+	// sample, FinalLoss that of the trained model, and Ratio their ratio
+	// (0 when BaselineLoss is 0). Passed reports whether Ratio is within
+	// the tolerance.
+	BaselineLoss float64
+	FinalLoss    float64
+	Ratio        float64
+	Passed       bool
+
+	// Conflicts counts the sampled rows whose features equal those of
+	// another sampled row with a different target. No model can fit them
+	// all; many conflicts usually mean the target was joined to the wrong
+	// rows or the features carry no signal.
+	Conflicts int
+
+	// ConstantFeatures lists the columns that take a single value, or are
+	// all NaN, on the sample.
+	ConstantFeatures []int
+
+	// WorstRows holds the indices into X of up to five sampled rows the
+	// model fits worst, worst first.
+	WorstRows []int
+
+	// Warnings holds the model's [GBM.Warnings].
+	Warnings []string
+}
+
+// CheckOverfit is a smoke test for a new dataset or pipeline: a model that
+// cannot memorize a handful of rows has a bug to find before any tuning.
+// It trains deep, unregularized trees on opts.Rows rows of X, sampled with
+// cfg.Seed, and compares the training loss with that of the best constant
+// prediction.
+//
+// cfg supplies the loss, seed, split criterion and thresholds, and cost
+// matrix, and MinHessian if above 1e-3; every setting that limits capacity
+// is replaced: 200 rounds at learning rate 0.3 of trees of depth 10, with
+// no sampling, validation, early stopping, shrinkage, or leaf transform.
+//
+// When the ratio of the two losses exceeds opts.Tolerance, CheckOverfit
+// returns the result together with an error wrapping
+// [ErrOverfitCheckFailed] that summarizes its diagnostics. It returns no
+// result with [ErrEmptyDataset] if X is empty, [ErrLengthMismatch] if X and y
+// differ in length, [ErrFeatureCountMismatch] if rows differ in length,
+// [ErrInvalidOverfitCheck] for invalid options, or the error of fitting
+// the model.
+func CheckOverfit(cfg Config, X [][]float64, y []float64, opts OverfitCheckOptions) (*OverfitCheckResult, error) {
+	switch {
+	case len(X) == 0:
+		return nil, ErrEmptyDataset
+	case len(X) != len(y):
+		return nil, ErrLengthMismatch
+	case !hasSimilarLength(X):
+		return nil, ErrFeatureCountMismatch
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	rows := newRNG(cfg.Seed).Perm(len(X))[:min(opts.rows(), len(X))]
+	slices.Sort(rows)
+	XSample, ySample := extractRows(X, rows), extractRows(y, rows)
+
+	deep := DefaultConfig()
+	deep.Seed = cfg.Seed
+	deep.Loss = cfg.Loss
+	deep.CostMatrix = cfg.CostMatrix
+	// Memorized logloss rows are predicted with near certainty, where the
+	// Hessians vanish; a floor keeps the Newton steps finite.
+	deep.MinHessian = max(cfg.MinHessian, 1e-3)
+	deep.SplitCriterion = cfg.SplitCriterion
+	deep.SplitThresholds = cfg.SplitThresholds
+	deep.SplitWorkers = cfg.SplitWorkers
+	deep.NEstimators = 200
+	deep.LearningRate = 0.3
+	deep.MaxDepth = 10
+	deep.KeepDiagnostics = true
+	model := New(deep)
+	if err := model.Fit(XSample, ySample); err != nil {
+		return nil, err
+	}
+
+	baseline := make([]float64, len(ySample))
+	for i := range baseline {
+		baseline[i] = model.initialPrediction
+	}
+	res := &OverfitCheckResult{
+		Rows:         rows,
+		BaselineLoss: evalLoss(deep.Loss, ySample, baseline, nil),
+		FinalLoss:    evalLoss(deep.Loss, ySample, model.Predict(XSample), nil),
+		Conflicts:    countConflicts(XSample, ySample),
+		Warnings:     model.Warnings(),
+	}
+	if res.BaselineLoss > 0 {
+		res.Ratio = res.FinalLoss / res.BaselineLoss
+	}
+	res.Passed = res.Ratio <= opts.tolerance()
+	for j := range XSample[0] {
+		if hasSingleValue(XSample, j) {
+			res.ConstantFeatures = append(res.ConstantFeatures, j)
+		}
+	}
+	for _, i := range model.TrainingDiagnostics().WorstFit(5) {
+		res.WorstRows = append(res.WorstRows, rows[i])
+	}
+	if res.Passed {
+		return res, nil
+	}
+
+	msg := fmt.Sprintf("training loss %.4g is %.1f%% of the constant prediction's %.4g on %d rows",
+		res.FinalLoss, 100*res.Ratio, res.BaselineLoss, len(rows))
+	if res.Conflicts > 0 {
+		msg += fmt.Sprintf("; %d rows share their features with a row of a different target", res.Conflicts)
+	}
+	if len(res.ConstantFeatures) == len(XSample[0]) {
+		msg += "; every feature is constant"
+	} else if len(res.ConstantFeatures) > 0 {
+		msg += fmt.Sprintf("; %d of %d features are constant", len(res.ConstantFeatures), len(XSample[0]))
+	}
+	if len(res.Warnings) > 0 {
+		msg += "; " + strings.Join(res.Warnings, "; ")
+	}
+	return res, fmt.Errorf("%w: %s", ErrOverfitCheckFailed, msg)
+}
+
+// countConflicts returns the number of rows of X whose features, compared
+// bit for bit, equal those of another row with a different target.
+func countConflicts(X [][]float64, y []float64) int {
+	groups := make(map[string][]float64)
+	for i, row := range X {
+		groups[rowKey(row)] = append(groups[rowKey(row)], y[i])
+	}
+	n := 0
+	for _, targets := range groups {
+		if slices.Min(targets) != slices.Max(targets) {
+			n += len(targets)
+		}
+	}
+	return n
+}
+
+// rowKey returns a string identifying the exact bits of row.
+func rowKey(row []float64) string {
+	b := make([]byte, 0, 17*len(row))
+	for _, v := range row {
+		b = strconv.AppendUint(b, math.Float64bits(v), 16)
+		b = append(b, ',')
+	}
+	return string(b)
+}
+
+// hasSingleValue reports whether column j of X holds at most one distinct
+// value other than NaN. Unlike isConstantColumn, it ignores missing values.
+func hasSingleValue(X [][]float64, j int) bool {
+	first := math.NaN()
+	for _, row := range X {
+		switch v := row[j]; {
+		case math.IsNaN(v):
+		case math.IsNaN(first):
+			first = v
+		case v != first:
+			return false
+		}
+	}
+	return true
+}
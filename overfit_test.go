@@ -0,0 +1,52 @@
+package gboost
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckOverfit(t *testing.T) {
+	X, y := generateNoisyData()
+	res, err := CheckOverfit(DefaultConfig(), X, y, OverfitCheckOptions{Rows: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Passed || len(res.Rows) != min(50, len(X)) || res.Ratio > 0.01 || res.BaselineLoss <= 0 {
+		t.Errorf("mse: %+v", res)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	Xb, yb := generateBinaryData(0.5)
+	if res, err := CheckOverfit(cfg, Xb, yb, OverfitCheckOptions{}); err != nil || !res.Passed {
+		t.Errorf("logloss: %+v, err = %v", res, err)
+	}
+}
+
+func TestCheckOverfitFails(t *testing.T) {
+	// Every row has the same features, so no model can tell the targets apart.
+	X := make([][]float64, 40)
+	y := make([]float64, 40)
+	for i := range X {
+		X[i] = []float64{1, 2}
+		y[i] = float64(i % 4)
+	}
+	res, err := CheckOverfit(DefaultConfig(), X, y, OverfitCheckOptions{})
+	if !errors.Is(err, ErrOverfitCheckFailed) || res == nil {
+		t.Fatalf("res = %+v, err = %v", res, err)
+	}
+	if res.Passed || res.Conflicts != 40 || len(res.ConstantFeatures) != 2 || len(res.WorstRows) != 5 {
+		t.Errorf("diagnostics: %+v", res)
+	}
+	if !strings.Contains(err.Error(), "40 rows share their features") || !strings.Contains(err.Error(), "every feature is constant") {
+		t.Errorf("error message: %v", err)
+	}
+
+	if _, err := CheckOverfit(DefaultConfig(), X, y, OverfitCheckOptions{Tolerance: 1}); !errors.Is(err, ErrInvalidOverfitCheck) {
+		t.Errorf("tolerance 1: err = %v", err)
+	}
+	if _, err := CheckOverfit(DefaultConfig(), X, y[1:], OverfitCheckOptions{}); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("short y: err = %v", err)
+	}
+}
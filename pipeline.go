@@ -0,0 +1,201 @@
+package gboost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Transformer is a preprocessing step that learns its parameters from
+// training data and applies them to new data, such as an [Imputer].
+type Transformer interface {
+	// Fit learns the step's parameters from X, and y where supervised.
+	Fit(X [][]float64, y []float64) error
+
+	// Transform applies the learned parameters to X without modifying it.
+	Transform(X [][]float64) ([][]float64, error)
+}
+
+// fitTransformer is implemented by transformers whose output on their own
+// training data differs from [Transformer.Transform], such as [WOEEncoder],
+// which encodes training rows out of fold, and [OrderedTargetEncoder].
+// [Pipeline.Fit] uses it when present.
+type fitTransformer interface {
+	FitTransform(X [][]float64, y []float64) ([][]float64, error)
+}
+
+// persistentTransformer is implemented by the package's transformers that
+// [Pipeline.Save] knows how to write; the kind names the step in the file.
+type persistentTransformer interface {
+	Transformer
+	transformerKind() string
+}
+
+// transformerKinds constructs an empty transformer for each kind that
+// [LoadPipeline] can read.
+var transformerKinds = map[string]func() Transformer{
+	"imputer":                func() Transformer { return &Imputer{} },
+	"woe":                    func() Transformer { return &WOEBinner{} },
+	"woe_encoder":            func() Transformer { return &WOEEncoder{} },
+	"ordered_target_encoder": func() Transformer { return &OrderedTargetEncoder{} },
+	"time_series":            func() Transformer { return &TimeSeriesFeatures{} },
+}
+
+// Pipeline chains preprocessing steps in front of a [GBM], so the exact
+// transformations fitted on the training data are reapplied at prediction
+// time and saved alongside the model.
+//
+// Encoder, if set, encodes raw string records for [Pipeline.Encode],
+// [Pipeline.PredictRecords], and [Pipeline.PredictProbaRecords], including
+// its policy for categories not seen in training. Without it, every feature
+// of a record must be numeric.
+type Pipeline struct {
+	Encoder *CategoryEncoder
+	Steps   []Transformer
+	Model   *GBM
+}
+
+// NewPipeline creates a [Pipeline] that applies steps in order before model.
+func NewPipeline(model *GBM, steps ...Transformer) *Pipeline {
+	return &Pipeline{Steps: steps, Model: model}
+}
+
+// Fit fits each step on the output of the previous one, then trains the model
+// on the fully transformed data. Steps with a FitTransform method, such as
+// [WOEEncoder], produce their training output with it.
+func (p *Pipeline) Fit(X [][]float64, y []float64) error {
+	for i, step := range p.Steps {
+		var err error
+		if ft, ok := step.(fitTransformer); ok {
+			X, err = ft.FitTransform(X, y)
+		} else if err = step.Fit(X, y); err == nil {
+			X, err = step.Transform(X)
+		}
+		if err != nil {
+			return fmt.Errorf("pipeline step %d: %w", i, err)
+		}
+	}
+	return p.Model.Fit(X, y)
+}
+
+// Transform applies every fitted step to X and returns the model's input.
+func (p *Pipeline) Transform(X [][]float64) ([][]float64, error) {
+	for i, step := range p.Steps {
+		var err error
+		if X, err = step.Transform(X); err != nil {
+			return nil, fmt.Errorf("pipeline step %d: %w", i, err)
+		}
+	}
+	return X, nil
+}
+
+// Predict transforms X and returns the model's raw predictions.
+func (p *Pipeline) Predict(X [][]float64) ([]float64, error) {
+	Xt, err := p.Transform(X)
+	if err != nil {
+		return nil, err
+	}
+	return p.Model.Predict(Xt), nil
+}
+
+// PredictProbaAll transforms X and returns P(y=1) for each sample.
+// Only meaningful for models trained with logloss.
+func (p *Pipeline) PredictProbaAll(X [][]float64) ([]float64, error) {
+	Xt, err := p.Transform(X)
+	if err != nil {
+		return nil, err
+	}
+	return p.Model.PredictProbaAll(Xt), nil
+}
+
+// Encode converts raw feature records into the input of the pipeline's
+// first step with its Encoder; see [CategoryEncoder.Encode].
+func (p *Pipeline) Encode(records [][]string) ([][]float64, error) {
+	enc := p.Encoder
+	if enc == nil {
+		enc = &CategoryEncoder{NumFeatures: p.Model.NumFeatures()}
+	}
+	return enc.Encode(records)
+}
+
+// PredictRecords encodes records with [Pipeline.Encode] and returns the
+// model's raw predictions.
+func (p *Pipeline) PredictRecords(records [][]string) ([]float64, error) {
+	X, err := p.Encode(records)
+	if err != nil {
+		return nil, err
+	}
+	return p.Predict(X)
+}
+
+// PredictProbaRecords encodes records with [Pipeline.Encode] and returns
+// P(y=1) for each of them. Only meaningful for models trained with logloss.
+func (p *Pipeline) PredictProbaRecords(records [][]string) ([]float64, error) {
+	X, err := p.Encode(records)
+	if err != nil {
+		return nil, err
+	}
+	return p.PredictProbaAll(X)
+}
+
+// ExportedStep is the JSON form of one [Pipeline] step.
+type ExportedStep struct {
+	Kind string          `json:"kind"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// ExportedPipeline is the JSON form of a [Pipeline].
+type ExportedPipeline struct {
+	Encoder *CategoryEncoder `json:"encoder,omitempty"`
+	Steps   []ExportedStep   `json:"steps"`
+	Model   *ExportedModel   `json:"model"`
+}
+
+// Save writes the pipeline's encoder, fitted steps, and model to a JSON file at path.
+// The file can be restored with [LoadPipeline].
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or
+// [ErrUnsupportedTransformer] if a step is not one of the package's
+// transformers.
+func (p *Pipeline) Save(path string) error {
+	if !p.Model.isFitted {
+		return ErrModelNotFitted
+	}
+	exported := ExportedPipeline{Encoder: p.Encoder, Model: p.Model.toExported()}
+	for i, step := range p.Steps {
+		ps, ok := step.(persistentTransformer)
+		if !ok {
+			return fmt.Errorf("pipeline step %d (%T): %w", i, step, ErrUnsupportedTransformer)
+		}
+		spec, err := json.Marshal(step)
+		if err != nil {
+			return fmt.Errorf("pipeline step %d: %w", i, err)
+		}
+		exported.Steps = append(exported.Steps, ExportedStep{Kind: ps.transformerKind(), Spec: spec})
+	}
+	return writeJSON(path, exported)
+}
+
+// LoadPipeline reads a pipeline previously written by [Pipeline.Save].
+func LoadPipeline(path string) (*Pipeline, error) {
+	var exported ExportedPipeline
+	if err := readJSON(path, &exported); err != nil {
+		return nil, err
+	}
+	if exported.Model == nil {
+		return nil, fmt.Errorf("pipeline file has no model")
+	}
+
+	p := &Pipeline{Encoder: exported.Encoder, Model: fromExported(exported.Model)}
+	for i, e := range exported.Steps {
+		newStep, ok := transformerKinds[e.Kind]
+		if !ok {
+			return nil, fmt.Errorf("pipeline step %d (%q): %w", i, e.Kind, ErrUnsupportedTransformer)
+		}
+		step := newStep()
+		if err := json.Unmarshal(e.Spec, step); err != nil {
+			return nil, fmt.Errorf("pipeline step %d: %w", i, err)
+		}
+		p.Steps = append(p.Steps, step)
+	}
+	return p, nil
+}
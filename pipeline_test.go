@@ -0,0 +1,105 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// withMissing returns a copy of X with every fifth value of feature 0 set to NaN.
+func withMissing(X [][]float64) [][]float64 {
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		res[i] = append([]float64(nil), row...)
+		if i%5 == 0 {
+			res[i][0] = math.NaN()
+		}
+	}
+	return res
+}
+
+func TestPipelineFitPredict(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	X = withMissing(X)
+
+	imputer := NewImputer(ImputeMedian)
+	p := NewPipeline(New(DefaultConfig()), imputer)
+	if err := p.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.Predict(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Xt, _ := imputer.Transform(X)
+	want := p.Model.Predict(Xt)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Predict[%d] = %v, want %v", i, got[i], want[i])
+		}
+		if math.IsNaN(got[i]) {
+			t.Fatalf("Predict[%d] is NaN", i)
+		}
+	}
+
+	if _, err := p.Predict([][]float64{{1}}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+}
+
+func TestPipelineSaveLoad(t *testing.T) {
+	X, y := generateBinaryData(5)
+	X = withMissing(X)
+	config := DefaultConfig()
+	config.Loss = "logloss"
+	config.NEstimators = 20
+	p := NewPipeline(New(config), NewImputer(ImputeMean))
+	if err := p.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "pipeline.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPipeline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := p.PredictProbaAll(X)
+	got, err := loaded.PredictProbaAll(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("loaded PredictProbaAll[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// scaleTransformer is a Transformer the pipeline does not know how to persist.
+type scaleTransformer struct{}
+
+func (scaleTransformer) Fit([][]float64, []float64) error { return nil }
+
+func (scaleTransformer) Transform(X [][]float64) ([][]float64, error) { return X, nil }
+
+func TestPipelineSaveErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.json")
+	if err := NewPipeline(New(DefaultConfig())).Save(path); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+
+	X, y := generateDataWithFunc(linearFunc)
+	p := NewPipeline(New(DefaultConfig()), scaleTransformer{})
+	if err := p.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Save(path); !errors.Is(err, ErrUnsupportedTransformer) {
+		t.Errorf("expected ErrUnsupportedTransformer, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package gboost
+
+import (
+	"container/list"
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// Predictor scores samples with a trained [GBM] for serving, optionally
+// memoizing predictions in a fixed-size LRU cache. It is safe for concurrent
+// use as long as the underlying model is not refitted.
+//
+// The cache is keyed by the exact bit pattern of the input row, so it pays
+// off for services that see many repeated rows; near-duplicates that differ
+// in any feature are scored independently. Keying by the reached leaves
+// instead would still require walking every tree, which is most of the cost
+// of a prediction.
+type Predictor struct {
+	model *GBM
+
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List // front is most recently used
+	hits    int
+	misses  int
+}
+
+type cacheEntry struct {
+	key   string
+	value float64
+}
+
+// CacheStats reports the activity of a [Predictor] cache.
+type CacheStats struct {
+	Hits    int // Lookups answered from the cache
+	Misses  int // Lookups that walked the trees
+	Entries int // Rows currently cached
+	Size    int // Maximum number of cached rows
+}
+
+// HitRate returns the fraction of lookups answered from the cache, or 0
+// before the first lookup.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// NewPredictor returns a [Predictor] for g that caches up to cacheSize
+// predictions. A cacheSize of 0 disables caching.
+//
+// Returns [ErrModelNotFitted] if g has not been trained, or
+// [ErrInvalidCacheSize] if cacheSize is negative.
+func (g *GBM) NewPredictor(cacheSize int) (*Predictor, error) {
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+	if cacheSize < 0 {
+		return nil, ErrInvalidCacheSize
+	}
+	return &Predictor{
+		model:   g,
+		size:    cacheSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}, nil
+}
+
+// PredictSingle returns the raw prediction (a regression value or log-odds)
+// for one sample, from the cache when possible.
+func (p *Predictor) PredictSingle(x []float64) float64 {
+	if p.size == 0 {
+		return p.model.PredictSingle(x)
+	}
+
+	key := cacheKey(x)
+	p.mu.Lock()
+	if e, ok := p.entries[key]; ok {
+		p.hits++
+		p.lru.MoveToFront(e)
+		value := e.Value.(*cacheEntry).value
+		p.mu.Unlock()
+		return value
+	}
+	p.misses++
+	p.mu.Unlock()
+
+	// Score outside the lock so concurrent misses do not serialize.
+	value := p.model.PredictSingle(x)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.entries[key]; ok {
+		return value
+	}
+	p.entries[key] = p.lru.PushFront(&cacheEntry{key: key, value: value})
+	if p.lru.Len() > p.size {
+		oldest := p.lru.Back()
+		p.lru.Remove(oldest)
+		delete(p.entries, oldest.Value.(*cacheEntry).key)
+	}
+	return value
+}
+
+// Predict returns raw predictions for each sample in X.
+func (p *Predictor) Predict(X [][]float64) []float64 {
+	res := make([]float64, len(X))
+	for i, x := range X {
+		res[i] = p.PredictSingle(x)
+	}
+	return res
+}
+
+// PredictProba returns P(y=1) for one sample. Only meaningful for models
+// trained with logloss.
+func (p *Predictor) PredictProba(x []float64) float64 {
+	return sigmoid(p.PredictSingle(x))
+}
+
+// CacheStats returns the cache counters accumulated since the predictor was
+// created or last reset.
+func (p *Predictor) CacheStats() CacheStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return CacheStats{Hits: p.hits, Misses: p.misses, Entries: p.lru.Len(), Size: p.size}
+}
+
+// ResetCache empties the cache and zeroes its counters.
+func (p *Predictor) ResetCache() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clear(p.entries)
+	p.lru.Init()
+	p.hits, p.misses = 0, 0
+}
+
+// cacheKey encodes the exact bits of x, so distinct rows never collide.
+func cacheKey(x []float64) string {
+	buf := make([]byte, 8*len(x))
+	for j, v := range x {
+		binary.LittleEndian.PutUint64(buf[8*j:], math.Float64bits(v))
+	}
+	return string(buf)
+}
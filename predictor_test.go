@@ -0,0 +1,107 @@
+package gboost
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestPredictorCache(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	gbm := New(DefaultConfig())
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	p, err := gbm.NewPredictor(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b, c := X[0], X[1], X[2]
+	for _, x := range [][]float64{a, b, a, c, b, a} {
+		if got, want := p.PredictSingle(x), gbm.PredictSingle(x); got != want {
+			t.Fatalf("PredictSingle(%v) = %v, want %v", x, got, want)
+		}
+	}
+
+	// a, b miss; a hits; c evicts b; b misses and evicts a; a misses.
+	stats := p.CacheStats()
+	want := CacheStats{Hits: 1, Misses: 5, Entries: 2, Size: 2}
+	if stats != want {
+		t.Errorf("CacheStats() = %+v, want %+v", stats, want)
+	}
+	if rate := stats.HitRate(); rate != 1.0/6 {
+		t.Errorf("HitRate() = %v, want 1/6", rate)
+	}
+
+	p.ResetCache()
+	if stats := p.CacheStats(); stats != (CacheStats{Size: 2}) {
+		t.Errorf("after ResetCache, CacheStats() = %+v", stats)
+	}
+	if rate := p.CacheStats().HitRate(); rate != 0 {
+		t.Errorf("HitRate() with no lookups = %v, want 0", rate)
+	}
+}
+
+func TestPredictorWithoutCache(t *testing.T) {
+	X, y := generateBinaryData(5)
+	gbm := fitBinaryModel(t, X, y)
+	p, err := gbm.NewPredictor(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := p.Predict(X)
+	for i, want := range gbm.Predict(X) {
+		if got[i] != want {
+			t.Fatalf("Predict[%d] = %v, want %v", i, got[i], want)
+		}
+	}
+	if got, want := p.PredictProba(X[0]), gbm.PredictProba(X[0]); got != want {
+		t.Errorf("PredictProba = %v, want %v", got, want)
+	}
+	if stats := p.CacheStats(); stats.Hits+stats.Misses != 0 {
+		t.Errorf("disabled cache recorded lookups: %+v", stats)
+	}
+}
+
+func TestPredictorConcurrent(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	gbm := New(DefaultConfig())
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	p, err := gbm.NewPredictor(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Go(func() {
+			for i := range 200 {
+				x := X[(i+w)%16]
+				if got, want := p.PredictSingle(x), gbm.PredictSingle(x); got != want {
+					t.Errorf("PredictSingle(%v) = %v, want %v", x, got, want)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	stats := p.CacheStats()
+	if stats.Hits+stats.Misses != 800 || stats.Entries > 8 {
+		t.Errorf("CacheStats() = %+v, want 800 lookups and at most 8 entries", stats)
+	}
+}
+
+func TestNewPredictorErrors(t *testing.T) {
+	if _, err := New(DefaultConfig()).NewPredictor(10); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+	gbm := fitStumpModel(t)
+	if _, err := gbm.NewPredictor(-1); !errors.Is(err, ErrInvalidCacheSize) {
+		t.Errorf("expected ErrInvalidCacheSize, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"unsafe"
+)
+
+func TestQuantizeMatchesModel(t *testing.T) {
+	X, y := generateDataWithFunc(func(x1, x2 float64) float64 { return math.Sin(6*x1) + x2*x2 })
+	cfg := DefaultConfig()
+	cfg.NEstimators = 50
+	cfg.MaxDepth = 4
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := gbm.Quantize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.NumFeatures() != 2 {
+		t.Errorf("NumFeatures() = %d, want 2", q.NumFeatures())
+	}
+
+	// Training rows sit exactly on thresholds, so they exercise the
+	// boundaries; fresh points exercise everything in between.
+	rnd := rand.New(rand.NewSource(1))
+	inputs := append([][]float64{}, X...)
+	for range 200 {
+		inputs = append(inputs, []float64{rnd.Float64()*1.2 - 0.1, rnd.Float64()*1.2 - 0.1})
+	}
+
+	want := gbm.Predict(inputs)
+	got := q.Predict(inputs)
+	for i := range inputs {
+		if math.Abs(got[i]-want[i]) > 1e-5 {
+			t.Fatalf("input %v: quantized %v, model %v", inputs[i], got[i], want[i])
+		}
+		if single := q.PredictBinned(q.Bin(inputs[i])); single != got[i] {
+			t.Fatalf("PredictBinned = %v, Predict = %v", single, got[i])
+		}
+	}
+}
+
+func TestQuantizePredictBinnedBatch(t *testing.T) {
+	X, y := generateDataWithFunc(func(x1, x2 float64) float64 { return math.Sin(6*x1) + x2*x2 })
+	cfg := DefaultConfig()
+	cfg.NEstimators = 30
+	cfg.MaxDepth = 5
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	q, err := gbm.Quantize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sizes around the block size cover full, partial, and empty blocks.
+	for _, n := range []int{0, 1, 63, 64, 65, 150} {
+		bins := make([][]uint16, n)
+		for i := range bins {
+			bins[i] = q.Bin(X[i%len(X)])
+		}
+		got := q.PredictBinnedBatch(bins)
+		if len(got) != n {
+			t.Fatalf("n=%d: got %d predictions", n, len(got))
+		}
+		for i := range bins {
+			if want := q.PredictBinned(bins[i]); got[i] != want {
+				t.Fatalf("n=%d, row %d: batch %v, single %v", n, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestQuantizeBoundaries(t *testing.T) {
+	gbm := fitStumpModel(t)
+	q, err := gbm.Quantize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	threshold := gbm.trees[0].Threshold
+	for _, v := range []float64{math.Inf(-1), math.Nextafter(threshold, math.Inf(-1)), threshold, math.Inf(1)} {
+		x := []float64{v}
+		if got, want := q.PredictSingle(x), gbm.PredictSingle(x); math.Abs(got-want) > 1e-5 {
+			t.Errorf("x=%v: quantized %v, model %v", v, got, want)
+		}
+	}
+}
+
+func TestQuantizeProba(t *testing.T) {
+	X, y := generateBinaryData(5)
+	gbm := fitBinaryModel(t, X, y)
+	q, err := gbm.Quantize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range X[:20] {
+		if got, want := q.PredictProba(x), gbm.PredictProba(x); math.Abs(got-want) > 1e-5 {
+			t.Errorf("PredictProba(%v) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestQuantizeShrinksFootprint(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 50
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	q, err := gbm.Quantize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := 0
+	for _, tree := range gbm.trees {
+		nodes += 2*tree.numLeaves() - 1
+	}
+	original := nodes * int(unsafe.Sizeof(Node{}))
+	if q.SizeBytes()*3 > original {
+		t.Errorf("quantized model uses %d bytes, original nodes %d", q.SizeBytes(), original)
+	}
+}
+
+func TestQuantizeNotFitted(t *testing.T) {
+	if _, err := New(DefaultConfig()).Quantize(); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+}
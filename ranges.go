@@ -0,0 +1,71 @@
+package gboost
+
+import "math"
+
+// FeatureRanges returns the per-feature minimum and maximum seen in the
+// training data passed to [GBM.Fit], or nil slices for untrained models and
+// models saved before ranges were recorded.
+func (g *GBM) FeatureRanges() (lo, hi []float64) {
+	return g.featureMin, g.featureMax
+}
+
+// OutOfRange returns the indices of the features of x that fall outside
+// the training range, including NaN and infinite values. It returns nil when
+// every value is in range or the model has no recorded ranges.
+//
+// Use it to flag inputs from a corrupted upstream pipeline, for example a
+// value in cents where dollars were expected. Trees cannot extrapolate, so
+// such inputs do not produce absurd predictions by themselves; they silently
+// receive the prediction for the nearest training extreme instead, which is
+// what makes them worth flagging.
+func (g *GBM) OutOfRange(x []float64) []int {
+	if g.featureMin == nil || len(x) != len(g.featureMin) {
+		return nil
+	}
+	var out []int
+	for j, v := range x {
+		if math.IsNaN(v) || v < g.featureMin[j] || v > g.featureMax[j] {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+// ClipToRange returns a copy of x with every feature clamped to its training
+// range; NaN values are left as is. Predictions of this model are unchanged
+// by clipping, since every split threshold lies inside the training range,
+// but clipped rows are safe to hand to downstream consumers that do
+// extrapolate, such as linear models or a blended ensemble.
+func (g *GBM) ClipToRange(x []float64) []float64 {
+	res := append([]float64(nil), x...)
+	if g.featureMin == nil || len(x) != len(g.featureMin) {
+		return res
+	}
+	for j, v := range res {
+		res[j] = min(max(v, g.featureMin[j]), g.featureMax[j])
+	}
+	return res
+}
+
+// featureRanges returns the per-column minimum and maximum of X, ignoring
+// NaN. A column with no other values gets a NaN range.
+func featureRanges(X [][]float64) (lo, hi []float64) {
+	lo = make([]float64, len(X[0]))
+	hi = make([]float64, len(X[0]))
+	for j := range lo {
+		lo[j], hi[j] = math.NaN(), math.NaN()
+	}
+	for _, row := range X {
+		for j, v := range row {
+			switch {
+			case math.IsNaN(v):
+			case math.IsNaN(lo[j]):
+				lo[j], hi[j] = v, v
+			default:
+				lo[j] = min(lo[j], v)
+				hi[j] = max(hi[j], v)
+			}
+		}
+	}
+	return lo, hi
+}
@@ -0,0 +1,103 @@
+package gboost
+
+import (
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFeatureRanges(t *testing.T) {
+	X := [][]float64{{1, -2}, {3, 5}, {2, 0}, {0, 1}}
+	y := []float64{1, 2, 3, 4}
+	gbm := New(DefaultConfig())
+	if lo, hi := gbm.FeatureRanges(); lo != nil || hi != nil {
+		t.Errorf("untrained model has ranges %v, %v", lo, hi)
+	}
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	lo, hi := gbm.FeatureRanges()
+	if !slices.Equal(lo, []float64{0, -2}) || !slices.Equal(hi, []float64{3, 5}) {
+		t.Errorf("FeatureRanges() = %v, %v, want [0 -2], [3 5]", lo, hi)
+	}
+
+	tests := []struct {
+		x    []float64
+		want []int
+	}{
+		{[]float64{0, 5}, nil},
+		{[]float64{1.5, 2}, nil},
+		{[]float64{-0.1, 2}, []int{0}},
+		{[]float64{1, 6}, []int{1}},
+		{[]float64{math.NaN(), math.Inf(-1)}, []int{0, 1}},
+	}
+	for _, tt := range tests {
+		if got := gbm.OutOfRange(tt.x); !slices.Equal(got, tt.want) {
+			t.Errorf("OutOfRange(%v) = %v, want %v", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestClipToRangeKeepsPredictions(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	gbm := New(DefaultConfig())
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	x := []float64{-100, 1e6}
+	clipped := gbm.ClipToRange(x)
+	lo, hi := gbm.FeatureRanges()
+	if clipped[0] != lo[0] || clipped[1] != hi[1] {
+		t.Errorf("ClipToRange(%v) = %v, want [%v %v]", x, clipped, lo[0], hi[1])
+	}
+	if x[0] != -100 {
+		t.Error("ClipToRange modified its input")
+	}
+	if gbm.OutOfRange(clipped) != nil {
+		t.Errorf("clipped input %v is still out of range", clipped)
+	}
+	if got, want := gbm.PredictSingle(clipped), gbm.PredictSingle(x); got != want {
+		t.Errorf("clipping changed the prediction from %v to %v", want, got)
+	}
+}
+
+func TestSaveLoadPreservesFeatureRanges(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	gbm := New(DefaultConfig())
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := gbm.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lo, hi := gbm.FeatureRanges()
+	gotLo, gotHi := loaded.FeatureRanges()
+	if !slices.Equal(gotLo, lo) || !slices.Equal(gotHi, hi) {
+		t.Errorf("loaded ranges %v, %v, want %v, %v", gotLo, gotHi, lo, hi)
+	}
+}
+
+func TestFeatureRangesIgnoreNaN(t *testing.T) {
+	X := [][]float64{{math.NaN(), 1}, {2, math.NaN()}, {-1, 3}, {math.NaN(), 2}}
+	y := []float64{1, 2, 3, 4}
+	gbm := New(DefaultConfig())
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	lo, hi := gbm.FeatureRanges()
+	if !slices.Equal(lo, []float64{-1, 1}) || !slices.Equal(hi, []float64{2, 3}) {
+		t.Errorf("ranges %v, %v, want [-1 1], [2 3]", lo, hi)
+	}
+	if err := gbm.Save(filepath.Join(t.TempDir(), "model.json")); err != nil {
+		t.Errorf("Save after training on NaN: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package gboost
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ReasonCode explains one feature's part in a single prediction.
+type ReasonCode struct {
+	FeatureIndex int
+	Feature      string  // Name from [GBM.FeatureNames], or "f<j>".
+	Value        float64 // The sample's value for the feature.
+
+	// Contribution is the feature's SHAP value: how far it moved the raw
+	// prediction (log-odds for logloss) from [GBM.BaseValue]. Positive values
+	// push toward a higher prediction or class 1.
+	Contribution float64
+}
+
+// String formats the reason as "name = value (+contribution)".
+func (r ReasonCode) String() string {
+	return fmt.Sprintf("%s = %g (%+.4g)", r.Feature, r.Value, r.Contribution)
+}
+
+// ReasonCodes returns the k features that contributed most to the prediction
+// for x, ranked by the magnitude of their SHAP value with the sign kept.
+// Features with no contribution are never listed, so fewer than k codes may
+// be returned; k <= 0 returns every contributing feature.
+//
+// For adverse action notices, filter on the sign of Contribution: with a
+// model predicting default risk, the positive contributions are the reasons
+// an applicant was declined.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or
+// [ErrFeatureCountMismatch] if len(x) does not match the number of features.
+func (g *GBM) ReasonCodes(x []float64, k int) ([]ReasonCode, error) {
+	phi, err := g.ShapValuesSingle(x)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]ReasonCode, 0, len(phi))
+	for j, c := range phi {
+		if c == 0 {
+			continue
+		}
+		codes = append(codes, ReasonCode{
+			FeatureIndex: j,
+			Feature:      g.featureName(j),
+			Value:        x[j],
+			Contribution: c,
+		})
+	}
+	slices.SortStableFunc(codes, func(a, b ReasonCode) int {
+		return cmp.Compare(math.Abs(b.Contribution), math.Abs(a.Contribution))
+	})
+
+	if k > 0 && k < len(codes) {
+		codes = codes[:k]
+	}
+	return codes, nil
+}
@@ -0,0 +1,80 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReasonCodesRankedByMagnitude(t *testing.T) {
+	// y depends strongly on x1 and weakly on x2.
+	X, y := generateDataWithFunc(func(x1, x2 float64) float64 { return 10*x1 + x2 })
+	cfg := DefaultConfig()
+	cfg.NEstimators = 30
+	cfg.MaxDepth = 3
+	gbm := New(cfg)
+	if err := gbm.SetFeatureNames([]string{"income", "age"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	x := []float64{0.95, 0.5}
+	codes, err := gbm.ReasonCodes(x, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) == 0 {
+		t.Fatal("expected at least one reason code")
+	}
+
+	first := codes[0]
+	if first.FeatureIndex != 0 || first.Feature != "income" || first.Value != 0.95 {
+		t.Errorf("top reason = %+v, want income = 0.95", first)
+	}
+	if first.Contribution <= 0 {
+		t.Errorf("high income should raise the prediction, got %v", first.Contribution)
+	}
+	for i := 1; i < len(codes); i++ {
+		if math.Abs(codes[i].Contribution) > math.Abs(codes[i-1].Contribution) {
+			t.Errorf("codes not ranked by magnitude: %v", codes)
+		}
+	}
+
+	// The codes are SHAP values, so all of them sum back to the prediction.
+	all, _ := gbm.ReasonCodes(x, 0)
+	total := gbm.BaseValue()
+	for _, c := range all {
+		total += c.Contribution
+	}
+	if math.Abs(total-gbm.PredictSingle(x)) > 1e-9 {
+		t.Errorf("contributions sum to %v, prediction is %v", total, gbm.PredictSingle(x))
+	}
+}
+
+func TestReasonCodesLimitAndString(t *testing.T) {
+	gbm := fitStumpModel(t)
+
+	codes, err := gbm.ReasonCodes([]float64{4}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 1 {
+		t.Fatalf("expected 1 code for a single-feature model, got %d", len(codes))
+	}
+	if got := codes[0].String(); !strings.HasPrefix(got, "f0 = 4 (") {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestReasonCodesErrors(t *testing.T) {
+	if _, err := New(DefaultConfig()).ReasonCodes([]float64{1}, 1); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+	gbm := fitStumpModel(t)
+	if _, err := gbm.ReasonCodes([]float64{1, 2}, 1); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// RedundantFeatures describes feature columns that add no information to a model:
+// columns with a single value and columns that repeat an earlier column.
+type RedundantFeatures struct {
+	// Constant lists the features whose values are all equal (within tolerance).
+	Constant []int
+
+	// Duplicates maps each duplicated feature to the earlier feature it repeats.
+	Duplicates map[int]int
+}
+
+// Indices returns every redundant feature index in ascending order.
+func (r RedundantFeatures) Indices() []int {
+	res := make([]int, 0, len(r.Constant)+len(r.Duplicates))
+	res = append(res, r.Constant...)
+	for j := range r.Duplicates {
+		res = append(res, j)
+	}
+	slices.Sort(res)
+	return res
+}
+
+// FindRedundantFeatures scans the columns of X for constant features and for
+// features duplicating an earlier column. Two values are considered equal when
+// they differ by at most tol, so tol=0 finds exact matches and a small positive
+// tol also catches near duplicates (e.g. the same measurement rounded differently).
+// Of each group of duplicates, the lowest feature index is kept.
+func FindRedundantFeatures(X [][]float64, tol float64) RedundantFeatures {
+	res := RedundantFeatures{
+		Constant:   []int{},
+		Duplicates: make(map[int]int),
+	}
+	if len(X) == 0 {
+		return res
+	}
+
+	numFeatures := len(X[0])
+	kept := []int{} // features that are neither constant nor duplicates
+
+	for j := 0; j < numFeatures; j++ {
+		if isConstantColumn(X, j, tol) {
+			res.Constant = append(res.Constant, j)
+			continue
+		}
+
+		duplicate := false
+		for _, k := range kept {
+			if columnsEqual(X, j, k, tol) {
+				res.Duplicates[j] = k
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			kept = append(kept, j)
+		}
+	}
+	return res
+}
+
+// FindRedundantFeatures reports the constant and duplicated feature columns of
+// the dataset. See [FindRedundantFeatures] for the meaning of tol.
+func (ds *Dataset) FindRedundantFeatures(tol float64) RedundantFeatures {
+	return FindRedundantFeatures(ds.X, tol)
+}
+
+// DropFeatures removes the given feature columns from the dataset in place.
+// Encodings and FeatureNames are re-indexed to match the remaining columns.
+// Header is left untouched since it describes the original CSV file.
+func (ds *Dataset) DropFeatures(indices []int) error {
+	if len(ds.X) == 0 {
+		return ErrEmptyDataset
+	}
+
+	numFeatures := len(ds.X[0])
+	drop := make([]bool, numFeatures)
+	nDrop := 0
+	for _, j := range indices {
+		if j < 0 || j >= numFeatures {
+			return fmt.Errorf("feature index %d out of range for %d features", j, numFeatures)
+		}
+		if !drop[j] {
+			drop[j] = true
+			nDrop++
+		}
+	}
+	if nDrop == numFeatures {
+		return ErrEmptyFeatures
+	}
+
+	for i, row := range ds.X {
+		kept := make([]float64, 0, numFeatures-nDrop)
+		for j, v := range row {
+			if !drop[j] {
+				kept = append(kept, v)
+			}
+		}
+		ds.X[i] = kept
+	}
+
+	encodings := make(map[int]map[string]float64, len(ds.Encodings))
+	var names []string
+	newIdx := 0
+	for j := 0; j < numFeatures; j++ {
+		if drop[j] {
+			continue
+		}
+		if enc, ok := ds.Encodings[j]; ok {
+			encodings[newIdx] = enc
+		}
+		if ds.FeatureNames != nil {
+			names = append(names, ds.FeatureNames[j])
+		}
+		newIdx++
+	}
+	ds.Encodings = encodings
+	ds.FeatureNames = names
+
+	return nil
+}
+
+// DropRedundantFeatures finds the dataset's redundant features with the given
+// tolerance, removes them, and returns the indices that were dropped (relative
+// to the columns before the call).
+func (ds *Dataset) DropRedundantFeatures(tol float64) ([]int, error) {
+	dropped := ds.FindRedundantFeatures(tol).Indices()
+	if len(dropped) == 0 {
+		return dropped, nil
+	}
+	if err := ds.DropFeatures(dropped); err != nil {
+		return nil, err
+	}
+	return dropped, nil
+}
+
+// nonRedundantFeatures returns the feature indices of X that are neither
+// constant nor exact duplicates of an earlier column.
+func nonRedundantFeatures(X [][]float64) []int {
+	redundant := FindRedundantFeatures(X, 0).Indices()
+	features := make([]int, 0, len(X[0])-len(redundant))
+	for j := range len(X[0]) {
+		if !slices.Contains(redundant, j) {
+			features = append(features, j)
+		}
+	}
+	return features
+}
+
+func isConstantColumn(X [][]float64, j int, tol float64) bool {
+	lo, hi := X[0][j], X[0][j]
+	for _, row := range X {
+		lo = min(lo, row[j])
+		hi = max(hi, row[j])
+		if hi-lo > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func columnsEqual(X [][]float64, a, b int, tol float64) bool {
+	for _, row := range X {
+		if math.Abs(row[a]-row[b]) > tol {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package gboost
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestFindRedundantFeaturesConstant(t *testing.T) {
+	X := [][]float64{
+		{1, 7, 3},
+		{2, 7, 3},
+		{3, 7, 3},
+	}
+
+	r := FindRedundantFeatures(X, 0)
+
+	if !slices.Equal(r.Constant, []int{1, 2}) {
+		t.Errorf("Constant = %v, want [1 2]", r.Constant)
+	}
+	if len(r.Duplicates) != 0 {
+		t.Errorf("Duplicates = %v, want none", r.Duplicates)
+	}
+}
+
+func TestFindRedundantFeaturesExactDuplicates(t *testing.T) {
+	X := [][]float64{
+		{1, 5, 1, 5},
+		{2, 6, 2, 6},
+		{3, 4, 3, 4},
+	}
+
+	r := FindRedundantFeatures(X, 0)
+
+	if len(r.Constant) != 0 {
+		t.Errorf("Constant = %v, want none", r.Constant)
+	}
+	if len(r.Duplicates) != 2 || r.Duplicates[2] != 0 || r.Duplicates[3] != 1 {
+		t.Errorf("Duplicates = %v, want map[2:0 3:1]", r.Duplicates)
+	}
+	if !slices.Equal(r.Indices(), []int{2, 3}) {
+		t.Errorf("Indices() = %v, want [2 3]", r.Indices())
+	}
+}
+
+func TestFindRedundantFeaturesNearDuplicates(t *testing.T) {
+	X := [][]float64{
+		{1.00, 1.001},
+		{2.00, 1.999},
+		{3.00, 3.000},
+	}
+
+	if r := FindRedundantFeatures(X, 0); len(r.Duplicates) != 0 {
+		t.Errorf("tol=0: Duplicates = %v, want none", r.Duplicates)
+	}
+	if r := FindRedundantFeatures(X, 0.01); r.Duplicates[1] != 0 {
+		t.Errorf("tol=0.01: Duplicates = %v, want map[1:0]", r.Duplicates)
+	}
+}
+
+func TestFindRedundantFeaturesDuplicateOfConstantIsConstant(t *testing.T) {
+	// Two identical constant columns are both reported as constant, not as duplicates.
+	X := [][]float64{
+		{4, 4, 1},
+		{4, 4, 2},
+	}
+
+	r := FindRedundantFeatures(X, 0)
+
+	if !slices.Equal(r.Constant, []int{0, 1}) {
+		t.Errorf("Constant = %v, want [0 1]", r.Constant)
+	}
+	if len(r.Duplicates) != 0 {
+		t.Errorf("Duplicates = %v, want none", r.Duplicates)
+	}
+}
+
+func TestFindRedundantFeaturesEmpty(t *testing.T) {
+	r := FindRedundantFeatures(nil, 0)
+	if len(r.Indices()) != 0 {
+		t.Errorf("Indices() = %v, want empty", r.Indices())
+	}
+}
+
+func TestDatasetDropFeatures(t *testing.T) {
+	ds := &Dataset{
+		X: [][]float64{
+			{1, 0, 10},
+			{2, 1, 20},
+		},
+		Y:            []float64{0, 1},
+		Encodings:    map[int]map[string]float64{1: {"a": 0, "b": 1}, 2: {"x": 10, "y": 20}},
+		FeatureNames: []string{"f0", "f1", "f2"},
+	}
+
+	if err := ds.DropFeatures([]int{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(ds.X[0], []float64{1, 10}) || !slices.Equal(ds.X[1], []float64{2, 20}) {
+		t.Errorf("X = %v, want [[1 10] [2 20]]", ds.X)
+	}
+	if !slices.Equal(ds.FeatureNames, []string{"f0", "f2"}) {
+		t.Errorf("FeatureNames = %v, want [f0 f2]", ds.FeatureNames)
+	}
+	if len(ds.Encodings) != 1 || ds.Encodings[1]["y"] != 20 {
+		t.Errorf("Encodings = %v, want feature 2's encoding re-keyed to 1", ds.Encodings)
+	}
+}
+
+func TestDatasetDropFeaturesErrors(t *testing.T) {
+	ds := &Dataset{X: [][]float64{{1, 2}}, Y: []float64{0}}
+
+	if err := ds.DropFeatures([]int{2}); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+	if err := ds.DropFeatures([]int{0, 1}); !errors.Is(err, ErrEmptyFeatures) {
+		t.Errorf("expected ErrEmptyFeatures, got %v", err)
+	}
+	if err := (&Dataset{}).DropFeatures([]int{0}); !errors.Is(err, ErrEmptyDataset) {
+		t.Errorf("expected ErrEmptyDataset, got %v", err)
+	}
+}
+
+func TestDatasetDropRedundantFeatures(t *testing.T) {
+	path := writeTestCSV(t, "redundant.csv", `a,const,a_copy,b,target
+1,9,1,5,0
+2,9,2,3,1
+3,9,3,4,0
+`)
+	ds, err := LoadCSV(path, -1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dropped, err := ds.DropRedundantFeatures(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(dropped, []int{1, 2}) {
+		t.Errorf("dropped = %v, want [1 2]", dropped)
+	}
+	if !slices.Equal(ds.FeatureNames, []string{"a", "b"}) {
+		t.Errorf("FeatureNames = %v, want [a b]", ds.FeatureNames)
+	}
+	if len(ds.X[0]) != 2 {
+		t.Errorf("expected 2 features after drop, got %d", len(ds.X[0]))
+	}
+}
+
+func TestFitDropRedundantFeatures(t *testing.T) {
+	// Feature 1 is constant and feature 2 duplicates feature 0.
+	X := [][]float64{}
+	y := []float64{}
+	for i := range 20 {
+		v := float64(i)
+		X = append(X, []float64{v, 1, v})
+		y = append(y, 2*v)
+	}
+
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	cfg.DropRedundantFeatures = true
+
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	importance := gbm.FeatureImportance()
+	if len(importance) != 3 {
+		t.Fatalf("expected importance for all 3 input features, got %d", len(importance))
+	}
+	if importance[1] != 0 || importance[2] != 0 {
+		t.Errorf("redundant features should have zero importance, got %v", importance)
+	}
+
+	// Prediction still takes the full-width input.
+	if pred := gbm.PredictSingle([]float64{10, 1, 10}); pred < 15 || pred > 25 {
+		t.Errorf("PredictSingle = %v, want close to 20", pred)
+	}
+}
+
+func TestNonRedundantFeatures(t *testing.T) {
+	X := [][]float64{
+		{1, 0, 1, 5},
+		{2, 0, 2, 6},
+	}
+	if got := nonRedundantFeatures(X); !slices.Equal(got, []int{0, 3}) {
+		t.Errorf("nonRedundantFeatures = %v, want [0 3]", got)
+	}
+}
@@ -0,0 +1,203 @@
+package gboost
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RegistryReport compares the models saved in a directory, typically the
+// successive retrains of one model, so that a team can track how the
+// relevance of each feature evolves. See [LoadRegistryReport].
+type RegistryReport struct {
+	// Importance is the [ImportanceType] the report was built with.
+	Importance ImportanceType
+
+	// Features holds every feature name found in the models, in order of
+	// first appearance.
+	Features []string
+
+	// Models holds one entry per model, ordered by file name, so that files
+	// named by training date, such as model-2026-01-05.json, appear in
+	// training order.
+	Models []RegistryModel
+
+	// Summary holds one entry per feature, ordered by decreasing mean
+	// importance.
+	Summary []FeatureTrend
+
+	// Skipped lists the JSON files in the directory that are not saved
+	// models, such as pipelines or fold files.
+	Skipped []string
+}
+
+// RegistryModel describes one saved model of a [RegistryReport].
+type RegistryModel struct {
+	// File is the model's file name within the directory, and ModTime its
+	// modification time.
+	File    string
+	ModTime time.Time
+
+	// Config is the configuration the model was trained with, and NumTrees
+	// the number of trees it kept.
+	Config   Config
+	NumTrees int
+
+	// Importance maps each of the model's feature names to its importance,
+	// normalized to sum to 1.0 within the model. Features the model was not
+	// trained on are absent.
+	Importance map[string]float64
+}
+
+// FeatureTrend aggregates one feature's importance over the models of a
+// [RegistryReport] that were trained on it.
+type FeatureTrend struct {
+	Name   string
+	Models int // number of models trained on the feature
+
+	Mean float64
+	Std  float64
+	Min  float64
+	Max  float64
+
+	// First and Last are the feature's importance in the first and last
+	// models, by file name, that were trained on it.
+	First float64
+	Last  float64
+}
+
+// LoadRegistryReport loads every model saved with [GBM.Save] in dir, as
+// files ending in .json, and reports their feature importance of type t
+// and configurations side by side. Features are matched by name, so models
+// trained on different feature sets can be compared; models without names
+// use f0, f1, ....
+//
+// Returns [ErrNoModels] if dir holds no saved model, an error naming the
+// file if one cannot be read, or an error if t is not a known
+// [ImportanceType].
+func LoadRegistryReport(dir string, t ImportanceType) (*RegistryReport, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+
+	r := &RegistryReport{Importance: t}
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		var exported ExportedModel
+		var typeErr *json.UnmarshalTypeError
+		err := readJSON(path, &exported)
+		if err != nil && !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		if err != nil || exported.NumFeatures == 0 {
+			r.Skipped = append(r.Skipped, filepath.Base(path))
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		model := fromExported(&exported)
+		importance, err := model.FeatureImportanceByType(t)
+		if err != nil {
+			return nil, err
+		}
+		m := RegistryModel{
+			File:       filepath.Base(path),
+			ModTime:    info.ModTime(),
+			Config:     model.Config,
+			NumTrees:   model.NumTrees(),
+			Importance: make(map[string]float64, len(importance)),
+		}
+		for j, name := range model.names() {
+			m.Importance[name] = importance[j]
+			if !seen[name] {
+				seen[name] = true
+				r.Features = append(r.Features, name)
+			}
+		}
+		r.Models = append(r.Models, m)
+	}
+	if len(r.Models) == 0 {
+		return nil, ErrNoModels
+	}
+
+	for _, name := range r.Features {
+		var values []float64
+		for _, m := range r.Models {
+			if v, ok := m.Importance[name]; ok {
+				values = append(values, v)
+			}
+		}
+		r.Summary = append(r.Summary, FeatureTrend{
+			Name:   name,
+			Models: len(values),
+			Mean:   mean(values),
+			Std:    math.Sqrt(variance(values)),
+			Min:    slices.Min(values),
+			Max:    slices.Max(values),
+			First:  values[0],
+			Last:   values[len(values)-1],
+		})
+	}
+	slices.SortStableFunc(r.Summary, func(a, b FeatureTrend) int { return cmp.Compare(b.Mean, a.Mean) })
+	return r, nil
+}
+
+// WriteCSV writes one line per model under the header
+//
+//	file,modified,loss,n_estimators,learning_rate,max_depth,num_trees,<feature>...
+//
+// with the importance columns in the order of [RegistryReport.Features] and
+// an empty cell where a model was not trained on a feature. Times are
+// RFC 3339.
+func (r *RegistryReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := append([]string{"file", "modified", "loss", "n_estimators", "learning_rate", "max_depth", "num_trees"}, r.Features...)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, 0, len(header))
+	for _, m := range r.Models {
+		record = append(record[:0],
+			m.File,
+			m.ModTime.Format(time.RFC3339),
+			m.Config.Loss,
+			strconv.Itoa(m.Config.NEstimators),
+			formatCSVFloat(m.Config.LearningRate),
+			strconv.Itoa(m.Config.MaxDepth),
+			strconv.Itoa(m.NumTrees),
+		)
+		for _, name := range r.Features {
+			v, ok := m.Importance[name]
+			if !ok {
+				v = math.NaN()
+			}
+			record = append(record, formatCSVFloat(v))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the whole report, including each model's full
+// configuration, as one indented JSON document.
+func (r *RegistryReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,99 @@
+package gboost
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadRegistryReport(t *testing.T) {
+	dir := t.TempDir()
+	X, y := generateDataWithFunc(linearFunc)
+	for i, names := range [][]string{{"a", "b"}, {"a", "c"}} {
+		cfg := DefaultConfig()
+		cfg.NEstimators = 10 * (i + 1)
+		model := New(cfg)
+		if err := model.Fit(X, y); err != nil {
+			t.Fatal(err)
+		}
+		if err := model.SetFeatureNames(names); err != nil {
+			t.Fatal(err)
+		}
+		if err := model.Save(filepath.Join(dir, "model-"+string(rune('1'+i))+".json")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SaveFolds(filepath.Join(dir, "folds.json"), Fold{Train: []int{0}, Test: []int{1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := LoadRegistryReport(dir, ImportanceGain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Models) != 2 || r.Models[0].File != "model-1.json" || r.Models[1].Config.NEstimators != 20 {
+		t.Fatalf("models: %+v", r.Models)
+	}
+	if strings.Join(r.Features, ",") != "a,b,c" || strings.Join(r.Skipped, ",") != "folds.json" {
+		t.Errorf("features %v, skipped %v", r.Features, r.Skipped)
+	}
+	if _, ok := r.Models[0].Importance["c"]; ok {
+		t.Error("first model reports a feature it was not trained on")
+	}
+	if r.Summary[0].Mean < r.Summary[len(r.Summary)-1].Mean {
+		t.Errorf("summary not ordered by mean: %+v", r.Summary)
+	}
+	var a FeatureTrend
+	for _, f := range r.Summary {
+		if f.Name == "a" {
+			a = f
+		}
+	}
+	if a.Models != 2 || a.First != r.Models[0].Importance["a"] || a.Last != r.Models[1].Importance["a"] {
+		t.Errorf("summary of a: %+v", a)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[0] != "file,modified,loss,n_estimators,learning_rate,max_depth,num_trees,a,b,c" {
+		t.Fatalf("csv:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "model-1.json,") || !strings.HasSuffix(lines[1], ",") {
+		t.Errorf("first model row should leave c empty: %s", lines[1])
+	}
+
+	buf.Reset()
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded RegistryReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Models) != 2 || decoded.Models[1].Importance["c"] != r.Models[1].Importance["c"] {
+		t.Errorf("json round trip: %+v", decoded)
+	}
+}
+
+func TestLoadRegistryReportErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadRegistryReport(dir, ImportanceGain); !errors.Is(err, ErrNoModels) {
+		t.Errorf("empty directory: err = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRegistryReport(dir, ImportanceGain); err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("malformed file: err = %v", err)
+	}
+}
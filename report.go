@@ -0,0 +1,418 @@
+package gboost
+
+import (
+	"cmp"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// ModelReport is a model card: everything a governance review of a trained
+// model usually asks for, computed once by [Report] and rendered with
+// [ModelReport.WriteMarkdown] or [ModelReport.WriteHTML]. Its fields are
+// plain data, so it can also be fed to custom templates or encoders.
+type ModelReport struct {
+	Config      Config
+	NumTrees    int
+	NumFeatures int
+
+	// Data profiles the evaluation dataset; see [Describe].
+	Data *DatasetProfile
+
+	// Metrics holds each requested metric's value on the dataset, sorted by
+	// name.
+	Metrics []MetricValue
+
+	// Importance lists the features by decreasing gain importance.
+	Importance []FeatureImportanceValue
+
+	// Calibration compares predictions with outcomes in reportCalibrationBins
+	// bins of equal size, ordered by prediction.
+	Calibration []CalibrationBin
+
+	// PartialDependence holds the partial dependence curves of the most
+	// important features, in importance order.
+	PartialDependence []PartialDependence
+}
+
+// MetricValue is a named metric score.
+type MetricValue struct {
+	Name  string
+	Value float64
+}
+
+// FeatureImportanceValue is one feature's share of the model's gain importance.
+type FeatureImportanceValue struct {
+	Feature    int
+	Name       string
+	Importance float64
+}
+
+// CalibrationBin groups rows with similar predictions. For a well-calibrated
+// model MeanPredicted and MeanObserved agree in every bin. Predictions are
+// probabilities for classification and raw values for regression.
+type CalibrationBin struct {
+	Count         int
+	MeanPredicted float64
+	MeanObserved  float64
+}
+
+// PartialDependence is the model's average output as one feature is swept
+// over Grid, with every other feature left at its observed values.
+// Values[k] is the mean prediction with the feature set to Grid[k], in
+// probability for classification.
+type PartialDependence struct {
+	Feature int
+	Name    string
+	Grid    []float64
+	Values  []float64
+}
+
+const (
+	// reportCalibrationBins is the number of calibration bins in a report.
+	reportCalibrationBins = 10
+
+	// reportPDPFeatures and reportPDPGrid bound the partial dependence
+	// curves: the number of features and grid points per feature.
+	reportPDPFeatures = 5
+	reportPDPGrid     = 10
+
+	// reportPDPRows caps the rows averaged over for partial dependence,
+	// which otherwise costs reportPDPFeatures·reportPDPGrid passes over ds.
+	reportPDPRows = 500
+)
+
+// Report evaluates model on ds and collects a [ModelReport]: the training
+// configuration, a profile of ds, every metric in metrics, feature
+// importance, a calibration table, and partial dependence data for the most
+// important features. Metrics receive probabilities for classification, as
+// with [CrossValidate]; metrics may be nil. Features are named after the
+// model's feature names, then ds.FeatureNames.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained,
+// [ErrEmptyDataset] if ds has no rows, [ErrLengthMismatch] if ds.X and ds.Y
+// differ in length, [ErrFeatureCountMismatch] if rows of ds.X do not have
+// numFeatures columns, or [ErrNilMetric] if a metric is nil.
+func Report(model *GBM, ds *Dataset, metrics map[string]Metric) (*ModelReport, error) {
+	switch {
+	case !model.isFitted:
+		return nil, ErrModelNotFitted
+	case ds == nil || len(ds.X) == 0:
+		return nil, ErrEmptyDataset
+	case len(ds.X) != len(ds.Y):
+		return nil, ErrLengthMismatch
+	case len(ds.X[0]) != model.numFeatures || !hasSimilarLength(ds.X):
+		return nil, ErrFeatureCountMismatch
+	}
+	for _, m := range metrics {
+		if m == nil {
+			return nil, ErrNilMetric
+		}
+	}
+
+	profile, err := Describe(ds.X, ds.Y, DescribeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, model.numFeatures)
+	for j := range names {
+		switch {
+		case model.featureNames != nil:
+			names[j] = model.featureNames[j]
+		case len(ds.FeatureNames) == model.numFeatures:
+			names[j] = ds.FeatureNames[j]
+		default:
+			names[j] = model.featureName(j)
+		}
+		profile.Features[j].Name = names[j]
+	}
+	for k, l := range profile.Leakage {
+		profile.Leakage[k].Name = names[l.Feature]
+	}
+
+	pred := model.predictOutput(ds.X)
+	r := &ModelReport{
+		Config:      model.Config,
+		NumTrees:    model.NumTrees(),
+		NumFeatures: model.numFeatures,
+		Data:        profile,
+		Calibration: calibrationBins(ds.Y, pred, reportCalibrationBins),
+	}
+
+	for name, m := range metrics {
+		r.Metrics = append(r.Metrics, MetricValue{Name: name, Value: m(ds.Y, pred)})
+	}
+	slices.SortFunc(r.Metrics, func(a, b MetricValue) int { return cmp.Compare(a.Name, b.Name) })
+
+	for j, imp := range model.featureImportance {
+		r.Importance = append(r.Importance, FeatureImportanceValue{Feature: j, Name: names[j], Importance: imp})
+	}
+	slices.SortStableFunc(r.Importance, func(a, b FeatureImportanceValue) int {
+		return cmp.Compare(b.Importance, a.Importance)
+	})
+
+	rows := strideRows(ds.X, reportPDPRows)
+	for _, fi := range r.Importance[:min(reportPDPFeatures, len(r.Importance))] {
+		if fi.Importance <= 0 {
+			break
+		}
+		pd := model.partialDependence(rows, fi.Feature, reportPDPGrid)
+		pd.Name = fi.Name
+		r.PartialDependence = append(r.PartialDependence, pd)
+	}
+	return r, nil
+}
+
+// calibrationBins sorts rows by prediction and averages predictions and
+// targets in up to bins groups of near-equal size.
+func calibrationBins(y, pred []float64, bins int) []CalibrationBin {
+	order := make([]int, len(pred))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(pred[a], pred[b]) })
+
+	bins = min(bins, len(order))
+	res := make([]CalibrationBin, bins)
+	for b := range res {
+		lo, hi := b*len(order)/bins, (b+1)*len(order)/bins
+		var sp, sy float64
+		for _, i := range order[lo:hi] {
+			sp += pred[i]
+			sy += y[i]
+		}
+		n := float64(hi - lo)
+		res[b] = CalibrationBin{Count: hi - lo, MeanPredicted: sp / n, MeanObserved: sy / n}
+	}
+	return res
+}
+
+// strideRows returns at most k rows of X, evenly spaced.
+func strideRows(X [][]float64, k int) [][]float64 {
+	if len(X) <= k {
+		return X
+	}
+	res := make([][]float64, k)
+	for i := range res {
+		res[i] = X[i*len(X)/k]
+	}
+	return res
+}
+
+// partialDependence sweeps feature j of X over up to points quantiles of
+// its observed values and averages the model output at each.
+func (g *GBM) partialDependence(X [][]float64, j, points int) PartialDependence {
+	var values []float64
+	for _, row := range X {
+		if !math.IsNaN(row[j]) {
+			values = append(values, row[j])
+		}
+	}
+	slices.Sort(values)
+
+	var grid []float64
+	if len(values) > 0 {
+		for k := range points {
+			grid = append(grid, quantileSorted(values, float64(k)/float64(max(points-1, 1))))
+		}
+		grid = uniq(grid)
+	}
+
+	pd := PartialDependence{Feature: j, Grid: grid, Values: make([]float64, len(grid))}
+	swept := make([][]float64, len(X))
+	for i, row := range X {
+		swept[i] = slices.Clone(row)
+	}
+	for k, v := range grid {
+		for _, row := range swept {
+			row[j] = v
+		}
+		pd.Values[k] = mean(g.predictOutput(swept))
+	}
+	return pd
+}
+
+// WriteMarkdown renders the report as a Markdown model card.
+func (r *ModelReport) WriteMarkdown(w io.Writer) error {
+	return markdownReport.Execute(w, r)
+}
+
+// WriteHTML renders the report as a self-contained HTML page with inline
+// styles and no external resources.
+func (r *ModelReport) WriteHTML(w io.Writer) error {
+	return htmlReport.Execute(w, r)
+}
+
+// sparkline draws values as a string of block characters scaled between
+// their minimum and maximum. It accepts []float64 or []int.
+func sparkline(values any) string {
+	var vs []float64
+	switch v := values.(type) {
+	case []float64:
+		vs = v
+	case []int:
+		for _, n := range v {
+			vs = append(vs, float64(n))
+		}
+	}
+	const blocks = "▁▂▃▄▅▆▇█"
+	levels := []rune(blocks)
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range vs {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	var sb strings.Builder
+	for _, v := range vs {
+		k := 0
+		if hi > lo {
+			k = int(math.Round((v - lo) / (hi - lo) * float64(len(levels)-1)))
+		}
+		sb.WriteRune(levels[k])
+	}
+	return sb.String()
+}
+
+var reportFuncs = map[string]any{
+	"num":   func(v float64) string { return fmt.Sprintf("%.4g", v) },
+	"spark": sparkline,
+	"first": func(v []float64) float64 {
+		if len(v) == 0 {
+			return math.NaN()
+		}
+		return v[0]
+	},
+	"last": func(v []float64) float64 {
+		if len(v) == 0 {
+			return math.NaN()
+		}
+		return v[len(v)-1]
+	},
+}
+
+var markdownReport = template.Must(template.New("report.md").Funcs(reportFuncs).Parse(markdownReportTemplate))
+
+var htmlReport = htmltemplate.Must(htmltemplate.New("report.html").Funcs(reportFuncs).Parse(htmlReportTemplate))
+
+const markdownReportTemplate = `# Model Report
+
+## Model
+
+| Setting | Value |
+|---|---|
+| Loss | {{.Config.Loss}} |
+| Trees | {{.NumTrees}} of {{.Config.NEstimators}} |
+| Learning rate | {{num .Config.LearningRate}} |
+| Max depth | {{.Config.MaxDepth}} |
+| Min samples per leaf | {{.Config.MinSamplesLeaf}} |
+| Subsample ratio | {{num .Config.SubsampleRatio}} |
+| Features | {{.NumFeatures}} |
+| Seed | {{.Config.Seed}} |
+
+## Data
+
+{{.Data.Rows}} rows{{if .Data.Classes}}; class counts {{range $i, $c := .Data.Classes}}{{if $i}}, {{end}}{{$c}}: {{index $.Data.ClassCounts $i}}{{end}}{{end}}.
+
+| Feature | Missing | Mean | Std | Min | Max | Histogram |{{range .Data.Classes}} Mean (y={{.}}) |{{end}}
+|---|---|---|---|---|---|---|{{range .Data.Classes}}---|{{end}}
+{{range .Data.Features}}| {{.Name}} | {{.Missing}} | {{num .Mean}} | {{num .Std}} | {{num .Min}} | {{num .Max}} | {{spark .Histogram.Counts}} |{{range .ByClass}} {{num .Mean}} |{{end}}
+{{end}}{{if .Data.Leakage}}
+Possible target leakage, from single-feature models: {{range $i, $l := .Data.Leakage}}{{if $i}}, {{end}}{{$l.Name}} ({{$l.Metric}} {{num $l.Score}}){{end}}.
+{{end}}
+## Metrics
+{{if .Metrics}}
+| Metric | Value |
+|---|---|
+{{range .Metrics}}| {{.Name}} | {{num .Value}} |
+{{end}}{{else}}
+No metrics requested.
+{{end}}
+## Feature Importance
+
+| Feature | Importance |
+|---|---|
+{{range .Importance}}| {{.Name}} | {{num .Importance}} |
+{{end}}
+## Calibration
+
+| Bin | Rows | Mean predicted | Mean observed |
+|---|---|---|---|
+{{range $i, $b := .Calibration}}| {{$i}} | {{$b.Count}} | {{num $b.MeanPredicted}} | {{num $b.MeanObserved}} |
+{{end}}
+## Partial Dependence
+{{if .PartialDependence}}
+| Feature | Range | Curve | Output range |
+|---|---|---|---|
+{{range .PartialDependence}}| {{.Name}} | {{num (first .Grid)}} to {{num (last .Grid)}} | {{spark .Values}} | {{num (first .Values)}} to {{num (last .Values)}} |
+{{end}}{{else}}
+The model uses no features.
+{{end}}`
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Model Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.7em; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.spark { font-family: monospace; letter-spacing: 1px; }
+</style>
+</head>
+<body>
+<h1>Model Report</h1>
+
+<h2>Model</h2>
+<table>
+<tr><th>Setting</th><th>Value</th></tr>
+<tr><td>Loss</td><td>{{.Config.Loss}}</td></tr>
+<tr><td>Trees</td><td>{{.NumTrees}} of {{.Config.NEstimators}}</td></tr>
+<tr><td>Learning rate</td><td>{{num .Config.LearningRate}}</td></tr>
+<tr><td>Max depth</td><td>{{.Config.MaxDepth}}</td></tr>
+<tr><td>Min samples per leaf</td><td>{{.Config.MinSamplesLeaf}}</td></tr>
+<tr><td>Subsample ratio</td><td>{{num .Config.SubsampleRatio}}</td></tr>
+<tr><td>Features</td><td>{{.NumFeatures}}</td></tr>
+<tr><td>Seed</td><td>{{.Config.Seed}}</td></tr>
+</table>
+
+<h2>Data</h2>
+<p>{{.Data.Rows}} rows{{if .Data.Classes}}; class counts {{range $i, $c := .Data.Classes}}{{if $i}}, {{end}}{{$c}}: {{index $.Data.ClassCounts $i}}{{end}}{{end}}.</p>
+<table>
+<tr><th>Feature</th><th>Missing</th><th>Mean</th><th>Std</th><th>Min</th><th>Max</th><th>Histogram</th>{{range .Data.Classes}}<th>Mean (y={{.}})</th>{{end}}</tr>
+{{range .Data.Features}}<tr><td>{{.Name}}</td><td>{{.Missing}}</td><td>{{num .Mean}}</td><td>{{num .Std}}</td><td>{{num .Min}}</td><td>{{num .Max}}</td><td class="spark">{{spark .Histogram.Counts}}</td>{{range .ByClass}}<td>{{num .Mean}}</td>{{end}}</tr>
+{{end}}</table>
+{{if .Data.Leakage}}<p>Possible target leakage, from single-feature models: {{range $i, $l := .Data.Leakage}}{{if $i}}, {{end}}{{$l.Name}} ({{$l.Metric}} {{num $l.Score}}){{end}}.</p>
+{{end}}
+<h2>Metrics</h2>
+{{if .Metrics}}<table>
+<tr><th>Metric</th><th>Value</th></tr>
+{{range .Metrics}}<tr><td>{{.Name}}</td><td>{{num .Value}}</td></tr>
+{{end}}</table>
+{{else}}<p>No metrics requested.</p>
+{{end}}
+<h2>Feature Importance</h2>
+<table>
+<tr><th>Feature</th><th>Importance</th></tr>
+{{range .Importance}}<tr><td>{{.Name}}</td><td>{{num .Importance}}</td></tr>
+{{end}}</table>
+
+<h2>Calibration</h2>
+<table>
+<tr><th>Bin</th><th>Rows</th><th>Mean predicted</th><th>Mean observed</th></tr>
+{{range $i, $b := .Calibration}}<tr><td>{{$i}}</td><td>{{$b.Count}}</td><td>{{num $b.MeanPredicted}}</td><td>{{num $b.MeanObserved}}</td></tr>
+{{end}}</table>
+
+<h2>Partial Dependence</h2>
+{{if .PartialDependence}}<table>
+<tr><th>Feature</th><th>Range</th><th>Curve</th><th>Output range</th></tr>
+{{range .PartialDependence}}<tr><td>{{.Name}}</td><td>{{num (first .Grid)}} to {{num (last .Grid)}}</td><td class="spark">{{spark .Values}}</td><td>{{num (first .Values)}} to {{num (last .Values)}}</td></tr>
+{{end}}</table>
+{{else}}<p>The model uses no features.</p>
+{{end}}</body>
+</html>
+`
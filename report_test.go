@@ -0,0 +1,113 @@
+package gboost
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost/metrics"
+)
+
+func TestReportContents(t *testing.T) {
+	X, y := generateBinaryData(5)
+	gbm := fitBinaryModel(t, X, y)
+	ds := &Dataset{X: X, Y: y, FeatureNames: []string{"signal", "noise"}}
+
+	r, err := Report(gbm, ds, map[string]Metric{"logloss": metrics.LogLoss, "auc": metrics.AUC})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Metrics) != 2 || r.Metrics[0].Name != "auc" || r.Metrics[1].Name != "logloss" {
+		t.Fatalf("Metrics = %+v, want auc then logloss", r.Metrics)
+	}
+	if r.Metrics[0].Value < 0.95 {
+		t.Errorf("training AUC = %v, want >= 0.95", r.Metrics[0].Value)
+	}
+	if r.Importance[0].Name != "signal" {
+		t.Errorf("top feature = %q, want signal", r.Importance[0].Name)
+	}
+	if r.Data.Features[0].Name != "signal" || len(r.Data.Classes) != 2 {
+		t.Errorf("data profile names %q, classes %v", r.Data.Features[0].Name, r.Data.Classes)
+	}
+
+	total := 0
+	for i, b := range r.Calibration {
+		total += b.Count
+		if i > 0 && b.MeanPredicted < r.Calibration[i-1].MeanPredicted {
+			t.Errorf("calibration bins not ordered by prediction at bin %d", i)
+		}
+	}
+	if len(r.Calibration) != 10 || total != len(y) {
+		t.Errorf("%d calibration bins over %d rows, want 10 over %d", len(r.Calibration), total, len(y))
+	}
+
+	if len(r.PartialDependence) == 0 || r.PartialDependence[0].Feature != 0 {
+		t.Fatalf("PartialDependence = %+v, want the signal feature first", r.PartialDependence)
+	}
+	pd := r.PartialDependence[0]
+	if len(pd.Grid) != len(pd.Values) || pd.Values[0] > 0.5 || pd.Values[len(pd.Values)-1] < 0.5 {
+		t.Errorf("signal PDP = %v over %v, want rising across 0.5", pd.Values, pd.Grid)
+	}
+}
+
+func TestReportRendering(t *testing.T) {
+	X, y := generateBinaryData(5)
+	gbm := fitBinaryModel(t, X, y)
+	ds := &Dataset{X: X, Y: y, FeatureNames: []string{"<signal>", "noise"}}
+
+	r, err := Report(gbm, ds, map[string]Metric{"accuracy": metrics.Accuracy})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var md strings.Builder
+	if err := r.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Model Report", "| accuracy |", "| <signal> |", "## Calibration", "## Partial Dependence"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown report missing %q", want)
+		}
+	}
+
+	var html strings.Builder
+	if err := r.WriteHTML(&html); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "&lt;signal&gt;") || strings.Contains(html.String(), "<signal>") {
+		t.Error("HTML report does not escape feature names")
+	}
+	if !strings.Contains(html.String(), "<td>accuracy</td>") {
+		t.Error("HTML report missing the accuracy metric")
+	}
+}
+
+func TestReportErrors(t *testing.T) {
+	X, y := generateBinaryData(5)
+	ds := &Dataset{X: X, Y: y}
+
+	if _, err := Report(New(DefaultConfig()), ds, nil); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("unfitted model: err = %v, want ErrModelNotFitted", err)
+	}
+
+	gbm := fitBinaryModel(t, X, y)
+	if _, err := Report(gbm, &Dataset{}, nil); !errors.Is(err, ErrEmptyDataset) {
+		t.Errorf("empty dataset: err = %v, want ErrEmptyDataset", err)
+	}
+	if _, err := Report(gbm, &Dataset{X: [][]float64{{1}}, Y: []float64{0}}, nil); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("wrong width: err = %v, want ErrFeatureCountMismatch", err)
+	}
+	if _, err := Report(gbm, ds, map[string]Metric{"bad": nil}); !errors.Is(err, ErrNilMetric) {
+		t.Errorf("nil metric: err = %v, want ErrNilMetric", err)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]float64{0, 0.5, 1}); got != "▁▅█" {
+		t.Errorf("sparkline = %q, want ▁▅█", got)
+	}
+	if got := sparkline([]int{3, 3}); got != "▁▁" {
+		t.Errorf("flat sparkline = %q, want ▁▁", got)
+	}
+}
@@ -0,0 +1,39 @@
+package gboost
+
+import "math/rand"
+
+// Every random draw in the package, from row and column sampling to fold
+// assignment and shadow features, comes from a generator returned by newRNG
+// and seeded from a caller's seed, such as Config.Seed. The global math/rand
+// functions are never used, so results depend on the seeds alone and not on
+// what else runs in the process; TestRandomnessIsSeeded enforces this.
+
+// deriveSeed returns an independent seed for the given stream of a master seed
+// using the splitmix64 finalizer, so neighbouring streams are uncorrelated.
+func deriveSeed(master int64, stream int) int64 {
+	z := uint64(master) + uint64(stream+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// newRNG returns a generator for the stream of master named by path.
+// newRNG(seed) is seeded with seed itself, and each index of path selects a
+// sub-stream with deriveSeed, so newRNG(seed, i, j) draws from stream j of
+// stream i of seed, independently of every other stream.
+func newRNG(master int64, path ...int) *rand.Rand {
+	seed := master
+	for _, stream := range path {
+		seed = deriveSeed(seed, stream)
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// WithRNG returns a copy of c whose Seed is drawn from src, for callers that
+// manage randomness with a generator of their own, such as a simulation
+// seeded once at startup. Training is then as reproducible as src: the same
+// source state gives the same model.
+func (c Config) WithRNG(src rand.Source) Config {
+	c.Seed = src.Int63()
+	return c
+}
@@ -0,0 +1,110 @@
+package gboost
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestRandomnessIsSeeded keeps nondeterminism out of the library: no
+// package calls the global math/rand functions, and this package creates
+// generators only through newRNG. Commands are exempt.
+func TestRandomnessIsSeeded(t *testing.T) {
+	for _, dir := range []string{".", "datasets", "infer", "metrics", "mlflow", "serve"} {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range files {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			name := ""
+			for _, imp := range file.Imports {
+				if p, _ := strconv.Unquote(imp.Path.Value); p == "math/rand" || p == "math/rand/v2" {
+					name = filepath.Base(strings.TrimSuffix(p, "/v2"))
+					if imp.Name != nil {
+						name = imp.Name.Name
+					}
+				}
+			}
+			if name == "" {
+				continue
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != name {
+					return true
+				}
+				switch {
+				case dir == "." && path != "rng.go":
+					t.Errorf("%s calls rand.%s; use newRNG", path, sel.Sel.Name)
+				case sel.Sel.Name != "New" && sel.Sel.Name != "NewSource":
+					t.Errorf("%s calls the global rand.%s; use a seeded generator", path, sel.Sel.Name)
+				}
+				return true
+			})
+		}
+	}
+}
+
+func TestNewRNG(t *testing.T) {
+	if newRNG(7).Int63() != rand.New(rand.NewSource(7)).Int63() {
+		t.Error("newRNG(seed) does not draw from seed itself")
+	}
+	if newRNG(7, 2, 3).Int63() != rand.New(rand.NewSource(deriveSeed(deriveSeed(7, 2), 3))).Int63() {
+		t.Error("newRNG(seed, i, j) does not draw from stream j of stream i")
+	}
+	if newRNG(7, 0).Int63() == newRNG(7, 1).Int63() {
+		t.Error("sibling streams draw the same values")
+	}
+}
+
+func TestConfigWithRNG(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SubsampleRatio = 0.5
+	a := cfg.WithRNG(rand.NewSource(42))
+	b := cfg.WithRNG(rand.NewSource(42))
+	c := cfg.WithRNG(rand.NewSource(43))
+	if a.Seed != b.Seed || a.Seed == c.Seed || cfg.Seed != 0 {
+		t.Fatalf("seeds %d, %d, %d; original %d", a.Seed, b.Seed, c.Seed, cfg.Seed)
+	}
+
+	X, y := generateNoisyData()
+	fit := func(cfg Config) []float64 {
+		model := New(cfg)
+		if err := model.Fit(X, y); err != nil {
+			t.Fatal(err)
+		}
+		return model.Predict(X[:5])
+	}
+	pa, pb, pc := fit(a), fit(b), fit(c)
+	for i := range pa {
+		if pa[i] != pb[i] {
+			t.Fatalf("same source, different predictions: %v vs %v", pa, pb)
+		}
+	}
+	same := true
+	for i := range pa {
+		same = same && pa[i] == pc[i]
+	}
+	if same {
+		t.Error("different sources gave identical subsampled models")
+	}
+}
@@ -0,0 +1,166 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Condition bounds a single feature: Lower <= x[FeatureIndex] < Upper.
+// An unbounded side is -Inf or +Inf.
+type Condition struct {
+	FeatureIndex int
+	Lower        float64
+	Upper        float64
+}
+
+// Rule is an IF-THEN statement distilled from the ensemble: IF every condition
+// holds THEN the raw prediction moves by Effect relative to the model average.
+type Rule struct {
+	Conditions []Condition // sorted by FeatureIndex, one per feature
+
+	// Coverage is the fraction of training samples that satisfy the rule.
+	Coverage float64
+
+	// Effect is the rule's contribution to the raw prediction (log-odds for
+	// classification), summed over every tree that contains the same rule and
+	// measured relative to each tree's expected value.
+	Effect float64
+
+	// Trees is the number of trees the rule was found in.
+	Trees int
+}
+
+// Importance ranks rules: the magnitude of their effect weighted by how many
+// samples they apply to.
+func (r Rule) Importance() float64 {
+	return r.Coverage * math.Abs(r.Effect)
+}
+
+// String formats the rule with generic feature names (f0, f1, ...).
+func (r Rule) String() string {
+	return r.Format(nil)
+}
+
+// Format renders the rule as "IF ... THEN ..." text. names supplies feature
+// names by index; nil falls back to f0, f1, ...
+func (r Rule) Format(names []string) string {
+	parts := make([]string, 0, len(r.Conditions))
+	for _, c := range r.Conditions {
+		name := fmt.Sprintf("f%d", c.FeatureIndex)
+		if names != nil {
+			name = names[c.FeatureIndex]
+		}
+		switch {
+		case math.IsInf(c.Lower, -1):
+			parts = append(parts, fmt.Sprintf("%s < %g", name, c.Upper))
+		case math.IsInf(c.Upper, 1):
+			parts = append(parts, fmt.Sprintf("%s >= %g", name, c.Lower))
+		default:
+			parts = append(parts, fmt.Sprintf("%g <= %s < %g", c.Lower, name, c.Upper))
+		}
+	}
+	return fmt.Sprintf("IF %s THEN %+.4g (coverage %.1f%%)", strings.Join(parts, " AND "), r.Effect, 100*r.Coverage)
+}
+
+// ExtractRules distills the ensemble into at most maxRules human-readable
+// IF-THEN rules, ranked by [Rule.Importance]. Every root-to-leaf path in every
+// tree becomes a candidate rule; conditions on the same feature are merged
+// into a single interval, and paths describing the same region in different
+// trees are combined by summing their effects.
+//
+// The rules are a global surrogate, not an exact re-expression of the model:
+// a prediction is the sum over all trees, while each rule describes one
+// region's contribution. Use them to communicate what the model has learned.
+// Use [Rule.Format] with [GBM.FeatureNames] to render them with column names.
+//
+// Returns [ErrModelNotFitted] if the model has not been trained. A maxRules
+// of zero or less returns every rule.
+func (g *GBM) ExtractRules(maxRules int) ([]Rule, error) {
+	if !g.isFitted {
+		return nil, ErrModelNotFitted
+	}
+
+	byKey := make(map[string]*Rule)
+	var order []string
+	for _, tree := range g.trees {
+		if tree.isLeaf() || tree.NSamples == 0 {
+			continue
+		}
+		base := tree.expectedValue()
+		bounds := make(map[int]Condition)
+		collectRules(tree, bounds, func(leaf *Node, conds []Condition) {
+			key := fmt.Sprint(conds)
+			coverage := float64(leaf.NSamples) / float64(tree.NSamples)
+			effect := g.Config.LearningRate * (leaf.Value - base)
+
+			r, ok := byKey[key]
+			if !ok {
+				r = &Rule{Conditions: conds}
+				byKey[key] = r
+				order = append(order, key)
+			}
+			// Coverage can differ between trees under subsampling; keep the
+			// running mean so merged rules stay in [0, 1].
+			r.Coverage = (r.Coverage*float64(r.Trees) + coverage) / float64(r.Trees+1)
+			r.Effect += effect
+			r.Trees++
+		})
+	}
+
+	rules := make([]Rule, 0, len(order))
+	for _, key := range order {
+		rules = append(rules, *byKey[key])
+	}
+	slices.SortStableFunc(rules, func(a, b Rule) int {
+		switch {
+		case a.Importance() > b.Importance():
+			return -1
+		case a.Importance() < b.Importance():
+			return 1
+		}
+		return 0
+	})
+
+	if maxRules > 0 && maxRules < len(rules) {
+		rules = rules[:maxRules]
+	}
+	return rules, nil
+}
+
+// collectRules walks n, narrowing bounds along the way, and calls emit with
+// the sorted conditions of every leaf's path.
+func collectRules(n *Node, bounds map[int]Condition, emit func(leaf *Node, conds []Condition)) {
+	if n.isLeaf() {
+		conds := make([]Condition, 0, len(bounds))
+		for _, c := range bounds {
+			conds = append(conds, c)
+		}
+		slices.SortFunc(conds, func(a, b Condition) int { return a.FeatureIndex - b.FeatureIndex })
+		emit(n, conds)
+		return
+	}
+
+	prev, had := bounds[n.FeatureIndex]
+	c := prev
+	if !had {
+		c = Condition{FeatureIndex: n.FeatureIndex, Lower: math.Inf(-1), Upper: math.Inf(1)}
+	}
+
+	left := c
+	left.Upper = min(left.Upper, n.Threshold)
+	bounds[n.FeatureIndex] = left
+	collectRules(n.Left, bounds, emit)
+
+	right := c
+	right.Lower = max(right.Lower, n.Threshold)
+	bounds[n.FeatureIndex] = right
+	collectRules(n.Right, bounds, emit)
+
+	if had {
+		bounds[n.FeatureIndex] = prev
+	} else {
+		delete(bounds, n.FeatureIndex)
+	}
+}
@@ -0,0 +1,135 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestExtractRulesStump(t *testing.T) {
+	gbm := fitStumpModel(t)
+
+	rules, err := gbm.ExtractRules(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both stumps split on the same threshold, so their paths merge into
+	// one "low" and one "high" rule.
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d: %v", len(rules), rules)
+	}
+	var low, high Rule
+	for _, r := range rules {
+		if len(r.Conditions) != 1 || r.Conditions[0].FeatureIndex != 0 {
+			t.Fatalf("unexpected conditions %v", r.Conditions)
+		}
+		if math.IsInf(r.Conditions[0].Lower, -1) {
+			low = r
+		} else {
+			high = r
+		}
+	}
+
+	for _, r := range []Rule{low, high} {
+		if r.Trees != 2 {
+			t.Errorf("rule %v found in %d trees, want 2", r, r.Trees)
+		}
+		if math.Abs(r.Coverage-0.5) > 1e-9 {
+			t.Errorf("rule %v coverage = %v, want 0.5", r, r.Coverage)
+		}
+	}
+	if low.Effect >= 0 || high.Effect <= 0 {
+		t.Errorf("expected low rule to decrease and high rule to increase, got %v and %v", low.Effect, high.Effect)
+	}
+}
+
+func TestExtractRulesMergesConditionsOnSameFeature(t *testing.T) {
+	// A hand-built tree that splits twice on feature 0.
+	tree := &Node{
+		FeatureIndex: 0, Threshold: 5, NSamples: 4,
+		Left: &Node{
+			FeatureIndex: 0, Threshold: 2, NSamples: 2,
+			Left:  &Node{Value: -1, NSamples: 1},
+			Right: &Node{Value: 0, NSamples: 1},
+		},
+		Right: &Node{Value: 1, NSamples: 2},
+	}
+	gbm := New(DefaultConfig())
+	gbm.trees = []*Node{tree}
+	gbm.numFeatures = 1
+	gbm.isFitted = true
+
+	rules, err := gbm.ExtractRules(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 rules, got %d", len(rules))
+	}
+
+	var middle *Rule
+	for i, r := range rules {
+		if len(r.Conditions) != 1 {
+			t.Fatalf("expected conditions to merge into one interval, got %v", r.Conditions)
+		}
+		if c := r.Conditions[0]; c.Lower == 2 && c.Upper == 5 {
+			middle = &rules[i]
+		}
+	}
+	if middle == nil {
+		t.Fatalf("no rule for 2 <= f0 < 5 in %v", rules)
+	}
+	if got := middle.String(); !strings.HasPrefix(got, "IF 2 <= f0 < 5 THEN") {
+		t.Errorf("String() = %q", got)
+	}
+	if got := middle.Format([]string{"age"}); !strings.HasPrefix(got, "IF 2 <= age < 5 THEN") {
+		t.Errorf("Format() = %q", got)
+	}
+}
+
+func TestExtractRulesRankedAndLimited(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 20
+	cfg.MaxDepth = 3
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := gbm.ExtractRules(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Importance() > all[i-1].Importance() {
+			t.Fatalf("rules not ranked at %d: %v > %v", i, all[i].Importance(), all[i-1].Importance())
+		}
+	}
+	for _, r := range all {
+		if r.Coverage <= 0 || r.Coverage > 1 {
+			t.Errorf("coverage %v outside (0, 1]", r.Coverage)
+		}
+	}
+
+	top, err := gbm.ExtractRules(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 5 {
+		t.Fatalf("expected 5 rules, got %d", len(top))
+	}
+	for i := range top {
+		if top[i].String() != all[i].String() {
+			t.Errorf("rule %d: %q, want %q", i, top[i], all[i])
+		}
+	}
+}
+
+func TestExtractRulesNotFitted(t *testing.T) {
+	if _, err := New(DefaultConfig()).ExtractRules(10); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+}
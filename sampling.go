@@ -0,0 +1,193 @@
+package gboost
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// Row and column sampling for one boosting round.
+//
+// Rows are sampled with exactly one of three strategies: uniform subsampling
+// (SubsampleRatio, drawn as SamplingMethod says), gradient-based one-side
+// sampling (GOSSTopRate and GOSSOtherRate), or uniform subsampling followed
+// by negative downsampling (NegativeSampleRatio). GOSS is not combined with
+// the other two, since its reweighting assumes it sees every row. Sampled
+// rows carry a weight that scales their gradient and Hessian, so Newton leaf
+// values stay unbiased.
+//
+// Columns (ColsampleByTree) are drawn afterwards from a stream of their own,
+// so every row strategy sees the same columns in a given round, and column
+// sampling never changes the gradients or weights of the rows.
+
+// sampleRows picks the rows the round's tree is built on and their weights.
+// weights has one entry per row of y; only entries for the returned indices
+// are meaningful.
+func (g *GBM) sampleRows(rnd *rand.Rand, indices []int, y, gradients []float64) ([]int, []float64) {
+	weights := make([]float64, len(y))
+	for i := range weights {
+		weights[i] = 1
+	}
+
+	if g.Config.GOSSTopRate > 0 {
+		return g.sampleGOSS(rnd, indices, gradients, weights), weights
+	}
+	if g.Config.SamplingMethod == "bootstrap" {
+		indices = g.sampleBootstrap(rnd, indices, weights)
+	} else if r := g.Config.SubsampleRatio; r > 0 && r < 1.0 {
+		if g.Config.SamplingMethod == "bernoulli" {
+			indices = g.sampleBernoulli(rnd, indices)
+		} else {
+			indices = g.sampleIndices(rnd, indices)
+		}
+	}
+	if r := g.Config.NegativeSampleRatio; r > 0 && r < 1.0 {
+		indices = g.downsampleNegatives(rnd, indices, y, weights)
+	}
+	return indices, weights
+}
+
+func (g *GBM) sampleIndices(rnd *rand.Rand, indices []int) []int {
+	sampleRatio := g.Config.SubsampleRatio
+
+	n := len(indices)
+	sampleSize := int(float64(n) * sampleRatio)
+	shuffled := make([]int, n)
+	copy(shuffled, indices)
+	rnd.Shuffle(n, func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled[0:sampleSize]
+}
+
+// sampleBernoulli keeps each row independently with probability
+// SubsampleRatio, and at least one row.
+func (g *GBM) sampleBernoulli(rnd *rand.Rand, indices []int) []int {
+	kept := make([]int, 0, int(float64(len(indices))*g.Config.SubsampleRatio))
+	for _, i := range indices {
+		if rnd.Float64() < g.Config.SubsampleRatio {
+			kept = append(kept, i)
+		}
+	}
+	if len(kept) == 0 && len(indices) > 0 {
+		kept = append(kept, indices[rnd.Intn(len(indices))])
+	}
+	return kept
+}
+
+// sampleBootstrap draws SubsampleRatio·n rows with replacement and returns
+// the distinct rows drawn, sorted, with weights set to their draw counts.
+func (g *GBM) sampleBootstrap(rnd *rand.Rand, indices []int, weights []float64) []int {
+	draws := max(int(float64(len(indices))*g.Config.SubsampleRatio), 1)
+	counts := make(map[int]int, draws)
+	for range draws {
+		counts[indices[rnd.Intn(len(indices))]]++
+	}
+	kept := make([]int, 0, len(counts))
+	for i, c := range counts {
+		kept = append(kept, i)
+		weights[i] = float64(c)
+	}
+	slices.Sort(kept)
+	return kept
+}
+
+// honestSplit shuffles indices and holds out a fraction of them for leaf
+// estimation, keeping at least one row on each side. Both halves are sorted.
+func honestSplit(rnd *rand.Rand, indices []int, fraction float64) (structure, estimation []int) {
+	n := len(indices)
+	shuffled := slices.Clone(indices)
+	rnd.Shuffle(n, func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	k := min(max(int(math.Round(fraction*float64(n))), 1), n-1)
+	estimation, structure = shuffled[:k], shuffled[k:]
+	slices.Sort(structure)
+	slices.Sort(estimation)
+	return structure, estimation
+}
+
+// downsampleNegatives keeps every positive in indices and each negative with
+// probability NegativeSampleRatio, multiplying the weight of the kept
+// negatives by 1/NegativeSampleRatio to keep leaf values unbiased. If that
+// would keep nothing, it keeps one negative at random, since a tree grown on
+// no rows has leaf values of 0/0.
+func (g *GBM) downsampleNegatives(rnd *rand.Rand, indices []int, y, weights []float64) []int {
+	ratio := g.Config.NegativeSampleRatio
+	kept := make([]int, 0, len(indices))
+	for _, i := range indices {
+		if y[i] != 0 {
+			kept = append(kept, i)
+			continue
+		}
+		if rnd.Float64() < ratio {
+			weights[i] /= ratio
+			kept = append(kept, i)
+		}
+	}
+	if len(kept) == 0 && len(indices) > 0 {
+		i := indices[rnd.Intn(len(indices))]
+		weights[i] /= ratio
+		kept = append(kept, i)
+	}
+	return kept
+}
+
+// sampleGOSS implements gradient-based one-side sampling (Ke et al., 2017):
+// it keeps the GOSSTopRate fraction of rows with the largest |gradient| and a
+// random GOSSOtherRate fraction of the rest, which it weights by
+// (1 - GOSSTopRate) / GOSSOtherRate to stand in for the rows left out.
+func (g *GBM) sampleGOSS(rnd *rand.Rand, indices []int, gradients, weights []float64) []int {
+	a, b := g.Config.GOSSTopRate, g.Config.GOSSOtherRate
+	n := len(indices)
+
+	byGradient := slices.Clone(indices)
+	slices.SortStableFunc(byGradient, func(i, j int) int {
+		return cmp.Compare(math.Abs(gradients[j]), math.Abs(gradients[i]))
+	})
+
+	nTop := max(int(a*float64(n)), 1)
+	nOther := min(int(b*float64(n)), n-nTop)
+	rest := byGradient[nTop:]
+	rnd.Shuffle(len(rest), func(i, j int) { rest[i], rest[j] = rest[j], rest[i] })
+
+	kept := byGradient[:nTop+nOther]
+	amplify := (1 - a) / b
+	for _, i := range kept[nTop:] {
+		weights[i] = amplify
+	}
+	slices.Sort(kept)
+	return kept
+}
+
+// sampleColumns returns a sorted random ColsampleByTree fraction of features,
+// keeping at least one. An empty features, as when DropRedundantFeatures
+// removes every column, is returned unchanged.
+func (g *GBM) sampleColumns(rnd *rand.Rand, features []int) []int {
+	if len(features) == 0 {
+		return features
+	}
+	k := max(int(math.Round(g.Config.ColsampleByTree*float64(len(features)))), 1)
+	chosen := make([]int, k)
+	for i, p := range rnd.Perm(len(features))[:k] {
+		chosen[i] = features[p]
+	}
+	slices.Sort(chosen)
+	return chosen
+}
+
+// effectiveSampleSize returns Kish's effective sample size of the weighted
+// rows, (sum w)^2 / sum w^2. It equals len(indices) when all weights are 1
+// and shrinks as reweighting concentrates influence on fewer rows.
+func effectiveSampleSize(indices []int, weights []float64) float64 {
+	var sw, sw2 float64
+	for _, i := range indices {
+		sw += weights[i]
+		sw2 += weights[i] * weights[i]
+	}
+	if sw2 == 0 {
+		return 0
+	}
+	return sw * sw / sw2
+}
@@ -0,0 +1,318 @@
+package gboost
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost/metrics"
+)
+
+func TestSampleGOSS(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.GOSSTopRate = 0.2
+	cfg.GOSSOtherRate = 0.3
+	gbm := New(cfg)
+
+	// Row i has gradient ±i, so rows 80..99 have the largest magnitudes.
+	indices := make([]int, 100)
+	gradients := make([]float64, 100)
+	weights := make([]float64, 100)
+	for i := range indices {
+		indices[i] = i
+		gradients[i] = float64(i)
+		if i%2 == 1 {
+			gradients[i] = -gradients[i]
+		}
+		weights[i] = 1
+	}
+
+	kept := gbm.sampleGOSS(rand.New(rand.NewSource(1)), indices, gradients, weights)
+	if len(kept) != 50 {
+		t.Fatalf("kept %d rows, want 20 top + 30 others", len(kept))
+	}
+	if !slices.IsSorted(kept) {
+		t.Error("kept rows are not sorted")
+	}
+
+	amplify := (1 - 0.2) / 0.3
+	for _, i := range kept {
+		want := amplify
+		if i >= 80 {
+			want = 1
+		}
+		if math.Abs(weights[i]-want) > 1e-12 {
+			t.Errorf("row %d weight = %v, want %v", i, weights[i], want)
+		}
+	}
+	for i := 80; i < 100; i++ {
+		if !slices.Contains(kept, i) {
+			t.Errorf("top-gradient row %d was not kept", i)
+		}
+	}
+}
+
+func TestSampleColumns(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ColsampleByTree = 0.5
+	gbm := New(cfg)
+
+	features := []int{0, 2, 3, 5}
+	cols := gbm.sampleColumns(rand.New(rand.NewSource(3)), features)
+	if len(cols) != 2 {
+		t.Fatalf("sampled %d columns, want 2", len(cols))
+	}
+	for _, j := range cols {
+		if !slices.Contains(features, j) {
+			t.Errorf("sampled column %d not among %v", j, features)
+		}
+	}
+	if !slices.IsSorted(cols) {
+		t.Errorf("columns %v are not sorted", cols)
+	}
+
+	// A tiny fraction still keeps one column.
+	gbm.Config.ColsampleByTree = 0.01
+	if cols := gbm.sampleColumns(rand.New(rand.NewSource(3)), features); len(cols) != 1 {
+		t.Errorf("sampled %d columns, want 1", len(cols))
+	}
+
+	if cols := gbm.sampleColumns(rand.New(rand.NewSource(3)), nil); len(cols) != 0 {
+		t.Errorf("sampled %v from no columns", cols)
+	}
+}
+
+func TestColsampleAllConstantColumns(t *testing.T) {
+	// DropRedundantFeatures leaves no columns to sample from.
+	X := make([][]float64, 20)
+	y := make([]float64, 20)
+	for i := range X {
+		X[i] = []float64{1, 2}
+		y[i] = float64(i)
+	}
+	cfg := DefaultConfig()
+	cfg.NEstimators = 5
+	cfg.ColsampleByTree = 0.5
+	cfg.DropRedundantFeatures = true
+
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if pred := gbm.PredictSingle(X[0]); math.Abs(pred-9.5) > 1e-9 {
+		t.Errorf("PredictSingle = %v, want the mean 9.5", pred)
+	}
+
+	batches := make(chan Batch, 1)
+	batches <- Batch{X: X, Y: y}
+	close(batches)
+	if err := New(cfg).FitStream(batches); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDownsampleNegativesKeepsARow(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NegativeSampleRatio = 1e-6
+	gbm := New(cfg)
+
+	indices := make([]int, 50)
+	y := make([]float64, 50)
+	weights := make([]float64, 50)
+	for i := range indices {
+		indices[i] = i
+		weights[i] = 1
+	}
+	kept := gbm.downsampleNegatives(rand.New(rand.NewSource(1)), indices, y, weights)
+	if len(kept) != 1 {
+		t.Fatalf("kept %d rows of an all-negative sample, want 1", len(kept))
+	}
+	if w := weights[kept[0]]; w != 1e6 {
+		t.Errorf("kept negative has weight %v, want 1/NegativeSampleRatio", w)
+	}
+}
+
+func TestEffectiveSampleSize(t *testing.T) {
+	weights := []float64{1, 1, 1, 3, 3}
+	if ess := effectiveSampleSize([]int{0, 1, 2}, weights); ess != 3 {
+		t.Errorf("unweighted ESS = %v, want 3", ess)
+	}
+	// (1+1+1+3+3)^2 / (1+1+1+9+9) = 81/21
+	if ess := effectiveSampleSize([]int{0, 1, 2, 3, 4}, weights); math.Abs(ess-81.0/21) > 1e-12 {
+		t.Errorf("weighted ESS = %v, want %v", ess, 81.0/21)
+	}
+}
+
+// usedFeatures returns the features split on anywhere in the tree.
+func usedFeatures(n *Node) []int {
+	var used []int
+	walkSplits(n, func(n *Node) {
+		if !slices.Contains(used, n.FeatureIndex) {
+			used = append(used, n.FeatureIndex)
+		}
+	})
+	return used
+}
+
+func TestGOSSWithColumnSampling(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 200)
+	y := make([]float64, 200)
+	for i := range X {
+		X[i] = []float64{rnd.Float64(), rnd.Float64(), rnd.Float64(), rnd.Float64()}
+		y[i] = 3*X[i][0] + 2*X[i][1] + X[i][2]
+	}
+
+	base := DefaultConfig()
+	base.Seed = 5
+	base.NEstimators = 20
+	base.MaxDepth = 3
+	base.ColsampleByTree = 0.5
+
+	goss := base
+	goss.GOSSTopRate = 0.2
+	goss.GOSSOtherRate = 0.1
+
+	for name, cfg := range map[string]Config{"uniform": base, "goss": goss} {
+		t.Run(name, func(t *testing.T) {
+			gbm := New(cfg)
+			if err := gbm.Fit(X, y); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, tree := range gbm.trees {
+				// Columns come from their own stream, whatever the row strategy.
+				roundSeed := deriveSeed(cfg.Seed, i)
+				cols := gbm.sampleColumns(rand.New(rand.NewSource(deriveSeed(roundSeed, 0))), allFeatures(4))
+				for _, j := range usedFeatures(tree) {
+					if !slices.Contains(cols, j) {
+						t.Errorf("round %d split on feature %d outside its columns %v", i, j, cols)
+					}
+				}
+
+				h := gbm.History()[i]
+				if h.Features != 2 {
+					t.Errorf("round %d: Features = %d, want 2", i, h.Features)
+				}
+				if cfg.GOSSTopRate == 0 {
+					if h.SampleSize != 200 || h.EffectiveSampleSize != 200 {
+						t.Errorf("round %d: sample sizes (%d, %v), want (200, 200)", i, h.SampleSize, h.EffectiveSampleSize)
+					}
+					continue
+				}
+				if h.SampleSize != 60 {
+					t.Errorf("round %d: SampleSize = %d, want 40 top + 20 others", i, h.SampleSize)
+				}
+				if h.EffectiveSampleSize >= float64(h.SampleSize) {
+					t.Errorf("round %d: EffectiveSampleSize %v should be below SampleSize %d under reweighting", i, h.EffectiveSampleSize, h.SampleSize)
+				}
+			}
+
+			// Both strategies still explain most of the variance.
+			var sse float64
+			for i, p := range gbm.Predict(X) {
+				sse += (p - y[i]) * (p - y[i])
+			}
+			if mse := sse / float64(len(y)); mse > 0.25*variance(y) {
+				t.Errorf("training MSE = %v, want < %v", mse, 0.25*variance(y))
+			}
+		})
+	}
+}
+
+func TestHonestSplit(t *testing.T) {
+	indices := []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}
+	structure, estimation := honestSplit(rand.New(rand.NewSource(1)), indices, 0.3)
+	if len(structure) != 7 || len(estimation) != 3 {
+		t.Fatalf("split sizes (%d, %d), want (7, 3)", len(structure), len(estimation))
+	}
+	if !slices.IsSorted(structure) || !slices.IsSorted(estimation) {
+		t.Error("halves are not sorted")
+	}
+	if all := slices.Sorted(slices.Values(append(slices.Clone(structure), estimation...))); !slices.Equal(all, indices) {
+		t.Errorf("halves %v and %v do not partition %v", structure, estimation, indices)
+	}
+
+	// Both halves keep at least one row.
+	structure, estimation = honestSplit(rand.New(rand.NewSource(1)), indices[:2], 0.01)
+	if len(structure) != 1 || len(estimation) != 1 {
+		t.Errorf("split sizes (%d, %d), want (1, 1)", len(structure), len(estimation))
+	}
+}
+
+func TestSampleBernoulli(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SubsampleRatio = 0.3
+	cfg.SamplingMethod = "bernoulli"
+	gbm := New(cfg)
+
+	indices := allFeatures(1000)
+	kept := gbm.sampleBernoulli(rand.New(rand.NewSource(1)), indices)
+	if len(kept) < 250 || len(kept) > 350 {
+		t.Errorf("kept %d of 1000 rows, want about 300", len(kept))
+	}
+	if !slices.IsSorted(kept) {
+		t.Error("kept rows are not sorted")
+	}
+
+	// A round never ends up empty.
+	gbm.Config.SubsampleRatio = 1e-9
+	if kept := gbm.sampleBernoulli(rand.New(rand.NewSource(1)), indices[:5]); len(kept) != 1 {
+		t.Errorf("kept %d rows at a tiny ratio, want 1", len(kept))
+	}
+}
+
+func TestSampleBootstrap(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SamplingMethod = "bootstrap"
+	gbm := New(cfg)
+
+	indices := allFeatures(1000)
+	weights := make([]float64, 1000)
+	for i := range weights {
+		weights[i] = 1
+	}
+	kept := gbm.sampleBootstrap(rand.New(rand.NewSource(1)), indices, weights)
+	if !slices.IsSorted(kept) || len(slices.Compact(slices.Clone(kept))) != len(kept) {
+		t.Error("kept rows are not sorted and distinct")
+	}
+	// About 1 - 1/e of the rows are drawn at least once.
+	if len(kept) < 600 || len(kept) > 665 {
+		t.Errorf("kept %d distinct rows, want about 632", len(kept))
+	}
+	var draws float64
+	for _, i := range kept {
+		draws += weights[i]
+	}
+	if draws != 1000 {
+		t.Errorf("weights of kept rows sum to %v, want 1000 draws", draws)
+	}
+}
+
+func TestSamplingMethodsFit(t *testing.T) {
+	X, y := generateBinaryData(5)
+	for _, method := range []string{"shuffle", "bernoulli", "bootstrap"} {
+		t.Run(method, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Loss = "logloss"
+			cfg.NEstimators = 10
+			cfg.SubsampleRatio = 0.5
+			cfg.SamplingMethod = method
+			cfg.NegativeSampleRatio = 0.5
+			gbm := New(cfg)
+			if err := gbm.Fit(X, y); err != nil {
+				t.Fatal(err)
+			}
+			for _, h := range gbm.History() {
+				if h.SampleSize == 0 || h.SampleSize >= len(y) {
+					t.Errorf("round %d sampled %d of %d rows", h.Round, h.SampleSize, len(y))
+				}
+			}
+			if acc := metrics.Accuracy(y, gbm.PredictProbaAll(X)); acc < 0.9 {
+				t.Errorf("training accuracy %v, want >= 0.9", acc)
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+package gboost
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ColumnType is the type of a CSV column in a [CSVSchema].
+type ColumnType string
+
+const (
+	// ColumnNumeric columns are parsed as floats.
+	ColumnNumeric ColumnType = "numeric"
+
+	// ColumnCategorical columns are label-encoded.
+	ColumnCategorical ColumnType = "categorical"
+)
+
+// OtherCategory is the category that rare levels of a categorical column
+// are grouped into; see [ColumnSchema].
+const OtherCategory = "__other__"
+
+// CSVSchema describes the layout of a CSV file so that [LoadCSVWithSchema]
+// does not have to infer it. Unlike [LoadCSV], which label-encodes any
+// column containing a non-numeric value and rejects empty cells, loading
+// with a schema fails loudly when a numeric column holds text and maps
+// declared missing markers to NaN.
+type CSVSchema struct {
+	// HasHeader reports whether the first row holds column names. When set,
+	// the names must match Columns in order.
+	HasHeader bool `json:"has_header"`
+
+	// Columns describes every column of the file, in order.
+	Columns []ColumnSchema `json:"columns"`
+
+	// Target is the name of the target column.
+	Target string `json:"target"`
+
+	// MissingValues lists the cell values, compared after trimming
+	// whitespace, that mean "missing" and load as NaN in feature columns.
+	// The target may not be missing.
+	MissingValues []string `json:"missing_values,omitempty"`
+
+	// Drop lists the names of columns to skip entirely.
+	Drop []string `json:"drop,omitempty"`
+}
+
+// ColumnSchema describes one CSV column.
+type ColumnSchema struct {
+	Name string     `json:"name"`
+	Type ColumnType `json:"type"`
+
+	// Categories fixes the label encoding of a categorical column: category
+	// Categories[i] is encoded as i, and any other value is an error unless
+	// Categories includes [OtherCategory], which then absorbs it. When
+	// empty, categories are encoded in order of first appearance, as
+	// [LoadCSV] does. Keeping them fixed keeps encodings stable across
+	// files.
+	Categories []string `json:"categories,omitempty"`
+
+	// MaxCategories caps the number of distinct codes of a categorical
+	// feature column, so that ID-like and free-text columns cannot explode
+	// into thousands of levels. When a column has more, its
+	// MaxCategories-1 most frequent values, ties broken by first appearance,
+	// keep their own codes, and all others share [OtherCategory], encoded
+	// after them. Zero means no cap; otherwise it must be at least 2.
+	MaxCategories int `json:"max_categories,omitempty"`
+
+	// MinCount groups the values of a categorical feature column that occur
+	// in fewer than MinCount rows into [OtherCategory]. It applies before
+	// MaxCategories. Zero or 1 keeps every value.
+	MinCount int `json:"min_count,omitempty"`
+}
+
+// bucketed reports whether rare values of the column are grouped.
+func (c ColumnSchema) bucketed() bool {
+	return c.MaxCategories > 0 || c.MinCount > 1
+}
+
+// DecodeFile decodes the JSON file at path into v, or the YAML file if path
+// ends in .yaml or .yml. YAML is decoded generically and re-encoded as
+// JSON, so v's json tags are the single source of truth for key names in
+// both formats. Unknown keys are rejected so that typos do not silently
+// fall back to defaults.
+func DecodeFile(path string, v any) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yaml" || ext == ".yml" {
+		var generic map[string]any
+		if err := yaml.Unmarshal(raw, &generic); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		if raw, err = json.Marshal(generic); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadSchema reads a [CSVSchema] from a JSON file, or from a YAML file if
+// path ends in .yaml or .yml, with [DecodeFile].
+//
+// Returns an error wrapping [ErrInvalidSchema] if the schema is inconsistent.
+func LoadSchema(path string) (*CSVSchema, error) {
+	var schema CSVSchema
+	if err := DecodeFile(path, &schema); err != nil {
+		return nil, err
+	}
+	if err := schema.validate(); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (s *CSVSchema) validate() error {
+	if len(s.Columns) < 2 {
+		return fmt.Errorf("%w: need at least 2 columns, got %d", ErrInvalidSchema, len(s.Columns))
+	}
+	names := make(map[string]bool, len(s.Columns))
+	for i, c := range s.Columns {
+		switch {
+		case c.Name == "":
+			return fmt.Errorf("%w: column %d has no name", ErrInvalidSchema, i)
+		case names[c.Name]:
+			return fmt.Errorf("%w: duplicate column %q", ErrInvalidSchema, c.Name)
+		case c.Type != ColumnNumeric && c.Type != ColumnCategorical:
+			return fmt.Errorf("%w: column %q has type %q, want \"numeric\" or \"categorical\"", ErrInvalidSchema, c.Name, c.Type)
+		case c.Type == ColumnNumeric && len(c.Categories) > 0:
+			return fmt.Errorf("%w: numeric column %q lists categories", ErrInvalidSchema, c.Name)
+		case c.MaxCategories < 0 || c.MaxCategories == 1 || c.MinCount < 0:
+			return fmt.Errorf("%w: column %q: max_categories must be 0 or >= 2 and min_count >= 0", ErrInvalidSchema, c.Name)
+		case c.bucketed() && (c.Type != ColumnCategorical || len(c.Categories) > 0 || c.Name == s.Target):
+			return fmt.Errorf("%w: column %q: max_categories and min_count apply only to categorical feature columns without declared categories", ErrInvalidSchema, c.Name)
+		}
+		names[c.Name] = true
+	}
+	dropped := make(map[string]bool, len(s.Drop))
+	for _, name := range s.Drop {
+		if !names[name] {
+			return fmt.Errorf("%w: dropped column %q is not defined", ErrInvalidSchema, name)
+		}
+		dropped[name] = true
+	}
+	switch {
+	case !names[s.Target]:
+		return fmt.Errorf("%w: target column %q is not defined", ErrInvalidSchema, s.Target)
+	case dropped[s.Target]:
+		return fmt.Errorf("%w: target column %q is dropped", ErrInvalidSchema, s.Target)
+	case len(s.Columns)-len(dropped) < 2:
+		return fmt.Errorf("%w: no feature columns left after dropping", ErrInvalidSchema)
+	}
+	return nil
+}
+
+// LoadCSVWithSchema reads the CSV file at path as described by the schema
+// file at schemaPath (see [LoadSchema]). It is a shorthand for loading the
+// schema and calling [CSVSchema.LoadCSV].
+func LoadCSVWithSchema(path, schemaPath string) (*Dataset, error) {
+	schema, err := LoadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return schema.LoadCSV(path)
+}
+
+// LoadCSV reads the CSV file at path as described by s. Dataset.Header holds
+// every column name, including dropped ones, and Dataset.FeatureNames the
+// names of the feature columns; categorical columns have their encodings in
+// Dataset.Encodings and Dataset.TargetEncoding, as with [LoadCSV].
+//
+// Returns an error wrapping [ErrInvalidSchema] if s is inconsistent, or if
+// the file does not match it: a wrong column count or header, text in a
+// numeric column, an undeclared category, or a missing target.
+// Returns [ErrEmptyDataset] if the file has no data rows.
+func (s *CSVSchema) LoadCSV(path string) (*Dataset, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open csv: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1 // checked below against the schema
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+
+	nCols := len(s.Columns)
+	header := make([]string, nCols)
+	for j, c := range s.Columns {
+		header[j] = c.Name
+	}
+	if s.HasHeader {
+		if len(records) == 0 {
+			return nil, ErrEmptyDataset
+		}
+		got := records[0]
+		for j := range got {
+			got[j] = strings.TrimSpace(got[j])
+		}
+		if !slices.Equal(got, header) {
+			return nil, fmt.Errorf("%w: header %v does not match columns %v", ErrInvalidSchema, got, header)
+		}
+		records = records[1:]
+	}
+	if len(records) == 0 {
+		return nil, ErrEmptyDataset
+	}
+
+	dropped := make([]bool, nCols)
+	for j, name := range header {
+		dropped[j] = slices.Contains(s.Drop, name)
+	}
+	target := slices.Index(header, s.Target)
+
+	// Label encodings of categorical columns, seeded with declared categories
+	// or, for bucketed columns, with the frequent values.
+	encodings := make([]map[string]int, nCols)
+	for j, c := range s.Columns {
+		if c.Type != ColumnCategorical || dropped[j] {
+			continue
+		}
+		categories := c.Categories
+		if c.bucketed() {
+			categories = s.frequentCategories(j, records)
+		}
+		encodings[j] = make(map[string]int, len(categories))
+		for i, cat := range categories {
+			encodings[j][cat] = i
+		}
+	}
+
+	ds := &Dataset{
+		X:         make([][]float64, len(records)),
+		Y:         make([]float64, len(records)),
+		Encodings: make(map[int]map[string]float64),
+		Header:    header,
+	}
+	line := 1
+	if s.HasHeader {
+		line = 2
+	}
+	for i, record := range records {
+		if len(record) != nCols {
+			return nil, fmt.Errorf("%w: line %d has %d columns, expected %d", ErrInvalidSchema, line+i, len(record), nCols)
+		}
+		features := make([]float64, 0, nCols-1)
+		for j, cell := range record {
+			if dropped[j] {
+				continue
+			}
+			v, err := s.parseCell(j, strings.TrimSpace(cell), encodings[j])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidSchema, line+i, err)
+			}
+			if j == target {
+				if math.IsNaN(v) {
+					return nil, fmt.Errorf("%w: line %d: target %q is missing", ErrInvalidSchema, line+i, s.Target)
+				}
+				ds.Y[i] = v
+			} else {
+				features = append(features, v)
+			}
+		}
+		ds.X[i] = features
+	}
+
+	feature := 0
+	for j, c := range s.Columns {
+		if dropped[j] {
+			continue
+		}
+		var enc map[string]float64
+		if encodings[j] != nil {
+			enc = make(map[string]float64, len(encodings[j]))
+			for cat, v := range encodings[j] {
+				enc[cat] = float64(v)
+			}
+		}
+		if j == target {
+			ds.TargetEncoding = enc
+			continue
+		}
+		if enc != nil {
+			ds.Encodings[feature] = enc
+		}
+		ds.FeatureNames = append(ds.FeatureNames, c.Name)
+		feature++
+	}
+	return ds, nil
+}
+
+// frequentCategories returns the values of column j that keep their own
+// code under MinCount and MaxCategories, in order of first appearance,
+// followed by [OtherCategory] if any value is grouped. Rows of the wrong
+// length are skipped; LoadCSV reports them.
+func (s *CSVSchema) frequentCategories(j int, records [][]string) []string {
+	c := s.Columns[j]
+	counts := make(map[string]int)
+	var order []string
+	for _, record := range records {
+		if len(record) != len(s.Columns) {
+			continue
+		}
+		cell := strings.TrimSpace(record[j])
+		if cell == "" || slices.Contains(s.MissingValues, cell) {
+			continue
+		}
+		if counts[cell] == 0 {
+			order = append(order, cell)
+		}
+		counts[cell]++
+	}
+
+	kept := slices.DeleteFunc(slices.Clone(order), func(v string) bool { return counts[v] < c.MinCount })
+	codes := len(kept)
+	if len(kept) < len(order) {
+		codes++ // for OtherCategory
+	}
+	if c.MaxCategories > 0 && codes > c.MaxCategories {
+		// Keep the most frequent, then restore first-appearance order.
+		rank := slices.Clone(kept)
+		slices.SortStableFunc(rank, func(a, b string) int { return counts[b] - counts[a] })
+		top := make(map[string]bool, c.MaxCategories-1)
+		for _, v := range rank[:c.MaxCategories-1] {
+			top[v] = true
+		}
+		kept = slices.DeleteFunc(kept, func(v string) bool { return !top[v] })
+	}
+	if len(kept) < len(order) {
+		kept = append(kept, OtherCategory)
+	}
+	return kept
+}
+
+// parseCell converts one trimmed cell of column j. Missing markers become
+// NaN, and other empty cells are an error. Values missing from enc map to
+// [OtherCategory] if the column groups rare values or declares it, are an
+// error if the column declares categories, and are otherwise added to enc.
+func (s *CSVSchema) parseCell(j int, cell string, enc map[string]int) (float64, error) {
+	c := s.Columns[j]
+	if slices.Contains(s.MissingValues, cell) {
+		return math.NaN(), nil
+	}
+	if cell == "" {
+		return 0, fmt.Errorf("column %q: empty value", c.Name)
+	}
+	if c.Type == ColumnNumeric {
+		v, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return 0, fmt.Errorf("column %q: %q is not numeric", c.Name, cell)
+		}
+		return v, nil
+	}
+	v, ok := enc[cell]
+	if !ok {
+		if other, ok := enc[OtherCategory]; ok && (c.bucketed() || len(c.Categories) > 0) {
+			return float64(other), nil
+		}
+		if len(c.Categories) > 0 {
+			return 0, fmt.Errorf("column %q: undeclared category %q", c.Name, cell)
+		}
+		v = len(enc)
+		enc[cell] = v
+	}
+	return float64(v), nil
+}
@@ -0,0 +1,263 @@
+package gboost
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+)
+
+const schemaTestCSV = `id,age,color,income,label
+1,34,red,NA,yes
+2,?,blue,52000,no
+3,51,red,61000,yes
+`
+
+func TestLoadCSVWithSchemaJSON(t *testing.T) {
+	csvPath := writeTestCSV(t, "data.csv", schemaTestCSV)
+	schemaPath := writeTestCSV(t, "schema.json", `{
+  "has_header": true,
+  "columns": [
+    {"name": "id", "type": "numeric"},
+    {"name": "age", "type": "numeric"},
+    {"name": "color", "type": "categorical", "categories": ["blue", "red", "green"]},
+    {"name": "income", "type": "numeric"},
+    {"name": "label", "type": "categorical", "categories": ["no", "yes"]}
+  ],
+  "target": "label",
+  "missing_values": ["NA", "?"],
+  "drop": ["id"]
+}`)
+
+	ds, err := LoadCSVWithSchema(csvPath, schemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ds.FeatureNames, []string{"age", "color", "income"}) {
+		t.Errorf("FeatureNames = %v", ds.FeatureNames)
+	}
+	if !slices.Equal(ds.Y, []float64{1, 0, 1}) {
+		t.Errorf("Y = %v, want [1 0 1]", ds.Y)
+	}
+	if ds.X[0][1] != 1 || ds.X[1][1] != 0 {
+		t.Errorf("color not encoded by declared categories: %v, %v", ds.X[0], ds.X[1])
+	}
+	if !math.IsNaN(ds.X[0][2]) || !math.IsNaN(ds.X[1][0]) {
+		t.Errorf("missing markers not loaded as NaN: %v, %v", ds.X[0], ds.X[1])
+	}
+	if ds.Encodings[1]["green"] != 2 || ds.TargetEncoding["yes"] != 1 {
+		t.Errorf("Encodings = %v, TargetEncoding = %v", ds.Encodings, ds.TargetEncoding)
+	}
+	if _, ok := ds.Encodings[0]; ok {
+		t.Error("numeric feature should have no encoding")
+	}
+}
+
+func TestLoadCSVWithSchemaYAML(t *testing.T) {
+	csvPath := writeTestCSV(t, "data.csv", "1,0.5,a\n0,1.5,b\n1,2.5,a\n")
+	schemaPath := writeTestCSV(t, "schema.yaml", `
+columns:
+  - name: y
+    type: numeric
+  - name: x
+    type: numeric
+  - name: group
+    type: categorical
+target: y
+`)
+
+	ds, err := LoadCSVWithSchema(csvPath, schemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ds.Y, []float64{1, 0, 1}) || ds.X[1][0] != 1.5 || ds.X[1][1] != 1 {
+		t.Errorf("X = %v, Y = %v", ds.X, ds.Y)
+	}
+	if !slices.Equal(ds.Header, []string{"y", "x", "group"}) {
+		t.Errorf("Header = %v, want the schema's column names", ds.Header)
+	}
+}
+
+func TestLoadSchemaRejectsUnknownKeys(t *testing.T) {
+	path := writeTestCSV(t, "schema.json", `{"columns": [], "targte": "y"}`)
+	if _, err := LoadSchema(path); err == nil {
+		t.Error("expected an error for a misspelled key")
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	type options struct {
+		MaxRows int    `json:"max_rows"`
+		Name    string `json:"name"`
+	}
+	for _, path := range []string{
+		writeTestCSV(t, "options.json", `{"max_rows": 5, "name": "a"}`),
+		writeTestCSV(t, "options.yaml", "max_rows: 5\nname: a\n"),
+	} {
+		var got options
+		if err := DecodeFile(path, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != (options{MaxRows: 5, Name: "a"}) {
+			t.Errorf("%s: decoded %+v", path, got)
+		}
+	}
+	var v options
+	if err := DecodeFile(writeTestCSV(t, "options.yml", "maxrows: 5\n"), &v); err == nil {
+		t.Error("expected an error for a misspelled key")
+	}
+}
+
+func TestCSVSchemaInvalid(t *testing.T) {
+	cols := []ColumnSchema{{Name: "x", Type: ColumnNumeric}, {Name: "y", Type: ColumnNumeric}}
+	tests := []struct {
+		name   string
+		schema CSVSchema
+	}{
+		{"one column", CSVSchema{Columns: cols[:1], Target: "x"}},
+		{"unknown target", CSVSchema{Columns: cols, Target: "z"}},
+		{"dropped target", CSVSchema{Columns: cols, Target: "y", Drop: []string{"y"}}},
+		{"no features left", CSVSchema{Columns: cols, Target: "y", Drop: []string{"x"}}},
+		{"unknown drop", CSVSchema{Columns: cols, Target: "y", Drop: []string{"z"}}},
+		{"duplicate name", CSVSchema{Columns: []ColumnSchema{cols[0], cols[0]}, Target: "x"}},
+		{"bad type", CSVSchema{Columns: []ColumnSchema{cols[0], {Name: "y", Type: "text"}}, Target: "x"}},
+		{"max_categories 1", CSVSchema{Columns: []ColumnSchema{cols[0], {Name: "c", Type: ColumnCategorical, MaxCategories: 1}}, Target: "x"}},
+		{"negative min_count", CSVSchema{Columns: []ColumnSchema{cols[0], {Name: "c", Type: ColumnCategorical, MinCount: -1}}, Target: "x"}},
+		{"max_categories on numeric", CSVSchema{Columns: []ColumnSchema{cols[0], {Name: "y", Type: ColumnNumeric, MaxCategories: 5}}, Target: "x"}},
+		{"max_categories on target", CSVSchema{Columns: []ColumnSchema{cols[0], {Name: "c", Type: ColumnCategorical, MaxCategories: 5}}, Target: "c"}},
+		{"max_categories with categories", CSVSchema{Columns: []ColumnSchema{cols[0], {Name: "c", Type: ColumnCategorical, Categories: []string{"a"}, MaxCategories: 5}}, Target: "x"}},
+	}
+	path := writeTestCSV(t, "data.csv", "1,2\n")
+	for _, tt := range tests {
+		if _, err := tt.schema.LoadCSV(path); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("%s: err = %v, want ErrInvalidSchema", tt.name, err)
+		}
+	}
+}
+
+func TestCSVSchemaRareCategories(t *testing.T) {
+	// city has a x4, b x3, c x2, d x1 (and one missing); user is an ID.
+	path := writeTestCSV(t, "data.csv", `user,city,y
+u1,a,1
+u2,b,0
+u3,c,1
+u4,a,0
+u5,d,1
+u6,b,0
+u7,a,1
+u8,c,0
+u9,b,1
+u10,a,0
+u11,NA,1
+`)
+	load := func(city, user ColumnSchema) *Dataset {
+		t.Helper()
+		city.Name, city.Type = "city", ColumnCategorical
+		user.Name, user.Type = "user", ColumnCategorical
+		schema := CSVSchema{
+			HasHeader:     true,
+			Columns:       []ColumnSchema{user, city, {Name: "y", Type: ColumnNumeric}},
+			Target:        "y",
+			MissingValues: []string{"NA"},
+		}
+		ds, err := schema.LoadCSV(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ds
+	}
+	column := func(ds *Dataset, j int) []float64 {
+		var res []float64
+		for _, x := range ds.X {
+			res = append(res, x[j])
+		}
+		return res
+	}
+
+	// min_count 3 keeps a and b; c and d share the other bucket.
+	ds := load(ColumnSchema{MinCount: 3}, ColumnSchema{})
+	want := map[string]float64{"a": 0, "b": 1, OtherCategory: 2}
+	if !maps.Equal(ds.Encodings[1], want) {
+		t.Errorf("min_count encoding = %v, want %v", ds.Encodings[1], want)
+	}
+	if got := column(ds, 1)[:5]; !slices.Equal(got, []float64{0, 1, 2, 0, 2}) {
+		t.Errorf("min_count codes = %v", got)
+	}
+	if !math.IsNaN(ds.X[10][1]) {
+		t.Errorf("missing city = %v, want NaN", ds.X[10][1])
+	}
+
+	// max_categories 3 keeps the two most frequent values plus the bucket;
+	// an ID column collapses to a single kept value and the bucket.
+	ds = load(ColumnSchema{MaxCategories: 3}, ColumnSchema{MaxCategories: 2})
+	if !maps.Equal(ds.Encodings[1], want) {
+		t.Errorf("max_categories encoding = %v, want %v", ds.Encodings[1], want)
+	}
+	if len(ds.Encodings[0]) != 2 || ds.Encodings[0][OtherCategory] != 1 {
+		t.Errorf("user encoding = %v, want one ID and the other bucket", ds.Encodings[0])
+	}
+
+	// A cap that min_count already meets adds no bucket beyond it.
+	ds = load(ColumnSchema{MinCount: 2, MaxCategories: 3}, ColumnSchema{})
+	if len(ds.Encodings[1]) != 3 {
+		t.Errorf("encoding = %v, want at most 3 codes", ds.Encodings[1])
+	}
+
+	// Without grouping, every value gets its own code.
+	ds = load(ColumnSchema{}, ColumnSchema{})
+	if len(ds.Encodings[1]) != 4 || len(ds.Encodings[0]) != 11 {
+		t.Errorf("encodings = %v", ds.Encodings)
+	}
+}
+
+func TestCSVSchemaDeclaredOtherCategory(t *testing.T) {
+	schema := CSVSchema{
+		Columns: []ColumnSchema{
+			{Name: "c", Type: ColumnCategorical, Categories: []string{"a", "b", OtherCategory}},
+			{Name: "y", Type: ColumnNumeric},
+		},
+		Target: "y",
+	}
+	ds, err := schema.LoadCSV(writeTestCSV(t, "data.csv", "a,1\nz,0\nb,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := []float64{ds.X[0][0], ds.X[1][0], ds.X[2][0]}; !slices.Equal(got, []float64{0, 2, 1}) {
+		t.Errorf("codes = %v, want the undeclared value in the other bucket", got)
+	}
+}
+
+func TestCSVSchemaFileMismatch(t *testing.T) {
+	schema := CSVSchema{
+		HasHeader: true,
+		Columns: []ColumnSchema{
+			{Name: "x", Type: ColumnNumeric},
+			{Name: "c", Type: ColumnCategorical, Categories: []string{"a", "b"}},
+			{Name: "y", Type: ColumnNumeric},
+		},
+		Target:        "y",
+		MissingValues: []string{"NA"},
+	}
+	tests := []struct {
+		name, content string
+	}{
+		{"header", "x,y,c\n1,2,a\n"},
+		{"column count", "x,c,y\n1,a\n"},
+		{"text in numeric column", "x,c,y\nhigh,a,1\n"},
+		{"undeclared category", "x,c,y\n1,z,1\n"},
+		{"missing target", "x,c,y\n1,a,NA\n"},
+		{"empty cell", "x,c,y\n,a,1\n"},
+	}
+	for _, tt := range tests {
+		path := writeTestCSV(t, "data.csv", tt.content)
+		if _, err := schema.LoadCSV(path); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("%s: err = %v, want ErrInvalidSchema", tt.name, err)
+		}
+	}
+
+	path := writeTestCSV(t, "data.csv", "x,c,y\n")
+	if _, err := schema.LoadCSV(path); !errors.Is(err, ErrEmptyDataset) {
+		t.Errorf("header only: err = %v, want ErrEmptyDataset", err)
+	}
+}
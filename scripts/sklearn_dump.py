@@ -0,0 +1,145 @@
+"""Dump a fitted scikit-learn gradient boosting model as JSON for gboost.
+
+The output is read by gboost.LoadSklearn, so that models trained in Python
+can be scored, served, and explained from Go. Supported estimators are
+GradientBoostingRegressor, GradientBoostingClassifier (binary, log_loss),
+HistGradientBoostingRegressor, and HistGradientBoostingClassifier (binary),
+with losses whose raw score is the prediction itself (squared_error,
+absolute_error, huber, quantile) or its log-odds (log_loss).
+
+Usage, with a model pickled by pickle or joblib:
+
+    python sklearn_dump.py model.pkl model.json [--no-missing]
+
+or from Python:
+
+    from sklearn_dump import dump
+    dump(model, "model.json")
+
+gboost sends missing values (NaN) to the right child of every split, while
+scikit-learn may send them left. Such splits are recorded and rejected by
+LoadSklearn; pass --no-missing (missing=False) to drop the routing when the
+model is never scored on missing values.
+"""
+
+import argparse
+import json
+
+import joblib
+import numpy as np
+
+FORMAT = "gboost-sklearn-trees"
+VERSION = 1
+
+IDENTITY_LOSSES = {"squared_error", "absolute_error", "huber", "quantile"}
+
+
+def _decision_tree(tree, missing):
+    """Arrays of one DecisionTreeRegressor from a GradientBoosting model."""
+    t = tree.tree_
+    left = t.children_left.tolist()
+    right = t.children_right.tolist()
+    w = t.weighted_n_node_samples
+    imp = t.impurity
+    gain = []
+    for i in range(t.node_count):
+        if left[i] == -1:
+            gain.append(0.0)
+        else:
+            l, r = left[i], right[i]
+            gain.append(float(w[i] * imp[i] - w[l] * imp[l] - w[r] * imp[r]))
+    out = {
+        "left": left,
+        "right": right,
+        "feature": [max(int(f), 0) for f in t.feature],
+        "threshold": [float(x) for x in t.threshold],
+        "value": [float(v) for v in t.value[:, 0, 0]],
+        "n_samples": [int(n) for n in t.n_node_samples],
+        "gain": gain,
+    }
+    go_left = getattr(t, "missing_go_to_left", None)
+    if missing and go_left is not None:
+        out["missing_go_to_left"] = [int(m) if left[i] != -1 else 0 for i, m in enumerate(go_left)]
+    return out
+
+
+def _hist_predictor(predictor, missing):
+    """Arrays of one TreePredictor from a HistGradientBoosting model."""
+    nodes = predictor.nodes
+    if nodes["is_categorical"].any():
+        raise ValueError("categorical splits are not supported")
+    leaf = nodes["is_leaf"].astype(bool)
+    out = {
+        "left": np.where(leaf, -1, nodes["left"]).astype(int).tolist(),
+        "right": np.where(leaf, -1, nodes["right"]).astype(int).tolist(),
+        "feature": np.where(leaf, 0, nodes["feature_idx"]).astype(int).tolist(),
+        "threshold": np.where(leaf, 0.0, nodes["num_threshold"]).astype(float).tolist(),
+        "value": nodes["value"].astype(float).tolist(),
+        "n_samples": nodes["count"].astype(int).tolist(),
+        "gain": np.where(leaf, 0.0, nodes["gain"]).astype(float).tolist(),
+    }
+    if missing:
+        out["missing_go_to_left"] = np.where(leaf, 0, nodes["missing_go_to_left"]).astype(int).tolist()
+    return out
+
+
+def to_dict(model, missing=True):
+    """Return the JSON-ready dump of a fitted model."""
+    name = type(model).__name__
+    classifier = name.endswith("Classifier")
+    if classifier and len(model.classes_) != 2:
+        raise ValueError("only binary classifiers are supported")
+    loss = model.loss
+    if loss not in IDENTITY_LOSSES and loss != "log_loss":
+        raise ValueError(f"loss {loss!r} is not supported")
+
+    if name in ("GradientBoostingRegressor", "GradientBoostingClassifier"):
+        if model.init not in (None, "zero"):
+            raise ValueError("custom init estimators are not supported")
+        init = float(model._raw_predict_init(np.zeros((1, model.n_features_in_)))[0, 0])
+        trees = [_decision_tree(t, missing) for t in model.estimators_[: model.n_estimators_, 0]]
+        scaled = False
+    elif name in ("HistGradientBoostingRegressor", "HistGradientBoostingClassifier"):
+        if getattr(model, "_preprocessor", None) is not None:
+            raise ValueError("models with categorical preprocessing are not supported")
+        init = float(np.ravel(model._baseline_prediction)[0])
+        trees = [_hist_predictor(p[0], missing) for p in model._predictors]
+        scaled = True  # HistGradientBoosting shrinks leaf values while growing
+    else:
+        raise ValueError(f"unsupported estimator {name}")
+
+    out = {
+        "format": FORMAT,
+        "version": VERSION,
+        "estimator": name,
+        "loss": loss,
+        "learning_rate": float(model.learning_rate),
+        "init_prediction": init,
+        "leaf_values_scaled": scaled,
+        "n_features": int(model.n_features_in_),
+        "trees": trees,
+    }
+    if hasattr(model, "feature_names_in_"):
+        out["feature_names"] = [str(f) for f in model.feature_names_in_]
+    return out
+
+
+def dump(model, path, missing=True):
+    """Write the dump of a fitted model to path."""
+    with open(path, "w") as f:
+        json.dump(to_dict(model, missing), f)
+
+
+def main():
+    parser = argparse.ArgumentParser(description=__doc__.splitlines()[0])
+    parser.add_argument("model", help="scikit-learn model saved with pickle or joblib")
+    parser.add_argument("output", help="JSON file to write")
+    parser.add_argument("--no-missing", action="store_true", help="drop the routing of missing values")
+    args = parser.parse_args()
+
+    model = joblib.load(args.model)  # also reads plain pickles
+    dump(model, args.output, missing=not args.no_missing)
+
+
+if __name__ == "__main__":
+    main()
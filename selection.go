@@ -0,0 +1,316 @@
+package gboost
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// RFEOptions controls [SelectFeaturesRFE]. The embedded [CVOptions] score
+// every candidate feature set.
+type RFEOptions struct {
+	CVOptions
+
+	// Step is the number of features dropped per round. Zero means 1, which
+	// is slowest but re-ranks the features after every removal.
+	Step int
+
+	// Importance selects the importance that ranks features for removal.
+	// Empty means [ImportanceGain].
+	Importance ImportanceType
+}
+
+// RFEStep is one round of [SelectFeaturesRFE]: a feature set and its
+// cross-validated score.
+type RFEStep struct {
+	Features []int     // Indices into the original columns of X, ascending.
+	Scores   []float64 // One score per fold, in fold order.
+	Mean     float64
+	Std      float64
+}
+
+// RFEResult is the outcome of [SelectFeaturesRFE].
+type RFEResult struct {
+	// Selected holds the keep features that survived elimination, as
+	// indices into the original columns of X, ascending.
+	Selected []int
+
+	// Steps traces the elimination from all features down to Selected.
+	Steps []RFEStep
+}
+
+// Best returns the step with the best mean score, preferring the smaller
+// feature set on ties. Elimination often passes a smaller set that scores
+// as well as the final one; Best finds it.
+func (r *RFEResult) Best(greaterIsBetter bool) RFEStep {
+	best := 0
+	for i, s := range r.Steps {
+		better := s.Mean <= r.Steps[best].Mean
+		if greaterIsBetter {
+			better = s.Mean >= r.Steps[best].Mean
+		}
+		if better {
+			best = i
+		}
+	}
+	return r.Steps[best]
+}
+
+// SelectFeaturesRFE selects keep features by recursive feature elimination:
+// it cross-validates cfg on the current feature set, trains one model on all
+// rows to rank the features by importance, drops the opts.Step least
+// important ones, and repeats until keep features remain. Features are
+// re-ranked every round, so a feature that looked important only alongside
+// a since-dropped correlated twin can still be removed.
+//
+// Every round uses the same folds, so the scores in the returned trajectory
+// are comparable. The importance ranking uses cfg as given, including its
+// Seed; ties drop the higher index first.
+//
+// Returns [ErrInvalidRFEKeep] unless 1 <= keep <= the number of features,
+// [ErrInvalidRFEStep] if opts.Step < 0, and otherwise any error from
+// [CrossValidate] or [GBM.Fit].
+func SelectFeaturesRFE(cfg Config, X [][]float64, y []float64, keep int, opts RFEOptions) (*RFEResult, error) {
+	switch {
+	case len(X) == 0:
+		return nil, ErrEmptyDataset
+	case keep < 1 || keep > len(X[0]):
+		return nil, ErrInvalidRFEKeep
+	case opts.Step < 0:
+		return nil, ErrInvalidRFEStep
+	}
+	step := max(opts.Step, 1)
+	importanceType := opts.Importance
+	if importanceType == "" {
+		importanceType = ImportanceGain
+	}
+
+	features := allFeatures(len(X[0]))
+	res := &RFEResult{}
+	for {
+		Xs := selectColumns(X, features)
+		cv, err := CrossValidate(cfg, Xs, y, opts.CVOptions)
+		if err != nil {
+			return nil, err
+		}
+		res.Steps = append(res.Steps, RFEStep{Features: features, Scores: cv.Scores, Mean: cv.Mean, Std: cv.Std})
+		if len(features) == keep {
+			break
+		}
+
+		model := New(cfg)
+		if err := model.Fit(Xs, y); err != nil {
+			return nil, err
+		}
+		importance, err := model.FeatureImportanceByType(importanceType)
+		if err != nil {
+			return nil, err
+		}
+		order := allFeatures(len(features))
+		slices.SortStableFunc(order, func(a, b int) int {
+			switch {
+			case importance[a] < importance[b]:
+				return -1
+			case importance[a] > importance[b]:
+				return 1
+			}
+			return b - a
+		})
+		dropped := make(map[int]bool, step)
+		for _, k := range order[:min(step, len(features)-keep)] {
+			dropped[features[k]] = true
+		}
+		features = slices.DeleteFunc(slices.Clone(features), func(j int) bool { return dropped[j] })
+	}
+	res.Selected = features
+	return res, nil
+}
+
+// selectColumns returns the rows of X restricted to the given columns, in
+// that order.
+func selectColumns(X [][]float64, columns []int) [][]float64 {
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		res[i] = make([]float64, len(columns))
+		for k, j := range columns {
+			res[i][k] = row[j]
+		}
+	}
+	return res
+}
+
+// FeatureDecision is the verdict of [SelectFeaturesBoruta] on one feature.
+type FeatureDecision string
+
+const (
+	FeatureConfirmed FeatureDecision = "confirmed" // Beats the best shadow feature significantly often.
+	FeatureRejected  FeatureDecision = "rejected"  // Loses to the best shadow feature significantly often.
+	FeatureTentative FeatureDecision = "tentative" // Undecided after MaxRuns.
+)
+
+// BorutaOptions controls [SelectFeaturesBoruta].
+type BorutaOptions struct {
+	// MaxRuns is the number of models trained at most. Zero means 100.
+	// Features still undecided after MaxRuns are tentative.
+	MaxRuns int
+
+	// Alpha is the significance level of the per-feature tests, Bonferroni
+	// corrected for the number of features. Zero means 0.05.
+	Alpha float64
+
+	// Seed is the master seed for the shadow permutations and the models.
+	Seed int64
+
+	// Importance selects the importance compared against the shadows.
+	// Empty means [ImportanceGain].
+	Importance ImportanceType
+}
+
+// BorutaResult is the outcome of [SelectFeaturesBoruta].
+type BorutaResult struct {
+	Decisions []FeatureDecision // One per column of X.
+	Hits      []int             // Runs in which each feature beat the best shadow.
+	Runs      []int             // Runs each feature took part in before it was decided.
+}
+
+// Confirmed returns the indices of the confirmed features, ascending.
+func (r *BorutaResult) Confirmed() []int {
+	var res []int
+	for j, d := range r.Decisions {
+		if d == FeatureConfirmed {
+			res = append(res, j)
+		}
+	}
+	return res
+}
+
+// SelectFeaturesBoruta finds all features relevant to y, not a minimal
+// subset, with the Boruta algorithm. Each run appends a shadow copy of every
+// undecided or confirmed feature, its values shuffled across rows to destroy
+// any relation to y, trains a model with cfg, and counts a hit for each
+// feature whose importance exceeds that of the most important shadow. After
+// each run, a feature whose hits are significantly more frequent than
+// chance, under a two-sided binomial test at opts.Alpha divided by the
+// number of features, is confirmed, and one significantly less frequent is
+// rejected and left out of later runs.
+//
+// Unlike [SelectFeaturesRFE], Boruta keeps redundant features that carry
+// the same signal, which suits finding what drives y rather than the
+// smallest model.
+//
+// Returns [ErrInvalidBorutaOptions] for a negative MaxRuns or an Alpha
+// outside [0, 1), [ErrEmptyDataset] if X is empty, and otherwise any error
+// from [GBM.Fit].
+func SelectFeaturesBoruta(cfg Config, X [][]float64, y []float64, opts BorutaOptions) (*BorutaResult, error) {
+	switch {
+	case opts.MaxRuns < 0 || !(opts.Alpha >= 0 && opts.Alpha < 1):
+		return nil, ErrInvalidBorutaOptions
+	case len(X) == 0:
+		return nil, ErrEmptyDataset
+	}
+	maxRuns := opts.MaxRuns
+	if maxRuns == 0 {
+		maxRuns = 100
+	}
+	alpha := opts.Alpha
+	if alpha == 0 {
+		alpha = 0.05
+	}
+	importanceType := opts.Importance
+	if importanceType == "" {
+		importanceType = ImportanceGain
+	}
+
+	numFeatures := len(X[0])
+	alpha /= float64(numFeatures)
+	res := &BorutaResult{
+		Decisions: make([]FeatureDecision, numFeatures),
+		Hits:      make([]int, numFeatures),
+		Runs:      make([]int, numFeatures),
+	}
+	for j := range res.Decisions {
+		res.Decisions[j] = FeatureTentative
+	}
+
+	for run := range maxRuns {
+		var active []int
+		undecided := false
+		for j, d := range res.Decisions {
+			if d != FeatureRejected {
+				active = append(active, j)
+			}
+			undecided = undecided || d == FeatureTentative
+		}
+		if !undecided {
+			break
+		}
+
+		rnd := newRNG(opts.Seed, 2*run)
+		Xs := withShadows(rnd, X, active)
+		runCfg := cfg
+		runCfg.Seed = deriveSeed(opts.Seed, 2*run+1)
+		model := New(runCfg)
+		if err := model.Fit(Xs, y); err != nil {
+			return nil, err
+		}
+		importance, err := model.FeatureImportanceByType(importanceType)
+		if err != nil {
+			return nil, err
+		}
+		shadowMax := slices.Max(importance[len(active):])
+
+		for k, j := range active {
+			if res.Decisions[j] != FeatureTentative {
+				continue
+			}
+			res.Runs[j]++
+			if importance[k] > shadowMax {
+				res.Hits[j]++
+			}
+			n, h := res.Runs[j], res.Hits[j]
+			switch {
+			case binomialTail(n, h, true) < alpha:
+				res.Decisions[j] = FeatureConfirmed
+			case binomialTail(n, h, false) < alpha:
+				res.Decisions[j] = FeatureRejected
+			}
+		}
+	}
+	return res, nil
+}
+
+// withShadows returns X restricted to columns, followed by a copy of each of
+// those columns with its values shuffled across rows.
+func withShadows(rnd *rand.Rand, X [][]float64, columns []int) [][]float64 {
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		res[i] = make([]float64, 2*len(columns))
+		for k, j := range columns {
+			res[i][k] = row[j]
+		}
+	}
+	for k, j := range columns {
+		perm := rnd.Perm(len(X))
+		for i := range X {
+			res[i][len(columns)+k] = X[perm[i]][j]
+		}
+	}
+	return res
+}
+
+// binomialTail returns P(H >= h) if upper, and P(H <= h) otherwise, for H
+// binomially distributed with n trials and success probability 1/2.
+func binomialTail(n, h int, upper bool) float64 {
+	lo, hi := 0, h
+	if upper {
+		lo, hi = h, n
+	}
+	var p float64
+	for k := lo; k <= hi; k++ {
+		lnN, _ := math.Lgamma(float64(n + 1))
+		lnK, _ := math.Lgamma(float64(k + 1))
+		lnNK, _ := math.Lgamma(float64(n - k + 1))
+		p += math.Exp(lnN - lnK - lnNK - float64(n)*math.Ln2)
+	}
+	return p
+}
@@ -0,0 +1,143 @@
+package gboost
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost/metrics"
+)
+
+// generateNoisyFeatures returns a target driven by features 1 and 3 of six;
+// the others are noise.
+func generateNoisyFeatures(n int) ([][]float64, []float64) {
+	rnd := rand.New(rand.NewSource(9))
+	X := make([][]float64, n)
+	y := make([]float64, n)
+	for i := range X {
+		X[i] = make([]float64, 6)
+		for j := range X[i] {
+			X[i][j] = rnd.Float64()
+		}
+		y[i] = 3*X[i][1] + 2*X[i][3] + 0.1*rnd.NormFloat64()
+	}
+	return X, y
+}
+
+func TestSelectFeaturesRFE(t *testing.T) {
+	X, y := generateNoisyFeatures(150)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 15
+	cfg.MaxDepth = 3
+	opts := RFEOptions{CVOptions: CVOptions{Folds: 3, Metric: metrics.RMSE, Seed: 1}}
+
+	res, err := SelectFeaturesRFE(cfg, X, y, 2, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(res.Selected, []int{1, 3}) {
+		t.Errorf("selected %v, want the informative features [1 3]", res.Selected)
+	}
+	if len(res.Steps) != 5 {
+		t.Fatalf("%d steps, want one per feature count from 6 down to 2", len(res.Steps))
+	}
+	for i, s := range res.Steps {
+		if len(s.Features) != 6-i || len(s.Scores) != 3 {
+			t.Errorf("step %d: %d features and %d scores, want %d and 3", i, len(s.Features), len(s.Scores), 6-i)
+		}
+	}
+	if first, last := res.Steps[0], res.Steps[len(res.Steps)-1]; last.Mean > first.Mean {
+		t.Errorf("RMSE rose from %v with all features to %v with the informative ones", first.Mean, last.Mean)
+	}
+	if best := res.Best(false); len(best.Features) > 3 {
+		t.Errorf("best step keeps %v", best.Features)
+	}
+
+	opts.Step = 3
+	res, err = SelectFeaturesRFE(cfg, X, y, 2, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make([]int, len(res.Steps))
+	for i, s := range res.Steps {
+		counts[i] = len(s.Features)
+	}
+	if !slices.Equal(counts, []int{6, 3, 2}) || !slices.Equal(res.Selected, []int{1, 3}) {
+		t.Errorf("Step 3: feature counts %v, selected %v; want [6 3 2] and [1 3]", counts, res.Selected)
+	}
+}
+
+func TestSelectFeaturesRFEErrors(t *testing.T) {
+	X, y := generateNoisyFeatures(20)
+	opts := RFEOptions{CVOptions: CVOptions{Folds: 2, Metric: metrics.RMSE}}
+	for _, keep := range []int{0, 7} {
+		if _, err := SelectFeaturesRFE(DefaultConfig(), X, y, keep, opts); err != ErrInvalidRFEKeep {
+			t.Errorf("keep %d: err = %v, want ErrInvalidRFEKeep", keep, err)
+		}
+	}
+	opts.Step = -1
+	if _, err := SelectFeaturesRFE(DefaultConfig(), X, y, 2, opts); err != ErrInvalidRFEStep {
+		t.Errorf("err = %v, want ErrInvalidRFEStep", err)
+	}
+	if _, err := SelectFeaturesRFE(DefaultConfig(), X, y, 2, RFEOptions{}); err != ErrInvalidFolds {
+		t.Errorf("without CV options: err = %v, want ErrInvalidFolds", err)
+	}
+}
+
+func TestSelectFeaturesBoruta(t *testing.T) {
+	X, y := generateNoisyFeatures(150)
+	// Feature 6 duplicates the signal of feature 1. RFE would drop one of
+	// the two; Boruta keeps both as relevant.
+	rnd := rand.New(rand.NewSource(4))
+	for i := range X {
+		X[i] = append(X[i], X[i][1]+0.01*rnd.NormFloat64())
+	}
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	cfg.MaxDepth = 3
+	cfg.SubsampleRatio = 0.8
+
+	res, err := SelectFeaturesBoruta(cfg, X, y, BorutaOptions{MaxRuns: 30, Seed: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Confirmed(); !slices.Equal(got, []int{1, 3, 6}) {
+		t.Errorf("confirmed %v, want [1 3 6]; decisions %v, hits %v of %v runs", got, res.Decisions, res.Hits, res.Runs)
+	}
+	for _, j := range []int{0, 2, 4, 5} {
+		if res.Decisions[j] == FeatureConfirmed {
+			t.Errorf("noise feature %d confirmed with %d hits in %d runs", j, res.Hits[j], res.Runs[j])
+		}
+	}
+
+	again, err := SelectFeaturesBoruta(cfg, X, y, BorutaOptions{MaxRuns: 30, Seed: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(again.Hits, res.Hits) {
+		t.Errorf("hits %v, then %v with the same seed", res.Hits, again.Hits)
+	}
+}
+
+func TestBinomialTail(t *testing.T) {
+	// Ten runs: P(H >= 10) = P(H <= 0) = 1/1024, and the tails overlap at h.
+	if got := binomialTail(10, 10, true); math.Abs(got-1.0/1024) > 1e-12 {
+		t.Errorf("P(H >= 10) = %v, want 1/1024", got)
+	}
+	if got := binomialTail(10, 0, false); math.Abs(got-1.0/1024) > 1e-12 {
+		t.Errorf("P(H <= 0) = %v, want 1/1024", got)
+	}
+	if got := binomialTail(10, 5, true) + binomialTail(10, 4, false); math.Abs(got-1) > 1e-12 {
+		t.Errorf("P(H >= 5) + P(H <= 4) = %v, want 1", got)
+	}
+}
+
+func TestSelectFeaturesBorutaErrors(t *testing.T) {
+	X, y := generateNoisyFeatures(20)
+	for _, opts := range []BorutaOptions{{MaxRuns: -1}, {Alpha: 1}, {Alpha: -0.1}} {
+		if _, err := SelectFeaturesBoruta(DefaultConfig(), X, y, opts); err != ErrInvalidBorutaOptions {
+			t.Errorf("%+v: err = %v, want ErrInvalidBorutaOptions", opts, err)
+		}
+	}
+}
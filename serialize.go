@@ -3,6 +3,7 @@ package gboost
 import (
 	"encoding/json"
 	"os"
+	"sync/atomic"
 )
 
 // ExportedNode is the JSON-serializable representation of a Node
@@ -14,6 +15,8 @@ type ExportedNode struct {
 	Left         *ExportedNode `json:"left,omitempty"`
 	Right        *ExportedNode `json:"right,omitempty"`
 	NSamples     int           `json:"n_samples"`
+	Gain         float64       `json:"gain,omitempty"`
+	Cover        float64       `json:"cover,omitempty"`
 }
 
 // ExportedModel is the JSON-serializable representation of a GBM model
@@ -23,6 +26,13 @@ type ExportedModel struct {
 	Trees             []*ExportedNode `json:"trees"`
 	NumFeatures       int             `json:"num_features"`
 	FeatureImportance []float64       `json:"feature_importance"`
+	FeatureNames      []string        `json:"feature_names,omitempty"`
+	AliasedNames      bool            `json:"feature_names_aliased,omitempty"`
+	FeatureMin        []float64       `json:"feature_min,omitempty"`
+	FeatureMax        []float64       `json:"feature_max,omitempty"`
+	SnapshotWeights   []float64       `json:"snapshot_weights,omitempty"`
+	LeafTransform     string          `json:"leaf_transform,omitempty"`
+	Warnings          []string        `json:"warnings,omitempty"`
 }
 
 // toExported converts an internal Node to an ExportedNode
@@ -41,6 +51,8 @@ func (n *Node) toExported() *ExportedNode {
 		Left:         n.Left.toExported(),
 		Right:        n.Right.toExported(),
 		NSamples:     n.NSamples,
+		Gain:         n.Gain,
+		Cover:        n.Cover,
 	}
 }
 
@@ -57,6 +69,8 @@ func nodeFromExported(e *ExportedNode) *Node {
 		Left:         nodeFromExported(e.Left),
 		Right:        nodeFromExported(e.Right),
 		NSamples:     e.NSamples,
+		Gain:         e.Gain,
+		Cover:        e.Cover,
 	}
 }
 
@@ -73,24 +87,42 @@ func (g *GBM) toExported() *ExportedModel {
 		Trees:             trees,
 		NumFeatures:       g.numFeatures,
 		FeatureImportance: g.featureImportance,
+		FeatureNames:      g.featureNames,
+		AliasedNames:      g.aliasedNames,
+		FeatureMin:        g.featureMin,
+		FeatureMax:        g.featureMax,
+		SnapshotWeights:   g.snapshotWeights,
+		LeafTransform:     g.leafTransform,
+		Warnings:          g.warnings,
 	}
 }
 
 // fromExported restores a GBM model from an ExportedModel
 func fromExported(e *ExportedModel) *GBM {
 	trees := make([]*Node, len(e.Trees))
+	maxDepth := 0
 	for i, tree := range e.Trees {
 		trees[i] = nodeFromExported(tree)
+		maxDepth = max(maxDepth, trees[i].depth())
 	}
 
 	return &GBM{
 		Config:            e.Config,
 		initialPrediction: e.InitialPrediction,
 		trees:             trees,
+		maxDepth:          maxDepth,
 		featureImportance: e.FeatureImportance,
 		numFeatures:       e.NumFeatures,
+		featureNames:      e.FeatureNames,
+		aliasedNames:      e.AliasedNames,
+		featureMin:        e.FeatureMin,
+		featureMax:        e.FeatureMax,
+		snapshotWeights:   e.SnapshotWeights,
+		leafTransform:     e.LeafTransform,
+		warnings:          e.Warnings,
 		loss:              createLossFunction(e.Config),
 		isFitted:          true,
+		forest:            new(atomic.Pointer[layoutForest]),
 	}
 }
 
@@ -102,6 +134,21 @@ func (g *GBM) Save(path string) error {
 		return ErrModelNotFitted
 	}
 
+	return writeJSON(path, g.toExported())
+}
+
+// Load reads a trained model from a JSON file previously written by [GBM.Save].
+// The returned model is ready for prediction without retraining.
+func Load(path string) (*GBM, error) {
+	var exported ExportedModel
+	if err := readJSON(path, &exported); err != nil {
+		return nil, err
+	}
+	return fromExported(&exported), nil
+}
+
+// writeJSON writes v to path as indented JSON.
+func writeJSON(path string, v any) error {
 	file, err := os.Create(path)
 	if err != nil {
 		return err
@@ -110,23 +157,16 @@ func (g *GBM) Save(path string) error {
 
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(g.toExported())
+	return encoder.Encode(v)
 }
 
-// Load reads a trained model from a JSON file previously written by [GBM.Save].
-// The returned model is ready for prediction without retraining.
-func Load(path string) (*GBM, error) {
+// readJSON decodes the JSON file at path into v.
+func readJSON(path string, v any) error {
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer file.Close()
 
-	var exported ExportedModel
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&exported); err != nil {
-		return nil, err
-	}
-
-	return fromExported(&exported), nil
+	return json.NewDecoder(file).Decode(v)
 }
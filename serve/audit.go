@@ -0,0 +1,115 @@
+package serve
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// AuditRecord describes one prediction request to a served model.
+type AuditRecord struct {
+	// Time is when the request was received.
+	Time time.Time
+
+	// Model and Version identify the model that answered.
+	Model   string
+	Version string
+
+	// Features holds the rows as received, or the encoding of the records
+	// as received, with NaN for missing cells. It is nil if the request body
+	// could not be decoded or its records could not be encoded.
+	Features [][]float64
+
+	// Predictions and Labels are the response: probabilities and
+	// thresholded labels for logloss models, expected values or raw
+	// predictions and no labels otherwise. Both are nil if Err is set.
+	Predictions []float64
+	Labels      []int
+
+	// Partial reports whether the model's Budget ran out before every tree
+	// was evaluated.
+	Partial bool
+
+	// Latency is the time spent decoding and scoring the request, up to but
+	// not including writing the response.
+	Latency time.Duration
+
+	// Err is the error returned to the client, if any.
+	Err error
+}
+
+// AuditHook receives a record for every request to a served model, so that
+// requests can be logged or captured for monitoring and retraining.
+//
+// Audit is called on the request's goroutine after the response has been
+// written, so it adds no latency for the client but does hold the
+// connection. Implementations must be safe for concurrent use and should
+// hand slow work, such as producing to a message queue, to a background
+// goroutine. The record's slices are not reused by the server and may be
+// retained.
+type AuditHook interface {
+	Audit(rec AuditRecord)
+}
+
+// AuditFunc adapts an ordinary function to the [AuditHook] interface.
+type AuditFunc func(rec AuditRecord)
+
+// Audit calls f(rec).
+func (f AuditFunc) Audit(rec AuditRecord) {
+	f(rec)
+}
+
+// JSONAuditLog is an [AuditHook] that writes each record to an io.Writer as
+// one line of JSON. Missing feature values are written as null.
+type JSONAuditLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONAuditLog returns a [JSONAuditLog] writing to w.
+func NewJSONAuditLog(w io.Writer) *JSONAuditLog {
+	return &JSONAuditLog{enc: json.NewEncoder(w)}
+}
+
+type auditLine struct {
+	Time        time.Time    `json:"time"`
+	Model       string       `json:"model"`
+	Version     string       `json:"version"`
+	Features    [][]*float64 `json:"features"`
+	Predictions []float64    `json:"predictions,omitempty"`
+	Labels      []int        `json:"labels,omitempty"`
+	Partial     bool         `json:"partial,omitempty"`
+	LatencyMS   float64      `json:"latency_ms"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// Audit implements [AuditHook]. Write errors are ignored.
+func (l *JSONAuditLog) Audit(rec AuditRecord) {
+	line := auditLine{
+		Time:        rec.Time,
+		Model:       rec.Model,
+		Version:     rec.Version,
+		Features:    make([][]*float64, len(rec.Features)),
+		Predictions: rec.Predictions,
+		Labels:      rec.Labels,
+		Partial:     rec.Partial,
+		LatencyMS:   float64(rec.Latency) / float64(time.Millisecond),
+	}
+	for i, row := range rec.Features {
+		line.Features[i] = make([]*float64, len(row))
+		for j := range row {
+			if !math.IsNaN(row[j]) {
+				line.Features[i][j] = &row[j]
+			}
+		}
+	}
+	if rec.Err != nil {
+		line.Error = rec.Err.Error()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enc.Encode(line)
+}
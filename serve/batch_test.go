@@ -0,0 +1,108 @@
+package serve_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/serve"
+)
+
+func batchServer(t *testing.T, batch string) (*serve.Server, *gboost.GBM) {
+	t.Helper()
+	dir := t.TempDir()
+	gbm := writeModel(t, dir, "m.json", "logloss", 20)
+	srv, err := serve.Load(writeManifest(t, dir, "models:\n  - {name: m, path: m.json, batch: "+batch+"}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+	return srv, gbm
+}
+
+func TestMicroBatchConcurrentRequests(t *testing.T) {
+	srv, gbm := batchServer(t, "{max_delay: 20ms, workers: 4}")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			x := []float64{float64(i % 8), float64(i % 2)}
+			code, out := post(t, srv, "/predict/m", fmt.Sprintf(`{"rows": [[%g, %g], [%g, %g]]}`, x[0], x[1], x[0], x[1]))
+			if code != http.StatusOK {
+				errs <- fmt.Errorf("request %d: status %d, body %v", i, code, out)
+				return
+			}
+			preds := out["predictions"].([]any)
+			want := gbm.PredictProba(x)
+			if len(preds) != 2 || math.Abs(preds[0].(float64)-want) > 1e-12 || len(out["labels"].([]any)) != 2 {
+				errs <- fmt.Errorf("request %d: got %v, want two predictions of %v", i, out, want)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestMicroBatchFlushesOnMaxRows(t *testing.T) {
+	srv, _ := batchServer(t, "{max_delay: 1h, max_rows: 2}")
+
+	start := time.Now()
+	if code, out := post(t, srv, "/predict/m", `{"rows": [[1, 0], [6, 1]]}`); code != http.StatusOK {
+		t.Fatalf("status %d, body %v", code, out)
+	}
+	if elapsed := time.Since(start); elapsed > time.Minute {
+		t.Errorf("request took %v, want an immediate flush at max_rows", elapsed)
+	}
+}
+
+func TestMicroBatchWaitsForMaxDelay(t *testing.T) {
+	srv, _ := batchServer(t, "{max_delay: 30ms, max_rows: 1000}")
+
+	start := time.Now()
+	if code, out := post(t, srv, "/predict/m", `{"rows": [[1, 0]]}`); code != http.StatusOK {
+		t.Fatalf("status %d, body %v", code, out)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("request took %v, want at least the 30ms batch delay", elapsed)
+	}
+}
+
+func TestMicroBatchIsolatesBadRequests(t *testing.T) {
+	srv, _ := batchServer(t, "{max_delay: 50ms}")
+
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i, body := range []string{`{"rows": [[1, 0]]}`, `{"rows": [[1, 0, 5]]}`} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i], _ = post(t, srv, "/predict/m", body)
+		}()
+	}
+	wg.Wait()
+	if codes[0] != http.StatusOK || codes[1] != http.StatusBadRequest {
+		t.Errorf("statuses = %v, want [200 400]", codes)
+	}
+}
+
+func TestMicroBatchInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeModel(t, dir, "m.json", "mse", 5)
+	for _, batch := range []string{"{max_delay: soon}", "{max_delay: -1ms}", "{max_rows: -1}", "{workers: -2}"} {
+		_, err := serve.Load(writeManifest(t, dir, "models:\n  - {name: m, path: m.json, batch: "+batch+"}\n"))
+		if !errors.Is(err, serve.ErrInvalidManifest) {
+			t.Errorf("batch %s: err = %v, want ErrInvalidManifest", batch, err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package serve
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sync"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+const (
+	defaultDriftWindow    = 10000
+	defaultDriftThreshold = 0.2
+
+	// reservoirSize is the number of values per feature and window sampled
+	// for quantile estimates.
+	reservoirSize = 1024
+
+	// psiFloor replaces empty bin proportions so that the PSI stays finite.
+	psiFloor = 1e-4
+)
+
+// DriftOptions enables input drift monitoring for a served model.
+//
+// The server compares the rows sent to the model with the training data
+// described by Reference, in consecutive windows of Window rows. For each
+// feature it tracks the missing rate, the mean, quantiles estimated from a
+// uniform sample of the window, and the share of values in each bin of the
+// reference histogram, plus the shares below and above the training range.
+// Because categorical features are label-encoded, their histogram bins track
+// category frequencies. Each feature's drift is the population stability
+// index (PSI) of these shares against the reference:
+//
+//	PSI = Σ (live - ref) · ln(live / ref)
+//
+// A PSI below 0.1 is conventionally read as no shift, 0.1 to 0.2 as a
+// moderate shift, and above 0.2 as a significant one.
+type DriftOptions struct {
+	// Reference profiles the training data, as returned by
+	// [gboost.Describe]. It must describe the rows as they are sent to the
+	// server, before any pipeline steps.
+	Reference *gboost.DatasetProfile
+
+	// Window is the number of rows per monitoring window. Zero means 10000.
+	Window int
+
+	// Threshold is the PSI at or above which a feature counts as drifted.
+	// Zero means 0.2.
+	Threshold float64
+}
+
+func (o *DriftOptions) window() int {
+	if o.Window == 0 {
+		return defaultDriftWindow
+	}
+	return o.Window
+}
+
+func (o *DriftOptions) threshold() float64 {
+	if o.Threshold == 0 {
+		return defaultDriftThreshold
+	}
+	return o.Threshold
+}
+
+func (o *DriftOptions) validate(numFeatures int) error {
+	ref := o.Reference
+	switch {
+	case ref == nil || len(ref.Features) == 0:
+		return fmt.Errorf("drift reference has no features")
+	case numFeatures > 0 && len(ref.Features) != numFeatures:
+		return fmt.Errorf("drift reference has %d features, model expects %d", len(ref.Features), numFeatures)
+	case o.Window < 0:
+		return fmt.Errorf("drift window %d is negative", o.Window)
+	case !(o.Threshold >= 0) || math.IsInf(o.Threshold, 0):
+		return fmt.Errorf("drift threshold %v is not a non-negative number", o.Threshold)
+	}
+	for j, f := range ref.Features {
+		if f.Count == 0 || len(f.Histogram.Counts) == 0 || len(f.Histogram.Edges) != len(f.Histogram.Counts)+1 {
+			return fmt.Errorf("drift reference feature %d has no histogram", j)
+		}
+	}
+	return nil
+}
+
+// DriftReport compares one window of live rows with the training data.
+type DriftReport struct {
+	Model string `json:"model"`
+
+	// Rows is the number of rows in the window, and Complete whether the
+	// window is full. Reports on partial windows are noisier.
+	Rows     int  `json:"rows"`
+	Complete bool `json:"complete"`
+
+	// Score is the largest feature PSI, and Drifted whether it reaches
+	// Threshold.
+	Score     float64 `json:"score"`
+	Threshold float64 `json:"threshold"`
+	Drifted   bool    `json:"drifted"`
+
+	// QuantileLevels holds the levels of every feature's Quantiles, taken
+	// from the reference profile.
+	QuantileLevels []float64 `json:"quantile_levels"`
+
+	Features []FeatureDrift `json:"features"`
+}
+
+// FeatureDrift compares one feature's live values with the training data.
+// Live statistics are zero when the window has no values for the feature.
+type FeatureDrift struct {
+	Index int    `json:"index"`
+	Name  string `json:"name,omitempty"`
+
+	// PSI is the population stability index of the live histogram against
+	// the reference histogram.
+	PSI float64 `json:"psi"`
+
+	// MissingRate is the share of rows with a missing value.
+	MissingRate          float64 `json:"missing_rate"`
+	ReferenceMissingRate float64 `json:"reference_missing_rate"`
+
+	// OutOfRange is the share of present values outside the training range.
+	OutOfRange float64 `json:"out_of_range"`
+
+	// MeanShift is the difference of the means in reference standard
+	// deviations, or 0 for a constant reference feature.
+	Mean          float64 `json:"mean"`
+	ReferenceMean float64 `json:"reference_mean"`
+	MeanShift     float64 `json:"mean_shift"`
+
+	Quantiles          []float64 `json:"quantiles,omitempty"`
+	ReferenceQuantiles []float64 `json:"reference_quantiles"`
+}
+
+// driftMonitor accumulates the live statistics of one model's inputs.
+type driftMonitor struct {
+	model string
+	opts  DriftOptions
+
+	mu   sync.Mutex
+	rnd  *rand.Rand
+	cur  []featureWindow
+	rows int
+	last *DriftReport
+}
+
+// featureWindow holds one feature's statistics for the current window.
+type featureWindow struct {
+	count, missing int
+	sum            float64
+	below, above   int
+	bins           []int
+	sample         []float64
+}
+
+func newDriftMonitor(model string, opts DriftOptions) *driftMonitor {
+	d := &driftMonitor{model: model, opts: opts, rnd: rand.New(rand.NewSource(1))}
+	d.reset()
+	return d
+}
+
+func (d *driftMonitor) reset() {
+	d.rows = 0
+	d.cur = make([]featureWindow, len(d.opts.Reference.Features))
+	for j, f := range d.opts.Reference.Features {
+		d.cur[j].bins = make([]int, len(f.Histogram.Counts))
+	}
+}
+
+// observe adds the rows of X to the current window and returns a report for
+// every window completed by them. Rows of the wrong width are ignored.
+func (d *driftMonitor) observe(X [][]float64) []DriftReport {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var done []DriftReport
+	for _, row := range X {
+		if len(row) != len(d.cur) {
+			continue
+		}
+		for j, v := range row {
+			d.add(j, v)
+		}
+		d.rows++
+		if d.rows == d.opts.window() {
+			r := d.report(true)
+			d.last = &r
+			done = append(done, r)
+			d.reset()
+		}
+	}
+	return done
+}
+
+func (d *driftMonitor) add(j int, v float64) {
+	w := &d.cur[j]
+	if math.IsNaN(v) {
+		w.missing++
+		return
+	}
+	w.count++
+	w.sum += v
+
+	edges := d.opts.Reference.Features[j].Histogram.Edges
+	switch {
+	case v < edges[0]:
+		w.below++
+	case v > edges[len(edges)-1]:
+		w.above++
+	default:
+		// Index of the first edge above v, less one, is v's bin, as in
+		// gboost's histograms.
+		b, _ := slices.BinarySearchFunc(edges[1:], v, func(e, v float64) int {
+			if e > v {
+				return 1
+			}
+			return -1
+		})
+		w.bins[min(b, len(w.bins)-1)]++
+	}
+
+	// Reservoir sampling keeps a uniform sample of the window's values.
+	if len(w.sample) < reservoirSize {
+		w.sample = append(w.sample, v)
+	} else if k := d.rnd.Intn(w.count); k < reservoirSize {
+		w.sample[k] = v
+	}
+}
+
+// current returns a report on the current window, or on the last complete
+// one while the current window is empty.
+func (d *driftMonitor) current() DriftReport {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.rows == 0 && d.last != nil {
+		return *d.last
+	}
+	return d.report(false)
+}
+
+func (d *driftMonitor) report(complete bool) DriftReport {
+	ref := d.opts.Reference
+	r := DriftReport{
+		Model:          d.model,
+		Rows:           d.rows,
+		Complete:       complete,
+		Threshold:      d.opts.threshold(),
+		QuantileLevels: ref.Quantiles,
+		Features:       make([]FeatureDrift, len(ref.Features)),
+	}
+	for j, f := range ref.Features {
+		w := &d.cur[j]
+		refTotal := float64(f.Count + f.Missing)
+		fd := FeatureDrift{
+			Index:                f.Index,
+			Name:                 f.Name,
+			ReferenceMissingRate: float64(f.Missing) / refTotal,
+			ReferenceMean:        f.Mean,
+			ReferenceQuantiles:   f.Quantiles,
+		}
+
+		if total := float64(w.count + w.missing); total > 0 {
+			// Shares of missing, below-range, per-bin, and above-range
+			// values; the reference has none outside its own range.
+			live := []float64{float64(w.missing) / total, float64(w.below) / total, float64(w.above) / total}
+			want := []float64{fd.ReferenceMissingRate, 0, 0}
+			for b, c := range w.bins {
+				live = append(live, float64(c)/total)
+				want = append(want, float64(f.Histogram.Counts[b])/refTotal)
+			}
+			fd.PSI = psi(live, want)
+			fd.MissingRate = float64(w.missing) / total
+		}
+		if w.count > 0 {
+			fd.OutOfRange = float64(w.below+w.above) / float64(w.count)
+			fd.Mean = w.sum / float64(w.count)
+			if f.Std > 0 {
+				fd.MeanShift = (fd.Mean - f.Mean) / f.Std
+			}
+			sorted := slices.Clone(w.sample)
+			slices.Sort(sorted)
+			fd.Quantiles = make([]float64, len(ref.Quantiles))
+			for i, q := range ref.Quantiles {
+				fd.Quantiles[i] = quantile(sorted, q)
+			}
+		}
+
+		r.Features[j] = fd
+		r.Score = max(r.Score, fd.PSI)
+	}
+	r.Drifted = r.Rows > 0 && r.Score >= r.Threshold
+	return r
+}
+
+// psi returns the population stability index of the live proportions
+// against the reference ones.
+func psi(live, ref []float64) float64 {
+	var sum float64
+	for b := range live {
+		p, q := max(live[b], psiFloor), max(ref[b], psiFloor)
+		sum += (p - q) * math.Log(p/q)
+	}
+	return sum
+}
+
+// quantile returns the q-quantile of sorted, interpolating linearly between
+// order statistics as gboost does.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := min(lo+1, len(sorted)-1)
+	return sorted[lo] + (pos-float64(lo))*(sorted[hi]-sorted[lo])
+}
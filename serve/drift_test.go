@@ -0,0 +1,176 @@
+package serve_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/serve"
+)
+
+// driftServer serves a regression model on two uniform features with a drift
+// profile of its training data.
+func driftServer(t *testing.T, window int) *serve.Server {
+	t.Helper()
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 500)
+	y := make([]float64, len(X))
+	for i := range X {
+		X[i] = []float64{rnd.Float64(), rnd.Float64()}
+		y[i] = X[i][0] + X[i][1]
+	}
+	cfg := gboost.DefaultConfig()
+	cfg.NEstimators = 10
+	gbm := gboost.New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	profile, err := gboost.Describe(X, y, gboost.DescribeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := gbm.Save(filepath.Join(dir, "m.json")); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "profile.json"), raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv, err := serve.Load(writeManifest(t, dir, fmt.Sprintf(`
+models:
+  - name: m
+    path: m.json
+    drift: {profile: profile.json, window: %d}
+`, window)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+// sendRows posts n rows, 100 per request, drawing feature 0 from f0 and
+// feature 1 uniformly.
+func sendRows(t *testing.T, srv *serve.Server, rnd *rand.Rand, n int, f0 func() float64) {
+	t.Helper()
+	for sent := 0; sent < n; sent += 100 {
+		rows := make([]string, 100)
+		for i := range rows {
+			rows[i] = fmt.Sprintf("[%g, %g]", f0(), rnd.Float64())
+		}
+		if code, out := post(t, srv, "/predict/m", `{"rows": [`+strings.Join(rows, ",")+`]}`); code != http.StatusOK {
+			t.Fatalf("status %d, body %v", code, out)
+		}
+	}
+}
+
+func TestDriftMatchingInputs(t *testing.T) {
+	srv := driftServer(t, 1000)
+	var alerts []serve.DriftReport
+	srv.DriftAlert = func(r serve.DriftReport) { alerts = append(alerts, r) }
+
+	rnd := rand.New(rand.NewSource(1))
+	sendRows(t, srv, rnd, 1000, rnd.Float64)
+
+	r, ok := srv.Drift("m")
+	if !ok {
+		t.Fatal("model has no drift monitoring")
+	}
+	if !r.Complete || r.Rows != 1000 || r.Drifted || len(alerts) != 0 {
+		t.Errorf("report rows %d complete %v score %v, %d alerts; want a quiet complete window", r.Rows, r.Complete, r.Score, len(alerts))
+	}
+	if r.Score >= 0.1 {
+		t.Errorf("PSI %v for inputs drawn like the training data, want < 0.1", r.Score)
+	}
+	if f := r.Features[0]; f.MeanShift > 0.2 || f.MeanShift < -0.2 || f.OutOfRange > 0.02 || len(f.Quantiles) != len(r.QuantileLevels) {
+		t.Errorf("feature 0 = %+v", f)
+	}
+}
+
+func TestDriftShiftedInputs(t *testing.T) {
+	srv := driftServer(t, 1000)
+	var alerts []serve.DriftReport
+	srv.DriftAlert = func(r serve.DriftReport) { alerts = append(alerts, r) }
+
+	// Feature 0 moves from U(0, 1) to U(0.5, 1.5).
+	rnd := rand.New(rand.NewSource(1))
+	sendRows(t, srv, rnd, 1500, func() float64 { return 0.5 + rnd.Float64() })
+
+	if len(alerts) != 1 {
+		t.Fatalf("got %d alerts, want 1 for the one complete window", len(alerts))
+	}
+	a := alerts[0]
+	if !a.Drifted || a.Features[0].PSI < 0.2 || a.Features[1].PSI >= 0.1 {
+		t.Errorf("alert PSI %v and %v, want only feature 0 drifted", a.Features[0].PSI, a.Features[1].PSI)
+	}
+	if f := a.Features[0]; f.OutOfRange < 0.4 || f.MeanShift < 1 {
+		t.Errorf("feature 0 out of range %v, mean shift %v; want about half out of range and a large shift", f.OutOfRange, f.MeanShift)
+	}
+
+	// The next window is in progress.
+	r, _ := srv.Drift("m")
+	if r.Complete || r.Rows != 500 {
+		t.Errorf("current window has %d rows (complete %v), want 500 in progress", r.Rows, r.Complete)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/drift/m", nil))
+	var got serve.DriftReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || rec.Code != http.StatusOK || got.Rows != 500 {
+		t.Errorf("GET /drift/m: status %d, rows %d, err %v", rec.Code, got.Rows, err)
+	}
+}
+
+func TestDriftInvalid(t *testing.T) {
+	dir := t.TempDir()
+	gbm := writeModel(t, dir, "m.json", "mse", 5)
+	narrow, err := gboost.Describe([][]float64{{1}, {2}}, nil, gboost.DescribeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wide, err := gboost.Describe(trainX, trainY, gboost.DescribeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts serve.DriftOptions
+	}{
+		{"no reference", serve.DriftOptions{}},
+		{"feature count", serve.DriftOptions{Reference: narrow}},
+		{"negative window", serve.DriftOptions{Reference: wide, Window: -1}},
+		{"negative threshold", serve.DriftOptions{Reference: wide, Threshold: -0.1}},
+	}
+	for _, tt := range tests {
+		m := &serve.Model{Name: "m", Pipeline: gboost.NewPipeline(gbm), Drift: &tt.opts}
+		if _, err := serve.New(m); !errors.Is(err, serve.ErrInvalidManifest) {
+			t.Errorf("%s: err = %v, want ErrInvalidManifest", tt.name, err)
+		}
+	}
+
+	srv, err := serve.New(&serve.Model{Name: "m", Pipeline: gboost.NewPipeline(gbm)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.Drift("m"); ok {
+		t.Error("Drift reported monitoring for a model without drift options")
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/drift/m", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /drift/m: status %d, want 404", rec.Code)
+	}
+}
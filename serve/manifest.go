@@ -0,0 +1,231 @@
+package serve
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ahmedaabouzied/gboost"
+)
+
+// Manifest lists the models served by one process.
+type Manifest struct {
+	Models []ManifestEntry `json:"models"`
+}
+
+// ManifestEntry names one model. Exactly one of Path, a model saved with
+// [gboost.GBM.Save] or, with the extension .onnx, a tree ensemble read by
+// [gboost.LoadONNX], and Pipeline, a pipeline saved with
+// [gboost.Pipeline.Save] whose fitted encoders are applied to every request,
+// must be set. Relative paths are resolved against the manifest's directory.
+type ManifestEntry struct {
+	Name     string `json:"name"`
+	Path     string `json:"path,omitempty"`
+	Pipeline string `json:"pipeline,omitempty"`
+
+	// Version identifies the model in audit records. It defaults to the first
+	// 12 hex digits of the SHA-256 of the model file, so retraining in place
+	// changes the version.
+	Version string `json:"version,omitempty"`
+
+	// Challenger names another model in the manifest that scores every
+	// request to this one in the background. Both predictions are written to
+	// the shadow log; responses only ever come from this model.
+	Challenger string `json:"challenger,omitempty"`
+
+	// Threshold is the probability at or above which a logloss model labels
+	// a row 1. It defaults to the model's [gboost.GBM.DecisionThreshold],
+	// 0.5 unless it was trained with a cost matrix, and may not be set for
+	// regression models.
+	Threshold *float64 `json:"threshold,omitempty"`
+
+	// Unseen overrides the pipeline encoder's policy for categories not
+	// seen in training when requests send raw records. It requires a
+	// pipeline saved with a [gboost.CategoryEncoder].
+	Unseen gboost.UnseenPolicy `json:"unseen,omitempty"`
+
+	// Drift enables input drift monitoring.
+	Drift *DriftConfig `json:"drift,omitempty"`
+
+	// Batch enables micro-batching.
+	Batch *BatchConfig `json:"batch,omitempty"`
+
+	// Budget is the model's latency budget per request as a Go duration
+	// string such as "5ms"; see [Model.Budget].
+	Budget string `json:"budget,omitempty"`
+}
+
+// BatchConfig configures [MicroBatch] in a manifest. MaxDelay is a Go
+// duration string such as "2ms".
+type BatchConfig struct {
+	MaxDelay string `json:"max_delay,omitempty"`
+	MaxRows  int    `json:"max_rows,omitempty"`
+	Workers  int    `json:"workers,omitempty"`
+}
+
+// DriftConfig configures [DriftOptions] in a manifest.
+type DriftConfig struct {
+	// Profile is the path of the training data's [gboost.DatasetProfile],
+	// written as JSON. Relative paths are resolved like model paths.
+	Profile string `json:"profile"`
+
+	Window    int     `json:"window,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// LoadManifest reads a [Manifest] from a JSON file, or from a YAML file if
+// path ends in .yaml or .yml, with [gboost.DecodeFile], which rejects
+// unknown keys.
+func LoadManifest(path string) (*Manifest, error) {
+	var m Manifest
+	if err := gboost.DecodeFile(path, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Load reads the manifest at path and loads every model it lists.
+func Load(path string) (*Server, error) {
+	m, err := LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	return m.Load(filepath.Dir(path))
+}
+
+// Load loads every model in m, resolving relative paths against dir, and
+// returns a [Server] for them.
+//
+// Returns an error wrapping [ErrInvalidManifest] if m lists no models, a
+// name is empty or repeated, an entry does not set exactly one of Path and
+// Pipeline, an unseen category policy is invalid or set without a pipeline
+// encoder, a threshold is out of range or set on a regression model, a
+// challenger is missing or expects a different number of features, a drift
+// profile does not match its model, batch options or the budget are
+// invalid, or a budget is set together with batch options.
+func (m *Manifest) Load(dir string) (*Server, error) {
+	if len(m.Models) == 0 {
+		return nil, fmt.Errorf("%w: no models", ErrInvalidManifest)
+	}
+	resolve := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+
+	models := make([]*Model, 0, len(m.Models))
+	seen := make(map[string]bool, len(m.Models))
+	for i, e := range m.Models {
+		switch {
+		case e.Name == "":
+			return nil, fmt.Errorf("%w: model %d has no name", ErrInvalidManifest, i)
+		case seen[e.Name]:
+			return nil, fmt.Errorf("%w: duplicate model name %q", ErrInvalidManifest, e.Name)
+		case (e.Path == "") == (e.Pipeline == ""):
+			return nil, fmt.Errorf("%w: model %q: set exactly one of path and pipeline", ErrInvalidManifest, e.Name)
+		}
+		seen[e.Name] = true
+
+		model := &Model{Name: e.Name, Version: e.Version, Challenger: e.Challenger}
+		file := resolve(e.Path)
+		if e.Path != "" {
+			load := gboost.Load
+			if strings.EqualFold(filepath.Ext(e.Path), ".onnx") {
+				load = gboost.LoadONNX
+			}
+			gbm, err := load(file)
+			if err != nil {
+				return nil, fmt.Errorf("model %q: load %s: %w", e.Name, e.Path, err)
+			}
+			model.Pipeline = gboost.NewPipeline(gbm)
+		} else {
+			file = resolve(e.Pipeline)
+			p, err := gboost.LoadPipeline(file)
+			if err != nil {
+				return nil, fmt.Errorf("model %q: load %s: %w", e.Name, e.Pipeline, err)
+			}
+			model.Pipeline = p
+		}
+		model.Threshold = model.Pipeline.Model.DecisionThreshold()
+		if model.Version == "" {
+			v, err := fileVersion(file)
+			if err != nil {
+				return nil, fmt.Errorf("model %q: %w", e.Name, err)
+			}
+			model.Version = v
+		}
+
+		if e.Drift != nil {
+			var profile gboost.DatasetProfile
+			if err := readJSON(resolve(e.Drift.Profile), &profile); err != nil {
+				return nil, fmt.Errorf("model %q: load drift profile %s: %w", e.Name, e.Drift.Profile, err)
+			}
+			model.Drift = &DriftOptions{Reference: &profile, Window: e.Drift.Window, Threshold: e.Drift.Threshold}
+		}
+
+		if e.Batch != nil {
+			model.Batch = &MicroBatch{MaxRows: e.Batch.MaxRows, Workers: e.Batch.Workers}
+			if e.Batch.MaxDelay != "" {
+				d, err := time.ParseDuration(e.Batch.MaxDelay)
+				if err != nil {
+					return nil, fmt.Errorf("%w: model %q: %v", ErrInvalidManifest, e.Name, err)
+				}
+				model.Batch.MaxDelay = d
+			}
+		}
+
+		if e.Budget != "" {
+			d, err := time.ParseDuration(e.Budget)
+			if err != nil {
+				return nil, fmt.Errorf("%w: model %q: %v", ErrInvalidManifest, e.Name, err)
+			}
+			model.Budget = d
+		}
+
+		if e.Unseen != "" {
+			switch {
+			case model.Pipeline.Encoder == nil:
+				return nil, fmt.Errorf("%w: model %q: unseen set without a pipeline encoder", ErrInvalidManifest, e.Name)
+			case !slices.Contains([]gboost.UnseenPolicy{gboost.UnseenError, gboost.UnseenOther, gboost.UnseenMostFrequent, gboost.UnseenMissing}, e.Unseen):
+				return nil, fmt.Errorf("%w: model %q: %v", ErrInvalidManifest, e.Name, gboost.ErrInvalidUnseenPolicy)
+			}
+			enc := *model.Pipeline.Encoder
+			enc.Unseen = e.Unseen
+			model.Pipeline.Encoder = &enc
+		}
+
+		if e.Threshold != nil {
+			if !model.Classifier() {
+				return nil, fmt.Errorf("%w: model %q: threshold set on a %s model", ErrInvalidManifest, e.Name, model.Pipeline.Model.Config.Loss)
+			}
+			model.Threshold = *e.Threshold
+		}
+		models = append(models, model)
+	}
+	return New(models...)
+}
+
+func readJSON(path string, v any) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// fileVersion returns the first 12 hex digits of the SHA-256 of a file.
+func fileVersion(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])[:12], nil
+}
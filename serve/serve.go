@@ -0,0 +1,518 @@
+// Package serve exposes gboost models over HTTP.
+//
+// A [Server] hosts several named models in one process, each a plain model
+// or a [gboost.Pipeline] whose fitted encoders are applied to every request,
+// with its own classification threshold. Models are usually listed in a
+// [Manifest] file and loaded with [Load]. The server answers two endpoints:
+//
+//	GET  /models            list the served models
+//	POST /predict/{model}   score {"rows": [[...], ...]}; null cells are missing
+//
+// Requests may instead send {"records": [[...], ...]} of raw string cells,
+// encoded by the pipeline's [gboost.CategoryEncoder] and its policy for
+// categories not seen in training.
+//
+// Responses hold probabilities and thresholded labels for logloss models,
+// expected values for gamma models, and raw predictions otherwise. A model
+// with a latency budget answers with the trees it could evaluate in time
+// and a completeness flag. A model may name a challenger that scores the
+// same requests in the background (see [Server.StartShadow]), an
+// [AuditHook] receives every request for logging or monitoring, and models
+// with [DriftOptions] compare their live inputs with the training data:
+//
+//	GET  /drift/{model}     drift report on the model's recent inputs
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/internal/fpmath"
+)
+
+// Errors returned by [New] and [Manifest.Load].
+var (
+	ErrInvalidManifest = errors.New("serve: invalid manifest")
+)
+
+// maxRequestBytes bounds the size of a prediction request body.
+const maxRequestBytes = 32 << 20
+
+// Model is a model served under a name.
+type Model struct {
+	Name string
+
+	// Version identifies the model in audit records.
+	Version string
+
+	// Pipeline scores requests. Plain models are wrapped in a pipeline
+	// without steps.
+	Pipeline *gboost.Pipeline
+
+	// Threshold is the probability at or above which a logloss model labels
+	// a row 1. It is ignored for regression models.
+	Threshold float64
+
+	// Challenger names another served model that shadows this one.
+	Challenger string
+
+	// Drift, if set, enables input drift monitoring.
+	Drift *DriftOptions
+
+	// Batch, if set, enables micro-batching.
+	Batch *MicroBatch
+
+	// Budget, if positive, bounds the time spent on a request: trees are
+	// evaluated heaviest first until Budget has passed since the request
+	// arrived, and the response reports whether the scores are complete
+	// (see [gboost.BudgetPredictor]). It cannot be combined with Batch.
+	Budget time.Duration
+}
+
+// Classifier reports whether the model was trained with logloss.
+func (m *Model) Classifier() bool {
+	return m.Pipeline.Model.Config.Loss == "logloss"
+}
+
+// NumFeatures returns the width of the rows the model expects, or 0 when the
+// pipeline has steps, which may change the width and check it themselves.
+func (m *Model) NumFeatures() int {
+	if len(m.Pipeline.Steps) > 0 {
+		return 0
+	}
+	return m.Pipeline.Model.NumFeatures()
+}
+
+// transform applies the pipeline's steps to X and checks the width of the
+// result.
+func (m *Model) transform(X [][]float64) ([][]float64, error) {
+	Xt, err := m.Pipeline.Transform(X)
+	if err != nil {
+		return nil, err
+	}
+	nf := m.Pipeline.Model.NumFeatures()
+	for i, row := range Xt {
+		if len(row) != nf {
+			return nil, fmt.Errorf("row %d has %d features, expected %d", i, len(row), nf)
+		}
+	}
+	return Xt, nil
+}
+
+// score returns the model's output for X: probabilities and thresholded
+// labels for logloss models, expected values for gamma models, and raw
+// predictions otherwise. The rows are split across up to workers
+// goroutines, where 0 means GOMAXPROCS.
+func (m *Model) score(X [][]float64, workers int) (preds []float64, labels []int, err error) {
+	Xt, err := m.transform(X)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if workers == 1 {
+		if m.Classifier() {
+			preds = m.Pipeline.Model.PredictProbaAll(Xt)
+		} else {
+			preds = m.Pipeline.Model.Predict(Xt)
+		}
+	} else {
+		opts := gboost.BatchOptions{Workers: workers, Proba: m.Classifier()}
+		// Chunks small enough to give every worker a share of the batch.
+		opts.ChunkSize = max(64, len(Xt)/max(workers, runtime.GOMAXPROCS(0)))
+		if preds, err = m.Pipeline.Model.PredictBatch(context.Background(), Xt, opts); err != nil {
+			return nil, nil, err
+		}
+	}
+	m.expected(preds)
+	return preds, m.labels(preds), nil
+}
+
+// scoreBudget is like score, but scores X with p until deadline.
+func (m *Model) scoreBudget(X [][]float64, p *gboost.BudgetPredictor, deadline time.Time) (res gboost.BudgetResult, labels []int, err error) {
+	Xt, err := m.transform(X)
+	if err != nil {
+		return res, nil, err
+	}
+	if m.Classifier() {
+		res = p.PredictProba(Xt, deadline)
+	} else {
+		res = p.Predict(Xt, deadline)
+	}
+	m.expected(res.Predictions)
+	return res, m.labels(res.Predictions), nil
+}
+
+// expected replaces the raw predictions of a gamma model, the logs of the
+// expected values, with the expected values, and leaves those of other
+// models alone.
+func (m *Model) expected(preds []float64) {
+	if m.Pipeline.Model.Config.Loss != "gamma" {
+		return
+	}
+	for i, raw := range preds {
+		preds[i] = fpmath.Exp(raw)
+	}
+}
+
+// labels thresholds the probabilities of a logloss model, and returns nil
+// for regression models.
+func (m *Model) labels(preds []float64) []int {
+	if !m.Classifier() {
+		return nil
+	}
+	labels := make([]int, len(preds))
+	for i, p := range preds {
+		if p >= m.Threshold {
+			labels[i] = 1
+		}
+	}
+	return labels
+}
+
+// Server serves a fixed set of named models. It implements [http.Handler].
+type Server struct {
+	// Audit, if set, receives a record for every request to a served model.
+	// Set it before the server starts handling requests.
+	Audit AuditHook
+
+	// DriftAlert, if set, is called with the report on every complete drift
+	// monitoring window whose score reaches the model's threshold. It is
+	// called on the goroutine of the request that completed the window,
+	// after the response has been written. Set it before the server starts
+	// handling requests.
+	DriftAlert func(DriftReport)
+
+	models map[string]*Model
+	names  []string // sorted
+	mux    *http.ServeMux
+	drift  map[string]*driftMonitor
+	batch  map[string]*batcher
+	budget map[string]*gboost.BudgetPredictor
+
+	shadow        chan shadowJob
+	shadowDone    chan struct{}
+	shadowDropped atomic.Int64
+}
+
+// New returns a [Server] for models.
+//
+// Returns an error wrapping [ErrInvalidManifest] if a name is empty or
+// repeated, a model has no pipeline, a logloss model's threshold is outside
+// [0, 1], a challenger is missing, is the model itself, or expects a
+// different number of features, drift or batch options are invalid, or a
+// budget is negative or set together with batch options.
+//
+// Models with a [MicroBatch] are scored on background goroutines; call
+// [Server.Close] to stop them.
+func New(models ...*Model) (*Server, error) {
+	s := &Server{
+		models: make(map[string]*Model, len(models)),
+		drift:  make(map[string]*driftMonitor),
+		batch:  make(map[string]*batcher),
+		budget: make(map[string]*gboost.BudgetPredictor),
+	}
+	for i, m := range models {
+		switch {
+		case m.Name == "":
+			return nil, fmt.Errorf("%w: model %d has no name", ErrInvalidManifest, i)
+		case s.models[m.Name] != nil:
+			return nil, fmt.Errorf("%w: duplicate model name %q", ErrInvalidManifest, m.Name)
+		case m.Pipeline == nil || m.Pipeline.Model == nil:
+			return nil, fmt.Errorf("%w: model %q has no pipeline", ErrInvalidManifest, m.Name)
+		case m.Classifier() && !(m.Threshold >= 0 && m.Threshold <= 1):
+			return nil, fmt.Errorf("%w: model %q: threshold %v outside [0, 1]", ErrInvalidManifest, m.Name, m.Threshold)
+		case m.Budget < 0:
+			return nil, fmt.Errorf("%w: model %q: budget %v is negative", ErrInvalidManifest, m.Name, m.Budget)
+		case m.Budget > 0 && m.Batch != nil:
+			return nil, fmt.Errorf("%w: model %q: budget and batch cannot be combined", ErrInvalidManifest, m.Name)
+		}
+		if m.Budget > 0 {
+			p, err := m.Pipeline.Model.NewBudgetPredictor()
+			if err != nil {
+				return nil, fmt.Errorf("%w: model %q: %v", ErrInvalidManifest, m.Name, err)
+			}
+			s.budget[m.Name] = p
+		}
+		if m.Drift != nil {
+			if err := m.Drift.validate(m.NumFeatures()); err != nil {
+				return nil, fmt.Errorf("%w: model %q: %v", ErrInvalidManifest, m.Name, err)
+			}
+			s.drift[m.Name] = newDriftMonitor(m.Name, *m.Drift)
+		}
+		if m.Batch != nil {
+			if err := m.Batch.validate(); err != nil {
+				return nil, fmt.Errorf("%w: model %q: %v", ErrInvalidManifest, m.Name, err)
+			}
+		}
+		s.models[m.Name] = m
+		s.names = append(s.names, m.Name)
+	}
+	slices.Sort(s.names)
+
+	for _, name := range s.names {
+		m := s.models[name]
+		if m.Challenger == "" {
+			continue
+		}
+		c, ok := s.models[m.Challenger]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%w: model %q: challenger %q is not served", ErrInvalidManifest, name, m.Challenger)
+		case m.Challenger == name:
+			return nil, fmt.Errorf("%w: model %q: a model cannot challenge itself", ErrInvalidManifest, name)
+		case m.NumFeatures() > 0 && c.NumFeatures() > 0 && m.NumFeatures() != c.NumFeatures():
+			return nil, fmt.Errorf("%w: model %q: challenger %q expects %d features, not %d", ErrInvalidManifest, name, m.Challenger, c.NumFeatures(), m.NumFeatures())
+		}
+	}
+
+	for _, m := range models {
+		if m.Batch != nil {
+			s.batch[m.Name] = newBatcher(m)
+		}
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /models", s.handleModels)
+	s.mux.HandleFunc("POST /predict/{model}", s.handlePredict)
+	s.mux.HandleFunc("GET /drift/{model}", s.handleDrift)
+	return s, nil
+}
+
+// Model returns the model served under name, or nil.
+func (s *Server) Model(name string) *Model {
+	return s.models[name]
+}
+
+// Models returns the served models, sorted by name.
+func (s *Server) Models() []*Model {
+	models := make([]*Model, len(s.names))
+	for i, name := range s.names {
+		models[i] = s.models[name]
+	}
+	return models
+}
+
+// Close stops the goroutines that score micro-batches, once the requests
+// already queued are answered. Call it once the server has stopped handling
+// requests.
+func (s *Server) Close() {
+	for _, b := range s.batch {
+		b.close()
+	}
+	s.batch = nil
+}
+
+// Drift returns the drift report on the current monitoring window of the
+// named model, or on the last complete window while the current one is
+// empty. It reports false if the model is not served or not monitored.
+func (s *Server) Drift(name string) (DriftReport, bool) {
+	d, ok := s.drift[name]
+	if !ok {
+		return DriftReport{}, false
+	}
+	return d.current(), true
+}
+
+// ServeHTTP implements [http.Handler].
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ModelInfo describes a served model in the /models response.
+type ModelInfo struct {
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	Loss       string   `json:"loss"`
+	Features   int      `json:"features,omitempty"`
+	Trees      int      `json:"trees"`
+	Steps      int      `json:"steps"`
+	Challenger string   `json:"challenger,omitempty"`
+	Threshold  *float64 `json:"threshold,omitempty"`
+	BudgetMS   float64  `json:"budget_ms,omitempty"`
+	Classifier bool     `json:"classifier"`
+	Drift      bool     `json:"drift"`
+}
+
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	infos := make([]ModelInfo, len(s.names))
+	for i, m := range s.Models() {
+		infos[i] = ModelInfo{
+			Name:       m.Name,
+			Version:    m.Version,
+			Loss:       m.Pipeline.Model.Config.Loss,
+			Features:   m.NumFeatures(),
+			Trees:      m.Pipeline.Model.NumTrees(),
+			Steps:      len(m.Pipeline.Steps),
+			Challenger: m.Challenger,
+			BudgetMS:   float64(m.Budget) / float64(time.Millisecond),
+			Classifier: m.Classifier(),
+			Drift:      m.Drift != nil,
+		}
+		if m.Classifier() {
+			infos[i].Threshold = &m.Threshold
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"models": infos})
+}
+
+// PredictRequest holds the rows to score, either as encoded numbers in
+// Rows or as raw strings in Records, which the model's pipeline encodes with
+// [gboost.Pipeline.Encode]. Exactly one must be set. A null cell is a
+// missing value.
+type PredictRequest struct {
+	Rows    [][]*float64 `json:"rows,omitempty"`
+	Records [][]*string  `json:"records,omitempty"`
+}
+
+// PredictResponse holds probabilities and thresholded labels for logloss
+// models, expected values for gamma models, and raw predictions for other
+// regression models. For models with a Budget, Complete reports whether
+// every tree was evaluated and Trees how many were; both are omitted
+// otherwise.
+type PredictResponse struct {
+	Model       string    `json:"model"`
+	Version     string    `json:"version"`
+	Predictions []float64 `json:"predictions"`
+	Labels      []int     `json:"labels,omitempty"`
+	Complete    *bool     `json:"complete,omitempty"`
+	Trees       int       `json:"trees,omitempty"`
+}
+
+func (s *Server) handlePredict(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	name := r.PathValue("model")
+	m, ok := s.models[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown model %q", name))
+		return
+	}
+
+	rec := AuditRecord{Time: start, Model: name, Version: m.Version}
+	status, resp := s.predict(w, m, r, &rec)
+	var windows []DriftReport
+	if d := s.drift[name]; d != nil && rec.Err == nil {
+		windows = d.observe(rec.Features)
+	}
+	rec.Latency = time.Since(start)
+	if rec.Err != nil {
+		writeError(w, status, rec.Err)
+	} else {
+		writeJSON(w, status, resp)
+	}
+	if s.Audit != nil {
+		s.Audit.Audit(rec)
+	}
+	if s.DriftAlert != nil {
+		for _, report := range windows {
+			if report.Drifted {
+				s.DriftAlert(report)
+			}
+		}
+	}
+}
+
+func (s *Server) handleDrift(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("model")
+	if _, ok := s.models[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown model %q", name))
+		return
+	}
+	report, ok := s.Drift(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("model %q has no drift monitoring", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// predict decodes and scores one request, filling in rec as it goes.
+func (s *Server) predict(w http.ResponseWriter, m *Model, r *http.Request, rec *AuditRecord) (int, *PredictResponse) {
+	var req PredictRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		rec.Err = fmt.Errorf("decode request: %w", err)
+		return http.StatusBadRequest, nil
+	}
+	if len(req.Rows) > 0 && len(req.Records) > 0 {
+		rec.Err = errors.New("request has both rows and records")
+		return http.StatusBadRequest, nil
+	}
+	if len(req.Rows) == 0 && len(req.Records) == 0 {
+		rec.Err = errors.New("request has no rows")
+		return http.StatusBadRequest, nil
+	}
+
+	var X [][]float64
+	var records [][]string
+	if len(req.Records) > 0 {
+		records = make([][]string, len(req.Records))
+		for i, row := range req.Records {
+			records[i] = make([]string, len(row))
+			for j, v := range row {
+				if v != nil {
+					records[i][j] = *v
+				}
+			}
+		}
+		var err error
+		if X, err = m.Pipeline.Encode(records); err != nil {
+			rec.Err = fmt.Errorf("model %q: %w", m.Name, err)
+			return http.StatusBadRequest, nil
+		}
+	} else {
+		X = make([][]float64, len(req.Rows))
+		for i, row := range req.Rows {
+			X[i] = make([]float64, len(row))
+			for j, v := range row {
+				if v == nil {
+					X[i][j] = math.NaN()
+				} else {
+					X[i][j] = *v
+				}
+			}
+		}
+	}
+	rec.Features = X
+
+	resp := &PredictResponse{Model: m.Name, Version: m.Version}
+	var err error
+	if p := s.budget[m.Name]; p != nil {
+		var res gboost.BudgetResult
+		res, resp.Labels, err = m.scoreBudget(X, p, rec.Time.Add(m.Budget))
+		resp.Predictions, resp.Trees, resp.Complete = res.Predictions, res.Trees, &res.Complete
+		rec.Partial = !res.Complete
+	} else if b := s.batch[m.Name]; b != nil {
+		resp.Predictions, resp.Labels, err = b.score(X)
+	} else {
+		resp.Predictions, resp.Labels, err = m.score(X, 1)
+	}
+	if err != nil {
+		rec.Err = fmt.Errorf("model %q: %w", m.Name, err)
+		return http.StatusBadRequest, nil
+	}
+	rec.Predictions, rec.Labels = resp.Predictions, resp.Labels
+	if m.Challenger != "" {
+		s.enqueueShadow(shadowJob{time: rec.Time, champion: m.Name, X: X, records: records, predictions: resp.Predictions})
+	}
+	return http.StatusOK, resp
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,444 @@
+package serve_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahmedaabouzied/gboost"
+	"github.com/ahmedaabouzied/gboost/serve"
+)
+
+var (
+	trainX = [][]float64{{0, 1}, {1, 0}, {2, 1}, {3, 0}, {4, 1}, {5, 0}, {6, 1}, {7, 0}}
+	trainY = []float64{0, 0, 0, 0, 1, 1, 1, 1}
+)
+
+// writeModel trains a model with the given loss and rounds and saves it to
+// dir/name.
+func writeModel(t *testing.T, dir, name, loss string, rounds int) *gboost.GBM {
+	t.Helper()
+	cfg := gboost.DefaultConfig()
+	cfg.Loss = loss
+	cfg.NEstimators = rounds
+	gbm := gboost.New(cfg)
+	if err := gbm.Fit(trainX, trainY); err != nil {
+		t.Fatal(err)
+	}
+	if err := gbm.Save(filepath.Join(dir, name)); err != nil {
+		t.Fatal(err)
+	}
+	return gbm
+}
+
+func writeManifest(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "models.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func post(t *testing.T, h http.Handler, path, body string) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	var out map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("POST %s: decode %q: %v", path, rec.Body.String(), err)
+	}
+	return rec.Code, out
+}
+
+func TestServerRoutesByName(t *testing.T) {
+	dir := t.TempDir()
+	clf := writeModel(t, dir, "clf.json", "logloss", 20)
+	reg := writeModel(t, dir, "reg.json", "mse", 20)
+	srv, err := serve.Load(writeManifest(t, dir, `
+models:
+  - name: clf
+    path: clf.json
+    threshold: 0.9
+  - name: reg
+    path: reg.json
+    version: v2
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, out := post(t, srv, "/predict/clf", `{"rows": [[6, 1], [1, null]]}`)
+	if code != http.StatusOK {
+		t.Fatalf("clf: status %d, body %v", code, out)
+	}
+	preds := out["predictions"].([]any)
+	if got, want := preds[0].(float64), clf.PredictProba([]float64{6, 1}); math.Abs(got-want) > 1e-12 {
+		t.Errorf("clf probability = %v, want %v", got, want)
+	}
+	// Labels must follow the manifest's threshold, not the default 0.5.
+	labels := out["labels"].([]any)
+	for i := range preds {
+		if want := preds[i].(float64) >= 0.9; (labels[i].(float64) == 1) != want {
+			t.Errorf("clf row %d: label %v for probability %v at threshold 0.9", i, labels[i], preds[i])
+		}
+	}
+	if v := out["version"].(string); len(v) != 12 {
+		t.Errorf("default version = %q, want 12 hex digits", v)
+	}
+
+	code, out = post(t, srv, "/predict/reg", `{"rows": [[6, 1]]}`)
+	if code != http.StatusOK {
+		t.Fatalf("reg: status %d, body %v", code, out)
+	}
+	if got, want := out["predictions"].([]any)[0].(float64), reg.PredictSingle([]float64{6, 1}); math.Abs(got-want) > 1e-12 {
+		t.Errorf("reg prediction = %v, want %v", got, want)
+	}
+	if _, ok := out["labels"]; ok || out["version"] != "v2" {
+		t.Errorf("reg response = %v, want version v2 and no labels", out)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/models", nil))
+	var list struct{ Models []serve.ModelInfo }
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Models) != 2 || list.Models[0].Name != "clf" || *list.Models[0].Threshold != 0.9 || list.Models[1].Threshold != nil {
+		t.Errorf("/models = %+v", list.Models)
+	}
+}
+
+func TestServerGammaModel(t *testing.T) {
+	cfg := gboost.DefaultConfig()
+	cfg.Loss = "gamma"
+	cfg.NEstimators = 20
+	gbm := gboost.New(cfg)
+	y := make([]float64, len(trainY))
+	for i, v := range trainY {
+		y[i] = 1 + 9*v
+	}
+	if err := gbm.Fit(trainX, y); err != nil {
+		t.Fatal(err)
+	}
+	for _, budget := range []time.Duration{0, time.Hour} {
+		srv, err := serve.New(&serve.Model{Name: "gamma", Pipeline: gboost.NewPipeline(gbm), Budget: budget})
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, out := post(t, srv, "/predict/gamma", `{"rows": [[6, 1], [1, 0]]}`)
+		if code != http.StatusOK {
+			t.Fatalf("budget %v: status %d, body %v", budget, code, out)
+		}
+		for i, x := range [][]float64{{6, 1}, {1, 0}} {
+			if got, want := out["predictions"].([]any)[i].(float64), math.Exp(gbm.PredictSingle(x)); math.Abs(got-want) > 1e-9*want {
+				t.Errorf("budget %v: prediction %d = %v, want the expected value %v", budget, i, got, want)
+			}
+		}
+	}
+}
+
+func TestServerONNXModel(t *testing.T) {
+	// testdata/regressor.onnx: 0.5 + (x0 <= 1 ? 10 : x2 > 5 ? 20 : 30) + (x2 < 0 ? -1 : 1).
+	path, err := filepath.Abs(filepath.Join("testdata", "regressor.onnx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := serve.Load(writeManifest(t, t.TempDir(), "models:\n  - {name: onnx, path: "+path+"}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, out := post(t, srv, "/predict/onnx", `{"rows": [[2, 0, 6], [0, 0, -1]]}`)
+	if code != http.StatusOK {
+		t.Fatalf("status %d, body %v", code, out)
+	}
+	preds := out["predictions"].([]any)
+	if preds[0].(float64) != 21.5 || preds[1].(float64) != 9.5 {
+		t.Errorf("predictions = %v, want [21.5 9.5]", preds)
+	}
+}
+
+func TestServerPipelineEncoders(t *testing.T) {
+	dir := t.TempDir()
+	cfg := gboost.DefaultConfig()
+	cfg.NEstimators = 20
+	p := gboost.NewPipeline(gboost.New(cfg), gboost.NewImputer(gboost.ImputeConstant))
+	X := [][]float64{{0, math.NaN()}, {1, 0}, {2, 1}, {3, 0}, {4, 1}, {5, 0}}
+	if err := p.Fit(X, []float64{0, 1, 2, 3, 4, 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Save(filepath.Join(dir, "pipe.json")); err != nil {
+		t.Fatal(err)
+	}
+	srv, err := serve.Load(writeManifest(t, dir, "models:\n  - name: p\n    pipeline: pipe.json\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := p.Predict([][]float64{{4, math.NaN()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, out := post(t, srv, "/predict/p", `{"rows": [[4, null]]}`)
+	if code != http.StatusOK || out["predictions"].([]any)[0].(float64) != want[0] {
+		t.Errorf("status %d, body %v, want prediction %v", code, out, want[0])
+	}
+}
+
+func TestServerRecordsUnseenPolicy(t *testing.T) {
+	dir := t.TempDir()
+	ds := &gboost.Dataset{
+		X:         [][]float64{{0, 0}, {1, 1}, {2, 1}, {3, 0}, {4, 1}, {5, 1}, {6, 0}, {7, 1}},
+		Y:         trainY,
+		Encodings: map[int]map[string]float64{1: {"red": 0, "blue": 1}},
+	}
+	cfg := gboost.DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 5
+	p := gboost.NewPipeline(gboost.New(cfg))
+	p.Encoder = ds.CategoryEncoder(gboost.UnseenError)
+	if err := p.Fit(ds.X, ds.Y); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Save(filepath.Join(dir, "pipe.json")); err != nil {
+		t.Fatal(err)
+	}
+	srv, err := serve.Load(writeManifest(t, dir, `
+models:
+  - {name: strict, pipeline: pipe.json}
+  - {name: lenient, pipeline: pipe.json, unseen: most_frequent}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := p.PredictProbaAll([][]float64{{6, 1}})
+	body := `{"records": [["6", "green"]]}`
+	if code, out := post(t, srv, "/predict/strict", body); code != http.StatusBadRequest || !strings.Contains(out["error"].(string), "not seen in training") {
+		t.Errorf("strict: status %d, body %v, want an unseen category error", code, out)
+	}
+	code, out := post(t, srv, "/predict/lenient", body)
+	if code != http.StatusOK || out["predictions"].([]any)[0].(float64) != want[0] {
+		t.Errorf("lenient: status %d, body %v, want prediction %v for the most frequent category", code, out, want[0])
+	}
+	if code, _ := post(t, srv, "/predict/lenient", `{"rows": [[6, 1]], "records": [["6", "blue"]]}`); code != http.StatusBadRequest {
+		t.Errorf("rows and records: status %d, want 400", code)
+	}
+}
+
+func TestServerRequestErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeModel(t, dir, "m.json", "mse", 5)
+	srv, err := serve.Load(writeManifest(t, dir, "models:\n  - name: m\n    path: m.json\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, body string
+		want       int
+	}{
+		{"/predict/other", `{"rows": [[1, 2]]}`, http.StatusNotFound},
+		{"/predict/m", `{"rows": []}`, http.StatusBadRequest},
+		{"/predict/m", `{"rows": [[1]]}`, http.StatusBadRequest},
+		{"/predict/m", `{"rowz": [[1, 2]]}`, http.StatusBadRequest},
+		{"/predict/m", `not json`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		code, out := post(t, srv, tt.path, tt.body)
+		if code != tt.want || out["error"] == nil {
+			t.Errorf("POST %s %s: status %d, body %v, want %d with an error", tt.path, tt.body, code, out, tt.want)
+		}
+	}
+}
+
+func TestServerBudget(t *testing.T) {
+	dir := t.TempDir()
+	clf := writeModel(t, dir, "clf.json", "logloss", 20)
+	srv, err := serve.Load(writeManifest(t, dir, "models:\n  - {name: clf, path: clf.json, budget: 1h}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, out := post(t, srv, "/predict/clf", `{"rows": [[6, 1]]}`)
+	if code != http.StatusOK {
+		t.Fatalf("status %d, body %v", code, out)
+	}
+	if out["complete"] != true || out["trees"] != float64(clf.NumTrees()) {
+		t.Errorf("generous budget: complete %v after %v trees, want all %d", out["complete"], out["trees"], clf.NumTrees())
+	}
+	if got, want := out["predictions"].([]any)[0].(float64), clf.PredictProba([]float64{6, 1}); math.Abs(got-want) > 1e-12 {
+		t.Errorf("probability = %v, want %v", got, want)
+	}
+
+	// A budget that has run out by the time the model is scored still
+	// evaluates the heaviest tree.
+	srv, err = serve.New(&serve.Model{Name: "clf", Pipeline: gboost.NewPipeline(clf), Threshold: 0.5, Budget: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var partial bool
+	srv.Audit = serve.AuditFunc(func(rec serve.AuditRecord) { partial = rec.Partial })
+	code, out = post(t, srv, "/predict/clf", `{"rows": [[6, 1], [1, 0]]}`)
+	if code != http.StatusOK {
+		t.Fatalf("status %d, body %v", code, out)
+	}
+	if out["complete"] != false || out["trees"] != 1.0 || len(out["labels"].([]any)) != 2 || !partial {
+		t.Errorf("exhausted budget: response %v, audit partial %v", out, partial)
+	}
+}
+
+func TestManifestInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeModel(t, dir, "clf.json", "logloss", 5)
+	writeModel(t, dir, "reg.json", "mse", 5)
+
+	tests := []struct {
+		name, manifest string
+	}{
+		{"no models", "models: []\n"},
+		{"no name", "models:\n  - path: clf.json\n"},
+		{"duplicate", "models:\n  - {name: a, path: clf.json}\n  - {name: a, path: reg.json}\n"},
+		{"path and pipeline", "models:\n  - {name: a, path: clf.json, pipeline: clf.json}\n"},
+		{"regression threshold", "models:\n  - {name: a, path: reg.json, threshold: 0.5}\n"},
+		{"threshold range", "models:\n  - {name: a, path: clf.json, threshold: 1.5}\n"},
+		{"unknown challenger", "models:\n  - {name: a, path: clf.json, challenger: b}\n"},
+		{"self challenger", "models:\n  - {name: a, path: clf.json, challenger: a}\n"},
+		{"unseen without encoder", "models:\n  - {name: a, path: clf.json, unseen: missing}\n"},
+		{"bad budget", "models:\n  - {name: a, path: clf.json, budget: fast}\n"},
+		{"negative budget", "models:\n  - {name: a, path: clf.json, budget: -1ms}\n"},
+		{"budget and batch", "models:\n  - {name: a, path: clf.json, budget: 5ms, batch: {}}\n"},
+	}
+	for _, tt := range tests {
+		if _, err := serve.Load(writeManifest(t, dir, tt.manifest)); !errors.Is(err, serve.ErrInvalidManifest) {
+			t.Errorf("%s: err = %v, want ErrInvalidManifest", tt.name, err)
+		}
+	}
+
+	if _, err := serve.Load(writeManifest(t, dir, "models:\n  - {name: a, path: clf.json, treshold: 0.5}\n")); err == nil {
+		t.Error("expected an error for a misspelled key")
+	}
+}
+
+func TestServerShadow(t *testing.T) {
+	dir := t.TempDir()
+	writeModel(t, dir, "champ.json", "logloss", 5)
+	challenger := writeModel(t, dir, "chal.json", "logloss", 30)
+	srv, err := serve.Load(writeManifest(t, dir, `
+models:
+  - {name: champ, path: champ.json, challenger: chal}
+  - {name: chal, path: chal.json}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log bytes.Buffer
+	srv.StartShadow(&log)
+
+	_, champOut := post(t, srv, "/predict/champ", `{"rows": [[6, 1]]}`)
+	post(t, srv, "/predict/chal", `{"rows": [[6, 1]]}`)
+	if dropped := srv.StopShadow(); dropped != 0 {
+		t.Errorf("dropped %d shadow requests", dropped)
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("shadow log has %d lines, want 1 (only the champion is shadowed):\n%s", len(lines), log.String())
+	}
+	var rec struct {
+		Champion, Challenger  string
+		ChampionPredictions   []float64 `json:"champion_predictions"`
+		ChallengerPredictions []float64 `json:"challenger_predictions"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Champion != "champ" || rec.Challenger != "chal" {
+		t.Errorf("shadow record names %q and %q", rec.Champion, rec.Challenger)
+	}
+	if rec.ChampionPredictions[0] != champOut["predictions"].([]any)[0].(float64) {
+		t.Errorf("champion prediction %v differs from the response %v", rec.ChampionPredictions, champOut["predictions"])
+	}
+	if want := challenger.PredictProba([]float64{6, 1}); math.Abs(rec.ChallengerPredictions[0]-want) > 1e-12 {
+		t.Errorf("challenger prediction = %v, want %v", rec.ChallengerPredictions[0], want)
+	}
+}
+
+func TestServerAudit(t *testing.T) {
+	dir := t.TempDir()
+	writeModel(t, dir, "m.json", "logloss", 5)
+	srv, err := serve.Load(writeManifest(t, dir, "models:\n  - {name: m, path: m.json, version: v7}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var records []serve.AuditRecord
+	srv.Audit = serve.AuditFunc(func(rec serve.AuditRecord) {
+		mu.Lock()
+		defer mu.Unlock()
+		records = append(records, rec)
+	})
+	post(t, srv, "/predict/m", `{"rows": [[6, null]]}`)
+	post(t, srv, "/predict/m", `{"rows": [[6]]}`)
+	post(t, srv, "/predict/unknown", `{"rows": [[6, 1]]}`)
+
+	if len(records) != 2 {
+		t.Fatalf("got %d audit records, want 2 (unknown models are not audited)", len(records))
+	}
+	ok := records[0]
+	if ok.Model != "m" || ok.Version != "v7" || ok.Err != nil || ok.Latency <= 0 {
+		t.Errorf("record = %+v", ok)
+	}
+	if len(ok.Features) != 1 || ok.Features[0][0] != 6 || !math.IsNaN(ok.Features[0][1]) {
+		t.Errorf("Features = %v, want [[6 NaN]]", ok.Features)
+	}
+	if len(ok.Predictions) != 1 || len(ok.Labels) != 1 {
+		t.Errorf("Predictions = %v, Labels = %v", ok.Predictions, ok.Labels)
+	}
+	if records[1].Err == nil || records[1].Predictions != nil {
+		t.Errorf("failed request record = %+v, want an error and no predictions", records[1])
+	}
+
+	var buf bytes.Buffer
+	serve.NewJSONAuditLog(&buf).Audit(ok)
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatal(err)
+	}
+	if line["version"] != "v7" || line["features"].([]any)[0].([]any)[1] != nil {
+		t.Errorf("JSON audit line = %s, want version v7 and a null missing value", buf.String())
+	}
+}
+
+func TestCostMatrixSetsDefaultThreshold(t *testing.T) {
+	dir := t.TempDir()
+	cfg := gboost.DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 5
+	cfg.CostMatrix = [][]float64{{0, 1}, {3, 0}}
+	gbm := gboost.New(cfg)
+	if err := gbm.Fit(trainX, trainY); err != nil {
+		t.Fatal(err)
+	}
+	if err := gbm.Save(filepath.Join(dir, "m.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := serve.Load(writeManifest(t, dir, "models:\n  - {name: m, path: m.json}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	if got := srv.Model("m").Threshold; got != 0.25 {
+		t.Errorf("threshold = %v, want the cost-minimizing 0.25", got)
+	}
+}
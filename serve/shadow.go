@@ -0,0 +1,109 @@
+package serve
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// shadowQueueSize bounds the challenger requests waiting to be scored. When
+// the queue is full new requests are dropped rather than slowing responses.
+const shadowQueueSize = 1024
+
+// shadowJob is a champion request waiting to be scored by its challenger.
+type shadowJob struct {
+	time        time.Time
+	champion    string
+	X           [][]float64
+	records     [][]string // raw request records, nil for encoded rows
+	predictions []float64
+}
+
+// shadowRecord is one line of the shadow log. Predictions are as in
+// responses: probabilities for logloss models, expected values for gamma
+// models, and raw predictions otherwise.
+type shadowRecord struct {
+	Time                  time.Time `json:"time"`
+	Champion              string    `json:"champion"`
+	Challenger            string    `json:"challenger"`
+	ChampionPredictions   []float64 `json:"champion_predictions"`
+	ChallengerPredictions []float64 `json:"challenger_predictions,omitempty"`
+	Error                 string    `json:"error,omitempty"`
+}
+
+func (s *Server) hasChallengers() bool {
+	for _, m := range s.models {
+		if m.Challenger != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// StartShadow starts scoring requests to models with a challenger. Each
+// request is queued after its response is computed, scored by the
+// challenger on a background goroutine, and written to w as one JSON line
+// holding both models' predictions. Responses never wait for the challenger:
+// when the queue is full, requests are dropped from it. Without StartShadow
+// challengers are ignored. Call it before the server starts handling
+// requests.
+func (s *Server) StartShadow(w io.Writer) {
+	if !s.hasChallengers() {
+		return
+	}
+	s.shadow = make(chan shadowJob, shadowQueueSize)
+	s.shadowDone = make(chan struct{})
+	go func() {
+		defer close(s.shadowDone)
+		enc := json.NewEncoder(w)
+		for job := range s.shadow {
+			name := s.models[job.champion].Challenger
+			rec := shadowRecord{
+				Time:                job.time,
+				Champion:            job.champion,
+				Challenger:          name,
+				ChampionPredictions: job.predictions,
+			}
+			// Records are encoded by the challenger's own pipeline, whose
+			// encodings may differ from the champion's.
+			X, err := job.X, error(nil)
+			if job.records != nil {
+				X, err = s.models[name].Pipeline.Encode(job.records)
+			}
+			var preds []float64
+			if err == nil {
+				preds, _, err = s.models[name].score(X, 1)
+			}
+			if err != nil {
+				rec.Error = err.Error()
+			}
+			rec.ChallengerPredictions = preds
+			enc.Encode(rec)
+		}
+	}()
+}
+
+// enqueueShadow queues job for its challenger without blocking, dropping it
+// if the queue is full or shadow scoring was not started.
+func (s *Server) enqueueShadow(job shadowJob) {
+	if s.shadow == nil {
+		return
+	}
+	select {
+	case s.shadow <- job:
+	default:
+		s.shadowDropped.Add(1)
+	}
+}
+
+// StopShadow scores the requests still queued, stops the goroutine started
+// by [Server.StartShadow], and returns the number of requests dropped. Call
+// it once the server has stopped handling requests.
+func (s *Server) StopShadow() int64 {
+	if s.shadow == nil {
+		return 0
+	}
+	close(s.shadow)
+	<-s.shadowDone
+	return s.shadowDropped.Load()
+}
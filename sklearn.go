@@ -0,0 +1,181 @@
+package gboost
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// sklearnFormat identifies dumps written by scripts/sklearn_dump.py.
+const sklearnFormat = "gboost-sklearn-trees"
+
+// sklearnDump is the JSON written by scripts/sklearn_dump.py, version 1.
+type sklearnDump struct {
+	Format           string        `json:"format"`
+	Version          int           `json:"version"`
+	Estimator        string        `json:"estimator"`
+	Loss             string        `json:"loss"`
+	LearningRate     float64       `json:"learning_rate"`
+	InitPrediction   float64       `json:"init_prediction"`
+	LeafValuesScaled bool          `json:"leaf_values_scaled"`
+	NumFeatures      int           `json:"n_features"`
+	FeatureNames     []string      `json:"feature_names"`
+	Trees            []sklearnTree `json:"trees"`
+}
+
+// sklearnTree holds one tree as parallel arrays indexed by node, root
+// first, as in scikit-learn's Tree. Leaves have Left and Right -1. Gain is
+// the total, sample-weighted gain of each split, whose sums per feature
+// give scikit-learn's feature_importances_.
+type sklearnTree struct {
+	Left            []int     `json:"left"`
+	Right           []int     `json:"right"`
+	Feature         []int     `json:"feature"`
+	Threshold       []float64 `json:"threshold"`
+	Value           []float64 `json:"value"`
+	NSamples        []int     `json:"n_samples"`
+	Gain            []float64 `json:"gain"`
+	MissingGoToLeft []int     `json:"missing_go_to_left"`
+}
+
+// LoadSklearn reads the scikit-learn model dump at path with [ParseSklearn].
+func LoadSklearn(path string) (*GBM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSklearn(data)
+}
+
+// ParseSklearn converts a scikit-learn GradientBoostingRegressor,
+// GradientBoostingClassifier, HistGradientBoostingRegressor, or
+// HistGradientBoostingClassifier, dumped to JSON by scripts/sklearn_dump.py,
+// into a trained [GBM], so that models trained in Python can be scored,
+// saved, served, and explained from Go.
+//
+// The model keeps the estimator's learning rate and starts from its initial
+// raw prediction, so [GBM.PredictSingle] returns scikit-learn's raw score
+// (decision_function for classifiers) and [GBM.PredictProba] its positive
+// class probability. HistGradientBoosting leaf values, which scikit-learn
+// stores already shrunk, are divided by the learning rate, so predictions
+// agree to within rounding. GradientBoosting compares inputs as float32;
+// gboost compares float64 inputs exactly, so values within float32 rounding
+// of a threshold can fall on the other side. Classifiers become "logloss"
+// models and regressors "mse" models, whatever their identity-link loss.
+// Sample counts and split gains carry over, so feature importance and
+// [GBM.ShapValues] work as for native models.
+//
+// Returns an error wrapping [ErrInvalidSklearnDump] if data is not a
+// well-formed dump, or [ErrUnsupportedSklearn] for a newer format version
+// or a split that sends missing values left, which gboost cannot represent;
+// dump such models with --no-missing if they are never scored on missing
+// values.
+func ParseSklearn(data []byte) (*GBM, error) {
+	var d sklearnDump
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSklearnDump, err)
+	}
+	switch {
+	case d.Format != sklearnFormat:
+		return nil, fmt.Errorf("%w: format %q, want %q", ErrInvalidSklearnDump, d.Format, sklearnFormat)
+	case d.Version != 1:
+		return nil, fmt.Errorf("%w: format version %d", ErrUnsupportedSklearn, d.Version)
+	case d.NumFeatures < 1:
+		return nil, fmt.Errorf("%w: n_features must be >= 1", ErrInvalidSklearnDump)
+	case !(d.LearningRate > 0) || math.IsInf(d.LearningRate, 0):
+		return nil, fmt.Errorf("%w: learning_rate must be > 0", ErrInvalidSklearnDump)
+	case d.FeatureNames != nil && len(d.FeatureNames) != d.NumFeatures:
+		return nil, fmt.Errorf("%w: %d feature names for %d features", ErrInvalidSklearnDump, len(d.FeatureNames), d.NumFeatures)
+	}
+
+	classifier := strings.HasSuffix(d.Estimator, "Classifier")
+	// Regressors need a loss whose raw score is the prediction itself.
+	identity := slices.Contains([]string{"squared_error", "absolute_error", "huber", "quantile"}, d.Loss)
+	if classifier && d.Loss != "log_loss" || !classifier && !identity {
+		return nil, fmt.Errorf("%w: %s with loss %q", ErrUnsupportedSklearn, d.Estimator, d.Loss)
+	}
+
+	cfg := DefaultConfig()
+	cfg.LearningRate = d.LearningRate
+	cfg.NEstimators = len(d.Trees)
+	if classifier {
+		cfg.Loss = "logloss"
+	}
+	g := &GBM{
+		Config:            cfg,
+		initialPrediction: d.InitPrediction,
+		numFeatures:       d.NumFeatures,
+		featureNames:      d.FeatureNames,
+		forest:            new(atomic.Pointer[layoutForest]),
+	}
+	scale := 1.0
+	if d.LeafValuesScaled {
+		scale = d.LearningRate
+	}
+	for t, tree := range d.Trees {
+		root, err := tree.toNode(d.NumFeatures, scale)
+		if err != nil {
+			return nil, fmt.Errorf("tree %d: %w", t, err)
+		}
+		g.appendTree(root)
+	}
+	if g.maxDepth > 0 {
+		g.Config.MaxDepth = g.maxDepth
+	}
+	g.loss = createLossFunction(g.Config)
+	g.calculateFeatureImportance()
+	g.isFitted = true
+	return g, nil
+}
+
+// toNode builds the tree, dividing leaf values by scale. scikit-learn sends
+// x left when x <= threshold; the threshold is moved to the next float64 to
+// keep that with gboost's x < threshold.
+func (t *sklearnTree) toNode(numFeatures int, scale float64) (*Node, error) {
+	n := len(t.Left)
+	switch {
+	case n == 0:
+		return nil, fmt.Errorf("%w: tree has no nodes", ErrInvalidSklearnDump)
+	case len(t.Right) != n || len(t.Feature) != n || len(t.Threshold) != n || len(t.Value) != n ||
+		(t.NSamples != nil && len(t.NSamples) != n) || (t.Gain != nil && len(t.Gain) != n) ||
+		(t.MissingGoToLeft != nil && len(t.MissingGoToLeft) != n):
+		return nil, fmt.Errorf("%w: node arrays differ in length", ErrInvalidSklearnDump)
+	}
+
+	nodes := make([]Node, n)
+	referenced := make([]bool, n)
+	for i := range nodes {
+		node := &nodes[i]
+		node.Value = t.Value[i] / scale
+		if t.NSamples != nil {
+			node.NSamples = t.NSamples[i]
+		}
+		if t.Left[i] == -1 && t.Right[i] == -1 {
+			continue
+		}
+		// Children follow their parent, which also rules out cycles.
+		l, r := t.Left[i], t.Right[i]
+		if l <= i || r <= i || l >= n || r >= n || l == r || referenced[l] || referenced[r] {
+			return nil, fmt.Errorf("%w: node %d has invalid children", ErrInvalidSklearnDump, i)
+		}
+		if t.Feature[i] < 0 || t.Feature[i] >= numFeatures {
+			return nil, fmt.Errorf("%w: node %d splits on feature %d of %d", ErrInvalidSklearnDump, i, t.Feature[i], numFeatures)
+		}
+		if t.MissingGoToLeft != nil && t.MissingGoToLeft[i] != 0 {
+			return nil, fmt.Errorf("%w: node %d sends missing values left", ErrUnsupportedSklearn, i)
+		}
+		referenced[l], referenced[r] = true, true
+		node.FeatureIndex = t.Feature[i]
+		node.Threshold = math.Nextafter(t.Threshold[i], math.Inf(1))
+		node.Left, node.Right = &nodes[l], &nodes[r]
+		if t.Gain != nil && node.NSamples > 0 {
+			// Node gains are per sample; the dump's are totals.
+			node.Gain = t.Gain[i] / float64(node.NSamples)
+		}
+	}
+	return &nodes[0], nil
+}
@@ -7,6 +7,8 @@ import (
 	"math"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -227,3 +229,114 @@ func rankDescending(xs []float64) []int {
 func sameSign(a, b float64) bool {
 	return (a >= 0) == (b >= 0)
 }
+
+// sklearnDumpJSON is a GradientBoosting dump with learning rate 0.1 and one
+// tree: x0 <= 1.5 ? 2 : (x1 <= 0 ? -1 : 3).
+const sklearnDumpJSON = `{
+	"format": "gboost-sklearn-trees", "version": 1,
+	"estimator": "GradientBoostingRegressor", "loss": "squared_error",
+	"learning_rate": 0.1, "init_prediction": 0.5, "leaf_values_scaled": false,
+	"n_features": 2, "feature_names": ["a", "b"],
+	"trees": [{
+		"left": [1, -1, 3, -1, -1], "right": [2, -1, 4, -1, -1],
+		"feature": [0, 0, 1, 0, 0], "threshold": [1.5, -2, 0, -2, -2],
+		"value": [1, 2, 0.5, -1, 3], "n_samples": [10, 4, 6, 3, 3],
+		"gain": [5, 0, 2, 0, 0]
+	}]
+}`
+
+func TestParseSklearn(t *testing.T) {
+	g, err := ParseSklearn([]byte(sklearnDumpJSON))
+	require.NoError(t, err)
+	assert.Equal(t, "mse", g.Config.Loss)
+	assert.Equal(t, []string{"a", "b"}, g.FeatureNames())
+	assert.Equal(t, []float64{5.0 / 7, 2.0 / 7}, g.FeatureImportance())
+
+	tests := []struct {
+		x    []float64
+		want float64
+	}{
+		{[]float64{1.5, 0}, 0.5 + 0.1*2}, // x <= threshold goes left
+		{[]float64{2, 0}, 0.5 + 0.1*-1},
+		{[]float64{2, 1}, 0.5 + 0.1*3},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.want, g.PredictSingle(tt.x), 1e-15, "x = %v", tt.x)
+	}
+
+	// Sample counts carry over, so SHAP values add up to the prediction.
+	phi, err := g.ShapValuesSingle([]float64{2, 1})
+	require.NoError(t, err)
+	assert.InDelta(t, g.PredictSingle([]float64{2, 1}), g.BaseValue()+phi[0]+phi[1], 1e-12)
+}
+
+func TestParseSklearnScaledLeaves(t *testing.T) {
+	dump := strings.NewReplacer(
+		`"GradientBoostingRegressor"`, `"HistGradientBoostingClassifier"`,
+		`"squared_error"`, `"log_loss"`,
+		`"leaf_values_scaled": false`, `"leaf_values_scaled": true`,
+	).Replace(sklearnDumpJSON)
+	path := filepath.Join(t.TempDir(), "hgb.json")
+	require.NoError(t, os.WriteFile(path, []byte(dump), 0o644))
+	g, err := LoadSklearn(path)
+	require.NoError(t, err)
+	assert.Equal(t, "logloss", g.Config.Loss)
+	assert.Equal(t, 0.1, g.Config.LearningRate)
+	assert.InDelta(t, sigmoid(0.5+3), g.PredictProba([]float64{2, 1}), 1e-15)
+}
+
+func TestParseSklearnErrors(t *testing.T) {
+	tests := []struct {
+		name, old, new string
+		want           error
+	}{
+		{"format", `"gboost-sklearn-trees"`, `"other"`, ErrInvalidSklearnDump},
+		{"version", `"version": 1`, `"version": 2`, ErrUnsupportedSklearn},
+		{"missing left", `"gain": [5, 0, 2, 0, 0]`, `"gain": [5, 0, 2, 0, 0], "missing_go_to_left": [0, 0, 1, 0, 0]`, ErrUnsupportedSklearn},
+		{"cycle", `"left": [1, -1, 3, -1, -1]`, `"left": [1, -1, 0, -1, -1]`, ErrInvalidSklearnDump},
+		{"short array", `"value": [1, 2, 0.5, -1, 3]`, `"value": [1, 2]`, ErrInvalidSklearnDump},
+		{"feature range", `"feature": [0, 0, 1, 0, 0]`, `"feature": [0, 0, 2, 0, 0]`, ErrInvalidSklearnDump},
+		{"learning rate", `"learning_rate": 0.1`, `"learning_rate": 0`, ErrInvalidSklearnDump},
+		{"log link", `"squared_error"`, `"poisson"`, ErrUnsupportedSklearn},
+		{"syntax", `"trees": [`, `"trees": `, ErrInvalidSklearnDump},
+	}
+	for _, tt := range tests {
+		_, err := ParseSklearn([]byte(strings.Replace(sklearnDumpJSON, tt.old, tt.new, 1)))
+		assert.ErrorIs(t, err, tt.want, tt.name)
+	}
+}
+
+type sklearnImportResult struct {
+	X   [][]float64 `json:"X"`
+	Raw []float64   `json:"raw"`
+}
+
+// TestSklearnImportParity dumps sklearn's GradientBoosting and
+// HistGradientBoosting models with scripts/sklearn_dump.py and checks that
+// the imported models reproduce their raw scores. Gated by -sklearn.
+func TestSklearnImportParity(t *testing.T) {
+	if !*sklearnFlag {
+		t.Skip("sklearn import parity test requires -sklearn flag")
+	}
+	if _, err := exec.LookPath("uv"); err != nil {
+		t.Skip("uv not found in PATH, skipping sklearn import parity test")
+	}
+
+	dir := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "uv", "run", "--project", "e2e_tests", "e2e_tests/sklearn_import.py", "--data-dir", "data", "--out-dir", dir)
+	out, err := cmd.Output()
+	require.NoError(t, err, "sklearn script failed: %s", string(out))
+
+	var results map[string]sklearnImportResult
+	require.NoError(t, json.Unmarshal(out, &results), "failed to parse sklearn JSON output: %s", string(out))
+	require.Len(t, results, 4)
+	for name, res := range results {
+		g, err := LoadSklearn(filepath.Join(dir, name+".json"))
+		require.NoError(t, err, name)
+		for i, x := range res.X {
+			assert.InDelta(t, res.Raw[i], g.PredictSingle(x), 1e-9, "%s: row %d", name, i)
+		}
+	}
+}
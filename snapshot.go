@@ -0,0 +1,32 @@
+package gboost
+
+// blendSnapshots folds the average of the last Config.SnapshotBlend
+// checkpoints into the trees and records the checkpoints' weights. A
+// checkpoint with k trees predicts the sum of the first k, so tree t
+// contributes to every averaged checkpoint with more than t trees.
+func (g *GBM) blendSnapshots() {
+	g.snapshotWeights = nil
+	n := min(g.Config.SnapshotBlend, len(g.trees))
+	if n < 2 {
+		return
+	}
+	g.snapshotWeights = make([]float64, n)
+	for k := range g.snapshotWeights {
+		g.snapshotWeights[k] = 1 / float64(n)
+	}
+	// The last n trees are in n-1, n-2, ..., 1 fewer checkpoints than the
+	// ones before them.
+	first := len(g.trees) - n
+	for t := first + 1; t < len(g.trees); t++ {
+		scaleTree(g.trees[t], float64(len(g.trees)-t)/float64(n))
+	}
+	g.treesChanged()
+}
+
+// SnapshotWeights returns the weights of the checkpoints averaged by
+// Config.SnapshotBlend, oldest first: entry k weighs the model with the
+// first NumTrees()-len(weights)+k+1 trees. Returns nil for a model trained
+// without blending.
+func (g *GBM) SnapshotWeights() []float64 {
+	return g.snapshotWeights
+}
@@ -0,0 +1,81 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotBlend(t *testing.T) {
+	X, y := generateNoisyData()
+	cfg := DefaultConfig()
+	cfg.NEstimators = 20
+	plain := New(cfg)
+	if err := plain.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if plain.SnapshotWeights() != nil {
+		t.Errorf("unblended weights = %v", plain.SnapshotWeights())
+	}
+
+	cfg.SnapshotBlend = 5
+	blended := New(cfg)
+	if err := blended.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	weights := blended.SnapshotWeights()
+	if len(weights) != 5 || weights[0] != 0.2 {
+		t.Fatalf("weights = %v, want five of 0.2", weights)
+	}
+
+	// The blend predicts the mean of the unblended model's checkpoints
+	// with 16 through 20 trees.
+	for _, x := range X[:10] {
+		var want float64
+		for k := 16; k <= 20; k++ {
+			p := plain.initialPrediction
+			for _, tree := range plain.trees[:k] {
+				p += float64(cfg.LearningRate * tree.predict(x))
+			}
+			want += p / 5
+		}
+		if got := blended.PredictSingle(x); math.Abs(got-want) > 1e-12 {
+			t.Errorf("PredictSingle = %v, want %v", got, want)
+		}
+		phi, _ := blended.ShapValuesSingle(x)
+		if got := sum(phi) + blended.BaseValue(); math.Abs(got-want) > 1e-9 {
+			t.Errorf("SHAP sum = %v, want %v", got, want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := blended.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.SnapshotWeights()) != 5 || loaded.PredictSingle(X[0]) != blended.PredictSingle(X[0]) {
+		t.Errorf("reloaded weights %v", loaded.SnapshotWeights())
+	}
+
+	cfg.SnapshotBlend = 100
+	all := New(cfg)
+	if err := all.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if len(all.SnapshotWeights()) != 20 {
+		t.Errorf("SnapshotBlend above NumTrees: %d weights, want 20", len(all.SnapshotWeights()))
+	}
+}
+
+func TestSnapshotBlendInvalid(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.SnapshotBlend = -1
+	if err := New(cfg).Fit(X, y); !errors.Is(err, ErrInvalidSnapshotBlend) {
+		t.Errorf("err = %v, want ErrInvalidSnapshotBlend", err)
+	}
+}
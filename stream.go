@@ -0,0 +1,166 @@
+package gboost
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// StreamModel predicts from a model file written by [GBM.Save] without
+// loading the whole ensemble into memory. Trees are decoded from the file
+// ChunkTrees at a time, applied to the batch being scored, and discarded, so
+// memory is bounded by one chunk of trees plus the batch rather than by the
+// size of the model. Each prediction call reads the file once.
+//
+// Predictions are identical to the loaded model's [GBM.Predict].
+type StreamModel struct {
+	// ChunkTrees is the number of trees held in memory at once. Zero means 64.
+	ChunkTrees int
+
+	path              string
+	treesOffset       int64 // byte offset of the '[' opening the trees array
+	numTrees          int
+	numFeatures       int
+	initialPrediction float64
+	config            Config
+}
+
+// OpenStream scans the model file at path once, recording its header fields
+// and where the trees begin, and returns a [StreamModel] reading from it.
+// The file must not change while the StreamModel is in use.
+func OpenStream(path string) (*StreamModel, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	s := &StreamModel{path: path, treesOffset: -1}
+	dec := json.NewDecoder(file)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tok {
+		case "config":
+			err = dec.Decode(&s.config)
+		case "initial_prediction":
+			err = dec.Decode(&s.initialPrediction)
+		case "num_features":
+			err = dec.Decode(&s.numFeatures)
+		case "trees":
+			if err := expectDelim(dec, '['); err != nil {
+				return nil, err
+			}
+			s.treesOffset = dec.InputOffset() - 1
+			for dec.More() {
+				// Decode and drop each tree to validate it without keeping it.
+				var tree ExportedNode
+				if err := dec.Decode(&tree); err != nil {
+					return nil, fmt.Errorf("tree %d: %w", s.numTrees, err)
+				}
+				s.numTrees++
+			}
+			err = expectDelim(dec, ']')
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if s.treesOffset < 0 {
+		return nil, errors.New("model file has no trees")
+	}
+	return s, nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("expected %q in model file, got %v", want, tok)
+	}
+	return nil
+}
+
+// NumTrees returns the number of trees in the model file.
+func (s *StreamModel) NumTrees() int {
+	return s.numTrees
+}
+
+// NumFeatures returns the number of input features the model expects.
+func (s *StreamModel) NumFeatures() int {
+	return s.numFeatures
+}
+
+// Predict returns raw predictions for each sample in X, streaming the trees
+// from disk.
+func (s *StreamModel) Predict(X [][]float64) ([]float64, error) {
+	res := make([]float64, len(X))
+	for i := range res {
+		res[i] = s.initialPrediction
+	}
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if _, err := file.Seek(s.treesOffset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	chunkSize := s.ChunkTrees
+	if chunkSize <= 0 {
+		chunkSize = 64
+	}
+	chunk := make([]*Node, 0, chunkSize)
+	apply := func() {
+		for i, x := range X {
+			for _, tree := range chunk {
+				res[i] += float64(s.config.LearningRate * tree.predict(x))
+			}
+		}
+		chunk = chunk[:0]
+	}
+
+	dec := json.NewDecoder(file)
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		var tree ExportedNode
+		if err := dec.Decode(&tree); err != nil {
+			return nil, err
+		}
+		chunk = append(chunk, nodeFromExported(&tree))
+		if len(chunk) == chunkSize {
+			apply()
+		}
+	}
+	apply()
+	return res, nil
+}
+
+// PredictProbaAll returns P(y=1) for each sample in X. Only meaningful for
+// models trained with logloss.
+func (s *StreamModel) PredictProbaAll(X [][]float64) ([]float64, error) {
+	res, err := s.Predict(X)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res {
+		res[i] = sigmoid(res[i])
+	}
+	return res, nil
+}
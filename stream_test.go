@@ -0,0 +1,66 @@
+package gboost
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStreamModelMatchesLoad(t *testing.T) {
+	X, y := generateBinaryData(5)
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	cfg.NEstimators = 37
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := gbm.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := OpenStream(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.NumTrees() != 37 || s.NumFeatures() != gbm.NumFeatures() {
+		t.Errorf("NumTrees() = %d, NumFeatures() = %d; want 37, %d", s.NumTrees(), s.NumFeatures(), gbm.NumFeatures())
+	}
+
+	want := gbm.PredictProbaAll(X)
+	for _, chunk := range []int{0, 1, 5, 100} {
+		s.ChunkTrees = chunk
+		got, err := s.PredictProbaAll(X)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("ChunkTrees=%d: PredictProbaAll[%d] = %v, want %v", chunk, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestOpenStreamErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := OpenStream(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	for name, content := range map[string]string{
+		"no trees":     `{"config": {}, "initial_prediction": 1}`,
+		"not object":   `[1, 2]`,
+		"bad tree":     `{"trees": [{"feature_index": "x"}]}`,
+		"trees object": `{"trees": {}}`,
+	} {
+		path := filepath.Join(dir, "model.json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := OpenStream(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
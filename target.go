@@ -0,0 +1,235 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ahmedaabouzied/gboost/internal/fpmath"
+)
+
+// TargetTransform names a transformation of the regression target applied
+// by a [TransformedTargetRegressor].
+type TargetTransform string
+
+const (
+	TargetLog1p  TargetTransform = "log1p"   // log(1 + y), for targets > -1.
+	TargetBoxCox TargetTransform = "box-cox" // (y^λ - 1) / λ, or log(y) when λ = 0, for targets > 0.
+	TargetCustom TargetTransform = "custom"  // TransformedTargetRegressor.Func and Inverse; cannot be saved.
+)
+
+// boxCoxLambdaRange bounds the Box-Cox exponent estimated by Fit. Wider
+// exponents rarely help and make the inverse numerically fragile.
+const boxCoxLambdaRange = 2
+
+// TransformedTargetRegressor trains a regression [GBM] on a transformed
+// target and maps its predictions back to the original scale. Skewed targets,
+// such as prices or claim amounts, are usually easier to fit after a log or
+// Box-Cox transform, which keeps a few large values from dominating the
+// squared error.
+//
+// Predictions invert the transform of the model's output, which estimates
+// the mean of the transformed target. For log-like transforms that is
+// closer to the median of y than to its mean, so sums of predictions
+// underestimate totals on very skewed data.
+type TransformedTargetRegressor struct {
+	Model     *GBM
+	Transform TargetTransform
+
+	// Lambda is the Box-Cox exponent, set by Fit to its maximum likelihood
+	// estimate in [-2, 2].
+	Lambda float64
+
+	// Func and Inverse transform the target and predictions when Transform
+	// is TargetCustom. Inverse must undo Func.
+	Func    func(float64) float64
+	Inverse func(float64) float64
+}
+
+// NewTransformedTarget creates an untrained [TransformedTargetRegressor]
+// that fits a model with cfg on transform(y).
+func NewTransformedTarget(cfg Config, transform TargetTransform) *TransformedTargetRegressor {
+	return &TransformedTargetRegressor{Model: New(cfg), Transform: transform}
+}
+
+// NewTransformedTargetFunc creates an untrained [TransformedTargetRegressor]
+// that fits a model with cfg on fn(y) and predicts inverse of its output.
+func NewTransformedTargetFunc(cfg Config, fn, inverse func(float64) float64) *TransformedTargetRegressor {
+	return &TransformedTargetRegressor{Model: New(cfg), Transform: TargetCustom, Func: fn, Inverse: inverse}
+}
+
+// Fit transforms y, estimating the Box-Cox exponent first if needed, and
+// trains the model on the result.
+//
+// Returns [ErrInvalidLoss] if the model does not use the "mse" loss,
+// [ErrInvalidTargetTransform] for an unknown transform or a custom one
+// without both functions, an error wrapping [ErrTargetOutOfDomain] if a
+// target cannot be transformed, and otherwise any error from [GBM.Fit].
+func (r *TransformedTargetRegressor) Fit(X [][]float64, y []float64) error {
+	if r.Model.Config.Loss != "mse" {
+		return fmt.Errorf("transformed target regressor: %w", ErrInvalidLoss)
+	}
+	switch r.Transform {
+	case TargetLog1p, TargetBoxCox:
+	case TargetCustom:
+		if r.Func == nil || r.Inverse == nil {
+			return ErrInvalidTargetTransform
+		}
+	default:
+		return ErrInvalidTargetTransform
+	}
+	for i, v := range y {
+		if r.Transform == TargetLog1p && !(v > -1) || r.Transform == TargetBoxCox && !(v > 0) {
+			return fmt.Errorf("target %d = %v: %w", i, v, ErrTargetOutOfDomain)
+		}
+	}
+
+	if r.Transform == TargetBoxCox {
+		r.Lambda = boxCoxLambda(y)
+	}
+	ty := make([]float64, len(y))
+	for i, v := range y {
+		ty[i] = r.forward(v)
+		if math.IsNaN(ty[i]) || math.IsInf(ty[i], 0) {
+			return fmt.Errorf("target %d = %v transforms to %v: %w", i, v, ty[i], ErrTargetOutOfDomain)
+		}
+	}
+	return r.Model.Fit(X, ty)
+}
+
+// forward applies the transform to one target. Logarithms and powers use
+// the portable kernels so that training stays reproducible across platforms.
+func (r *TransformedTargetRegressor) forward(y float64) float64 {
+	switch r.Transform {
+	case TargetLog1p:
+		return fpmath.Log(1 + y)
+	case TargetBoxCox:
+		return boxCox(y, r.Lambda)
+	default:
+		return r.Func(y)
+	}
+}
+
+// inverse maps one model output back to the target scale.
+func (r *TransformedTargetRegressor) inverse(z float64) float64 {
+	switch r.Transform {
+	case TargetLog1p:
+		return fpmath.Exp(z) - 1
+	case TargetBoxCox:
+		if r.Lambda == 0 {
+			return fpmath.Exp(z)
+		}
+		// Outputs beyond the transform's range clamp to its boundary: 0 for
+		// λ > 0 and +Inf for λ < 0.
+		base := max(float64(r.Lambda*z)+1, 0)
+		return fpmath.Exp(float64(fpmath.Log(base) / r.Lambda))
+	default:
+		return r.Inverse(z)
+	}
+}
+
+// Predict returns the prediction for each sample in X on the original
+// target scale.
+func (r *TransformedTargetRegressor) Predict(X [][]float64) []float64 {
+	res := make([]float64, len(X))
+	for i := range X {
+		res[i] = r.PredictSingle(X[i])
+	}
+	return res
+}
+
+// PredictSingle returns the prediction for one sample on the original target
+// scale: the inverse transform of the model's prediction.
+func (r *TransformedTargetRegressor) PredictSingle(x []float64) float64 {
+	return r.inverse(r.Model.PredictSingle(x))
+}
+
+// exportedTransformedTarget is the JSON form of a [TransformedTargetRegressor].
+type exportedTransformedTarget struct {
+	Transform TargetTransform `json:"transform"`
+	Lambda    float64         `json:"lambda,omitempty"`
+	Model     *ExportedModel  `json:"model"`
+}
+
+// Save writes the model and its target transform to a JSON file at path.
+// The file can be restored with [LoadTransformedTarget].
+//
+// Returns [ErrModelNotFitted] if the model has not been trained, or
+// [ErrUnsupportedTransformer] for a custom transform, whose functions cannot
+// be saved.
+func (r *TransformedTargetRegressor) Save(path string) error {
+	if !r.Model.isFitted {
+		return ErrModelNotFitted
+	}
+	if r.Transform == TargetCustom {
+		return fmt.Errorf("target transform: %w", ErrUnsupportedTransformer)
+	}
+	return writeJSON(path, exportedTransformedTarget{Transform: r.Transform, Lambda: r.Lambda, Model: r.Model.toExported()})
+}
+
+// LoadTransformedTarget reads a regressor previously written by
+// [TransformedTargetRegressor.Save].
+func LoadTransformedTarget(path string) (*TransformedTargetRegressor, error) {
+	var exported exportedTransformedTarget
+	if err := readJSON(path, &exported); err != nil {
+		return nil, err
+	}
+	switch {
+	case exported.Model == nil:
+		return nil, fmt.Errorf("transformed target file has no model")
+	case exported.Transform != TargetLog1p && exported.Transform != TargetBoxCox:
+		return nil, ErrInvalidTargetTransform
+	}
+	return &TransformedTargetRegressor{
+		Model:     fromExported(exported.Model),
+		Transform: exported.Transform,
+		Lambda:    exported.Lambda,
+	}, nil
+}
+
+// boxCox returns the Box-Cox transform of y > 0 with exponent lambda.
+func boxCox(y, lambda float64) float64 {
+	if lambda == 0 {
+		return fpmath.Log(y)
+	}
+	return float64(fpmath.Exp(float64(lambda*fpmath.Log(y)))-1) / lambda
+}
+
+// boxCoxLambda returns the exponent in [-2, 2] that maximizes the Box-Cox
+// profile log-likelihood of y,
+//
+//	(λ - 1) Σ log y - n/2 log(var(boxCox(y, λ))),
+//
+// found by golden-section search, which assumes the likelihood is unimodal
+// on the interval, as it is for all but contrived data.
+func boxCoxLambda(y []float64) float64 {
+	logs := make([]float64, len(y))
+	for i, v := range y {
+		logs[i] = fpmath.Log(v)
+	}
+	sumLogs := sum(logs)
+	transformed := make([]float64, len(y))
+	llf := func(lambda float64) float64 {
+		for i, v := range y {
+			transformed[i] = boxCox(v, lambda)
+		}
+		return float64((lambda-1)*sumLogs) - float64(float64(len(y))/2*fpmath.Log(variance(transformed)))
+	}
+
+	// The golden ratio conjugate, (√5 - 1) / 2.
+	const invPhi = 0.6180339887498949
+	lo, hi := -float64(boxCoxLambdaRange), float64(boxCoxLambdaRange)
+	a, b := hi-float64(invPhi*(hi-lo)), lo+float64(invPhi*(hi-lo))
+	fa, fb := llf(a), llf(b)
+	for range 80 {
+		if fa < fb {
+			lo, a, fa = a, b, fb
+			b = lo + float64(invPhi*(hi-lo))
+			fb = llf(b)
+		} else {
+			hi, b, fb = b, a, fa
+			a = hi - float64(invPhi*(hi-lo))
+			fa = llf(a)
+		}
+	}
+	return (lo + hi) / 2
+}
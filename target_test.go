@@ -0,0 +1,153 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+// generateSkewedData returns a log-normal target whose logarithm is a step
+// function of the first feature plus noise.
+func generateSkewedData(n int) ([][]float64, []float64) {
+	rnd := rand.New(rand.NewSource(3))
+	X := make([][]float64, n)
+	y := make([]float64, n)
+	for i := range X {
+		X[i] = []float64{rnd.Float64(), rnd.Float64()}
+		mu := 2.0
+		if X[i][0] > 0.5 {
+			mu = 5
+		}
+		y[i] = math.Exp(mu + 0.3*rnd.NormFloat64())
+	}
+	return X, y
+}
+
+func TestTransformedTargetLog1p(t *testing.T) {
+	X, y := generateSkewedData(400)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 20
+	cfg.MaxDepth = 2
+	cfg.LearningRate = 0.3
+	r := NewTransformedTarget(cfg, TargetLog1p)
+	if err := r.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	// The model predicts on the log scale; predictions are back on y's.
+	got := r.PredictSingle([]float64{0.9, 0.5})
+	if raw := r.Model.PredictSingle([]float64{0.9, 0.5}); math.Abs(raw-5) > 0.2 {
+		t.Errorf("model output %v, want about log(1 + e^5) ≈ 5", raw)
+	}
+	if math.Abs(math.Log(got)-5) > 0.2 {
+		t.Errorf("prediction %v, want about e^5 ≈ 148", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := r.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadTransformedTarget(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := r.Predict(X)
+	for i, p := range loaded.Predict(X) {
+		if p != want[i] {
+			t.Fatalf("loaded prediction %d = %v, want %v", i, p, want[i])
+		}
+	}
+}
+
+func TestTransformedTargetBoxCox(t *testing.T) {
+	X, y := generateSkewedData(400)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 20
+	cfg.MaxDepth = 2
+	cfg.LearningRate = 0.3
+
+	r := NewTransformedTarget(cfg, TargetBoxCox)
+	if err := r.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	// Log-normal targets are normalized by the log, λ = 0.
+	if math.Abs(r.Lambda) > 0.1 {
+		t.Errorf("λ = %v for a log-normal target, want about 0", r.Lambda)
+	}
+	for _, x := range [][]float64{{0.1, 0.5}, {0.9, 0.5}} {
+		if got, want := r.inverse(boxCox(r.PredictSingle(x), r.Lambda)), r.PredictSingle(x); math.Abs(got-want) > 1e-9*want {
+			t.Errorf("inverse(boxCox(%v)) = %v", want, got)
+		}
+	}
+
+	// Squares of a normal variable far from zero are normalized by λ ≈ 0.5.
+	rnd := rand.New(rand.NewSource(5))
+	squares := make([]float64, 2000)
+	for i := range squares {
+		v := 10 + rnd.NormFloat64()
+		squares[i] = v * v
+	}
+	if lambda := boxCoxLambda(squares); math.Abs(lambda-0.5) > 0.15 {
+		t.Errorf("λ = %v for squared normal targets, want about 0.5", lambda)
+	}
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := r.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadTransformedTarget(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Lambda != r.Lambda || loaded.PredictSingle(X[0]) != r.PredictSingle(X[0]) {
+		t.Errorf("loaded λ %v, prediction %v; want %v, %v", loaded.Lambda, loaded.PredictSingle(X[0]), r.Lambda, r.PredictSingle(X[0]))
+	}
+}
+
+func TestTransformedTargetFunc(t *testing.T) {
+	X, y := generateSkewedData(400)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	r := NewTransformedTargetFunc(cfg, math.Sqrt, func(z float64) float64 { return z * z })
+	if err := r.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	raw := r.Model.PredictSingle(X[0])
+	if got := r.PredictSingle(X[0]); got != raw*raw {
+		t.Errorf("prediction %v, want the squared model output %v", got, raw*raw)
+	}
+	if err := r.Save(filepath.Join(t.TempDir(), "model.json")); !errors.Is(err, ErrUnsupportedTransformer) {
+		t.Errorf("Save with a custom transform: err = %v, want ErrUnsupportedTransformer", err)
+	}
+}
+
+func TestTransformedTargetErrors(t *testing.T) {
+	X := [][]float64{{1}, {2}, {3}}
+	logloss := DefaultConfig()
+	logloss.Loss = "logloss"
+
+	tests := []struct {
+		name string
+		r    *TransformedTargetRegressor
+		y    []float64
+		want error
+	}{
+		{"logloss", NewTransformedTarget(logloss, TargetLog1p), []float64{0, 1, 1}, ErrInvalidLoss},
+		{"unknown transform", NewTransformedTarget(DefaultConfig(), "sqrt"), []float64{1, 2, 3}, ErrInvalidTargetTransform},
+		{"custom without inverse", NewTransformedTargetFunc(DefaultConfig(), math.Sqrt, nil), []float64{1, 2, 3}, ErrInvalidTargetTransform},
+		{"log1p of -1", NewTransformedTarget(DefaultConfig(), TargetLog1p), []float64{1, -1, 3}, ErrTargetOutOfDomain},
+		{"box-cox of 0", NewTransformedTarget(DefaultConfig(), TargetBoxCox), []float64{1, 0, 3}, ErrTargetOutOfDomain},
+		{"custom NaN", NewTransformedTargetFunc(DefaultConfig(), math.Sqrt, math.Sqrt), []float64{1, -4, 3}, ErrTargetOutOfDomain},
+	}
+	for _, tt := range tests {
+		if err := tt.r.Fit(X, tt.y); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	if err := NewTransformedTarget(DefaultConfig(), TargetLog1p).Save(filepath.Join(t.TempDir(), "m.json")); err != ErrModelNotFitted {
+		t.Errorf("Save before Fit: err = %v, want ErrModelNotFitted", err)
+	}
+}
@@ -0,0 +1,101 @@
+package gboost
+
+import (
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// RoundResources is the cost of one boosting round, recorded in
+// [RoundStats] when Config.TrackResources is set. Allocations are counted
+// process-wide, so they include those of any other goroutines running
+// during the round.
+type RoundResources struct {
+	// Duration is the round's wall time.
+	Duration time.Duration
+
+	// Allocs and AllocBytes are the number and total size of the heap
+	// objects allocated during the round.
+	Allocs     uint64
+	AllocBytes uint64
+
+	// PeakHeapBytes is the largest growth of the heap above its size at the
+	// start of the round: the extra memory the round needed at its peak. The
+	// heap is sampled every millisecond, so a shorter spike can be missed,
+	// and garbage left before the round but collected during it offsets the
+	// growth, so it is a lower bound.
+	PeakHeapBytes uint64
+}
+
+// Metrics read by resourceMeter, in the order of its samples.
+var resourceMetrics = []string{
+	"/gc/heap/allocs:objects",
+	"/gc/heap/allocs:bytes",
+	"/memory/classes/heap/objects:bytes",
+}
+
+// resourceMeter measures one round for [RoundResources]. A nil meter
+// measures nothing, so rounds need not check whether tracking is on.
+type resourceMeter struct {
+	start               time.Time
+	allocs, bytes, heap uint64
+	peak                uint64 // written by the sampler until it stops
+	stop                chan struct{}
+	sampler             sync.WaitGroup
+}
+
+// startMeter starts measuring a round if enabled, and returns nil otherwise.
+func startMeter(enabled bool) *resourceMeter {
+	if !enabled {
+		return nil
+	}
+	m := &resourceMeter{stop: make(chan struct{})}
+	s := readResources()
+	m.allocs, m.bytes, m.heap = s[0].Value.Uint64(), s[1].Value.Uint64(), s[2].Value.Uint64()
+	m.peak = m.heap
+	m.sampler.Add(1)
+	go func() {
+		defer m.sampler.Done()
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		samples := readResources()
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				metrics.Read(samples)
+				m.peak = max(m.peak, samples[2].Value.Uint64())
+			}
+		}
+	}()
+	m.start = time.Now()
+	return m
+}
+
+// finish stops the meter and returns the round's resources.
+func (m *resourceMeter) finish() RoundResources {
+	if m == nil {
+		return RoundResources{}
+	}
+	d := time.Since(m.start)
+	close(m.stop)
+	m.sampler.Wait()
+	s := readResources()
+	m.peak = max(m.peak, s[2].Value.Uint64())
+	return RoundResources{
+		Duration:      d,
+		Allocs:        s[0].Value.Uint64() - m.allocs,
+		AllocBytes:    s[1].Value.Uint64() - m.bytes,
+		PeakHeapBytes: m.peak - m.heap,
+	}
+}
+
+func readResources() []metrics.Sample {
+	samples := make([]metrics.Sample, len(resourceMetrics))
+	for i, name := range resourceMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	return samples
+}
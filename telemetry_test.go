@@ -0,0 +1,70 @@
+package gboost
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestTrackResources(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 5
+
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range gbm.History() {
+		if s.Resources != (RoundResources{}) {
+			t.Fatalf("round %d: resources %+v recorded without TrackResources", s.Round, s.Resources)
+		}
+	}
+
+	cfg.TrackResources = true
+	tracked := New(cfg)
+	if err := tracked.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range tracked.History() {
+		if s.Resources.Duration <= 0 || s.Resources.Allocs == 0 || s.Resources.AllocBytes == 0 {
+			t.Errorf("round %d: resources %+v", s.Round, s.Resources)
+		}
+	}
+	want, _ := gbm.Fingerprint()
+	if got, _ := tracked.Fingerprint(); got != want {
+		t.Error("TrackResources changed the model")
+	}
+
+	grads, hessians := squaredErrorDerivatives(y, make([]float64, len(y)))
+	boosted := New(cfg)
+	if _, err := boosted.BoostOneRound(X, grads, hessians); err != nil {
+		t.Fatal(err)
+	}
+	if r := boosted.History()[0].Resources; r.Duration <= 0 || r.AllocBytes == 0 {
+		t.Errorf("BoostOneRound resources %+v", r)
+	}
+}
+
+func TestResourceMeterPeak(t *testing.T) {
+	const size = 64 << 20
+	// Start from a collected heap, so that no garbage is freed during the
+	// measurement to offset the growth.
+	runtime.GC()
+	m := startMeter(true)
+	buf := make([]byte, size)
+	for i := range buf {
+		buf[i] = 1
+	}
+	r := m.finish()
+	runtime.KeepAlive(buf)
+
+	if r.AllocBytes < size {
+		t.Errorf("AllocBytes = %d, want >= %d", r.AllocBytes, size)
+	}
+	if r.PeakHeapBytes < size*9/10 {
+		t.Errorf("PeakHeapBytes = %d, want about %d", r.PeakHeapBytes, size)
+	}
+	if (*resourceMeter)(nil).finish() != (RoundResources{}) {
+		t.Error("a nil meter measured something")
+	}
+}
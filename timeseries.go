@@ -0,0 +1,175 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// TimeSeriesFeatures is a [Transformer] that appends lagged values,
+// differences, and rolling-window statistics of selected columns to rows in
+// time order, the usual inputs of a forecasting model. For each column j in
+// Columns, in order, it appends:
+//
+//   - for each k in Lags, the value k rows earlier, x[i-k][j];
+//   - for each k in Diffs, the change over k rows, x[i][j] - x[i-k][j];
+//   - for each w in Windows, the mean, minimum, and maximum of the w rows
+//     ending at the current one, x[i-w+1..i][j].
+//
+// Rows are related by position only, so X must be sorted by time, one row
+// per period, and hold a single series; transform the series of a panel
+// separately. A feature whose rows reach back before the
+// first row of X is NaN, which the trees send right, as is a window that
+// contains a NaN. At prediction time, pass enough preceding rows to fill
+// the longest lag or window and discard their outputs.
+//
+// Lag a column that is not known at prediction time, such as past values
+// of the target added as a feature, and drop the column itself: windows and
+// differences include the current row.
+type TimeSeriesFeatures struct {
+	Columns []int `json:"columns"`
+	Lags    []int `json:"lags,omitempty"`
+	Diffs   []int `json:"diffs,omitempty"`
+	Windows []int `json:"windows,omitempty"`
+
+	// NumFeatures is the width of the rows seen by Fit; zero until fitted.
+	NumFeatures int `json:"num_features"`
+}
+
+// Fit checks the configuration against the width of X. y is ignored; it is
+// accepted so that TimeSeriesFeatures satisfies [Transformer].
+//
+// Returns [ErrEmptyDataset] if X is empty, [ErrFeatureCountMismatch] if its
+// rows differ in length, or an error wrapping
+// [ErrInvalidTimeSeriesFeatures] for a column out of range, a lag,
+// difference, or window below 1, or no features to generate.
+func (t *TimeSeriesFeatures) Fit(X [][]float64, _ []float64) error {
+	if len(X) == 0 {
+		return ErrEmptyDataset
+	}
+	if !hasSimilarLength(X) {
+		return ErrFeatureCountMismatch
+	}
+	if len(t.Columns) == 0 || len(t.Lags)+len(t.Diffs)+len(t.Windows) == 0 {
+		return fmt.Errorf("%w: no features to generate", ErrInvalidTimeSeriesFeatures)
+	}
+	for _, j := range t.Columns {
+		if j < 0 || j >= len(X[0]) {
+			return fmt.Errorf("%w: column %d out of range for %d features", ErrInvalidTimeSeriesFeatures, j, len(X[0]))
+		}
+	}
+	for _, k := range slices.Concat(t.Lags, t.Diffs, t.Windows) {
+		if k < 1 {
+			return fmt.Errorf("%w: lag, difference, or window %d", ErrInvalidTimeSeriesFeatures, k)
+		}
+	}
+	t.NumFeatures = len(X[0])
+	return nil
+}
+
+// Transform returns a copy of X with the generated features appended to
+// every row, in the order described on [TimeSeriesFeatures].
+//
+// Returns [ErrModelNotFitted] if Fit has not been called, or
+// [ErrFeatureCountMismatch] if a row's length differs from the data it was
+// fitted on.
+func (t *TimeSeriesFeatures) Transform(X [][]float64) ([][]float64, error) {
+	if t.NumFeatures == 0 {
+		return nil, ErrModelNotFitted
+	}
+	width := t.NumFeatures + len(t.Columns)*(len(t.Lags)+len(t.Diffs)+3*len(t.Windows))
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		if len(row) != t.NumFeatures {
+			return nil, ErrFeatureCountMismatch
+		}
+		out := make([]float64, 0, width)
+		out = append(out, row...)
+		for _, j := range t.Columns {
+			for _, k := range t.Lags {
+				out = append(out, lagged(X, i, j, k))
+			}
+			for _, k := range t.Diffs {
+				out = append(out, row[j]-lagged(X, i, j, k))
+			}
+			for _, w := range t.Windows {
+				out = append(out, rollingStats(X, i, j, w)...)
+			}
+		}
+		res[i] = out
+	}
+	return res, nil
+}
+
+// OutputNames returns the names of the output columns of
+// [TimeSeriesFeatures.Transform] given the names of its input columns: the
+// input names followed by, for a column "sales", names such as
+// "sales_lag_7", "sales_diff_1", "sales_mean_28", "sales_min_28", and
+// "sales_max_28".
+func (t *TimeSeriesFeatures) OutputNames(names []string) []string {
+	out := append([]string(nil), names...)
+	for _, j := range t.Columns {
+		for _, k := range t.Lags {
+			out = append(out, names[j]+"_lag_"+strconv.Itoa(k))
+		}
+		for _, k := range t.Diffs {
+			out = append(out, names[j]+"_diff_"+strconv.Itoa(k))
+		}
+		for _, w := range t.Windows {
+			for _, stat := range []string{"mean", "min", "max"} {
+				out = append(out, names[j]+"_"+stat+"_"+strconv.Itoa(w))
+			}
+		}
+	}
+	return out
+}
+
+func (t *TimeSeriesFeatures) transformerKind() string { return "time_series" }
+
+// AddTimeSeriesFeatures fits t on the dataset's features and replaces them
+// with its output, extending FeatureNames with [TimeSeriesFeatures.OutputNames].
+// Header is left untouched since it describes the original CSV file.
+// Returns the errors of [TimeSeriesFeatures.Fit].
+func (ds *Dataset) AddTimeSeriesFeatures(t *TimeSeriesFeatures) error {
+	if err := t.Fit(ds.X, ds.Y); err != nil {
+		return err
+	}
+	X, err := t.Transform(ds.X)
+	if err != nil {
+		return err
+	}
+	ds.X = X
+	if ds.FeatureNames != nil {
+		ds.FeatureNames = t.OutputNames(ds.FeatureNames)
+	}
+	return nil
+}
+
+// lagged returns X[i-k][j], or NaN if i < k.
+func lagged(X [][]float64, i, j, k int) float64 {
+	if i < k {
+		return math.NaN()
+	}
+	return X[i-k][j]
+}
+
+// rollingStats returns the mean, minimum, and maximum of X[i-w+1..i][j],
+// all NaN if the window starts before the first row. A NaN in the window
+// propagates to all three.
+func rollingStats(X [][]float64, i, j, w int) []float64 {
+	if i < w-1 {
+		return []float64{math.NaN(), math.NaN(), math.NaN()}
+	}
+	window := make([]float64, w)
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for r := range window {
+		v := X[i-w+1+r][j]
+		if math.IsNaN(v) {
+			return []float64{math.NaN(), math.NaN(), math.NaN()}
+		}
+		window[r] = v
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	return []float64{mean(window), lo, hi}
+}
@@ -0,0 +1,122 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestTimeSeriesFeatures(t *testing.T) {
+	X := [][]float64{{1, 10}, {2, 20}, {4, 30}, {7, 40}}
+	ts := &TimeSeriesFeatures{Columns: []int{0}, Lags: []int{1}, Diffs: []int{2}, Windows: []int{3}}
+	if err := ts.Fit(X, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ts.Transform(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nan := math.NaN()
+	want := [][]float64{
+		{1, 10, nan, nan, nan, nan, nan},
+		{2, 20, 1, nan, nan, nan, nan},
+		{4, 30, 2, 3, 7.0 / 3, 1, 4},
+		{7, 40, 4, 5, 13.0 / 3, 2, 7},
+	}
+	for i := range want {
+		if !slices.EqualFunc(got[i], want[i], func(a, b float64) bool {
+			return a == b || math.IsNaN(a) && math.IsNaN(b) || math.Abs(a-b) < 1e-12
+		}) {
+			t.Errorf("row %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if X[0][1] != 10 || len(X[0]) != 2 {
+		t.Errorf("Transform modified its input: %v", X)
+	}
+
+	names := ts.OutputNames([]string{"sales", "price"})
+	wantNames := []string{"sales", "price", "sales_lag_1", "sales_diff_2", "sales_mean_3", "sales_min_3", "sales_max_3"}
+	if !slices.Equal(names, wantNames) {
+		t.Errorf("OutputNames = %v, want %v", names, wantNames)
+	}
+
+	// A NaN inside a window blanks its statistics.
+	X[1][0] = nan
+	got, _ = ts.Transform(X)
+	if !math.IsNaN(got[2][4]) || !math.IsNaN(got[3][6]) || got[2][3] != 3 {
+		t.Errorf("rows with a missing value: %v", got)
+	}
+}
+
+func TestTimeSeriesFeaturesDataset(t *testing.T) {
+	ds := &Dataset{
+		X:            [][]float64{{1}, {2}, {3}},
+		Y:            []float64{1, 2, 3},
+		FeatureNames: []string{"t"},
+	}
+	ts := &TimeSeriesFeatures{Columns: []int{0}, Lags: []int{1, 2}}
+	if err := ds.AddTimeSeriesFeatures(ts); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ds.FeatureNames, []string{"t", "t_lag_1", "t_lag_2"}) || len(ds.X[2]) != 3 || ds.X[2][2] != 1 {
+		t.Errorf("dataset after AddTimeSeriesFeatures: %v %v", ds.FeatureNames, ds.X)
+	}
+}
+
+func TestTimeSeriesFeaturesPipeline(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	p := NewPipeline(New(DefaultConfig()), &TimeSeriesFeatures{Columns: []int{0, 1}, Lags: []int{1}, Windows: []int{3}})
+	if err := p.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if p.Model.NumFeatures() != 2+2*(1+3) {
+		t.Errorf("model has %d features", p.Model.NumFeatures())
+	}
+	want, _ := p.Predict(X)
+
+	path := filepath.Join(t.TempDir(), "pipeline.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPipeline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loaded.Predict(X); !slices.Equal(got, want) {
+		t.Errorf("loaded pipeline predicts differently")
+	}
+}
+
+func TestTimeSeriesFeaturesErrors(t *testing.T) {
+	X := [][]float64{{1, 2}, {3, 4}}
+	tests := []struct {
+		name string
+		ts   *TimeSeriesFeatures
+		X    [][]float64
+		want error
+	}{
+		{"empty", &TimeSeriesFeatures{Columns: []int{0}, Lags: []int{1}}, nil, ErrEmptyDataset},
+		{"nothing to generate", &TimeSeriesFeatures{Columns: []int{0}}, X, ErrInvalidTimeSeriesFeatures},
+		{"column", &TimeSeriesFeatures{Columns: []int{2}, Lags: []int{1}}, X, ErrInvalidTimeSeriesFeatures},
+		{"lag", &TimeSeriesFeatures{Columns: []int{0}, Lags: []int{0}}, X, ErrInvalidTimeSeriesFeatures},
+		{"window", &TimeSeriesFeatures{Columns: []int{0}, Windows: []int{-1}}, X, ErrInvalidTimeSeriesFeatures},
+	}
+	for _, tt := range tests {
+		if err := tt.ts.Fit(tt.X, nil); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	ts := &TimeSeriesFeatures{Columns: []int{0}, Lags: []int{1}}
+	if _, err := ts.Transform(X); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("unfitted: err = %v", err)
+	}
+	if err := ts.Fit(X, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.Transform([][]float64{{1}}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("row width: err = %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package gboost
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Tracker records training runs in an experiment tracking system, such as
+// the MLflow server implemented by the mlflow subpackage. Set it as
+// Config.Tracker and every call to [GBM.Fit], [GBM.FitWeighted], or
+// [GBM.FitStream] with a valid Config is reported as one run:
+//
+//   - LogParams once, before training, with the Config's settings;
+//   - LogMetrics after every round, with the round's [RoundStats];
+//   - LogArtifact once after successful training, with the model as
+//     "model.json" in the format written by [GBM.Save];
+//   - End once, with the error training failed with, or nil.
+//
+// An error from LogParams, LogMetrics, or LogArtifact stops training; Fit
+// returns it wrapped and still calls End with it. Fits run by helpers such
+// as [CrossValidate] and [GridSearch] are reported too, possibly from
+// several goroutines at once, so clear the Tracker from the Config passed
+// to them unless it is safe for concurrent use.
+type Tracker interface {
+	LogParams(params map[string]string) error
+	LogMetrics(step int, metrics map[string]float64) error
+	LogArtifact(name string, data []byte) error
+	End(err error) error
+}
+
+// tracked runs train, which must validate the Config, as one run of
+// Config.Tracker, if set.
+func (g *GBM) tracked(train func() error) error {
+	t := g.Config.Tracker
+	if t == nil {
+		return train()
+	}
+	if err := g.Config.validate(); err != nil {
+		return err
+	}
+	params, err := g.Config.trackerParams()
+	if err == nil {
+		err = t.LogParams(params)
+	}
+	if err != nil {
+		err = fmt.Errorf("tracker: %w", err)
+		t.End(err)
+		return err
+	}
+
+	err = train()
+	if err == nil {
+		var model []byte
+		if model, err = json.MarshalIndent(g.toExported(), "", "  "); err == nil {
+			err = t.LogArtifact("model.json", model)
+		}
+		if err != nil {
+			err = fmt.Errorf("tracker: %w", err)
+		}
+	}
+	if endErr := t.End(err); err == nil && endErr != nil {
+		err = fmt.Errorf("tracker: %w", endErr)
+	}
+	return err
+}
+
+// trackerParams returns the Config's serializable settings, keyed by their
+// field names, as strings: numbers in Go syntax, and CostMatrix in JSON.
+func (c Config) trackerParams() (map[string]string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string, len(fields))
+	for name, raw := range fields {
+		if string(raw) == "null" {
+			continue
+		}
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			params[name] = s
+		} else {
+			params[name] = string(raw)
+		}
+	}
+	return params, nil
+}
+
+// trackRound logs the last round of the history to Config.Tracker, if set.
+// Losses are logged as "train_loss" and, with a validation set,
+// "validation_loss"; EvalSlices scores as "<slice>.<metric>"; and, with
+// Config.TrackResources, the round's [RoundResources] as "round_seconds",
+// "round_allocs", "round_alloc_bytes", and "round_peak_heap_bytes".
+func (g *GBM) trackRound() error {
+	if g.Config.Tracker == nil {
+		return nil
+	}
+	stats := g.history[len(g.history)-1]
+	metrics := map[string]float64{
+		"learning_rate": stats.LearningRate,
+		"sample_size":   float64(stats.SampleSize),
+		"tree_depth":    float64(stats.Depth),
+		"tree_leaves":   float64(stats.Leaves),
+		"tree_gain":     stats.Gain,
+	}
+	if !math.IsNaN(stats.TrainLoss) {
+		metrics["train_loss"] = stats.TrainLoss
+	}
+	if !math.IsNaN(stats.ValidationLoss) {
+		metrics["validation_loss"] = stats.ValidationLoss
+	}
+	if g.Config.TrackResources {
+		metrics["round_seconds"] = stats.Resources.Duration.Seconds()
+		metrics["round_allocs"] = float64(stats.Resources.Allocs)
+		metrics["round_alloc_bytes"] = float64(stats.Resources.AllocBytes)
+		metrics["round_peak_heap_bytes"] = float64(stats.Resources.PeakHeapBytes)
+	}
+	for slice, scores := range stats.Slices {
+		for name, v := range scores {
+			metrics[slice+"."+name] = v
+		}
+	}
+	if err := g.Config.Tracker.LogMetrics(stats.Round, metrics); err != nil {
+		return fmt.Errorf("tracker: round %d: %w", stats.Round, err)
+	}
+	return nil
+}
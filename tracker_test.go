@@ -0,0 +1,116 @@
+package gboost
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// recordingTracker is a Tracker that keeps everything logged to it.
+type recordingTracker struct {
+	params    map[string]string
+	steps     []int
+	metrics   []map[string]float64
+	artifacts map[string][]byte
+	ended     bool
+	endErr    error
+	failAt    int // step whose LogMetrics fails, if positive
+}
+
+func (r *recordingTracker) LogParams(params map[string]string) error {
+	r.params = params
+	return nil
+}
+
+func (r *recordingTracker) LogMetrics(step int, metrics map[string]float64) error {
+	if step == r.failAt {
+		return errors.New("tracking server down")
+	}
+	r.steps = append(r.steps, step)
+	r.metrics = append(r.metrics, metrics)
+	return nil
+}
+
+func (r *recordingTracker) LogArtifact(name string, data []byte) error {
+	if r.artifacts == nil {
+		r.artifacts = make(map[string][]byte)
+	}
+	r.artifacts[name] = data
+	return nil
+}
+
+func (r *recordingTracker) End(err error) error {
+	r.ended, r.endErr = true, err
+	return nil
+}
+
+func TestTracker(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 5
+	cfg.ValidationFraction = 0.2
+	cfg.Patience = 5
+	tracker := &recordingTracker{}
+	cfg.Tracker = tracker
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	if tracker.params["NEstimators"] != "5" || tracker.params["Loss"] != "mse" || tracker.params["LearningRate"] != "0.1" {
+		t.Errorf("params = %v", tracker.params)
+	}
+	if len(tracker.steps) != 5 || tracker.steps[4] != 5 {
+		t.Fatalf("logged steps %v, want 1 to 5", tracker.steps)
+	}
+	for i, h := range gbm.History() {
+		m := tracker.metrics[i]
+		if m["train_loss"] != h.TrainLoss || m["validation_loss"] != h.ValidationLoss || m["tree_leaves"] != float64(h.Leaves) {
+			t.Errorf("round %d: metrics %v do not match %+v", h.Round, m, h)
+		}
+	}
+	if _, ok := tracker.metrics[0]["round_seconds"]; ok {
+		t.Error("resources logged without TrackResources")
+	}
+	var model ExportedModel
+	if err := json.Unmarshal(tracker.artifacts["model.json"], &model); err != nil || len(model.Trees) != gbm.NumTrees() {
+		t.Errorf("model artifact: %d trees, err %v", len(model.Trees), err)
+	}
+	if !tracker.ended || tracker.endErr != nil {
+		t.Errorf("ended = %v with %v, want a successful end", tracker.ended, tracker.endErr)
+	}
+
+	tracker = &recordingTracker{}
+	cfg.Tracker = tracker
+	cfg.TrackResources = true
+	gbm = New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	for i, h := range gbm.History() {
+		m := tracker.metrics[i]
+		if m["round_seconds"] != h.Resources.Duration.Seconds() || m["round_alloc_bytes"] != float64(h.Resources.AllocBytes) {
+			t.Errorf("round %d: metrics %v do not match %+v", h.Round, m, h.Resources)
+		}
+	}
+	cfg.TrackResources = false
+
+	// A tracker failure stops training and ends the run as failed.
+	tracker = &recordingTracker{failAt: 2}
+	cfg.Tracker = tracker
+	err := New(cfg).Fit(X, y)
+	if err == nil || !errors.Is(tracker.endErr, err) {
+		t.Errorf("err = %v, ended with %v", err, tracker.endErr)
+	}
+	if tracker.artifacts != nil {
+		t.Error("logged a model from a failed run")
+	}
+
+	// Invalid configurations do not start a run.
+	tracker = &recordingTracker{}
+	cfg.Tracker = tracker
+	cfg.NEstimators = -1
+	if err := New(cfg).Fit(X, y); !errors.Is(err, ErrInvalidNEstimators) || tracker.params != nil || tracker.ended {
+		t.Errorf("err = %v, params %v, ended %v", err, tracker.params, tracker.ended)
+	}
+}
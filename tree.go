@@ -1,5 +1,10 @@
 package gboost
 
+import (
+	"slices"
+	"sync"
+)
+
 // Node is the basic tree node.
 // A leaf node has Left == Right == nil.
 type Node struct {
@@ -11,6 +16,7 @@ type Node struct {
 
 	Gain     float64 // Recording how much gain the split at this node contributed, then we can get the important features.
 	NSamples int     // Number of samples at this node.
+	Cover    float64 // Sum of the Hessians of the samples at this node.
 }
 
 type Split struct {
@@ -27,11 +33,13 @@ func buildLeafNode(y, hessians []float64) *Node {
 		Threshold:    0,  // Not relevant in this case
 		Value:        sum(y) / sum(hessians),
 		NSamples:     len(y),
+		Cover:        sum(hessians),
 	}
 }
 
 // buildTree recursively builds a decision tree picking up the best split it can.
-func buildTree(X [][]float64, y []float64, hessians []float64, indices []int, depth int, cfg Config) *Node {
+// features lists the feature columns eligible for splitting; nil means all of them.
+func buildTree(X [][]float64, y []float64, hessians []float64, indices []int, features []int, depth int, cfg Config) *Node {
 	if depth >= cfg.MaxDepth || len(indices) < 2 {
 		return buildLeafNode(
 			extractRows(y, indices),
@@ -39,7 +47,7 @@ func buildTree(X [][]float64, y []float64, hessians []float64, indices []int, de
 		)
 	}
 
-	split := findBestSplit(X, y, indices, cfg.MinSamplesLeaf)
+	split := findBestSplitParallel(X, y, hessians, indices, features, cfg.MinSamplesLeaf, cfg.SplitWorkers, cfg.SplitThresholds, cfg.SplitCriterion)
 	if split == nil {
 		// Return leaf node
 		return buildLeafNode(
@@ -53,23 +61,90 @@ func buildTree(X [][]float64, y []float64, hessians []float64, indices []int, de
 		Threshold:    split.Threshold,
 		Gain:         split.Gain,
 		NSamples:     len(indices),
+		Cover:        sum(extractRows(hessians, indices)),
 	}
-	node.Left = buildTree(X, y, hessians, split.LeftIndices, depth+1, cfg)
-	node.Right = buildTree(X, y, hessians, split.RightIndices, depth+1, cfg)
+	node.Left = buildTree(X, y, hessians, split.LeftIndices, features, depth+1, cfg)
+	node.Right = buildTree(X, y, hessians, split.RightIndices, features, depth+1, cfg)
 	return node
 }
 
-func findBestSplit(X [][]float64, y []float64, indices []int, minSamplesLeaf int) *Split {
+// shrinkTree applies hierarchical shrinkage to a tree using the samples in
+// indices: each node's value becomes its parent's shrunk value plus the
+// change in raw Newton value along the edge, damped by 1 + lambda/N(parent).
+// Leaves that rest on few samples are pulled toward their ancestors.
+//
+// indices need not be the rows the tree was built on. A node that none of
+// them reach keeps its parent's value, so with lambda == 0 shrinkTree
+// re-estimates each leaf from the rows that reach it, falling back to the
+// nearest ancestor with any.
+func shrinkTree(n *Node, X [][]float64, y, hessians []float64, indices []int, lambda float64) {
+	raw := sum(extractRows(y, indices)) / sum(extractRows(hessians, indices))
+	shrinkNode(n, X, y, hessians, indices, raw, raw, lambda)
+}
+
+func shrinkNode(n *Node, X [][]float64, y, hessians []float64, indices []int, raw, shrunk, lambda float64) {
+	if n.isLeaf() {
+		n.Value = shrunk
+		return
+	}
+	left, right := partition(X, indices, n.FeatureIndex, n.Threshold)
+	damping := 1.0
+	if lambda > 0 {
+		damping += lambda / float64(len(indices))
+	}
+	for _, child := range []struct {
+		node    *Node
+		indices []int
+	}{{n.Left, left}, {n.Right, right}} {
+		childRaw := raw
+		if len(child.indices) > 0 {
+			childRaw = sum(extractRows(y, child.indices)) / sum(extractRows(hessians, child.indices))
+		}
+		shrinkNode(child.node, X, y, hessians, child.indices, childRaw, shrunk+(childRaw-raw)/damping, lambda)
+	}
+}
+
+// scaleTree multiplies every node value of the tree rooted at n by factor,
+// scaling the tree's contribution as a smaller learning rate would.
+func scaleTree(n *Node, factor float64) {
+	n.Value *= factor
+	if !n.isLeaf() {
+		scaleTree(n.Left, factor)
+		scaleTree(n.Right, factor)
+	}
+}
+
+// findBestSplit returns the split of the rows in indices with the highest
+// gain, or nil if none improves on the parent. A feature's candidate
+// thresholds are its values in those rows, or, for features listed in
+// thresholds, only the listed values. Gains are variance reductions of y,
+// or scored by criterion if it is not nil.
+func findBestSplit(X [][]float64, y, hessians []float64, indices []int, features []int, minSamplesLeaf int, thresholds map[int][]float64, criterion SplitCriterion) *Split {
 	var bestSplit *Split
 	var bestGain float64 = 0.0
 
-	numFeatures := len(X[0])
+	if features == nil {
+		features = allFeatures(len(X[0]))
+	}
 
-	parentVariance := variance(extractRows(y, indices))
+	var (
+		parentVariance float64
+		parent         NodeRows
+	)
+	if criterion == nil {
+		parentVariance = variance(extractRows(y, indices))
+	} else {
+		parent = nodeRows(y, hessians, indices)
+	}
 
-	for featureIndex := 0; featureIndex < numFeatures; featureIndex++ {
-		featureValues := extractFeatureValues(X, indices, featureIndex)
-		candidateThresholds := uniq(sort(featureValues))
+	for _, featureIndex := range features {
+		var candidateThresholds []float64
+		if allowed, ok := thresholds[featureIndex]; ok {
+			candidateThresholds = uniq(sort(slices.Clone(allowed)))
+		} else {
+			featureValues := extractFeatureValues(X, indices, featureIndex)
+			candidateThresholds = uniq(sort(featureValues))
+		}
 
 		for _, threshold := range candidateThresholds {
 			leftIndices, rightIndices := partition(X, indices, featureIndex, threshold)
@@ -82,7 +157,13 @@ func findBestSplit(X [][]float64, y []float64, indices []int, minSamplesLeaf int
 				LeftIndices:  leftIndices,
 				RightIndices: rightIndices,
 			}
-			gain := split.ComputeGain(y, indices, parentVariance)
+			var gain float64
+			if criterion == nil {
+				gain = split.ComputeGain(y, indices, parentVariance)
+			} else {
+				gain = criterion.Gain(parent, nodeRows(y, hessians, leftIndices), nodeRows(y, hessians, rightIndices))
+				split.Gain = gain
+			}
 			if gain > bestGain {
 				bestGain = gain
 				bestSplit = split
@@ -92,23 +173,99 @@ func findBestSplit(X [][]float64, y []float64, indices []int, minSamplesLeaf int
 	return bestSplit
 }
 
-func (s *Split) ComputeGain(y []float64, indices []int, parentVariance float64) float64 {
-	n := len(indices)
-	nLeft := len(s.LeftIndices)
-	nRight := len(s.RightIndices)
+// findBestSplitParallel searches features with up to workers goroutines,
+// each owning a contiguous block of features and proposing the best split
+// within it. Proposals are reduced in block order, keeping the first of
+// equal gains, so the result is the split findBestSplit would choose.
+func findBestSplitParallel(X [][]float64, y, hessians []float64, indices []int, features []int, minSamplesLeaf, workers int, thresholds map[int][]float64, criterion SplitCriterion) *Split {
+	if features == nil {
+		features = allFeatures(len(X[0]))
+	}
+	workers = min(workers, len(features))
+	if workers <= 1 {
+		return findBestSplit(X, y, hessians, indices, features, minSamplesLeaf, thresholds, criterion)
+	}
 
-	leftVariance := variance(extractRows(y, s.LeftIndices))
-	rightVariance := variance(extractRows(y, s.RightIndices))
+	proposals := make([]*Split, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		block := features[w*len(features)/workers : (w+1)*len(features)/workers]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			proposals[w] = findBestSplit(X, y, hessians, indices, block, minSamplesLeaf, thresholds, criterion)
+		}()
+	}
+	wg.Wait()
 
-	weightedChildVariance := (float64(nLeft)/float64(n))*leftVariance +
-		(float64(nRight)/float64(n))*rightVariance
+	var best *Split
+	for _, p := range proposals {
+		if p != nil && (best == nil || p.Gain > best.Gain) {
+			best = p
+		}
+	}
+	return best
+}
 
-	gain := parentVariance - weightedChildVariance
+func (s *Split) ComputeGain(y []float64, indices []int, parentVariance float64) float64 {
+	gain := varianceReduction(parentVariance, extractRows(y, s.LeftIndices), extractRows(y, s.RightIndices))
 	s.Gain = gain
 
 	return gain
 }
 
+// unrolledMaxDepth is the deepest tree scored by predictUnrolled.
+const unrolledMaxDepth = 8
+
+// predictUnrolled returns the same value as predict, walking the first depth
+// <= unrolledMaxDepth levels of the tree with a fixed sequence of steps
+// instead of recursion. The switch jumps once per tree into the chain of
+// steps, which saves a call per level on the latency-sensitive serving path.
+// Trees deeper than depth finish the walk recursively.
+func (n *Node) predictUnrolled(x []float64, depth int) float64 {
+	switch depth {
+	case 8:
+		n = n.step(x)
+		fallthrough
+	case 7:
+		n = n.step(x)
+		fallthrough
+	case 6:
+		n = n.step(x)
+		fallthrough
+	case 5:
+		n = n.step(x)
+		fallthrough
+	case 4:
+		n = n.step(x)
+		fallthrough
+	case 3:
+		n = n.step(x)
+		fallthrough
+	case 2:
+		n = n.step(x)
+		fallthrough
+	case 1:
+		n = n.step(x)
+	}
+	if !n.isLeaf() {
+		return n.predict(x)
+	}
+	return n.Value
+}
+
+// step returns the child of n that x falls into, or n itself if n is a
+// leaf, so that leaves shallower than the walk absorb its remaining steps.
+func (n *Node) step(x []float64) *Node {
+	if n.Left == nil {
+		return n
+	}
+	if x[n.FeatureIndex] < n.Threshold {
+		return n.Left
+	}
+	return n.Right
+}
+
 // Tree predict single
 func (n *Node) predict(x []float64) float64 {
 	if n.Left == nil && n.Right == nil {
@@ -129,11 +286,39 @@ func (n *Node) collectGains(index []float64) {
 		// Leaf node. Return value
 		return
 	}
-	index[n.FeatureIndex] += float64(n.NSamples) * n.Gain
+	index[n.FeatureIndex] += float64(float64(n.NSamples) * n.Gain)
 	n.Left.collectGains(index)
 	n.Right.collectGains(index)
 }
 
+func (n *Node) isLeaf() bool {
+	return n.Left == nil && n.Right == nil
+}
+
+// depth returns the number of edges on the longest root-to-leaf path.
+func (n *Node) depth() int {
+	if n.isLeaf() {
+		return 0
+	}
+	return 1 + max(n.Left.depth(), n.Right.depth())
+}
+
+func (n *Node) numLeaves() int {
+	if n.isLeaf() {
+		return 1
+	}
+	return n.Left.numLeaves() + n.Right.numLeaves()
+}
+
+// totalGain returns the sample-weighted gain of every split in the tree,
+// in the same units feature importance is accumulated in.
+func (n *Node) totalGain() float64 {
+	if n.isLeaf() {
+		return 0
+	}
+	return float64(float64(n.NSamples)*n.Gain) + n.Left.totalGain() + n.Right.totalGain()
+}
+
 func (n *Node) expectedValue() float64 {
 	if n.Left == nil && n.Right == nil {
 		// Leaf node
@@ -143,7 +328,15 @@ func (n *Node) expectedValue() float64 {
 	rL := float64(n.Left.NSamples) / float64(n.NSamples)
 	rR := float64(n.Right.NSamples) / float64(n.NSamples)
 
-	return rL*n.Left.expectedValue() + rR*n.Right.expectedValue()
+	return float64(rL*n.Left.expectedValue()) + float64(rR*n.Right.expectedValue())
+}
+
+func allFeatures(numFeatures int) []int {
+	res := make([]int, numFeatures)
+	for i := range res {
+		res[i] = i
+	}
+	return res
 }
 
 func extractRows[T any](y []T, indices []int) []T {
@@ -1,7 +1,10 @@
 package gboost
 
 import (
+	"errors"
 	"math"
+	"math/rand"
+	"path/filepath"
 	"slices"
 	"testing"
 )
@@ -280,7 +283,7 @@ func TestFindBestSplit(t *testing.T) {
 	y := []float64{1.0, 2.0, 10.0, 11.0} // clear split between indices 1 and 2
 	indices := []int{0, 1, 2, 3}
 
-	split := findBestSplit(X, y, indices, 1)
+	split := findBestSplit(X, y, nil, indices, nil, 1, nil, nil)
 
 	if split == nil {
 		t.Fatal("expected a split, got nil")
@@ -317,13 +320,31 @@ func TestFindBestSplitNoValidSplit(t *testing.T) {
 	y := []float64{5.0, 5.0}
 	indices := []int{0, 1}
 
-	split := findBestSplit(X, y, indices, 1)
+	split := findBestSplit(X, y, nil, indices, nil, 1, nil, nil)
 
 	if split != nil {
 		t.Errorf("expected nil split for identical data, got %+v", split)
 	}
 }
 
+func TestFindBestSplitIgnoresRoundingGains(t *testing.T) {
+	// Identical gradients leave nothing to explain, but 0.1 is inexact, so
+	// their mean carries rounding error. Any split found is an artifact of
+	// that error.
+	rnd := rand.New(rand.NewSource(1))
+	X := make([][]float64, 1000)
+	y := make([]float64, len(X))
+	indices := make([]int, len(X))
+	for i := range X {
+		X[i] = []float64{rnd.Float64(), rnd.Float64()}
+		y[i] = 0.1 * 3
+		indices[i] = i
+	}
+	if split := findBestSplit(X, y, nil, indices, nil, 1, nil, nil); split != nil {
+		t.Errorf("split on feature %d at %v with gain %g, want none", split.FeatureIndex, split.Threshold, split.Gain)
+	}
+}
+
 func TestFindBestSplitMinSamplesLeaf(t *testing.T) {
 	X := [][]float64{
 		{1.0},
@@ -335,7 +356,7 @@ func TestFindBestSplitMinSamplesLeaf(t *testing.T) {
 
 	// With minSamplesLeaf=2, the only valid split is [0,1] vs [2]
 	// but [2] has only 1 sample, so no valid split
-	split := findBestSplit(X, y, indices, 2)
+	split := findBestSplit(X, y, nil, indices, nil, 2, nil, nil)
 
 	if split != nil {
 		// Check that both sides have at least 2 samples
@@ -365,7 +386,7 @@ func TestBuildTree(t *testing.T) {
 		hessians[i] = 1.0
 	}
 
-	tree := buildTree(X, y, hessians, indices, 0, cfg)
+	tree := buildTree(X, y, hessians, indices, nil, 0, cfg)
 
 	if tree == nil {
 		t.Fatal("expected a tree, got nil")
@@ -411,7 +432,7 @@ func TestBuildTreeMaxDepth(t *testing.T) {
 		hessians[i] = 1.0
 	}
 
-	tree := buildTree(X, y, hessians, indices, 0, cfg)
+	tree := buildTree(X, y, hessians, indices, nil, 0, cfg)
 
 	if tree == nil {
 		t.Fatal("expected a tree, got nil")
@@ -441,7 +462,7 @@ func TestBuildTreeSingleSample(t *testing.T) {
 		MinSamplesLeaf: 1,
 	}
 
-	tree := buildTree(X, y, hessians, indices, 0, cfg)
+	tree := buildTree(X, y, hessians, indices, nil, 0, cfg)
 
 	if tree == nil {
 		t.Fatal("expected a tree, got nil")
@@ -511,7 +532,7 @@ func TestBuildTreeWithNonUniformHessians(t *testing.T) {
 		MinSamplesLeaf: 1,
 	}
 
-	tree := buildTree(X, grads, hessians, indices, 0, cfg)
+	tree := buildTree(X, grads, hessians, indices, nil, 0, cfg)
 
 	if tree == nil {
 		t.Fatal("expected a tree, got nil")
@@ -528,6 +549,11 @@ func TestBuildTreeWithNonUniformHessians(t *testing.T) {
 	if math.Abs(tree.Right.Value-40.0) > 1e-10 {
 		t.Errorf("right leaf value = %v, want 40.0", tree.Right.Value)
 	}
+
+	// Cover is the Hessian sum at each node.
+	if tree.Cover != 1.5 || tree.Left.Cover != 1.0 || tree.Right.Cover != 0.5 {
+		t.Errorf("cover = (%v, %v, %v), want (1.5, 1, 0.5)", tree.Cover, tree.Left.Cover, tree.Right.Cover)
+	}
 }
 
 func TestCollectGainsLeafNode(t *testing.T) {
@@ -648,7 +674,7 @@ func TestNSamplesInvariant(t *testing.T) {
 		MinSamplesLeaf: 1,
 	}
 
-	tree := buildTree(X, y, hessians, indices, 0, cfg)
+	tree := buildTree(X, y, hessians, indices, nil, 0, cfg)
 
 	var check func(n *Node, path string)
 	check = func(n *Node, path string) {
@@ -679,3 +705,216 @@ func TestNSamplesInvariant(t *testing.T) {
 		t.Errorf("root NSamples=%d, want %d", tree.NSamples, len(indices))
 	}
 }
+
+func TestNodeShapeHelpers(t *testing.T) {
+	// Root splits into a leaf and a further split, so depth is 2 with 3 leaves.
+	tree := &Node{
+		FeatureIndex: 0, Threshold: 1, NSamples: 10, Gain: 0.5,
+		Left: &Node{Value: 1, NSamples: 4},
+		Right: &Node{
+			FeatureIndex: 1, Threshold: 2, NSamples: 6, Gain: 0.25,
+			Left:  &Node{Value: 2, NSamples: 3},
+			Right: &Node{Value: 3, NSamples: 3},
+		},
+	}
+
+	if d := tree.depth(); d != 2 {
+		t.Errorf("depth() = %d, want 2", d)
+	}
+	if l := tree.numLeaves(); l != 3 {
+		t.Errorf("numLeaves() = %d, want 3", l)
+	}
+	// 10*0.5 + 6*0.25 = 6.5
+	if g := tree.totalGain(); math.Abs(g-6.5) > 1e-12 {
+		t.Errorf("totalGain() = %v, want 6.5", g)
+	}
+	if leaf := tree.Left; leaf.depth() != 0 || leaf.numLeaves() != 1 || leaf.totalGain() != 0 {
+		t.Errorf("leaf stats = (%d, %d, %v), want (0, 1, 0)", leaf.depth(), leaf.numLeaves(), leaf.totalGain())
+	}
+}
+
+func TestShrinkTree(t *testing.T) {
+	X := [][]float64{{1.0}, {2.0}, {3.0}, {4.0}}
+	y := []float64{1.0, 1.0, 10.0, 10.0}
+	hessians := []float64{1.0, 1.0, 1.0, 1.0}
+	indices := []int{0, 1, 2, 3}
+	cfg := Config{MaxDepth: 1, MinSamplesLeaf: 1}
+
+	// lambda = 0 leaves the Newton values untouched.
+	tree := buildTree(X, y, hessians, indices, nil, 0, cfg)
+	shrinkTree(tree, X, y, hessians, indices, 0)
+	if tree.Left.Value != 1.0 || tree.Right.Value != 10.0 {
+		t.Errorf("lambda=0 leaves = (%v, %v), want (1, 10)", tree.Left.Value, tree.Right.Value)
+	}
+
+	// lambda = 4 with 4 root samples halves each leaf's distance from the
+	// root value 5.5: 5.5 + (1-5.5)/2 = 3.25 and 5.5 + (10-5.5)/2 = 7.75.
+	tree = buildTree(X, y, hessians, indices, nil, 0, cfg)
+	shrinkTree(tree, X, y, hessians, indices, 4)
+	if math.Abs(tree.Left.Value-3.25) > 1e-12 || math.Abs(tree.Right.Value-7.75) > 1e-12 {
+		t.Errorf("lambda=4 leaves = (%v, %v), want (3.25, 7.75)", tree.Left.Value, tree.Right.Value)
+	}
+	// With unit Hessians the sample-weighted mean of the leaves is preserved.
+	if ev := tree.expectedValue(); math.Abs(ev-5.5) > 1e-12 {
+		t.Errorf("expectedValue() = %v, want 5.5", ev)
+	}
+}
+
+func TestShrinkTreeReestimatesLeaves(t *testing.T) {
+	X := [][]float64{{1.0}, {2.0}, {3.0}, {4.0}, {1.5}, {3.5}, {3.6}}
+	y := []float64{1.0, 2.0, 10.0, 12.0, 2.0, 6.0, 8.0}
+	hessians := []float64{1, 1, 1, 1, 1, 1, 1}
+	cfg := Config{MaxDepth: 2, MinSamplesLeaf: 1}
+
+	// Structure from rows 0-3 splits at 3, then at 2 and 4.
+	tree := buildTree(X, y, hessians, []int{0, 1, 2, 3}, nil, 0, cfg)
+
+	// Row 4 reaches the leftmost leaf and rows 5, 6 the leaf for [3, 4); the
+	// other two leaves fall back to their parents' estimates.
+	shrinkTree(tree, X, y, hessians, []int{4, 5, 6}, 0)
+	want := []float64{2, 2, 7, 7}
+	var got []float64
+	var collect func(n *Node)
+	collect = func(n *Node) {
+		if n.isLeaf() {
+			got = append(got, n.Value)
+			return
+		}
+		collect(n.Left)
+		collect(n.Right)
+	}
+	collect(tree)
+	if !slices.Equal(got, want) {
+		t.Errorf("re-estimated leaves = %v, want %v", got, want)
+	}
+}
+
+func TestFindBestSplitParallelMatchesSerial(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	X := make([][]float64, 60)
+	y := make([]float64, 60)
+	for i := range X {
+		X[i] = make([]float64, 25)
+		for j := range X[i] {
+			X[i][j] = float64(rnd.Intn(8))
+		}
+		// Column 20 duplicates column 7, so their gains tie.
+		X[i][20] = X[i][7]
+		y[i] = X[i][7] + rnd.NormFloat64()*0.1
+	}
+	indices := allFeatures(len(X))
+
+	want := findBestSplit(X, y, nil, indices, nil, 3, nil, nil)
+	for _, workers := range []int{2, 3, 7, 25, 100} {
+		got := findBestSplitParallel(X, y, nil, indices, nil, 3, workers, nil, nil)
+		if got.FeatureIndex != want.FeatureIndex || got.Threshold != want.Threshold || got.Gain != want.Gain {
+			t.Errorf("workers=%d: split (%d, %v, %v), want (%d, %v, %v)",
+				workers, got.FeatureIndex, got.Threshold, got.Gain, want.FeatureIndex, want.Threshold, want.Gain)
+		}
+	}
+	if want.FeatureIndex != 7 {
+		t.Errorf("tie between duplicate columns resolved to feature %d, want the first, 7", want.FeatureIndex)
+	}
+}
+
+func TestPredictUnrolledMatchesPredict(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 300)
+	y := make([]float64, len(X))
+	for i := range X {
+		X[i] = []float64{rnd.Float64(), rnd.Float64(), rnd.Float64()}
+		y[i] = math.Sin(8*X[i][0]) + X[i][1]*X[i][2] + 0.1*rnd.NormFloat64()
+	}
+
+	for _, maxDepth := range []int{1, 3, 8, 10} {
+		cfg := DefaultConfig()
+		cfg.NEstimators = 5
+		cfg.MaxDepth = maxDepth
+		cfg.MinSamplesLeaf = 1
+		gbm := New(cfg)
+		if err := gbm.Fit(X, y); err != nil {
+			t.Fatal(err)
+		}
+		for _, tree := range gbm.trees {
+			// Walks shorter than the tree fall back to recursion, so every
+			// depth must agree with predict.
+			for depth := range unrolledMaxDepth + 1 {
+				for _, x := range X {
+					if got, want := tree.predictUnrolled(x, depth), tree.predict(x); got != want {
+						t.Fatalf("MaxDepth %d, depth %d: predictUnrolled = %v, predict = %v", maxDepth, depth, got, want)
+					}
+				}
+			}
+		}
+		if maxDepth <= unrolledMaxDepth && gbm.maxDepth > maxDepth {
+			t.Errorf("MaxDepth %d: model depth %d", maxDepth, gbm.maxDepth)
+		}
+	}
+}
+
+func TestSplitThresholds(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	cfg := DefaultConfig()
+	cfg.NEstimators = 10
+	cfg.SplitThresholds = map[int][]float64{0: {0.75, 0.25, 0.5, 0.25}}
+	gbm := New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	var splits0, splits1 int
+	for i := range gbm.NumTrees() {
+		tree, _ := gbm.Tree(i)
+		walkSplits(tree, func(n *Node) {
+			if n.FeatureIndex == 1 {
+				splits1++
+				return
+			}
+			splits0++
+			if !slices.Contains([]float64{0.25, 0.5, 0.75}, n.Threshold) {
+				t.Errorf("tree %d splits feature 0 at %v, outside its thresholds", i, n.Threshold)
+			}
+		})
+	}
+	if splits0 == 0 || splits1 == 0 {
+		t.Errorf("%d splits on feature 0 and %d on feature 1, want both used", splits0, splits1)
+	}
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := gbm.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(loaded.Config.SplitThresholds[0], cfg.SplitThresholds[0]) {
+		t.Errorf("loaded SplitThresholds = %v", loaded.Config.SplitThresholds)
+	}
+
+	// An empty list keeps the feature out of every tree.
+	cfg.SplitThresholds = map[int][]float64{0: {}}
+	gbm = New(cfg)
+	if err := gbm.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if imp := gbm.FeatureImportance(); imp[0] != 0 {
+		t.Errorf("feature 0 importance = %v with no allowed thresholds", imp[0])
+	}
+}
+
+func TestSplitThresholdsInvalid(t *testing.T) {
+	X, y := generateDataWithFunc(linearFunc)
+	for _, thresholds := range []map[int][]float64{
+		{0: {math.NaN()}},
+		{1: {math.Inf(1)}},
+		{-1: {0.5}},
+		{2: {0.5}}, // the data has 2 features
+	} {
+		cfg := DefaultConfig()
+		cfg.SplitThresholds = thresholds
+		if err := New(cfg).Fit(X, y); !errors.Is(err, ErrInvalidSplitThresholds) {
+			t.Errorf("SplitThresholds %v: err = %v, want ErrInvalidSplitThresholds", thresholds, err)
+		}
+	}
+}
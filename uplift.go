@@ -0,0 +1,126 @@
+package gboost
+
+import "fmt"
+
+// UpliftMethod selects how an [UpliftModel] estimates treatment effects.
+type UpliftMethod string
+
+const (
+	// UpliftTwoModel trains separate models on the treated and control rows
+	// and predicts the difference of their outputs (the "T-learner").
+	UpliftTwoModel UpliftMethod = "two-model"
+
+	// UpliftTransformedOutcome trains a single regression model on the
+	// transformed outcome z = y*(w-p)/(p*(1-p)), where w is the treatment
+	// indicator and p the treated fraction. Under randomized treatment
+	// E[z | x] equals the treatment effect, so the model predicts it directly.
+	UpliftTransformedOutcome UpliftMethod = "transformed-outcome"
+)
+
+// UpliftModel estimates the effect of a binary treatment on the target, such
+// as the change in conversion probability caused by a marketing campaign,
+// from data where treatment was assigned at random.
+//
+// With [UpliftTwoModel], Treated and Control are trained on their respective
+// rows and can be inspected like any [GBM]; for logloss models the uplift is
+// a difference of probabilities. With [UpliftTransformedOutcome], Effect is a
+// squared-error model of the effect itself.
+type UpliftModel struct {
+	Method UpliftMethod
+
+	Treated *GBM // Two-model: trained on treated rows.
+	Control *GBM // Two-model: trained on control rows.
+	Effect  *GBM // Transformed outcome: trained on the transformed target.
+}
+
+// NewUplift creates an untrained [UpliftModel]. cfg is used for every
+// underlying model, except that the transformed-outcome model always uses
+// the "mse" loss since its target is not binary.
+func NewUplift(cfg Config, method UpliftMethod) *UpliftModel {
+	u := &UpliftModel{Method: method}
+	switch method {
+	case UpliftTwoModel:
+		u.Treated = New(cfg)
+		u.Control = New(cfg)
+	case UpliftTransformedOutcome:
+		cfg.Loss = "mse"
+		u.Effect = New(cfg)
+	}
+	return u
+}
+
+// Fit trains the model on samples X with outcomes y, where treatment[i]
+// reports whether sample i received the treatment.
+//
+// Returns [ErrInvalidUpliftMethod] for an unknown method,
+// [ErrLengthMismatch] if X, y, and treatment differ in length,
+// [ErrNoTreatmentVariation] unless both groups are non-empty, and otherwise
+// any error from [GBM.Fit].
+func (u *UpliftModel) Fit(X [][]float64, y []float64, treatment []bool) error {
+	if u.Method != UpliftTwoModel && u.Method != UpliftTransformedOutcome {
+		return ErrInvalidUpliftMethod
+	}
+	if len(X) != len(y) || len(y) != len(treatment) {
+		return ErrLengthMismatch
+	}
+
+	var treatedX, controlX [][]float64
+	var treatedY, controlY []float64
+	for i, w := range treatment {
+		if w {
+			treatedX = append(treatedX, X[i])
+			treatedY = append(treatedY, y[i])
+		} else {
+			controlX = append(controlX, X[i])
+			controlY = append(controlY, y[i])
+		}
+	}
+	if len(treatedY) == 0 || len(controlY) == 0 {
+		return ErrNoTreatmentVariation
+	}
+
+	if u.Method == UpliftTransformedOutcome {
+		p := float64(len(treatedY)) / float64(len(y))
+		z := make([]float64, len(y))
+		for i, w := range treatment {
+			if w {
+				z[i] = y[i] / p
+			} else {
+				z[i] = -y[i] / (1 - p)
+			}
+		}
+		if err := u.Effect.Fit(X, z); err != nil {
+			return fmt.Errorf("uplift effect model: %w", err)
+		}
+		return nil
+	}
+
+	if err := u.Treated.Fit(treatedX, treatedY); err != nil {
+		return fmt.Errorf("uplift treated model: %w", err)
+	}
+	if err := u.Control.Fit(controlX, controlY); err != nil {
+		return fmt.Errorf("uplift control model: %w", err)
+	}
+	return nil
+}
+
+// PredictUplift returns the estimated treatment effect for one sample:
+// the expected outcome under treatment minus the expected outcome without.
+func (u *UpliftModel) PredictUplift(x []float64) float64 {
+	if u.Method == UpliftTransformedOutcome {
+		return u.Effect.PredictSingle(x)
+	}
+	if u.Treated.Config.Loss == "logloss" {
+		return u.Treated.PredictProba(x) - u.Control.PredictProba(x)
+	}
+	return u.Treated.PredictSingle(x) - u.Control.PredictSingle(x)
+}
+
+// PredictUpliftAll returns the estimated treatment effect for each sample in X.
+func (u *UpliftModel) PredictUpliftAll(X [][]float64) []float64 {
+	res := make([]float64, len(X))
+	for i := range X {
+		res[i] = u.PredictUplift(X[i])
+	}
+	return res
+}
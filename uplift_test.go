@@ -0,0 +1,123 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// generateUpliftData returns randomized-treatment samples whose treatment
+// raises the outcome by effect when x1 >= 0.5 and leaves it unchanged
+// otherwise. With binary set, outcomes are Bernoulli draws from a base rate
+// of 0.2 plus the effect.
+func generateUpliftData(effect float64, binary bool) ([][]float64, []float64, []bool) {
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 500)
+	y := make([]float64, 500)
+	treatment := make([]bool, 500)
+	for i := range X {
+		x1, x2 := rnd.Float64(), rnd.Float64()
+		X[i] = []float64{x1, x2}
+		treatment[i] = rnd.Float64() < 0.4
+		mean := x2
+		if binary {
+			mean = 0.2
+		}
+		if treatment[i] && x1 >= 0.5 {
+			mean += effect
+		}
+		if binary {
+			if rnd.Float64() < mean {
+				y[i] = 1
+			}
+		} else {
+			y[i] = mean + 0.1*rnd.NormFloat64()
+		}
+	}
+	return X, y, treatment
+}
+
+// meanUplift averages the predicted uplift over a grid on one side of x1 = 0.5.
+func meanUplift(u *UpliftModel, high bool) float64 {
+	var total float64
+	n := 0
+	for i := range 10 {
+		for j := range 10 {
+			x1 := 0.05 + float64(i)*0.04
+			if high {
+				x1 += 0.5
+			}
+			total += u.PredictUplift([]float64{x1, 0.05 + float64(j)*0.1})
+			n++
+		}
+	}
+	return total / float64(n)
+}
+
+func TestUpliftModel(t *testing.T) {
+	tests := []struct {
+		name   string
+		method UpliftMethod
+		binary bool
+		effect float64
+		tol    float64
+	}{
+		{"two-model regression", UpliftTwoModel, false, 2, 0.2},
+		{"two-model classification", UpliftTwoModel, true, 0.6, 0.2},
+		{"transformed outcome", UpliftTransformedOutcome, false, 2, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			X, y, treatment := generateUpliftData(tt.effect, tt.binary)
+			cfg := DefaultConfig()
+			cfg.NEstimators = 30
+			cfg.LearningRate = 0.3
+			cfg.MinSamplesLeaf = 20
+			cfg.MaxDepth = 3
+			if tt.binary {
+				cfg.Loss = "logloss"
+			}
+			u := NewUplift(cfg, tt.method)
+			if err := u.Fit(X, y, treatment); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := meanUplift(u, true); math.Abs(got-tt.effect) > tt.tol {
+				t.Errorf("mean uplift for x1 >= 0.5 is %v, want %v ± %v", got, tt.effect, tt.tol)
+			}
+			if got := meanUplift(u, false); math.Abs(got) > tt.tol {
+				t.Errorf("mean uplift for x1 < 0.5 is %v, want 0 ± %v", got, tt.tol)
+			}
+
+			all := u.PredictUpliftAll(X[:3])
+			for i, v := range all {
+				if v != u.PredictUplift(X[i]) {
+					t.Errorf("PredictUpliftAll[%d] = %v, PredictUplift = %v", i, v, u.PredictUplift(X[i]))
+				}
+			}
+		})
+	}
+}
+
+func TestUpliftTransformedOutcomeUsesMSE(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Loss = "logloss"
+	if u := NewUplift(cfg, UpliftTransformedOutcome); u.Effect.Config.Loss != "mse" {
+		t.Errorf("effect model loss = %q, want mse", u.Effect.Config.Loss)
+	}
+}
+
+func TestUpliftModelErrors(t *testing.T) {
+	X, y, treatment := generateUpliftData(1, false)
+
+	if err := NewUplift(DefaultConfig(), "s-learner").Fit(X, y, treatment); !errors.Is(err, ErrInvalidUpliftMethod) {
+		t.Errorf("expected ErrInvalidUpliftMethod, got %v", err)
+	}
+	if err := NewUplift(DefaultConfig(), UpliftTwoModel).Fit(X, y, treatment[1:]); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+	if err := NewUplift(DefaultConfig(), UpliftTwoModel).Fit(X, y, make([]bool, len(y))); !errors.Is(err, ErrNoTreatmentVariation) {
+		t.Errorf("expected ErrNoTreatmentVariation, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package gboost
 
 import (
+	"math"
 	"slices"
 
 	"golang.org/x/exp/constraints"
@@ -16,6 +17,33 @@ func hasSimilarLength(X [][]float64) bool {
 	return true
 }
 
+// allEqual reports whether every value in v equals the first, which holds
+// for an empty v.
+func allEqual(v []float64) bool {
+	return !slices.ContainsFunc(v, func(x float64) bool { return x != v[0] })
+}
+
+// validLabels reports whether every label in y is a probability in [0, 1],
+// or, unless soft is set, exactly 0 or 1.
+func validLabels(y []float64, soft bool) bool {
+	for _, v := range y {
+		if !(v >= 0 && v <= 1) || (!soft && v != 0 && v != 1) {
+			return false
+		}
+	}
+	return true
+}
+
+// positiveTargets reports whether every target in y is finite and > 0.
+func positiveTargets(y []float64) bool {
+	for _, v := range y {
+		if !(v > 0) || math.IsInf(v, 1) {
+			return false
+		}
+	}
+	return true
+}
+
 func sort[T constraints.Float | constraints.Integer](data []T) []T {
 	slices.Sort(data)
 	return data
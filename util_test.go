@@ -69,3 +69,22 @@ func TestHasSimilarLength(t *testing.T) {
 		})
 	}
 }
+
+func TestDeriveSeed(t *testing.T) {
+	if deriveSeed(42, 0) != deriveSeed(42, 0) {
+		t.Error("deriveSeed is not deterministic")
+	}
+
+	seen := make(map[int64]bool)
+	for stream := range 100 {
+		s := deriveSeed(42, stream)
+		if seen[s] {
+			t.Fatalf("stream %d repeats an earlier seed", stream)
+		}
+		seen[s] = true
+	}
+
+	if deriveSeed(1, 0) == deriveSeed(2, 0) {
+		t.Error("different master seeds produced the same stream seed")
+	}
+}
@@ -0,0 +1,309 @@
+package gboost
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/ahmedaabouzied/gboost/internal/fpmath"
+)
+
+// WOEBinner is a supervised [Transformer] for credit-scorecard workflows. It
+// cuts each numeric feature into a few bins whose weight of evidence (WOE)
+// is monotone in the feature, and replaces every value with the WOE of its
+// bin. With events y == 1,
+//
+//	WOE = ln(share of non-events in the bin / share of events in the bin)
+//
+// so a positive WOE marks a bin with a below-average event rate.
+//
+// Fit starts from quantile bins, merges adjacent bins that break
+// monotonicity (choosing the increasing or decreasing direction with the
+// higher information value), then merges bins smaller than MinBinFraction
+// and, while more than MaxBins remain, the adjacent pair with the closest
+// WOE. NaN inputs are mapped to a WOE of 0.
+type WOEBinner struct {
+	Columns        []int   `json:"columns,omitempty"` // Columns to bin; nil bins all of them.
+	MaxBins        int     `json:"max_bins"`          // Maximum bins per column.
+	MinBinFraction float64 `json:"min_bin_fraction"`  // Minimum fraction of rows per bin.
+
+	// Bins holds the fitted bins of each binned column; nil until fitted.
+	Bins []WOEBins `json:"bins,omitempty"`
+
+	NumFeatures int `json:"num_features,omitempty"`
+}
+
+// WOEBins describes the fitted bins of one column. Bin i holds the values v
+// with Edges[i-1] <= v < Edges[i], where the first and last bins are open.
+type WOEBins struct {
+	Column int       `json:"column"`
+	Edges  []float64 `json:"edges"`
+	WOE    []float64 `json:"woe"`
+	Counts []int     `json:"counts"` // Training rows per bin.
+	Events []int     `json:"events"` // Training rows with y == 1 per bin.
+	IV     float64   `json:"iv"`     // Information value of the binning.
+}
+
+// woeInitialBins is the number of quantile bins Fit starts from.
+const woeInitialBins = 20
+
+// NewWOEBinner creates an unfitted [WOEBinner] binning every column into at
+// most 10 bins of at least 5% of the rows each.
+func NewWOEBinner() *WOEBinner {
+	return &WOEBinner{MaxBins: 10, MinBinFraction: 0.05}
+}
+
+// Bin returns the index of the bin that v falls into.
+func (b *WOEBins) Bin(v float64) int {
+	pos, found := slices.BinarySearch(b.Edges, v)
+	if found {
+		pos++
+	}
+	return pos
+}
+
+// Fit learns monotone bins for each selected column of X against the binary
+// target y.
+//
+// Returns [ErrEmptyDataset] if X is empty, [ErrLengthMismatch] if X and y
+// differ in length, [ErrFeatureCountMismatch] if rows differ in length or a
+// column index is out of range, or [ErrNonBinaryTarget] unless y holds both
+// 0s and 1s and nothing else.
+func (w *WOEBinner) Fit(X [][]float64, y []float64) error {
+	switch {
+	case len(X) == 0:
+		return ErrEmptyDataset
+	case len(X) != len(y):
+		return ErrLengthMismatch
+	case !hasSimilarLength(X):
+		return ErrFeatureCountMismatch
+	case w.MaxBins < 1:
+		return fmt.Errorf("MaxBins must be >= 1, got %d", w.MaxBins)
+	}
+
+	events, err := binaryTargetEvents(y)
+	if err != nil {
+		return err
+	}
+
+	columns := w.Columns
+	if columns == nil {
+		columns = allFeatures(len(X[0]))
+	}
+	bins := make([]WOEBins, 0, len(columns))
+	for _, j := range columns {
+		if j < 0 || j >= len(X[0]) {
+			return fmt.Errorf("column %d: %w", j, ErrFeatureCountMismatch)
+		}
+		bins = append(bins, w.fitColumn(X, y, j, events))
+	}
+	w.Bins = bins
+	w.NumFeatures = len(X[0])
+	return nil
+}
+
+// woeBin accumulates the rows of one bin while merging.
+type woeBin struct {
+	upper  float64 // exclusive upper edge; +Inf for the last bin
+	count  int
+	events int
+}
+
+func (w *WOEBinner) fitColumn(X [][]float64, y []float64, j, totalEvents int) WOEBins {
+	var values []float64
+	for _, row := range X {
+		if !math.IsNaN(row[j]) {
+			values = append(values, row[j])
+		}
+	}
+	slices.Sort(values)
+
+	// Quantile edges over the observed values.
+	var edges []float64
+	for k := 1; k < woeInitialBins && len(values) > 0; k++ {
+		e := values[k*len(values)/woeInitialBins]
+		if e > values[0] && (len(edges) == 0 || e > edges[len(edges)-1]) {
+			edges = append(edges, e)
+		}
+	}
+	fine := make([]woeBin, len(edges)+1)
+	for k, e := range edges {
+		fine[k].upper = e
+	}
+	fine[len(edges)].upper = math.Inf(1)
+	spec := WOEBins{Edges: edges}
+	for i, row := range X {
+		if math.IsNaN(row[j]) {
+			continue
+		}
+		k := spec.Bin(row[j])
+		fine[k].count++
+		fine[k].events += int(y[i])
+	}
+
+	totalNonEvents := len(y) - totalEvents
+	woe := func(b woeBin) float64 {
+		return binWOE(b, totalEvents, totalNonEvents)
+	}
+
+	var best []woeBin
+	bestIV := math.Inf(-1)
+	for _, increasing := range []bool{true, false} {
+		merged := mergeMonotone(fine, woe, increasing)
+		merged = mergeSmall(merged, int(math.Ceil(w.MinBinFraction*float64(len(values)))))
+		merged = mergeClosest(merged, w.MaxBins, woe)
+		if iv := informationValue(merged, totalEvents, totalNonEvents); iv > bestIV {
+			best, bestIV = merged, iv
+		}
+	}
+
+	if len(best) == 0 {
+		// No observed values: a single neutral bin.
+		return WOEBins{Column: j, WOE: []float64{0}, Counts: []int{0}, Events: []int{0}}
+	}
+
+	res := WOEBins{Column: j, IV: bestIV}
+	for k, b := range best {
+		if k < len(best)-1 {
+			res.Edges = append(res.Edges, b.upper)
+		}
+		res.WOE = append(res.WOE, woe(b))
+		res.Counts = append(res.Counts, b.count)
+		res.Events = append(res.Events, b.events)
+	}
+	return res
+}
+
+// binWOE returns the weight of evidence of b, with half a count added to
+// both classes so that pure bins stay finite.
+func binWOE(b woeBin, totalEvents, totalNonEvents int) float64 {
+	return weightOfEvidence(b.events, b.count-b.events, totalEvents, totalNonEvents, 0.5)
+}
+
+// weightOfEvidence returns ln(share of non-events / share of events) for a
+// group, adding smoothing to both of the group's counts.
+func weightOfEvidence(events, nonEvents, totalEvents, totalNonEvents int, smoothing float64) float64 {
+	nonEventShare := (float64(nonEvents) + smoothing) / float64(totalNonEvents)
+	eventShare := (float64(events) + smoothing) / float64(totalEvents)
+	return fpmath.Log(nonEventShare / eventShare)
+}
+
+// binaryTargetEvents returns the number of 1s in y, or [ErrNonBinaryTarget]
+// unless y holds both 0s and 1s and nothing else.
+func binaryTargetEvents(y []float64) (int, error) {
+	var events int
+	for _, v := range y {
+		if v != 0 && v != 1 {
+			return 0, ErrNonBinaryTarget
+		}
+		events += int(v)
+	}
+	if events == 0 || events == len(y) {
+		return 0, ErrNonBinaryTarget
+	}
+	return events, nil
+}
+
+func informationValue(bins []woeBin, totalEvents, totalNonEvents int) float64 {
+	var iv float64
+	for _, b := range bins {
+		share := float64(b.count-b.events)/float64(totalNonEvents) - float64(b.events)/float64(totalEvents)
+		iv += share * binWOE(b, totalEvents, totalNonEvents)
+	}
+	return iv
+}
+
+func mergeBins(a, b woeBin) woeBin {
+	return woeBin{upper: b.upper, count: a.count + b.count, events: a.events + b.events}
+}
+
+// mergeMonotone merges adjacent bins, pool-adjacent-violators style, until
+// WOE strictly increases (or decreases) from bin to bin. Empty bins are
+// dropped.
+func mergeMonotone(bins []woeBin, woe func(woeBin) float64, increasing bool) []woeBin {
+	var stack []woeBin
+	for _, b := range bins {
+		if b.count == 0 {
+			if len(stack) > 0 {
+				stack[len(stack)-1].upper = b.upper
+			}
+			continue
+		}
+		stack = append(stack, b)
+		for len(stack) > 1 {
+			prev, last := woe(stack[len(stack)-2]), woe(stack[len(stack)-1])
+			if (increasing && prev < last) || (!increasing && prev > last) {
+				break
+			}
+			stack[len(stack)-2] = mergeBins(stack[len(stack)-2], stack[len(stack)-1])
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		stack[len(stack)-1].upper = math.Inf(1)
+	}
+	return stack
+}
+
+// mergeSmall merges each bin with fewer than minCount rows into its smaller
+// neighbor. Merging neighbors of a monotone sequence keeps it monotone.
+func mergeSmall(bins []woeBin, minCount int) []woeBin {
+	for len(bins) > 1 {
+		k := slices.IndexFunc(bins, func(b woeBin) bool { return b.count < minCount })
+		if k < 0 {
+			break
+		}
+		if k == len(bins)-1 || (k > 0 && bins[k-1].count < bins[k+1].count) {
+			k--
+		}
+		bins[k] = mergeBins(bins[k], bins[k+1])
+		bins = slices.Delete(bins, k+1, k+2)
+	}
+	return bins
+}
+
+// mergeClosest merges the adjacent pair with the closest WOE until at most
+// maxBins remain.
+func mergeClosest(bins []woeBin, maxBins int, woe func(woeBin) float64) []woeBin {
+	for len(bins) > maxBins {
+		k := 0
+		for i := 1; i < len(bins)-1; i++ {
+			if math.Abs(woe(bins[i+1])-woe(bins[i])) < math.Abs(woe(bins[k+1])-woe(bins[k])) {
+				k = i
+			}
+		}
+		bins[k] = mergeBins(bins[k], bins[k+1])
+		bins = slices.Delete(bins, k+1, k+2)
+	}
+	return bins
+}
+
+// Transform returns a copy of X with each binned column replaced by the WOE
+// of its bin. Other columns are copied unchanged.
+//
+// Returns [ErrModelNotFitted] if the binner has not been fitted, or
+// [ErrFeatureCountMismatch] if a row's length differs from the data it was
+// fitted on.
+func (w *WOEBinner) Transform(X [][]float64) ([][]float64, error) {
+	if w.Bins == nil {
+		return nil, ErrModelNotFitted
+	}
+	res := make([][]float64, len(X))
+	for i, row := range X {
+		if len(row) != w.NumFeatures {
+			return nil, ErrFeatureCountMismatch
+		}
+		res[i] = slices.Clone(row)
+		for _, b := range w.Bins {
+			v := row[b.Column]
+			if math.IsNaN(v) {
+				res[i][b.Column] = 0
+				continue
+			}
+			res[i][b.Column] = b.WOE[b.Bin(v)]
+		}
+	}
+	return res, nil
+}
+
+func (w *WOEBinner) transformerKind() string { return "woe" }
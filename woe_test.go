@@ -0,0 +1,163 @@
+package gboost
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+// generateScorecardData returns a default-risk style dataset: the event rate
+// falls with income (feature 0) and rises with utilization (feature 1);
+// feature 2 is noise.
+func generateScorecardData() ([][]float64, []float64) {
+	rnd := rand.New(rand.NewSource(0))
+	X := make([][]float64, 2000)
+	y := make([]float64, 2000)
+	for i := range X {
+		income, utilization := rnd.Float64()*100, rnd.Float64()
+		X[i] = []float64{income, utilization, rnd.Float64()}
+		p := sigmoid(-1 - 0.03*(income-50) + 3*(utilization-0.5))
+		if rnd.Float64() < p {
+			y[i] = 1
+		}
+	}
+	return X, y
+}
+
+func TestWOEBinnerMonotone(t *testing.T) {
+	X, y := generateScorecardData()
+	w := NewWOEBinner()
+	if err := w.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Bins) != 3 {
+		t.Fatalf("fitted %d columns, want 3", len(w.Bins))
+	}
+
+	for _, b := range w.Bins {
+		if len(b.WOE) > w.MaxBins || len(b.Edges) != len(b.WOE)-1 {
+			t.Errorf("column %d: %d bins with %d edges", b.Column, len(b.WOE), len(b.Edges))
+		}
+		total := 0
+		for k, c := range b.Counts {
+			total += c
+			if float64(c) < w.MinBinFraction*float64(len(X)) && len(b.Counts) > 1 {
+				t.Errorf("column %d bin %d has only %d rows", b.Column, k, c)
+			}
+		}
+		if total != len(X) {
+			t.Errorf("column %d bins hold %d rows, want %d", b.Column, total, len(X))
+		}
+	}
+
+	// Higher income lowers the event rate, so WOE increases with income;
+	// utilization does the opposite.
+	for k := 1; k < len(w.Bins[0].WOE); k++ {
+		if w.Bins[0].WOE[k] <= w.Bins[0].WOE[k-1] {
+			t.Errorf("income WOE %v is not increasing", w.Bins[0].WOE)
+			break
+		}
+	}
+	for k := 1; k < len(w.Bins[1].WOE); k++ {
+		if w.Bins[1].WOE[k] >= w.Bins[1].WOE[k-1] {
+			t.Errorf("utilization WOE %v is not decreasing", w.Bins[1].WOE)
+			break
+		}
+	}
+	if len(w.Bins[0].WOE) < 3 {
+		t.Errorf("income has only %d bins", len(w.Bins[0].WOE))
+	}
+	if w.Bins[2].IV >= 0.05 || w.Bins[0].IV <= 0.3 {
+		t.Errorf("IV noise = %v, income = %v; want noise < 0.05 < 0.3 < income", w.Bins[2].IV, w.Bins[0].IV)
+	}
+}
+
+func TestWOEBinnerTransform(t *testing.T) {
+	X, y := generateScorecardData()
+	w := NewWOEBinner()
+	w.Columns = []int{0}
+	w.MaxBins = 4
+	if err := w.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	b := w.Bins[0]
+	rows := [][]float64{{-10, 0.5, 0.5}, {b.Edges[0], 0.5, 0.5}, {1e9, 0.5, 0.5}, {math.NaN(), 0.5, 0.5}}
+	got, err := w.Transform(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{b.WOE[0], b.WOE[1], b.WOE[len(b.WOE)-1], 0}
+	for i := range rows {
+		if got[i][0] != want[i] {
+			t.Errorf("row %d: WOE %v, want %v", i, got[i][0], want[i])
+		}
+		if got[i][1] != 0.5 {
+			t.Errorf("row %d: unbinned column changed to %v", i, got[i][1])
+		}
+	}
+}
+
+func TestWOEBinnerInPipeline(t *testing.T) {
+	X, y := generateScorecardData()
+	config := DefaultConfig()
+	config.Loss = "logloss"
+	config.NEstimators = 20
+	config.MaxDepth = 2
+	p := NewPipeline(New(config), NewImputer(ImputeMedian), NewWOEBinner())
+	if err := p.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "scorecard.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPipeline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := p.PredictProbaAll(X[:50])
+	got, err := loaded.PredictProbaAll(X[:50])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("loaded PredictProbaAll[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWOEBinnerErrors(t *testing.T) {
+	X := [][]float64{{1}, {2}, {3}}
+	if err := NewWOEBinner().Fit(X, []float64{0, 1, 2}); !errors.Is(err, ErrNonBinaryTarget) {
+		t.Errorf("expected ErrNonBinaryTarget for a non-binary target, got %v", err)
+	}
+	if err := NewWOEBinner().Fit(X, []float64{1, 1, 1}); !errors.Is(err, ErrNonBinaryTarget) {
+		t.Errorf("expected ErrNonBinaryTarget for a single class, got %v", err)
+	}
+	if err := NewWOEBinner().Fit(X, []float64{0, 1}); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+	w := NewWOEBinner()
+	w.Columns = []int{3}
+	if err := w.Fit(X, []float64{0, 1, 0}); !errors.Is(err, ErrFeatureCountMismatch) {
+		t.Errorf("expected ErrFeatureCountMismatch, got %v", err)
+	}
+	if _, err := NewWOEBinner().Transform(X); !errors.Is(err, ErrModelNotFitted) {
+		t.Errorf("expected ErrModelNotFitted, got %v", err)
+	}
+
+	// A column with no observed values gets a single neutral bin.
+	nan := math.NaN()
+	w = NewWOEBinner()
+	if err := w.Fit([][]float64{{nan}, {nan}, {nan}}, []float64{0, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := w.Transform([][]float64{{5}}); err != nil || got[0][0] != 0 {
+		t.Errorf("all-missing column transformed to %v, %v; want 0", got, err)
+	}
+}